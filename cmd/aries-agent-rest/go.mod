@@ -6,12 +6,16 @@ module github.com/hyperledger/aries-framework-go/cmd/aries-agent-rest
 
 replace github.com/hyperledger/aries-framework-go => ../..
 
+replace github.com/hyperledger/aries-framework-go/pkg/storage/sql => ../../pkg/storage/sql
+
 require (
 	github.com/gorilla/mux v1.7.3
 	github.com/hyperledger/aries-framework-go v0.0.0
+	github.com/hyperledger/aries-framework-go/pkg/storage/sql v0.0.0
 	github.com/rs/cors v1.7.0
 	github.com/spf13/cobra v0.0.5
 	github.com/stretchr/testify v1.4.0
+	modernc.org/sqlite v1.7.5
 )
 
 go 1.13
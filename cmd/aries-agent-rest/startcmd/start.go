@@ -12,10 +12,12 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
 	"github.com/spf13/cobra"
+	_ "modernc.org/sqlite" // pure Go SQLite driver for the sql storage type
 
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/messaging/msghandler"
@@ -27,6 +29,9 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/framework/context"
 	"github.com/hyperledger/aries-framework-go/pkg/restapi"
 	"github.com/hyperledger/aries-framework-go/pkg/restapi/operation"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/bbolt"
+	sqlstore "github.com/hyperledger/aries-framework-go/pkg/storage/sql"
+	"github.com/hyperledger/aries-framework-go/pkg/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/vdri/httpbinding"
 )
 
@@ -69,6 +74,18 @@ const (
 
 	agentDBPathEnvKey = "ARIESD_DB_PATH"
 
+	agentDBTypeFlagName = "db-type"
+
+	agentDBTypeFlagShorthand = "s"
+
+	agentDBTypeFlagUsage = "Storage type used by the agent." +
+		" Possible values [leveldb] [bbolt] [sql]. Defaults to leveldb if not set." +
+		" For bbolt, db-path is the path to the single database file." +
+		" For sql, db-path is the SQLite data source name (e.g. the path to the database file)." +
+		" Alternatively, this can be set with the following environment variable: " + agentDBTypeEnvKey
+
+	agentDBTypeEnvKey = "ARIESD_DB_TYPE"
+
 	agentWebhookFlagName = "webhook-url"
 
 	agentWebhookFlagShorthand = "w"
@@ -128,8 +145,30 @@ const (
 		" Possible values [true] [false]. Defaults to false if not set." +
 		" Alternatively, this can be set with the following environment variable: " + agentAutoAcceptEnvKey
 
+	agentVDRICacheSizeFlagName = "vdri-cache-size"
+
+	agentVDRICacheSizeFlagUsage = "Enables the cache of the resolved DID documents with the given maximum number" +
+		" of the cached DIDs. Alternatively, this can be set with the following environment variable: " +
+		agentVDRICacheSizeEnvKey
+
+	agentVDRICacheSizeEnvKey = "ARIESD_VDRI_CACHE_SIZE"
+
+	agentVDRICacheTTLFlagName = "vdri-cache-ttl"
+
+	agentVDRICacheTTLFlagUsage = "Enables the cache of the resolved DID documents with the given time to live" +
+		" (e.g. 5m, 1h). Alternatively, this can be set with the following environment variable: " +
+		agentVDRICacheTTLEnvKey
+
+	agentVDRICacheTTLEnvKey = "ARIESD_VDRI_CACHE_TTL"
+
 	httpProtocol      = "http"
 	websocketProtocol = "ws"
+
+	levelDBStorageType = "leveldb"
+	bboltStorageType   = "bbolt"
+	sqlStorageType     = "sql"
+
+	sqliteDriverName = "sqlite"
 )
 
 var errMissingHost = errors.New("host not provided")
@@ -139,11 +178,12 @@ var errMissingInboundHost = errors.New("HTTP Inbound transport host not provided
 var logger = log.New("aries-framework/agent-rest")
 
 type agentParameters struct {
-	server                                                                                 server
-	host, inboundHostInternal, inboundHostExternal, dbPath, defaultLabel, inboundTransport string
-	webhookURLs, httpResolvers, outboundTransports                                         []string
-	autoAccept                                                                             bool
-	msgHandler                                                                             operation.MessageHandler
+	server                                                                                         server
+	host, inboundHostInternal, inboundHostExternal, dbPath, dbType, defaultLabel, inboundTransport string
+	webhookURLs, httpResolvers, outboundTransports                                                 []string
+	vdriCacheSize, vdriCacheTTL                                                                    string
+	autoAccept                                                                                     bool
+	msgHandler                                                                                     operation.MessageHandler
 }
 
 type server interface {
@@ -188,6 +228,11 @@ func createStartCMD(server server) *cobra.Command { //nolint funlen gocyclo
 				return err
 			}
 
+			dbType, err := getUserSetVar(cmd, agentDBTypeFlagName, agentDBTypeEnvKey, true)
+			if err != nil {
+				return err
+			}
+
 			inboundHostExternal, err := getUserSetVar(cmd, agentInboundHostExternalFlagName,
 				agentInboundHostExternalEnvKey, true)
 			if err != nil {
@@ -227,10 +272,21 @@ func createStartCMD(server server) *cobra.Command { //nolint funlen gocyclo
 				return err
 			}
 
+			vdriCacheSize, err := getUserSetVar(cmd, agentVDRICacheSizeFlagName, agentVDRICacheSizeEnvKey, true)
+			if err != nil {
+				return err
+			}
+
+			vdriCacheTTL, err := getUserSetVar(cmd, agentVDRICacheTTLFlagName, agentVDRICacheTTLEnvKey, true)
+			if err != nil {
+				return err
+			}
+
 			parameters := &agentParameters{server: server, host: host, inboundHostInternal: inboundHost,
-				inboundHostExternal: inboundHostExternal, dbPath: dbPath, defaultLabel: defaultLabel, webhookURLs: webhookURLs,
+				inboundHostExternal: inboundHostExternal, dbPath: dbPath, dbType: dbType, defaultLabel: defaultLabel,
+				webhookURLs:   webhookURLs,
 				httpResolvers: httpResolvers, outboundTransports: outboundTransports, inboundTransport: inboundTransport,
-				autoAccept: autoAccept}
+				autoAccept: autoAccept, vdriCacheSize: vdriCacheSize, vdriCacheTTL: vdriCacheTTL}
 			return startAgent(parameters)
 		},
 	}
@@ -253,6 +309,7 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(agentHostFlagName, agentHostFlagShorthand, "", agentHostFlagUsage)
 	startCmd.Flags().StringP(agentInboundHostFlagName, agentInboundHostFlagShorthand, "", agentInboundHostFlagUsage)
 	startCmd.Flags().StringP(agentDBPathFlagName, agentDBPathFlagShorthand, "", agentDBPathFlagUsage)
+	startCmd.Flags().StringP(agentDBTypeFlagName, agentDBTypeFlagShorthand, "", agentDBTypeFlagUsage)
 	startCmd.Flags().StringSliceP(agentWebhookFlagName, agentWebhookFlagShorthand, []string{},
 		agentWebhookFlagUsage)
 	startCmd.Flags().StringSliceP(agentHTTPResolverFlagName, agentHTTPResolverFlagShorthand, []string{},
@@ -268,6 +325,10 @@ func createFlags(startCmd *cobra.Command) {
 		agentInboundTransportFlagUsage)
 	startCmd.Flags().StringP(agentAutoAcceptFlagName, "", "",
 		agentAutoAcceptFlagUsage)
+	startCmd.Flags().StringP(agentVDRICacheSizeFlagName, "", "",
+		agentVDRICacheSizeFlagUsage)
+	startCmd.Flags().StringP(agentVDRICacheTTLFlagName, "", "",
+		agentVDRICacheTTLFlagUsage)
 }
 
 func getUserSetVar(cmd *cobra.Command, hostFlagName, envKey string, isOptional bool) (string, error) {
@@ -343,6 +404,35 @@ func getResolverOpts(httpResolvers []string) ([]aries.Option, error) {
 	return opts, nil
 }
 
+// getVDRICacheOpts enables the cache of the resolved DID documents if either the size or the TTL is set.
+func getVDRICacheOpts(cacheSize, cacheTTL string) ([]aries.Option, error) {
+	if cacheSize == "" && cacheTTL == "" {
+		return nil, nil
+	}
+
+	var cacheOpts []vdri.CacheOption
+
+	if cacheSize != "" {
+		size, err := strconv.Atoi(cacheSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vdri cache size: %w", err)
+		}
+
+		cacheOpts = append(cacheOpts, vdri.WithCacheSize(size))
+	}
+
+	if cacheTTL != "" {
+		ttl, err := time.ParseDuration(cacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vdri cache ttl: %w", err)
+		}
+
+		cacheOpts = append(cacheOpts, vdri.WithCacheTTL(ttl))
+	}
+
+	return []aries.Option{aries.WithVDRICache(cacheOpts...)}, nil
+}
+
 func getOutboundTransportOpts(outboundTransports []string) ([]aries.Option, error) {
 	var opts []aries.Option
 
@@ -371,6 +461,37 @@ func getOutboundTransportOpts(outboundTransports []string) ([]aries.Option, erro
 	return opts, nil
 }
 
+func getStorageOpts(dbType, dbPath string) ([]aries.Option, error) {
+	if dbType == "" {
+		dbType = levelDBStorageType
+	}
+
+	switch dbType {
+	case levelDBStorageType:
+		if dbPath == "" {
+			return nil, nil
+		}
+
+		return []aries.Option{defaults.WithStorePath(dbPath)}, nil
+	case bboltStorageType:
+		storeProvider, err := bbolt.NewProvider(dbPath)
+		if err != nil {
+			return nil, fmt.Errorf("bbolt storage initialization failed: %w", err)
+		}
+
+		return []aries.Option{aries.WithStoreProvider(storeProvider)}, nil
+	case sqlStorageType:
+		storeProvider, err := sqlstore.NewProvider(sqliteDriverName, dbPath)
+		if err != nil {
+			return nil, fmt.Errorf("sql storage initialization failed: %w", err)
+		}
+
+		return []aries.Option{aries.WithStoreProvider(storeProvider)}, nil
+	default:
+		return nil, fmt.Errorf("storage type [%s] not supported", dbType)
+	}
+}
+
 func getInboundTransportOpts(inboundTransport, inboundHostInternal, inboundHostExternal string) (aries.Option, error) {
 	if inboundTransport == "" {
 		inboundTransport = httpProtocol
@@ -432,10 +553,10 @@ func startAgent(parameters *agentParameters) error {
 }
 
 func createAriesAgent(parameters *agentParameters) (*context.Provider, error) {
-	var opts []aries.Option
-
-	if parameters.dbPath != "" {
-		opts = append(opts, defaults.WithStorePath(parameters.dbPath))
+	opts, err := getStorageOpts(parameters.dbType, parameters.dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start aries agent rest on port [%s], failed to storage opts : %w",
+			parameters.host, err)
 	}
 
 	inboundTransportOpt, err := getInboundTransportOpts(parameters.inboundTransport,
@@ -455,6 +576,14 @@ func createAriesAgent(parameters *agentParameters) (*context.Provider, error) {
 
 	opts = append(opts, resolverOpts...)
 
+	vdriCacheOpts, err := getVDRICacheOpts(parameters.vdriCacheSize, parameters.vdriCacheTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start aries agent rest on port [%s], failed to vdri cache opts : %w",
+			parameters.host, err)
+	}
+
+	opts = append(opts, vdriCacheOpts...)
+
 	outboundTransportOpts, err := getOutboundTransportOpts(parameters.outboundTransports)
 	if err != nil {
 		return nil, fmt.Errorf("failed to start aries agent rest on port [%s], failed to outbound transport opts : %w",
@@ -14,6 +14,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -79,6 +80,9 @@ func TestStartCmdContents(t *testing.T) {
 	checkFlagPropertiesCorrect(t, startCmd, agentInboundHostFlagName,
 		agentInboundHostFlagShorthand, agentInboundHostFlagUsage)
 	checkFlagPropertiesCorrect(t, startCmd, agentDBPathFlagName, agentDBPathFlagShorthand, agentDBPathFlagUsage)
+	checkFlagPropertiesCorrect(t, startCmd, agentDBTypeFlagName, agentDBTypeFlagShorthand, agentDBTypeFlagUsage)
+	checkFlagPropertiesCorrect(t, startCmd, agentVDRICacheSizeFlagName, "", agentVDRICacheSizeFlagUsage)
+	checkFlagPropertiesCorrect(t, startCmd, agentVDRICacheTTLFlagName, "", agentVDRICacheTTLFlagUsage)
 }
 
 func checkFlagPropertiesCorrect(t *testing.T, cmd *cobra.Command, flagName, flagShorthand, flagUsage string) {
@@ -537,6 +541,86 @@ func TestStartAriesWithInboundTransport(t *testing.T) {
 	})
 }
 
+func TestStartAriesWithStorageType(t *testing.T) {
+	t.Run("start aries with bbolt storage success", func(t *testing.T) {
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+
+		testHostURL := randomURL()
+		testInboundHostURL := randomURL()
+
+		go func() {
+			parameters := &agentParameters{server: &HTTPServer{}, host: testHostURL, inboundHostInternal: testInboundHostURL,
+				inboundHostExternal: "", dbPath: filepath.Join(path, "aries.db"), dbType: "bbolt", defaultLabel: "x",
+				webhookURLs: []string{}, httpResolvers: []string{},
+				outboundTransports: []string{}, inboundTransport: ""}
+
+			err := startAgent(parameters)
+			require.NoError(t, err)
+			require.FailNow(t, agentUnexpectedExitErrMsg+": "+err.Error())
+		}()
+
+		waitForServerToStart(t, testHostURL, testInboundHostURL)
+	})
+
+	t.Run("start aries with bbolt storage - invalid path", func(t *testing.T) {
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+
+		parameters := &agentParameters{server: &HTTPServer{}, host: randomURL(), inboundHostInternal: randomURL(),
+			inboundHostExternal: "", dbPath: path, dbType: "bbolt", defaultLabel: "x",
+			webhookURLs: []string{}, httpResolvers: []string{},
+			outboundTransports: []string{}, inboundTransport: ""}
+		err := startAgent(parameters)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "bbolt storage initialization failed")
+	})
+
+	t.Run("start aries with sql storage success", func(t *testing.T) {
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+
+		testHostURL := randomURL()
+		testInboundHostURL := randomURL()
+
+		go func() {
+			parameters := &agentParameters{server: &HTTPServer{}, host: testHostURL, inboundHostInternal: testInboundHostURL,
+				inboundHostExternal: "", dbPath: filepath.Join(path, "aries.db"), dbType: "sql", defaultLabel: "x",
+				webhookURLs: []string{}, httpResolvers: []string{},
+				outboundTransports: []string{}, inboundTransport: ""}
+
+			err := startAgent(parameters)
+			require.NoError(t, err)
+			require.FailNow(t, agentUnexpectedExitErrMsg+": "+err.Error())
+		}()
+
+		waitForServerToStart(t, testHostURL, testInboundHostURL)
+	})
+
+	t.Run("start aries with sql storage - missing data source name", func(t *testing.T) {
+		parameters := &agentParameters{server: &HTTPServer{}, host: randomURL(), inboundHostInternal: randomURL(),
+			inboundHostExternal: "", dbPath: "", dbType: "sql", defaultLabel: "x",
+			webhookURLs: []string{}, httpResolvers: []string{},
+			outboundTransports: []string{}, inboundTransport: ""}
+		err := startAgent(parameters)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "sql storage initialization failed")
+	})
+
+	t.Run("start aries with unsupported storage type", func(t *testing.T) {
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+
+		parameters := &agentParameters{server: &HTTPServer{}, host: randomURL(), inboundHostInternal: randomURL(),
+			inboundHostExternal: "", dbPath: path, dbType: "couchdb", defaultLabel: "x",
+			webhookURLs: []string{}, httpResolvers: []string{},
+			outboundTransports: []string{}, inboundTransport: ""}
+		err := startAgent(parameters)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "storage type [couchdb] not supported")
+	})
+}
+
 func TestStartAriesWithAutoAccept(t *testing.T) {
 	t.Run("start aries with auto accept success", func(t *testing.T) {
 		path, cleanup := generateTempDir(t)
@@ -560,6 +644,48 @@ func TestStartAriesWithAutoAccept(t *testing.T) {
 	})
 }
 
+func TestStartAriesWithVDRICache(t *testing.T) {
+	t.Run("start aries with vdri cache success", func(t *testing.T) {
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+
+		testHostURL := randomURL()
+		testInboundHostURL := randomURL()
+
+		go func() {
+			parameters := &agentParameters{server: &HTTPServer{}, host: testHostURL, inboundHostInternal: testInboundHostURL,
+				inboundHostExternal: "", dbPath: path, defaultLabel: "x",
+				webhookURLs: []string{}, httpResolvers: []string{},
+				outboundTransports: []string{}, inboundTransport: "", vdriCacheSize: "10", vdriCacheTTL: "1m"}
+
+			err := startAgent(parameters)
+			require.NoError(t, err)
+			require.FailNow(t, agentUnexpectedExitErrMsg+": "+err.Error())
+		}()
+
+		waitForServerToStart(t, testHostURL, testInboundHostURL)
+	})
+
+	t.Run("start aries with invalid vdri cache options", func(t *testing.T) {
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+
+		parameters := &agentParameters{server: &HTTPServer{}, host: randomURL(), inboundHostInternal: randomURL(),
+			inboundHostExternal: "", dbPath: path, defaultLabel: "x",
+			webhookURLs: []string{}, httpResolvers: []string{},
+			outboundTransports: []string{}, inboundTransport: "", vdriCacheSize: "ten"}
+		err := startAgent(parameters)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid vdri cache size")
+
+		parameters.vdriCacheSize = ""
+		parameters.vdriCacheTTL = "1 minute"
+		err = startAgent(parameters)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid vdri cache ttl")
+	})
+}
+
 func waitForServerToStart(t *testing.T, host, inboundHost string) {
 	if err := listenFor(host); err != nil {
 		t.Fatal(err)
@@ -103,10 +103,31 @@ func New(ctx provider) (*Client, error) {
 	}, nil
 }
 
+// InvitationOpt is the invitation option.
+type InvitationOpt func(opts *invitationOpts)
+
+type invitationOpts struct {
+	routerConnectionID string
+}
+
+// WithRouterConnectionID sets the connection ID of the router the invitation keys are advertised through.
+// The default router is used if the option is not provided.
+func WithRouterConnectionID(connectionID string) InvitationOpt {
+	return func(opts *invitationOpts) {
+		opts.routerConnectionID = connectionID
+	}
+}
+
 // CreateInvitation creates an invitation. New key pair will be generated and base58 encoded public key will be
 // used as basis for invitation. This invitation will be stored so client can cross reference this invitation during
 // did exchange protocol
-func (c *Client) CreateInvitation(label string) (*Invitation, error) {
+func (c *Client) CreateInvitation(label string, opts ...InvitationOpt) (*Invitation, error) {
+	invOpts := &invitationOpts{}
+
+	for _, opt := range opts {
+		opt(invOpts)
+	}
+
 	// TODO https://github.com/hyperledger/aries-framework-go/issues/623 'alias' should be passed as arg and persisted
 	//  with connection record
 	_, sigPubKey, err := c.legacyKMS.CreateKeySet()
@@ -115,7 +136,8 @@ func (c *Client) CreateInvitation(label string) (*Invitation, error) {
 	}
 
 	// get the route configs
-	serviceEndpoint, routingKeys, err := route.GetRouterConfig(c.routeSvc, c.inboundTransportEndpoint)
+	serviceEndpoint, routingKeys, err := route.GetRouterConfig(c.routeSvc, invOpts.routerConnectionID,
+		c.inboundTransportEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("create invitation - fetch router config : %w", err)
 	}
@@ -129,7 +151,7 @@ func (c *Client) CreateInvitation(label string) (*Invitation, error) {
 		RoutingKeys:     routingKeys,
 	}
 
-	if err = route.AddKeyToRouter(c.routeSvc, sigPubKey); err != nil {
+	if err = route.AddKeyToRouter(c.routeSvc, invOpts.routerConnectionID, sigPubKey); err != nil {
 		return nil, fmt.Errorf("create invitation - add key to the router : %w", err)
 	}
 
@@ -138,6 +160,14 @@ func (c *Client) CreateInvitation(label string) (*Invitation, error) {
 		return nil, fmt.Errorf("failed to save invitation: %w", err)
 	}
 
+	// the keys of the connections created from the invitation use the same router
+	if invOpts.routerConnectionID != "" {
+		err = c.connectionStore.SaveInvitationRouter(invitation.ID, invOpts.routerConnectionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save invitation router: %w", err)
+		}
+	}
+
 	return &Invitation{invitation}, nil
 }
 
@@ -273,6 +303,9 @@ func (c *Client) GetConnectionAtState(connectionID, stateID string) (*Connection
 
 // RemoveConnection removes connection record for given id
 func (c *Client) RemoveConnection(id string) error {
-	// TODO https://github.com/hyperledger/aries-framework-go/issues/553 RemoveConnection from did exchange service
+	if err := c.connectionStore.RemoveConnection(id); err != nil {
+		return fmt.Errorf("failed to remove connection: %w", err)
+	}
+
 	return nil
 }
@@ -29,6 +29,7 @@ import (
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
 	mockstore "github.com/hyperledger/aries-framework-go/pkg/internal/mock/storage"
 	mockvdri "github.com/hyperledger/aries-framework-go/pkg/internal/mock/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
 	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
@@ -238,6 +239,54 @@ func TestClient_CreateInvitation(t *testing.T) {
 		require.Equal(t, routingKeys, inviteReq.RoutingKeys)
 	})
 
+	t.Run("test success with router connection ID", func(t *testing.T) {
+		svc, err := didexchange.New(&mockprotocol.MockProvider{
+			ServiceMap: map[string]interface{}{
+				route.Coordination: &mockroute.MockRouteSvc{},
+			},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, svc)
+
+		var routerConnIDs []string
+
+		c, err := New(&mockprovider.Provider{
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: svc,
+				route.Coordination: &mockroute.MockRouteSvc{
+					RoutingKeys:    []string{"abc"},
+					RouterEndpoint: "http://router.example.com",
+					AddKeyFunc: func(connectionID, recKey string) error {
+						routerConnIDs = append(routerConnIDs, connectionID)
+						return nil
+					},
+				},
+			},
+			KMSValue:             &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+			InboundEndpointValue: "endpoint",
+		})
+		require.NoError(t, err)
+
+		invitation, err := c.CreateInvitation("agent")
+		require.NoError(t, err)
+
+		_, err = c.connectionStore.GetInvitationRouter(invitation.ID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		invitation, err = c.CreateInvitation("agent", WithRouterConnectionID("router2"))
+		require.NoError(t, err)
+
+		// the default router is used if the router is not chosen
+		require.Equal(t, []string{"", "router2"}, routerConnIDs)
+
+		// the router is saved for the connections created from the invitation
+		routerConnID, err := c.connectionStore.GetInvitationRouter(invitation.ID)
+		require.NoError(t, err)
+		require.Equal(t, "router2", routerConnID)
+	})
+
 	t.Run("test create invitation with router config error", func(t *testing.T) {
 		svc, err := didexchange.New(&mockprotocol.MockProvider{
 			ServiceMap: map[string]interface{}{
@@ -540,6 +589,39 @@ func TestClient_RemoveConnection(t *testing.T) {
 
 	err = c.RemoveConnection("sample-id")
 	require.NoError(t, err)
+
+	t.Run("remove existing connection", func(t *testing.T) {
+		connRec := &connection.Record{ConnectionID: "id1", ThreadID: "th1", State: "completed"}
+		require.NoError(t, c.connectionStore.SaveConnectionRecord(connRec))
+
+		_, err = c.GetConnection("id1")
+		require.NoError(t, err)
+
+		require.NoError(t, c.RemoveConnection("id1"))
+
+		_, err = c.GetConnection("id1")
+		require.EqualError(t, err, ErrConnectionNotFound.Error())
+	})
+
+	t.Run("remove connection - store error", func(t *testing.T) {
+		const errMsg = "batch error"
+		c, err := New(&mockprovider.Provider{
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			StorageProviderValue: mockstore.NewCustomMockStoreProvider(&mockstore.MockStore{
+				Store:    make(map[string][]byte),
+				ErrBatch: errors.New(errMsg),
+			}),
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: svc,
+				route.Coordination:      &mockroute.MockRouteSvc{},
+			},
+		})
+		require.NoError(t, err)
+
+		err = c.RemoveConnection("id1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errMsg)
+	})
 }
 
 func TestClient_HandleInvitation(t *testing.T) {
@@ -0,0 +1,189 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didexchange
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/common/invitationurl"
+)
+
+var logger = log.New("aries-framework/didexchange/client")
+
+const (
+	// invitationParam is the query parameter of the invitation URL holding the base64url encoded invitation.
+	invitationParam = "c_i"
+
+	// maxRedirects is the maximum number of redirects followed to resolve the short invitation URL.
+	maxRedirects = 10
+
+	// maxInvitationSize is the maximum size of the invitation read from the resource the short URL refers to.
+	maxInvitationSize = 64 * 1024
+
+	defaultHTTPTimeout = 10 * time.Second
+)
+
+// ErrInvitationNotFound is returned when neither the URL nor the resource it refers to has the invitation.
+var ErrInvitationNotFound = invitationurl.ErrInvitationNotFound
+
+// InvitationURL renders the invitation as the URL with the base64url encoded invitation in the `c_i` parameter,
+// e.g. https://example.com/path?c_i=eyJAdHlwZSI6...
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0160-connection-protocol#standard-invitation-encoding
+func InvitationURL(baseURL string, invitation *Invitation) (string, error) {
+	if invitation == nil || invitation.Invitation == nil {
+		return "", errors.New("missing invitation")
+	}
+
+	return invitationurl.Encode(baseURL, invitationParam, invitation)
+}
+
+// ParseOpt is the invitation URL parsing option.
+type ParseOpt func(opts *parseOpts)
+
+type parseOpts struct {
+	httpClient *http.Client
+	noShortURL bool
+}
+
+// WithHTTPClient sets the HTTP client used to resolve the short invitation URL.
+func WithHTTPClient(client *http.Client) ParseOpt {
+	return func(opts *parseOpts) {
+		opts.httpClient = client
+	}
+}
+
+// WithoutShortURL disables the short invitation URL resolution, only the URL with the invitation is parsed.
+// Use it when the URL comes from an untrusted source, e.g. a remote API caller, to not fetch arbitrary URLs.
+func WithoutShortURL() ParseOpt {
+	return func(opts *parseOpts) {
+		opts.noShortURL = true
+	}
+}
+
+// ParseInvitationURL parses the invitation from the `c_i` parameter of the URL (refer InvitationURL).
+// If the URL has no invitation, it is treated as the short URL: the redirects are followed until the URL
+// with the invitation is found, otherwise the invitation is read from the JSON response. Only the http and https
+// short URLs are resolved.
+func ParseInvitationURL(invitationURL string, opts ...ParseOpt) (*Invitation, error) {
+	pOpts := &parseOpts{httpClient: &http.Client{Timeout: defaultHTTPTimeout}}
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	u, err := url.Parse(invitationURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse invitation URL: %w", err)
+	}
+
+	if hasInvitation(u) {
+		return decodeInvitation(u)
+	}
+
+	if pOpts.noShortURL {
+		return nil, ErrInvitationNotFound
+	}
+
+	if !isHTTPURL(u) {
+		return nil, fmt.Errorf("fetch invitation: unsupported URL scheme %q", u.Scheme)
+	}
+
+	return fetchInvitation(u, pOpts.httpClient)
+}
+
+// fetchInvitation resolves the short invitation URL.
+func fetchInvitation(u *url.URL, httpClient *http.Client) (*Invitation, error) {
+	client := *httpClient
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+
+		if !isHTTPURL(req.URL) {
+			return fmt.Errorf("unsupported redirect URL scheme %q", req.URL.Scheme)
+		}
+
+		// the invitation is in the URL, no need to fetch the resource
+		if hasInvitation(req.URL) {
+			return http.ErrUseLastResponse
+		}
+
+		return nil
+	}
+
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return nil, fmt.Errorf("fetch invitation: %w", err)
+	}
+
+	defer func() {
+		if e := resp.Body.Close(); e != nil {
+			logger.Warnf("failed to close the response body: %s", e)
+		}
+	}()
+
+	if location, e := resp.Location(); e == nil && hasInvitation(location) {
+		return decodeInvitation(location)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch invitation: unexpected status code %d", resp.StatusCode)
+	}
+
+	// the body is read up to one byte past the limit to detect the oversized invitation
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxInvitationSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read invitation: %w", err)
+	}
+
+	if len(body) > maxInvitationSize {
+		return nil, fmt.Errorf("read invitation: exceeds %d bytes", maxInvitationSize)
+	}
+
+	invitation := &didexchange.Invitation{}
+
+	if err := json.Unmarshal(body, invitation); err != nil {
+		return nil, fmt.Errorf("unmarshal invitation: %w", err)
+	}
+
+	return checkInvitation(invitation)
+}
+
+func decodeInvitation(u *url.URL) (*Invitation, error) {
+	invitation := &didexchange.Invitation{}
+
+	if err := invitationurl.Decode(u, invitationParam, invitation); err != nil {
+		return nil, err
+	}
+
+	return checkInvitation(invitation)
+}
+
+func checkInvitation(invitation *didexchange.Invitation) (*Invitation, error) {
+	if invitation.Type != InvitationMsgType {
+		return nil, ErrInvitationNotFound
+	}
+
+	return &Invitation{invitation}, nil
+}
+
+func hasInvitation(u *url.URL) bool {
+	return u.Query().Get(invitationParam) != ""
+}
+
+func isHTTPURL(u *url.URL) bool {
+	return u.Scheme == "http" || u.Scheme == "https"
+}
@@ -0,0 +1,184 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didexchange
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+)
+
+func TestInvitationURL(t *testing.T) {
+	invitation := &Invitation{&didexchange.Invitation{
+		ID:              "invitation-1",
+		Type:            InvitationMsgType,
+		Label:           "Alice",
+		RecipientKeys:   []string{"8HH5gYEeNc3z7PYXmd54d4x6qAfCNrqQqEB3nS7Zfu7K"},
+		ServiceEndpoint: "https://example.com/endpoint",
+	}}
+
+	t.Run("test invitation URL - render and parse", func(t *testing.T) {
+		u, err := InvitationURL("https://example.com/path?name=value", invitation)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(u, "https://example.com/path?"))
+		require.Contains(t, u, "name=value")
+		require.Contains(t, u, "c_i=")
+
+		parsed, err := ParseInvitationURL(u)
+		require.NoError(t, err)
+		require.Equal(t, invitation, parsed)
+	})
+
+	t.Run("test invitation URL - parse without padding", func(t *testing.T) {
+		encoded := base64.RawURLEncoding.EncodeToString([]byte(
+			`{"@id":"invitation-1","@type":"` + InvitationMsgType + `","label":"Alice"}`))
+
+		parsed, err := ParseInvitationURL("https://example.com?c_i=" + encoded)
+		require.NoError(t, err)
+		require.Equal(t, "invitation-1", parsed.ID)
+		require.Equal(t, "Alice", parsed.Label)
+	})
+
+	t.Run("test invitation URL - render errors", func(t *testing.T) {
+		_, err := InvitationURL("https://example.com", nil)
+		require.EqualError(t, err, "missing invitation")
+
+		_, err = InvitationURL("%", invitation)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse base URL")
+	})
+
+	t.Run("test invitation URL - parse errors", func(t *testing.T) {
+		_, err := ParseInvitationURL("%")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse invitation URL")
+
+		_, err = ParseInvitationURL("https://example.com?c_i=@@@")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode invitation")
+
+		_, err = ParseInvitationURL("https://example.com?c_i=" + base64.URLEncoding.EncodeToString([]byte("invalid")))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal invitation")
+
+		_, err = ParseInvitationURL("https://example.com?c_i=" + base64.URLEncoding.EncodeToString(
+			[]byte(`{"@type":"https://didcomm.org/trust_ping/1.0/ping"}`)))
+		require.True(t, errors.Is(err, ErrInvitationNotFound))
+	})
+}
+
+func TestParseInvitationURL_ShortURL(t *testing.T) {
+	invitation := &Invitation{&didexchange.Invitation{
+		ID:              "invitation-1",
+		Type:            InvitationMsgType,
+		Label:           "Alice",
+		RecipientKeys:   []string{"8HH5gYEeNc3z7PYXmd54d4x6qAfCNrqQqEB3nS7Zfu7K"},
+		ServiceEndpoint: "https://example.com/endpoint",
+	}}
+
+	longURL, err := InvitationURL("https://example.com/connect", invitation)
+	require.NoError(t, err)
+
+	invitationJSON := `{"@id":"invitation-1","@type":"` + InvitationMsgType + `","label":"Alice"}`
+
+	mux := http.NewServeMux()
+	// the long URL is not served, the invitation is taken from the redirect location
+	mux.HandleFunc("/short", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, longURL, http.StatusFound)
+	})
+	mux.HandleFunc("/shorter", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/short", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, invitationJSON)
+	})
+	mux.HandleFunc("/html", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html></html>")
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"label":"`+strings.Repeat("a", maxInvitationSize)+`"}`)
+	})
+	mux.HandleFunc("/ftp", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "ftp://example.com/invitation", http.StatusFound)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	t.Run("test short URL - redirect", func(t *testing.T) {
+		parsed, err := ParseInvitationURL(server.URL + "/short")
+		require.NoError(t, err)
+		require.Equal(t, invitation, parsed)
+	})
+
+	t.Run("test short URL - resolution disabled", func(t *testing.T) {
+		_, err := ParseInvitationURL(server.URL+"/short", WithoutShortURL())
+		require.True(t, errors.Is(err, ErrInvitationNotFound))
+	})
+
+	t.Run("test short URL - multiple redirects", func(t *testing.T) {
+		parsed, err := ParseInvitationURL(server.URL+"/shorter", WithHTTPClient(server.Client()))
+		require.NoError(t, err)
+		require.Equal(t, invitation, parsed)
+	})
+
+	t.Run("test short URL - JSON response", func(t *testing.T) {
+		parsed, err := ParseInvitationURL(server.URL + "/json")
+		require.NoError(t, err)
+		require.Equal(t, "invitation-1", parsed.ID)
+		require.Equal(t, "Alice", parsed.Label)
+	})
+
+	t.Run("test short URL - not an invitation", func(t *testing.T) {
+		_, err := ParseInvitationURL(server.URL + "/html")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal invitation")
+	})
+
+	t.Run("test short URL - not found", func(t *testing.T) {
+		_, err := ParseInvitationURL(server.URL + "/unknown")
+		require.EqualError(t, err, "fetch invitation: unexpected status code 404")
+	})
+
+	t.Run("test short URL - invitation too large", func(t *testing.T) {
+		_, err := ParseInvitationURL(server.URL + "/large")
+		require.EqualError(t, err, fmt.Sprintf("read invitation: exceeds %d bytes", maxInvitationSize))
+	})
+
+	t.Run("test short URL - unsupported scheme", func(t *testing.T) {
+		_, err := ParseInvitationURL("file:///etc/passwd")
+		require.EqualError(t, err, `fetch invitation: unsupported URL scheme "file"`)
+
+		_, err = ParseInvitationURL(server.URL + "/ftp")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `unsupported redirect URL scheme "ftp"`)
+	})
+
+	t.Run("test short URL - redirect loop", func(t *testing.T) {
+		_, err := ParseInvitationURL(server.URL + "/loop")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "stopped after 10 redirects")
+	})
+
+	t.Run("test short URL - fetch error", func(t *testing.T) {
+		_, err := ParseInvitationURL("http://localhost:0/short")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "fetch invitation")
+	})
+}
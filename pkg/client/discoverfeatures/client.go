@@ -0,0 +1,124 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/discoverfeatures"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+// ErrQueryTimeout is returned when no disclose is received within the given timeout
+var ErrQueryTimeout = discoverfeatures.ErrQueryTimeout
+
+// provider contains dependencies for the discover features protocol and is typically created by using aries.Context()
+type provider interface {
+	Service(id string) (interface{}, error)
+	StorageProvider() storage.Provider
+	TransientStorageProvider() storage.Provider
+}
+
+// Client enable access to discover features api.
+type Client struct {
+	discoverFeaturesSvc protocolService
+	connectionStore     *connection.Recorder
+}
+
+// protocolService defines Discover Features service.
+type protocolService interface {
+	// DIDComm service
+	service.Handler
+
+	// Query queries the features supported by the agent on the other end of the connection
+	Query(connectionID, query string, timeout time.Duration) ([]discoverfeatures.ProtocolDescriptor, error)
+}
+
+// New return new instance of discover features client.
+func New(ctx provider) (*Client, error) {
+	svc, err := ctx.Service(discoverfeatures.DiscoverFeatures)
+	if err != nil {
+		return nil, err
+	}
+
+	discoverFeaturesSvc, ok := svc.(protocolService)
+	if !ok {
+		return nil, errors.New("cast service to discover features service failed")
+	}
+
+	connectionStore, err := connection.NewRecorder(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("open connection store : %w", err)
+	}
+
+	return &Client{
+		discoverFeaturesSvc: discoverFeaturesSvc,
+		connectionStore:     connectionStore,
+	}, nil
+}
+
+// Query sends a query to the agent on the other end of the connection (passed in connectionID) and waits for
+// the disclose. The query is a protocol identifier which may contain the * wildcard (e.g. https://didcomm.org/*).
+// The disclosed protocols are returned and added to the features saved with the connection,
+// ErrQueryTimeout is returned if no disclose is received within the given timeout.
+func (c *Client) Query(connectionID, query string,
+	timeout time.Duration) ([]discoverfeatures.ProtocolDescriptor, error) {
+	protocols, err := c.discoverFeaturesSvc.Query(connectionID, query, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("discover features : %w", err)
+	}
+
+	if err := c.saveFeatures(connectionID, protocols); err != nil {
+		return nil, fmt.Errorf("save features : %w", err)
+	}
+
+	return protocols, nil
+}
+
+// Features returns the protocols disclosed by the agent on the other end of the connection so far.
+// storage.ErrDataNotFound is returned if the connection was never queried or has been removed.
+func (c *Client) Features(connectionID string) ([]discoverfeatures.ProtocolDescriptor, error) {
+	var protocols []discoverfeatures.ProtocolDescriptor
+
+	if err := c.connectionStore.GetConnectionFeatures(connectionID, &protocols); err != nil {
+		return nil, fmt.Errorf("get features : %w", err)
+	}
+
+	return protocols, nil
+}
+
+// saveFeatures merges the disclosed protocols with the protocols saved for the connection,
+// the disclosure of the same protocol replaces the saved one. The features are removed with the connection.
+func (c *Client) saveFeatures(connectionID string, disclosed []discoverfeatures.ProtocolDescriptor) error {
+	protocols, err := c.Features(connectionID)
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return err
+	}
+
+	for _, protocol := range disclosed {
+		protocols = mergeProtocol(protocols, protocol)
+	}
+
+	return c.connectionStore.SaveConnectionFeatures(connectionID, protocols)
+}
+
+func mergeProtocol(protocols []discoverfeatures.ProtocolDescriptor,
+	protocol discoverfeatures.ProtocolDescriptor) []discoverfeatures.ProtocolDescriptor {
+	for i := range protocols {
+		if protocols[i].PID == protocol.PID {
+			protocols[i] = protocol
+
+			return protocols
+		}
+	}
+
+	return append(protocols, protocol)
+}
@@ -0,0 +1,188 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/discoverfeatures"
+	mockdiscoverfeatures "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/discoverfeatures"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/internal/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+const (
+	trustPingPID = "https://didcomm.org/trust_ping/1.0"
+	basicMsgPID  = "https://didcomm.org/basicmessage/1.0"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test new client", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{
+			ServiceValue:                  &mockdiscoverfeatures.MockDiscoverFeaturesSvc{},
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.NoError(t, err)
+		require.NotNil(t, svc)
+	})
+
+	t.Run("test error from get service from context", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceErr: fmt.Errorf("service error")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "service error")
+	})
+
+	t.Run("test error from cast service", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceValue: nil})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cast service to discover features service failed")
+	})
+
+	t.Run("test error from open store", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{
+			ServiceValue: &mockdiscoverfeatures.MockDiscoverFeaturesSvc{},
+			StorageProviderValue: &mockstore.MockStoreProvider{
+				ErrOpenStoreHandle: errors.New("error opening the store")},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error opening the store")
+	})
+}
+
+func TestQuery(t *testing.T) {
+	t.Run("test query - disclosed protocols are cached", func(t *testing.T) {
+		disclosed := map[string][]discoverfeatures.ProtocolDescriptor{
+			trustPingPID + "/*": {{PID: trustPingPID}},
+			"*":                 {{PID: trustPingPID, Roles: []string{"sender"}}, {PID: basicMsgPID}},
+		}
+
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockdiscoverfeatures.MockDiscoverFeaturesSvc{
+				QueryFunc: func(connectionID, query string,
+					timeout time.Duration) ([]discoverfeatures.ProtocolDescriptor, error) {
+					require.Equal(t, "conn1", connectionID)
+					require.Equal(t, time.Second, timeout)
+
+					return disclosed[query], nil
+				}},
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.NoError(t, err)
+
+		_, err = c.Features("conn1")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		protocols, err := c.Query("conn1", trustPingPID+"/*", time.Second)
+		require.NoError(t, err)
+		require.Equal(t, disclosed[trustPingPID+"/*"], protocols)
+
+		features, err := c.Features("conn1")
+		require.NoError(t, err)
+		require.Equal(t, protocols, features)
+
+		// the protocols disclosed again replace the cached ones
+		protocols, err = c.Query("conn1", "*", time.Second)
+		require.NoError(t, err)
+		require.Equal(t, disclosed["*"], protocols)
+
+		features, err = c.Features("conn1")
+		require.NoError(t, err)
+		require.Equal(t, disclosed["*"], features)
+
+		// the features are cached per connection
+		_, err = c.Features("conn2")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("test query - error", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockdiscoverfeatures.MockDiscoverFeaturesSvc{
+				QueryFunc: func(connectionID, query string,
+					timeout time.Duration) ([]discoverfeatures.ProtocolDescriptor, error) {
+					return nil, ErrQueryTimeout
+				}},
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.NoError(t, err)
+
+		_, err = c.Query("conn1", "*", time.Second)
+		require.True(t, errors.Is(err, ErrQueryTimeout))
+	})
+
+	t.Run("test query - store error", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockdiscoverfeatures.MockDiscoverFeaturesSvc{},
+			StorageProviderValue: &mockstore.MockStoreProvider{Store: &mockstore.MockStore{
+				Store:  make(map[string][]byte),
+				ErrPut: errors.New("put error"),
+			}},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.NoError(t, err)
+
+		_, err = c.Query("conn1", "*", time.Second)
+		require.EqualError(t, err, "save features : put error")
+	})
+}
+
+func TestFeatures(t *testing.T) {
+	t.Run("test features - invalid cached data", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockdiscoverfeatures.MockDiscoverFeaturesSvc{},
+			StorageProviderValue: &mockstore.MockStoreProvider{Store: &mockstore.MockStore{
+				Store: map[string][]byte{"connfeatures_conn1": []byte("invalid")},
+			}},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.NoError(t, err)
+
+		_, err = c.Features("conn1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get features")
+	})
+
+	t.Run("test features - removed with the connection", func(t *testing.T) {
+		prov := &mockprovider.Provider{
+			ServiceValue: &mockdiscoverfeatures.MockDiscoverFeaturesSvc{
+				QueryFunc: func(connectionID, query string,
+					timeout time.Duration) ([]discoverfeatures.ProtocolDescriptor, error) {
+					return []discoverfeatures.ProtocolDescriptor{{PID: trustPingPID}}, nil
+				}},
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		}
+
+		c, err := New(prov)
+		require.NoError(t, err)
+
+		connections, err := connection.NewRecorder(prov)
+		require.NoError(t, err)
+		require.NoError(t, connections.SaveConnectionRecord(&connection.Record{ConnectionID: "conn1", State: "completed"}))
+
+		_, err = c.Query("conn1", "*", time.Second)
+		require.NoError(t, err)
+
+		_, err = c.Features("conn1")
+		require.NoError(t, err)
+
+		require.NoError(t, connections.RemoveConnection("conn1"))
+
+		_, err = c.Features("conn1")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+}
@@ -0,0 +1,13 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package discoverfeatures enables the agent to find out which protocols are supported by the agent on the
+// other end of a connection. A query (a protocol identifier which may contain the * wildcard, e.g.
+// https://didcomm.org/*) is sent to the other agent which discloses the matching protocols. The disclosed
+// protocols are saved with the connection and are available through the Features function until the
+// connection is removed.
+// Inbound queries are answered automatically with the protocols of the services registered in the agent.
+package discoverfeatures
@@ -0,0 +1,178 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	verifiablestore "github.com/hyperledger/aries-framework-go/pkg/store/verifiable"
+)
+
+// Provider contains dependencies for the issue credential protocol and is typically created by using aries.Context()
+type Provider interface {
+	Service(id string) (interface{}, error)
+	StorageProvider() storage.Provider
+}
+
+// Client enable access to issue credential API
+type Client struct {
+	service.Event
+	service service.DIDComm
+	vcStore *verifiablestore.Store
+	newUUID func() string
+}
+
+// New return new instance of issue credential client
+func New(ctx Provider) (*Client, error) {
+	svc, err := ctx.Service(issuecredential.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	issueCredentialSvc, ok := svc.(service.DIDComm)
+	if !ok {
+		return nil, errors.New("cast service to Issue Credential Service failed")
+	}
+
+	vcStore, err := verifiablestore.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		Event:   issueCredentialSvc,
+		service: issueCredentialSvc,
+		vcStore: vcStore,
+		newUUID: func() string { return uuid.New().String() },
+	}, nil
+}
+
+// SendProposal sends a proposal to the Issuer (the client is the potential Holder).
+func (c *Client) SendProposal(msg *issuecredential.ProposeCredential, myDID, theirDID string) error {
+	msg.Type = issuecredential.ProposeCredentialMsgType
+
+	// a new thread is started by the message
+	if msg.ID == "" {
+		msg.ID = c.newUUID()
+	}
+
+	return c.handleOutbound(msg, myDID, theirDID)
+}
+
+// SendOffer sends an offer to the potential Holder (the client is the Issuer).
+func (c *Client) SendOffer(msg *issuecredential.OfferCredential, myDID, theirDID string) error {
+	msg.Type = issuecredential.OfferCredentialMsgType
+
+	// a new thread is started by the message
+	if msg.ID == "" {
+		msg.ID = c.newUUID()
+	}
+
+	return c.handleOutbound(msg, myDID, theirDID)
+}
+
+// SendRequest sends a request to the Issuer (the client is the potential Holder).
+func (c *Client) SendRequest(msg *issuecredential.RequestCredential, myDID, theirDID string) error {
+	msg.Type = issuecredential.RequestCredentialMsgType
+
+	// a new thread is started by the message
+	if msg.ID == "" {
+		msg.ID = c.newUUID()
+	}
+
+	return c.handleOutbound(msg, myDID, theirDID)
+}
+
+// SaveCredentials is a helper function that saves the credentials attached to the issue-credential message into
+// the verifiable credential store. It should be executed after receiving an IssueCredential action message and
+// before accepting it, opts are used to decode the credentials (e.g verifiable.WithPublicKeyFetcher).
+// usage:
+//  if event.Message.Type() == issuecredential.IssueCredentialMsgType {
+//    if err := client.SaveCredentials(event.Message); err != nil {
+//      event.Stop(err)
+//      continue
+//    }
+//  }
+//  event.Continue(nil)
+func (c *Client) SaveCredentials(msg service.DIDCommMsg, opts ...verifiable.CredentialOpt) error {
+	if msg.Type() != issuecredential.IssueCredentialMsgType {
+		return fmt.Errorf("unexpected message type: %s", msg.Type())
+	}
+
+	issueCredential := issuecredential.IssueCredential{}
+
+	if err := msg.Decode(&issueCredential); err != nil {
+		return fmt.Errorf("decode issue credential: %w", err)
+	}
+
+	var credentials []*verifiable.Credential
+
+	// all the credentials are decoded before saving any of them
+	for i, attachment := range issueCredential.CredentialsAttach {
+		data, err := attachmentData(attachment.Data)
+		if err != nil {
+			return fmt.Errorf("credential attachment %d: %w", i, err)
+		}
+
+		vc, _, err := verifiable.NewCredential(data, opts...)
+		if err != nil {
+			return fmt.Errorf("credential attachment %d: %w", i, err)
+		}
+
+		credentials = append(credentials, vc)
+	}
+
+	for _, vc := range credentials {
+		if err := c.vcStore.SaveVC(vc); err != nil {
+			return fmt.Errorf("save credential: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// attachmentData returns the payload of the attachment, JSON attachments holding a string (e.g JWT) are not
+// marshalled
+func attachmentData(data decorator.AttachmentData) ([]byte, error) {
+	if data.JSON != nil {
+		if s, ok := data.JSON.(string); ok {
+			return []byte(s), nil
+		}
+
+		return json.Marshal(data.JSON)
+	}
+
+	if data.Base64 != "" {
+		return base64.StdEncoding.DecodeString(data.Base64)
+	}
+
+	return nil, errors.New("no inline data")
+}
+
+func (c *Client) handleOutbound(msg interface{}, myDID, theirDID string) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal outbound msg: %w", err)
+	}
+
+	didMsg, err := service.ParseDIDCommMsgMap(payload)
+	if err != nil {
+		return fmt.Errorf("new outbound DIDCommMsg msg: %w", err)
+	}
+
+	return c.service.HandleOutbound(didMsg, myDID, theirDID)
+}
@@ -0,0 +1,186 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/client/issuecredential"
+	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
+	storageMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+const (
+	Alice = "Alice"
+	Bob   = "Bob"
+)
+
+const vcJSON = `{
+  "@context": ["https://www.w3.org/2018/credentials/v1"],
+  "id": "http://example.edu/credentials/1872",
+  "type": "VerifiableCredential",
+  "credentialSubject": {"id": "did:example:ebfeb1f712ebc6f1c276e12ec21"},
+  "issuer": "did:example:76e12ec712ebc6f1c221ebfeb1f",
+  "issuanceDate": "2010-01-01T19:23:24Z"
+}`
+
+func TestNew(t *testing.T) {
+	const errMsg = "test err"
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("get service error", func(t *testing.T) {
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().Service(issuecredential.Name).Return(nil, errors.New(errMsg))
+		_, err := New(provider)
+		require.EqualError(t, err, errMsg)
+	})
+
+	t.Run("cast service error", func(t *testing.T) {
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().Service(issuecredential.Name).Return(nil, nil)
+		_, err := New(provider)
+		require.EqualError(t, err, "cast service to Issue Credential Service failed")
+	})
+
+	t.Run("open store error", func(t *testing.T) {
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().Service(issuecredential.Name).Return(serviceMocks.NewMockDIDComm(ctrl), nil)
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(nil, errors.New(errMsg))
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		_, err := New(provider)
+		require.Contains(t, err.Error(), errMsg)
+	})
+}
+
+func TestClient_Send(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := serviceMocks.NewMockDIDComm(ctrl)
+
+	client := newClient(t, ctrl, svc)
+
+	expectOutbound := func(msgType string) {
+		svc.EXPECT().HandleOutbound(gomock.Any(), Alice, Bob).
+			DoAndReturn(func(msg service.DIDCommMsg, _, _ string) error {
+				require.Equal(t, msgType, msg.Type())
+				require.NotEmpty(t, msg.ID())
+
+				return nil
+			})
+	}
+
+	expectOutbound(issuecredential.ProposeCredentialMsgType)
+	require.NoError(t, client.SendProposal(&issuecredential.ProposeCredential{}, Alice, Bob))
+
+	expectOutbound(issuecredential.OfferCredentialMsgType)
+	require.NoError(t, client.SendOffer(&issuecredential.OfferCredential{}, Alice, Bob))
+
+	expectOutbound(issuecredential.RequestCredentialMsgType)
+	require.NoError(t, client.SendRequest(&issuecredential.RequestCredential{}, Alice, Bob))
+
+	svc.EXPECT().HandleOutbound(gomock.Any(), Alice, Bob).Return(errors.New("test err"))
+	require.EqualError(t, client.SendRequest(&issuecredential.RequestCredential{}, Alice, Bob), "test err")
+}
+
+func TestClient_handleOutbound(t *testing.T) {
+	c := &Client{}
+	err := c.handleOutbound(make(chan int), Alice, Bob)
+	require.EqualError(t, err, "marshal outbound msg: json: unsupported type: chan int")
+}
+
+func TestClient_SaveCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	issueCredential := func(attachments ...decorator.AttachmentData) service.DIDCommMsg {
+		msg := &issuecredential.IssueCredential{Type: issuecredential.IssueCredentialMsgType, ID: "ID"}
+
+		for _, data := range attachments {
+			msg.CredentialsAttach = append(msg.CredentialsAttach, decorator.Attachment{Data: data})
+		}
+
+		return service.NewDIDCommMsgMap(msg)
+	}
+
+	var vcObject interface{}
+	require.NoError(t, json.Unmarshal([]byte(vcJSON), &vcObject))
+
+	t.Run("save credentials", func(t *testing.T) {
+		client := newClient(t, ctrl, serviceMocks.NewMockDIDComm(ctrl))
+
+		require.NoError(t, client.SaveCredentials(issueCredential(
+			decorator.AttachmentData{JSON: vcObject},
+			decorator.AttachmentData{Base64: base64.StdEncoding.EncodeToString([]byte(vcJSON))},
+		)))
+
+		vc, err := client.vcStore.GetVC("http://example.edu/credentials/1872")
+		require.NoError(t, err)
+		require.Equal(t, "did:example:76e12ec712ebc6f1c221ebfeb1f", vc.Issuer.ID)
+	})
+
+	t.Run("unexpected message type", func(t *testing.T) {
+		client := newClient(t, ctrl, serviceMocks.NewMockDIDComm(ctrl))
+
+		err := client.SaveCredentials(service.NewDIDCommMsgMap(&issuecredential.OfferCredential{
+			Type: issuecredential.OfferCredentialMsgType,
+		}))
+		require.EqualError(t, err, "unexpected message type: "+issuecredential.OfferCredentialMsgType)
+	})
+
+	t.Run("no inline data", func(t *testing.T) {
+		client := newClient(t, ctrl, serviceMocks.NewMockDIDComm(ctrl))
+
+		err := client.SaveCredentials(issueCredential(decorator.AttachmentData{Links: []string{"http://example.edu"}}))
+		require.EqualError(t, err, "credential attachment 0: no inline data")
+	})
+
+	t.Run("invalid base64 data", func(t *testing.T) {
+		client := newClient(t, ctrl, serviceMocks.NewMockDIDComm(ctrl))
+
+		err := client.SaveCredentials(issueCredential(decorator.AttachmentData{Base64: "@"}))
+		require.Contains(t, err.Error(), "credential attachment 0: illegal base64 data")
+	})
+
+	t.Run("invalid credential", func(t *testing.T) {
+		client := newClient(t, ctrl, serviceMocks.NewMockDIDComm(ctrl))
+
+		err := client.SaveCredentials(issueCredential(
+			decorator.AttachmentData{JSON: vcObject},
+			decorator.AttachmentData{JSON: map[string]interface{}{"id": "invalid"}},
+		))
+		require.Contains(t, err.Error(), "credential attachment 1:")
+
+		// no credential is saved
+		_, err = client.vcStore.GetVC("http://example.edu/credentials/1872")
+		require.Error(t, err)
+	})
+}
+
+func newClient(t *testing.T, ctrl *gomock.Controller, svc service.DIDComm) *Client {
+	provider := mocks.NewMockProvider(ctrl)
+	provider.EXPECT().Service(issuecredential.Name).Return(svc, nil)
+	provider.EXPECT().StorageProvider().Return(mem.NewProvider())
+
+	client, err := New(provider)
+	require.NoError(t, err)
+
+	return client
+}
@@ -0,0 +1,40 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package issuecredential is responsible for the credential issuance between agents.
+// The protocol involves two participants: the Issuer and the Holder. The Holder may start the protocol by sending
+// a proposal (SendProposal) or a request (SendRequest), the Issuer may start it by sending an offer (SendOffer).
+// Incoming messages are handled through action events. The reply to the incoming message is provided
+// to the Continue function:
+// - ProposeCredential (Issuer) - *issuecredential.OfferCredential is required
+// - OfferCredential (Holder) - *issuecredential.RequestCredential is optional
+// - RequestCredential (Issuer) - *issuecredential.IssueCredential is required
+// - IssueCredential (Holder) - nothing, an ack is sent to the Issuer
+// The Stop function abandons the protocol, a problem-report is sent to the other participant.
+// The simplest way to handle incoming messages (actions) on the Holder side is:
+// 	client := issuecredential.New(...)
+// 	client.RegisterActionEvent(actions)
+// 	for {
+// 	  select {
+// 	    case event := <-actions:
+// 	      if event.Message.Type() == issuecredential.IssueCredentialMsgType {
+// 	        if err := client.SaveCredentials(event.Message); err != nil {
+// 	          event.Stop(err)
+// 	          continue
+// 	        }
+// 	      }
+// 	      event.Continue(nil)
+// 	  }
+// 	}
+//
+//  Basic Flow:
+//  1) Prepare client context
+//  2) Create client
+//  3) Register for action events
+//  4) Handle actions
+//  5) Send proposal, offer or request
+//
+package issuecredential
@@ -0,0 +1,90 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package messagepickup
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/messagepickup"
+)
+
+// provider contains dependencies for the message pickup protocol and is typically created by using aries.Context()
+type provider interface {
+	Service(id string) (interface{}, error)
+}
+
+// Client enable access to message pickup api.
+type Client struct {
+	messagePickupSvc protocolService
+}
+
+// protocolService defines Message Pickup service.
+type protocolService interface {
+	// DIDComm service
+	service.Handler
+
+	// StatusRequest requests the status of the queued messages from the router
+	StatusRequest(connectionID string) (*messagepickup.Status, error)
+
+	// BatchPickup picks up a batch of the queued messages from the router
+	BatchPickup(connectionID string, size int) (int, error)
+
+	// Noop requests the router to deliver the queued messages
+	Noop(connectionID string) error
+}
+
+// New return new instance of message pickup client.
+func New(ctx provider) (*Client, error) {
+	svc, err := ctx.Service(messagepickup.MessagePickup)
+	if err != nil {
+		return nil, err
+	}
+
+	messagePickupSvc, ok := svc.(protocolService)
+	if !ok {
+		return nil, errors.New("cast service to message pickup service failed")
+	}
+
+	return &Client{
+		messagePickupSvc: messagePickupSvc,
+	}, nil
+}
+
+// StatusRequest returns the status of the messages queued by the router on the other end of the connection
+// (passed in connectionID).
+func (c *Client) StatusRequest(connectionID string) (*messagepickup.Status, error) {
+	status, err := c.messagePickupSvc.StatusRequest(connectionID)
+	if err != nil {
+		return nil, fmt.Errorf("message pickup status request : %w", err)
+	}
+
+	return status, nil
+}
+
+// BatchPickup picks up a batch of at most size messages queued by the router on the other end of the
+// connection (passed in connectionID). The messages are processed by the agent, the number of messages
+// in the batch is returned.
+func (c *Client) BatchPickup(connectionID string, size int) (int, error) {
+	count, err := c.messagePickupSvc.BatchPickup(connectionID, size)
+	if err != nil {
+		return 0, fmt.Errorf("message pickup batch pickup : %w", err)
+	}
+
+	return count, nil
+}
+
+// Noop requests the router on the other end of the connection (passed in connectionID) to deliver the queued
+// messages over the open connection. The outbound transport must keep the connection open (e.g. websocket).
+func (c *Client) Noop(connectionID string) error {
+	if err := c.messagePickupSvc.Noop(connectionID); err != nil {
+		return fmt.Errorf("message pickup noop : %w", err)
+	}
+
+	return nil
+}
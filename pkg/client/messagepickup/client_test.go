@@ -0,0 +1,111 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package messagepickup
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/messagepickup"
+	mockmessagepickup "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/messagepickup"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test new client", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{
+			ServiceValue: &mockmessagepickup.MockMessagePickupSvc{}},
+		)
+		require.NoError(t, err)
+		require.NotNil(t, svc)
+	})
+
+	t.Run("test error from get service from context", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceErr: fmt.Errorf("service error")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "service error")
+	})
+
+	t.Run("test error from cast service", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceValue: nil})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cast service to message pickup service failed")
+	})
+}
+
+func TestStatusRequest(t *testing.T) {
+	t.Run("test status request - success", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockmessagepickup.MockMessagePickupSvc{
+				StatusRequestFunc: func(connectionID string) (*messagepickup.Status, error) {
+					require.Equal(t, "conn1", connectionID)
+
+					return &messagepickup.Status{MessageCount: 3}, nil
+				}}})
+		require.NoError(t, err)
+
+		status, err := c.StatusRequest("conn1")
+		require.NoError(t, err)
+		require.Equal(t, 3, status.MessageCount)
+	})
+
+	t.Run("test status request - error", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockmessagepickup.MockMessagePickupSvc{
+				StatusRequestFunc: func(connectionID string) (*messagepickup.Status, error) {
+					return nil, errors.New("status error")
+				}}})
+		require.NoError(t, err)
+
+		_, err = c.StatusRequest("conn1")
+		require.EqualError(t, err, "message pickup status request : status error")
+	})
+}
+
+func TestBatchPickup(t *testing.T) {
+	t.Run("test batch pickup - success", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockmessagepickup.MockMessagePickupSvc{
+				BatchPickupFunc: func(connectionID string, size int) (int, error) {
+					require.Equal(t, "conn1", connectionID)
+					require.Equal(t, 10, size)
+
+					return 2, nil
+				}}})
+		require.NoError(t, err)
+
+		count, err := c.BatchPickup("conn1", 10)
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+	})
+
+	t.Run("test batch pickup - error", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockmessagepickup.MockMessagePickupSvc{
+				BatchPickupFunc: func(connectionID string, size int) (int, error) {
+					return 0, errors.New("batch error")
+				}}})
+		require.NoError(t, err)
+
+		_, err = c.BatchPickup("conn1", 10)
+		require.EqualError(t, err, "message pickup batch pickup : batch error")
+	})
+}
+
+func TestNoop(t *testing.T) {
+	c, err := New(&mockprovider.Provider{ServiceValue: &mockmessagepickup.MockMessagePickupSvc{}})
+	require.NoError(t, err)
+	require.NoError(t, c.Noop("conn1"))
+
+	c, err = New(&mockprovider.Provider{
+		ServiceValue: &mockmessagepickup.MockMessagePickupSvc{NoopErr: errors.New("noop error")}})
+	require.NoError(t, err)
+	require.EqualError(t, c.Noop("conn1"), "message pickup noop : noop error")
+}
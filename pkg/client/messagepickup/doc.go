@@ -0,0 +1,13 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package messagepickup enables the agent to retrieve the messages queued by the router while the agent
+// was offline. The router queues the forwarded messages which cannot be delivered to the recipient.
+// When the agent reconnects, it checks the queue through the status request and either picks up a batch
+// of messages (BatchPickup) or sends a noop with the return route option (Noop) so the router delivers
+// the queued messages over the open connection. The retrieved messages are processed as any other
+// inbound message.
+package messagepickup
@@ -0,0 +1,245 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outofband
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/route"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/common/invitationurl"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+)
+
+// invitationParam is the query parameter of the invitation URL holding the base64url encoded invitation.
+const invitationParam = "oob"
+
+// ErrInvitationNotFound is returned when the URL has no invitation.
+var ErrInvitationNotFound = invitationurl.ErrInvitationNotFound
+
+// provider contains dependencies for the out-of-band protocol and is typically created by using aries.Context()
+type provider interface {
+	Service(id string) (interface{}, error)
+	LegacyKMS() legacykms.KeyManager
+	InboundTransportEndpoint() string
+}
+
+// protocolService is the out-of-band protocol service.
+type protocolService interface {
+	service.DIDComm
+	SaveInvitation(invitation *outofband.Invitation) error
+	SaveInvitationRouter(invitationID, routerConnectionID string) error
+	AcceptInvitation(invitation *outofband.Invitation) (string, error)
+}
+
+// Client enables access to the out-of-band protocol API.
+type Client struct {
+	service.Event
+	outOfBandSvc             protocolService
+	routeSvc                 route.ProtocolService
+	legacyKMS                legacykms.KeyManager
+	inboundTransportEndpoint string
+}
+
+// New returns new instance of the out-of-band client.
+func New(ctx provider) (*Client, error) {
+	svc, err := ctx.Service(outofband.OutOfBand)
+	if err != nil {
+		return nil, err
+	}
+
+	outOfBandSvc, ok := svc.(protocolService)
+	if !ok {
+		return nil, errors.New("cast service to OutOfBand Service failed")
+	}
+
+	s, err := ctx.Service(route.Coordination)
+	if err != nil {
+		return nil, err
+	}
+
+	routeSvc, ok := s.(route.ProtocolService)
+	if !ok {
+		return nil, errors.New("cast service to Route Service failed")
+	}
+
+	return &Client{
+		Event:                    outOfBandSvc,
+		outOfBandSvc:             outOfBandSvc,
+		routeSvc:                 routeSvc,
+		legacyKMS:                ctx.LegacyKMS(),
+		inboundTransportEndpoint: ctx.InboundTransportEndpoint(),
+	}, nil
+}
+
+// InvitationOpt is the invitation option.
+type InvitationOpt func(opts *invitationOpts)
+
+type invitationOpts struct {
+	goal               string
+	goalCode           string
+	requests           []*decorator.Attachment
+	services           []interface{}
+	routerConnectionID string
+}
+
+// WithGoal sets the human readable goal and the machine readable goal code of the invitation.
+func WithGoal(goal, goalCode string) InvitationOpt {
+	return func(opts *invitationOpts) {
+		opts.goal = goal
+		opts.goalCode = goalCode
+	}
+}
+
+// WithAttachments attaches the requests (e.g. a credential offer) to the invitation, the requests are handled
+// by the recipient once the connection is established.
+func WithAttachments(requests ...*decorator.Attachment) InvitationOpt {
+	return func(opts *invitationOpts) {
+		opts.requests = append(opts.requests, requests...)
+	}
+}
+
+// WithServices sets the service entries of the invitation, an entry is either a DID (string) or
+// an *outofband.InlineService. An inline service with the new key is created if the option is not provided.
+func WithServices(services ...interface{}) InvitationOpt {
+	return func(opts *invitationOpts) {
+		opts.services = append(opts.services, services...)
+	}
+}
+
+// WithRouterConnectionID sets the connection ID of the router the invitation keys are advertised through.
+// The default router is used if the option is not provided.
+func WithRouterConnectionID(connectionID string) InvitationOpt {
+	return func(opts *invitationOpts) {
+		opts.routerConnectionID = connectionID
+	}
+}
+
+// CreateInvitation creates an out-of-band invitation with the DID exchange handshake protocol. The invitation
+// is stored so the client can cross reference it during the DID exchange protocol.
+func (c *Client) CreateInvitation(label string, opts ...InvitationOpt) (*outofband.Invitation, error) {
+	invOpts := &invitationOpts{}
+
+	for _, opt := range opts {
+		opt(invOpts)
+	}
+
+	invitation := &outofband.Invitation{
+		ID:        uuid.New().String(),
+		Type:      outofband.InvitationMsgType,
+		Label:     label,
+		Goal:      invOpts.goal,
+		GoalCode:  invOpts.goalCode,
+		Protocols: []string{didexchange.DIDExchangeSpec},
+		Requests:  invOpts.requests,
+		Service:   invOpts.services,
+	}
+
+	if len(invitation.Service) == 0 {
+		inline, err := c.inlineService(invOpts.routerConnectionID)
+		if err != nil {
+			return nil, err
+		}
+
+		invitation.Service = []interface{}{inline}
+	}
+
+	if err := c.outOfBandSvc.SaveInvitation(invitation); err != nil {
+		return nil, fmt.Errorf("failed to save invitation: %w", err)
+	}
+
+	// the keys of the connection established from the invitation use the same router
+	if invOpts.routerConnectionID != "" {
+		if err := c.outOfBandSvc.SaveInvitationRouter(invitation.ID, invOpts.routerConnectionID); err != nil {
+			return nil, fmt.Errorf("failed to save invitation router: %w", err)
+		}
+	}
+
+	return invitation, nil
+}
+
+func (c *Client) inlineService(routerConnectionID string) (*outofband.InlineService, error) {
+	_, sigPubKey, err := c.legacyKMS.CreateKeySet()
+	if err != nil {
+		return nil, fmt.Errorf("failed CreateSigningKey: %w", err)
+	}
+
+	// get the route configs
+	serviceEndpoint, routingKeys, err := route.GetRouterConfig(c.routeSvc, routerConnectionID,
+		c.inboundTransportEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("create invitation - fetch router config : %w", err)
+	}
+
+	if err = route.AddKeyToRouter(c.routeSvc, routerConnectionID, sigPubKey); err != nil {
+		return nil, fmt.Errorf("create invitation - add key to the router : %w", err)
+	}
+
+	return &outofband.InlineService{
+		ID:              "#inline",
+		Type:            outofband.DIDCommServiceType,
+		RecipientKeys:   []string{sigPubKey},
+		RoutingKeys:     routingKeys,
+		ServiceEndpoint: serviceEndpoint,
+	}, nil
+}
+
+// AcceptInvitation accepts the invitation and returns the connectionID that can be used to query the state
+// of the DID exchange protocol. The requests attached to the invitation are handled once the connection
+// is established.
+func (c *Client) AcceptInvitation(invitation *outofband.Invitation) (string, error) {
+	connectionID, err := c.outOfBandSvc.AcceptInvitation(invitation)
+	if err != nil {
+		return "", fmt.Errorf("out-of-band service accept invitation: %w", err)
+	}
+
+	return connectionID, nil
+}
+
+// SendInvitation sends the invitation over the established connection.
+func (c *Client) SendInvitation(invitation *outofband.Invitation, myDID, theirDID string) error {
+	invitation.Type = outofband.InvitationMsgType
+
+	if invitation.ID == "" {
+		invitation.ID = uuid.New().String()
+	}
+
+	return c.outOfBandSvc.HandleOutbound(service.NewDIDCommMsgMap(invitation), myDID, theirDID)
+}
+
+// InvitationURL renders the invitation as the URL with the base64url encoded invitation in the `oob` parameter,
+// e.g. https://example.com/path?oob=eyJAdHlwZSI6...
+func InvitationURL(baseURL string, invitation *outofband.Invitation) (string, error) {
+	return invitationurl.Encode(baseURL, invitationParam, invitation)
+}
+
+// ParseInvitationURL parses the invitation from the `oob` parameter of the URL (refer InvitationURL).
+func ParseInvitationURL(invitationURL string) (*outofband.Invitation, error) {
+	u, err := url.Parse(invitationURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse invitation URL: %w", err)
+	}
+
+	invitation := &outofband.Invitation{}
+
+	if err := invitationurl.Decode(u, invitationParam, invitation); err != nil {
+		return nil, err
+	}
+
+	if invitation.Type != outofband.InvitationMsgType {
+		return nil, fmt.Errorf("unexpected message type: %s", invitation.Type)
+	}
+
+	return invitation, nil
+}
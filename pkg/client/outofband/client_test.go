@@ -0,0 +1,301 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outofband
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/route"
+	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/dispatcher"
+	mockdidexchange "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/didexchange"
+	mockroute "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/route"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/internal/mock/kms/legacykms"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test new - success", func(t *testing.T) {
+		c, err := New(newProvider(t, &mockdidexchange.MockDIDExchangeSvc{}, &mockroute.MockRouteSvc{}))
+		require.NoError(t, err)
+		require.NotNil(t, c)
+	})
+
+	t.Run("test new - out-of-band service error", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceErr: errors.New("service error")})
+		require.EqualError(t, err, "service error")
+	})
+
+	t.Run("test new - cast out-of-band service error", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceValue: struct{}{}})
+		require.EqualError(t, err, "cast service to OutOfBand Service failed")
+	})
+
+	t.Run("test new - cast route service error", func(t *testing.T) {
+		prov := newProvider(t, &mockdidexchange.MockDIDExchangeSvc{}, &mockroute.MockRouteSvc{})
+		prov.ServiceMap[route.Coordination] = struct{}{}
+
+		_, err := New(prov)
+		require.EqualError(t, err, "cast service to Route Service failed")
+	})
+}
+
+func TestClient_CreateInvitation(t *testing.T) {
+	t.Run("test create invitation - inline service", func(t *testing.T) {
+		prov := newProvider(t, &mockdidexchange.MockDIDExchangeSvc{}, &mockroute.MockRouteSvc{})
+
+		c, err := New(prov)
+		require.NoError(t, err)
+
+		attachment := &decorator.Attachment{ID: "request-1"}
+
+		invitation, err := c.CreateInvitation("Alice", WithGoal("issue a credential", "issue-vc"),
+			WithAttachments(attachment))
+		require.NoError(t, err)
+		require.NotEmpty(t, invitation.ID)
+		require.Equal(t, outofband.InvitationMsgType, invitation.Type)
+		require.Equal(t, "Alice", invitation.Label)
+		require.Equal(t, "issue a credential", invitation.Goal)
+		require.Equal(t, "issue-vc", invitation.GoalCode)
+		require.Equal(t, []string{didexchange.DIDExchangeSpec}, invitation.Protocols)
+		require.Equal(t, []*decorator.Attachment{attachment}, invitation.Requests)
+		require.Len(t, invitation.Service, 1)
+
+		inline, ok := invitation.Service[0].(*outofband.InlineService)
+		require.True(t, ok)
+		require.Equal(t, outofband.DIDCommServiceType, inline.Type)
+		require.Equal(t, []string{"sample-key"}, inline.RecipientKeys)
+		require.Equal(t, "http://alice.agent.example.com:8081", inline.ServiceEndpoint)
+
+		// the invitation is saved for the DID exchange
+		connections, err := connection.NewLookup(prov)
+		require.NoError(t, err)
+
+		saved := &didexchange.Invitation{}
+		require.NoError(t, connections.GetInvitation(invitation.ID, saved))
+		require.Equal(t, []string{"sample-key"}, saved.RecipientKeys)
+	})
+
+	t.Run("test create invitation - router", func(t *testing.T) {
+		prov := newProvider(t, &mockdidexchange.MockDIDExchangeSvc{}, &mockroute.MockRouteSvc{
+			RouterEndpoint: "http://router.example.com",
+			RoutingKeys:    []string{"routing-key"},
+		})
+
+		c, err := New(prov)
+		require.NoError(t, err)
+
+		invitation, err := c.CreateInvitation("Alice", WithRouterConnectionID("router-1"))
+		require.NoError(t, err)
+
+		inline, ok := invitation.Service[0].(*outofband.InlineService)
+		require.True(t, ok)
+		require.Equal(t, "http://router.example.com", inline.ServiceEndpoint)
+		require.Equal(t, []string{"routing-key"}, inline.RoutingKeys)
+
+		// the router is saved for the connection established from the invitation
+		connections, err := connection.NewLookup(prov)
+		require.NoError(t, err)
+
+		routerConnID, err := connections.GetInvitationRouter(invitation.ID)
+		require.NoError(t, err)
+		require.Equal(t, "router-1", routerConnID)
+	})
+
+	t.Run("test create invitation - DID service", func(t *testing.T) {
+		c, err := New(newProvider(t, &mockdidexchange.MockDIDExchangeSvc{}, &mockroute.MockRouteSvc{}))
+		require.NoError(t, err)
+
+		invitation, err := c.CreateInvitation("Alice", WithServices("did:example:123"))
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"did:example:123"}, invitation.Service)
+	})
+
+	t.Run("test create invitation - create key error", func(t *testing.T) {
+		prov := newProvider(t, &mockdidexchange.MockDIDExchangeSvc{}, &mockroute.MockRouteSvc{})
+		prov.KMSValue = &mockkms.CloseableKMS{CreateKeyErr: errors.New("key error")}
+
+		c, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = c.CreateInvitation("Alice")
+		require.EqualError(t, err, "failed CreateSigningKey: key error")
+	})
+
+	t.Run("test create invitation - router config error", func(t *testing.T) {
+		c, err := New(newProvider(t, &mockdidexchange.MockDIDExchangeSvc{}, &mockroute.MockRouteSvc{
+			ConfigErr: errors.New("config error"),
+		}))
+		require.NoError(t, err)
+
+		_, err = c.CreateInvitation("Alice")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create invitation - fetch router config")
+	})
+
+	t.Run("test create invitation - add key error", func(t *testing.T) {
+		c, err := New(newProvider(t, &mockdidexchange.MockDIDExchangeSvc{}, &mockroute.MockRouteSvc{
+			AddKeyErr: errors.New("add key error"),
+		}))
+		require.NoError(t, err)
+
+		_, err = c.CreateInvitation("Alice")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create invitation - add key to the router")
+	})
+
+	t.Run("test create invitation - save error", func(t *testing.T) {
+		c, err := New(newProvider(t, &mockdidexchange.MockDIDExchangeSvc{}, &mockroute.MockRouteSvc{}))
+		require.NoError(t, err)
+
+		_, err = c.CreateInvitation("Alice", WithServices(&outofband.InlineService{Type: "other-type"}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to save invitation")
+	})
+}
+
+func TestClient_AcceptInvitation(t *testing.T) {
+	t.Run("test accept invitation - success", func(t *testing.T) {
+		c, err := New(newProvider(t, &mockdidexchange.MockDIDExchangeSvc{
+			HandleFunc: func(msg service.DIDCommMsg) (string, error) {
+				return "connection-1", nil
+			},
+		}, &mockroute.MockRouteSvc{}))
+		require.NoError(t, err)
+
+		invitation, err := c.CreateInvitation("Alice")
+		require.NoError(t, err)
+
+		connectionID, err := c.AcceptInvitation(invitation)
+		require.NoError(t, err)
+		require.Equal(t, "connection-1", connectionID)
+	})
+
+	t.Run("test accept invitation - error", func(t *testing.T) {
+		c, err := New(newProvider(t, &mockdidexchange.MockDIDExchangeSvc{}, &mockroute.MockRouteSvc{}))
+		require.NoError(t, err)
+
+		_, err = c.AcceptInvitation(&outofband.Invitation{})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, outofband.ErrNoHandshakeProtocols))
+	})
+}
+
+func TestClient_SendInvitation(t *testing.T) {
+	prov := newProvider(t, &mockdidexchange.MockDIDExchangeSvc{}, &mockroute.MockRouteSvc{})
+	prov.OutboundDispatcherValue = &mockdispatcher.MockOutbound{
+		ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+			require.Equal(t, outofband.InvitationMsgType, msg.(service.DIDCommMsgMap).Type())
+			require.NotEmpty(t, msg.(service.DIDCommMsgMap).ID())
+			require.Equal(t, "did:example:mine", myDID)
+			require.Equal(t, "did:example:theirs", theirDID)
+
+			return nil
+		},
+	}
+
+	c, err := New(prov)
+	require.NoError(t, err)
+
+	require.NoError(t, c.SendInvitation(&outofband.Invitation{Label: "Alice"}, "did:example:mine", "did:example:theirs"))
+}
+
+func TestInvitationURL(t *testing.T) {
+	invitation := &outofband.Invitation{
+		ID:        "invitation-1",
+		Type:      outofband.InvitationMsgType,
+		Label:     "Alice",
+		Protocols: []string{didexchange.DIDExchangeSpec},
+		Service:   []interface{}{"did:example:123"},
+	}
+
+	t.Run("test invitation URL - encode and parse", func(t *testing.T) {
+		u, err := InvitationURL("https://example.com/path?name=value", invitation)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(u, "https://example.com/path?"))
+		require.Contains(t, u, "name=value")
+		require.Contains(t, u, "oob=")
+
+		parsed, err := ParseInvitationURL(u)
+		require.NoError(t, err)
+		require.Equal(t, invitation, parsed)
+	})
+
+	t.Run("test invitation URL - parse without padding", func(t *testing.T) {
+		encoded := base64.RawURLEncoding.EncodeToString([]byte(
+			`{"@id":"invitation-1","@type":"` + outofband.InvitationMsgType + `","service":["did:example:123"]}`))
+
+		parsed, err := ParseInvitationURL("https://example.com?oob=" + encoded)
+		require.NoError(t, err)
+		require.Equal(t, "invitation-1", parsed.ID)
+		require.Equal(t, []interface{}{"did:example:123"}, parsed.Service)
+	})
+
+	t.Run("test invitation URL - invalid base URL", func(t *testing.T) {
+		_, err := InvitationURL("%", invitation)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse base URL")
+	})
+
+	t.Run("test invitation URL - marshal error", func(t *testing.T) {
+		_, err := InvitationURL("https://example.com", &outofband.Invitation{Service: []interface{}{make(chan int)}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "marshal invitation")
+	})
+
+	t.Run("test parse invitation URL - errors", func(t *testing.T) {
+		_, err := ParseInvitationURL("%")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse invitation URL")
+
+		_, err = ParseInvitationURL("https://example.com?c_i=abc")
+		require.Equal(t, ErrInvitationNotFound, err)
+
+		_, err = ParseInvitationURL("https://example.com?oob=@@@")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode invitation")
+
+		_, err = ParseInvitationURL("https://example.com?oob=" + base64.URLEncoding.EncodeToString([]byte("invalid")))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal invitation")
+
+		_, err = ParseInvitationURL("https://example.com?oob=" + base64.URLEncoding.EncodeToString(
+			[]byte(`{"@type":"`+didexchange.InvitationMsgType+`"}`)))
+		require.EqualError(t, err, "unexpected message type: "+didexchange.InvitationMsgType)
+	})
+}
+
+func newProvider(t *testing.T, didexchangeSvc *mockdidexchange.MockDIDExchangeSvc,
+	routeSvc *mockroute.MockRouteSvc) *mockprovider.Provider {
+	prov := &mockprovider.Provider{
+		ServiceMap:                    map[string]interface{}{},
+		StorageProviderValue:          mem.NewProvider(),
+		TransientStorageProviderValue: mem.NewProvider(),
+		OutboundDispatcherValue:       &mockdispatcher.MockOutbound{},
+		KMSValue:                      &mockkms.CloseableKMS{CreateSigningKeyValue: "sample-key"},
+		InboundEndpointValue:          "http://alice.agent.example.com:8081",
+		ServiceValue:                  didexchangeSvc,
+	}
+
+	svc, err := outofband.New(prov)
+	require.NoError(t, err)
+
+	prov.ServiceMap[outofband.OutOfBand] = svc
+	prov.ServiceMap[route.Coordination] = routeSvc
+
+	return prov
+}
@@ -0,0 +1,19 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package outofband enables the agent to create and accept out-of-band invitations. A single invitation
+// can bootstrap the connection through the DID Exchange and carry the requests (e.g. a credential offer)
+// the recipient handles once the connection is established. The invitation has one or more services,
+// each one is either a DID or an inline service block, and can be shared as the URL with the `oob` parameter.
+//
+//  Basic Flow:
+//  1) Prepare client context
+//  2) Create client
+//  3) Create an invitation (inviter) and share it, e.g. using InvitationURL
+//  4) Parse the invitation (ParseInvitationURL) and accept it (invitee)
+//  5) Handle the DID Exchange events and the attached requests
+//
+package outofband
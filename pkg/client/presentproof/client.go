@@ -0,0 +1,315 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package presentproof
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+)
+
+// Provider contains dependencies for the present proof protocol and is typically created by using aries.Context()
+type Provider interface {
+	Service(id string) (interface{}, error)
+	VDRIRegistry() vdriapi.Registry
+}
+
+// Client enable access to present proof API
+type Client struct {
+	service.Event
+	service      service.DIDComm
+	vdriRegistry vdriapi.Registry
+	newUUID      func() string
+}
+
+// New return new instance of present proof client
+func New(ctx Provider) (*Client, error) {
+	svc, err := ctx.Service(presentproof.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	presentProofSvc, ok := svc.(service.DIDComm)
+	if !ok {
+		return nil, errors.New("cast service to Present Proof Service failed")
+	}
+
+	return &Client{
+		Event:        presentProofSvc,
+		service:      presentProofSvc,
+		vdriRegistry: ctx.VDRIRegistry(),
+		newUUID:      func() string { return uuid.New().String() },
+	}, nil
+}
+
+// SendRequestPresentation sends a request presentation to the Prover (the client is the Verifier).
+func (c *Client) SendRequestPresentation(msg *presentproof.RequestPresentation, myDID, theirDID string) error {
+	msg.Type = presentproof.RequestPresentationMsgType
+
+	// a new thread is started by the message
+	if msg.ID == "" {
+		msg.ID = c.newUUID()
+	}
+
+	return c.handleOutbound(msg, myDID, theirDID)
+}
+
+// SendProposePresentation sends a propose presentation to the Verifier (the client is the Prover).
+func (c *Client) SendProposePresentation(msg *presentproof.ProposePresentation, myDID, theirDID string) error {
+	msg.Type = presentproof.ProposePresentationMsgType
+
+	// a new thread is started by the message
+	if msg.ID == "" {
+		msg.ID = c.newUUID()
+	}
+
+	return c.handleOutbound(msg, myDID, theirDID)
+}
+
+// VerifyPresentations is a helper function that decodes and verifies the presentations attached to the presentation
+// message along with the credentials they enclose. Unsecured JWTs and credentials without a proof are rejected.
+// The public keys of the signers are resolved through the DIDs (vdri registry). It should be executed after
+// receiving a Presentation action message and before accepting it.
+// usage:
+//  if event.Message.Type() == presentproof.PresentationMsgType {
+//    if _, err := client.VerifyPresentations(event.Message); err != nil {
+//      event.Stop(err)
+//      continue
+//    }
+//  }
+//  event.Continue(nil)
+func (c *Client) VerifyPresentations(msg service.DIDCommMsg) ([]*verifiable.Presentation, error) {
+	if msg.Type() != presentproof.PresentationMsgType {
+		return nil, fmt.Errorf("unexpected message type: %s", msg.Type())
+	}
+
+	presentation := presentproof.Presentation{}
+
+	if err := msg.Decode(&presentation); err != nil {
+		return nil, fmt.Errorf("decode presentation: %w", err)
+	}
+
+	fetcher := verifiable.NewDIDKeyResolver(c.vdriRegistry).PublicKeyFetcher()
+
+	var presentations []*verifiable.Presentation
+
+	for i, attachment := range presentation.PresentationsAttach {
+		data, err := attachmentData(attachment.Data)
+		if err != nil {
+			return nil, fmt.Errorf("presentation attachment %d: %w", i, err)
+		}
+
+		vp, err := verifyPresentation(data, fetcher)
+		if err != nil {
+			return nil, fmt.Errorf("presentation attachment %d: %w", i, err)
+		}
+
+		presentations = append(presentations, vp)
+	}
+
+	return presentations, nil
+}
+
+// verifyPresentation decodes the presentation checking its proof (JWS or linked data proof) and the proofs
+// of the enclosed credentials.
+func verifyPresentation(data []byte, fetcher verifiable.PublicKeyFetcher) (*verifiable.Presentation, error) {
+	if isUnsecuredJWT(data) {
+		return nil, errors.New("unsecured JWT presentation is not accepted")
+	}
+
+	vp, err := verifiable.NewPresentation(data, verifiable.WithPresPublicKeyFetcher(fetcher))
+	if err != nil {
+		return nil, err
+	}
+
+	// JWS is verified while decoding, the linked data proof is only checked for presence
+	if !isJWT(data) {
+		if err = verifier.New(&keyResolver{fetcher: fetcher}).Verify(data); err != nil {
+			return nil, fmt.Errorf("check presentation proof: %w", err)
+		}
+	}
+
+	creds, err := rawCredentials(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, cred := range creds {
+		if err = verifyCredential(cred, fetcher); err != nil {
+			return nil, fmt.Errorf("credential %d: %w", i, err)
+		}
+	}
+
+	return vp, nil
+}
+
+// verifyCredential checks the proof of the credential enclosed into the presentation.
+func verifyCredential(cred interface{}, fetcher verifiable.PublicKeyFetcher) error {
+	var data []byte
+
+	if s, ok := cred.(string); ok {
+		data = []byte(s)
+	} else {
+		var err error
+
+		data, err = json.Marshal(cred)
+		if err != nil {
+			return fmt.Errorf("marshal credential: %w", err)
+		}
+	}
+
+	if isUnsecuredJWT(data) {
+		return errors.New("unsecured JWT credential is not accepted")
+	}
+
+	vc, _, err := verifiable.NewCredential(data, verifiable.WithPublicKeyFetcher(fetcher))
+	if err != nil {
+		return err
+	}
+
+	// the proof of JSON credential is checked only if it's present
+	if !isJWT(data) && len(vc.Proofs) == 0 {
+		return errors.New("credential proof is missing")
+	}
+
+	return nil
+}
+
+// rawCredentials returns the credentials enclosed into the presentation as they are, i.e. either JWT strings
+// or JSON objects.
+func rawCredentials(data []byte) ([]interface{}, error) {
+	raw := struct {
+		Credential interface{} `json:"verifiableCredential"`
+		VP         *struct {
+			Credential interface{} `json:"verifiableCredential"`
+		} `json:"vp"`
+	}{}
+
+	if isJWT(data) {
+		payload, err := base64.RawURLEncoding.DecodeString(strings.Split(string(data), ".")[1])
+		if err != nil {
+			return nil, fmt.Errorf("decode presentation JWT: %w", err)
+		}
+
+		data = payload
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal presentation: %w", err)
+	}
+
+	creds := raw.Credential
+	if raw.VP != nil {
+		creds = raw.VP.Credential
+	}
+
+	switch c := creds.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		return c, nil
+	default:
+		return []interface{}{c}, nil
+	}
+}
+
+// isJWT checks whether the data is JWT in compact serialization (either JWS or unsecured JWT).
+func isJWT(data []byte) bool {
+	return jwtHeader(data) != nil
+}
+
+// isUnsecuredJWT checks whether the data is JWT with "none" algorithm, i.e. without signature.
+func isUnsecuredJWT(data []byte) bool {
+	header := jwtHeader(data)
+
+	return header != nil && header["alg"] == "none"
+}
+
+func jwtHeader(data []byte) map[string]interface{} {
+	parts := strings.Split(string(data), ".")
+	if len(parts) != 3 {
+		return nil
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil
+	}
+
+	var header map[string]interface{}
+
+	if err = json.Unmarshal(headerBytes, &header); err != nil {
+		return nil
+	}
+
+	return header
+}
+
+// keyResolver resolves the public keys of the linked data proofs.
+type keyResolver struct {
+	fetcher verifiable.PublicKeyFetcher
+}
+
+func (r *keyResolver) Resolve(id string) ([]byte, error) {
+	pubKey, err := r.fetcher("", id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := pubKey.(type) {
+	case []byte:
+		return k, nil
+	case ed25519.PublicKey:
+		return k, nil
+	default:
+		return nil, errors.New("expecting []byte public key, got something else")
+	}
+}
+
+// attachmentData returns the payload of the attachment, JSON attachments holding a string (e.g JWT) are not
+// marshalled
+func attachmentData(data decorator.AttachmentData) ([]byte, error) {
+	if data.JSON != nil {
+		if s, ok := data.JSON.(string); ok {
+			return []byte(s), nil
+		}
+
+		return json.Marshal(data.JSON)
+	}
+
+	if data.Base64 != "" {
+		return base64.StdEncoding.DecodeString(data.Base64)
+	}
+
+	return nil, errors.New("no inline data")
+}
+
+func (c *Client) handleOutbound(msg interface{}, myDID, theirDID string) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal outbound msg: %w", err)
+	}
+
+	didMsg, err := service.ParseDIDCommMsgMap(payload)
+	if err != nil {
+		return fmt.Errorf("new outbound DIDCommMsg msg: %w", err)
+	}
+
+	return c.service.HandleOutbound(didMsg, myDID, theirDID)
+}
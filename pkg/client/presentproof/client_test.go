@@ -0,0 +1,335 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package presentproof
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/client/presentproof"
+	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
+	mockvdri "github.com/hyperledger/aries-framework-go/pkg/internal/mock/vdri"
+)
+
+const (
+	Alice = "Alice"
+	Bob   = "Bob"
+)
+
+const (
+	holderDID   = "did:example:ebfeb1f712ebc6f1c276e12ec21"
+	keyID       = holderDID + "#keys-1"
+	issuerDID   = "did:example:76e12ec712ebc6f1c221ebfeb1f"
+	issuerKeyID = issuerDID + "#keys-1"
+)
+
+const vcJSON = `{
+  "@context": ["https://www.w3.org/2018/credentials/v1"],
+  "id": "http://example.edu/credentials/1872",
+  "type": "VerifiableCredential",
+  "credentialSubject": {"id": "did:example:ebfeb1f712ebc6f1c276e12ec21"},
+  "issuer": "did:example:76e12ec712ebc6f1c221ebfeb1f",
+  "issuanceDate": "2010-01-01T19:23:24Z"
+}`
+
+func TestNew(t *testing.T) {
+	const errMsg = "test err"
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("get service error", func(t *testing.T) {
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().Service(presentproof.Name).Return(nil, errors.New(errMsg))
+		_, err := New(provider)
+		require.EqualError(t, err, errMsg)
+	})
+
+	t.Run("cast service error", func(t *testing.T) {
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().Service(presentproof.Name).Return(nil, nil)
+		_, err := New(provider)
+		require.EqualError(t, err, "cast service to Present Proof Service failed")
+	})
+}
+
+func TestClient_Send(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := serviceMocks.NewMockDIDComm(ctrl)
+
+	client := newClient(t, ctrl, svc, &mockvdri.MockVDRIRegistry{})
+
+	expectOutbound := func(msgType string) {
+		svc.EXPECT().HandleOutbound(gomock.Any(), Alice, Bob).
+			DoAndReturn(func(msg service.DIDCommMsg, _, _ string) error {
+				require.Equal(t, msgType, msg.Type())
+				require.NotEmpty(t, msg.ID())
+
+				return nil
+			})
+	}
+
+	expectOutbound(presentproof.RequestPresentationMsgType)
+	require.NoError(t, client.SendRequestPresentation(&presentproof.RequestPresentation{}, Alice, Bob))
+
+	expectOutbound(presentproof.ProposePresentationMsgType)
+	require.NoError(t, client.SendProposePresentation(&presentproof.ProposePresentation{}, Alice, Bob))
+
+	svc.EXPECT().HandleOutbound(gomock.Any(), Alice, Bob).Return(errors.New("test err"))
+	require.EqualError(t, client.SendRequestPresentation(&presentproof.RequestPresentation{}, Alice, Bob), "test err")
+}
+
+func TestClient_handleOutbound(t *testing.T) {
+	c := &Client{}
+	err := c.handleOutbound(make(chan int), Alice, Bob)
+	require.EqualError(t, err, "marshal outbound msg: json: unsupported type: chan int")
+}
+
+func TestClient_VerifyPresentations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	issuerPubKey, issuerPrivKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	keys := map[string]ed25519.PublicKey{holderDID: pubKey, issuerDID: issuerPubKey}
+
+	vdriRegistry := &mockvdri.MockVDRIRegistry{
+		ResolveFunc: func(didID string, _ ...vdriapi.ResolveOpts) (*did.Doc, error) {
+			key, ok := keys[didID]
+			if !ok {
+				return nil, errors.New("DID not found")
+			}
+
+			return &did.Doc{
+				ID: didID,
+				PublicKey: []did.PublicKey{{
+					ID:         didID + "#keys-1",
+					Type:       "Ed25519VerificationKey2018",
+					Controller: didID,
+					Value:      key,
+				}},
+			}, nil
+		},
+	}
+
+	presentation := func(attachments ...decorator.AttachmentData) service.DIDCommMsg {
+		msg := &presentproof.Presentation{Type: presentproof.PresentationMsgType, ID: "ID"}
+
+		for _, data := range attachments {
+			msg.PresentationsAttach = append(msg.PresentationsAttach, decorator.Attachment{Data: data})
+		}
+
+		return service.NewDIDCommMsgMap(msg)
+	}
+
+	vc := newCredential(t, issuerPrivKey)
+	vpJWS := newPresentationJWS(t, privKey, vc)
+
+	t.Run("verify presentations", func(t *testing.T) {
+		client := newClient(t, ctrl, serviceMocks.NewMockDIDComm(ctrl), vdriRegistry)
+
+		presentations, err := client.VerifyPresentations(presentation(
+			decorator.AttachmentData{JSON: vpJWS},
+			decorator.AttachmentData{Base64: base64.StdEncoding.EncodeToString([]byte(vpJWS))},
+		))
+		require.NoError(t, err)
+		require.Len(t, presentations, 2)
+		require.Equal(t, holderDID, presentations[0].Holder)
+		require.Equal(t, holderDID, presentations[1].Holder)
+	})
+
+	t.Run("unexpected message type", func(t *testing.T) {
+		client := newClient(t, ctrl, serviceMocks.NewMockDIDComm(ctrl), vdriRegistry)
+
+		_, err := client.VerifyPresentations(service.NewDIDCommMsgMap(&presentproof.RequestPresentation{
+			Type: presentproof.RequestPresentationMsgType,
+		}))
+		require.EqualError(t, err, "unexpected message type: "+presentproof.RequestPresentationMsgType)
+	})
+
+	t.Run("no inline data", func(t *testing.T) {
+		client := newClient(t, ctrl, serviceMocks.NewMockDIDComm(ctrl), vdriRegistry)
+
+		_, err := client.VerifyPresentations(presentation(decorator.AttachmentData{Links: []string{"http://example.edu"}}))
+		require.EqualError(t, err, "presentation attachment 0: no inline data")
+	})
+
+	t.Run("invalid base64 data", func(t *testing.T) {
+		client := newClient(t, ctrl, serviceMocks.NewMockDIDComm(ctrl), vdriRegistry)
+
+		_, err := client.VerifyPresentations(presentation(decorator.AttachmentData{Base64: "@"}))
+		require.Contains(t, err.Error(), "presentation attachment 0: illegal base64 data")
+	})
+
+	t.Run("signer DID is not resolved", func(t *testing.T) {
+		client := newClient(t, ctrl, serviceMocks.NewMockDIDComm(ctrl), &mockvdri.MockVDRIRegistry{
+			ResolveFunc: func(string, ...vdriapi.ResolveOpts) (*did.Doc, error) {
+				return nil, errors.New("DID not found")
+			},
+		})
+
+		_, err := client.VerifyPresentations(presentation(decorator.AttachmentData{JSON: vpJWS}))
+		require.Contains(t, err.Error(), "presentation attachment 0:")
+		require.Contains(t, err.Error(), "DID not found")
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		client := newClient(t, ctrl, serviceMocks.NewMockDIDComm(ctrl), vdriRegistry)
+
+		_, err = client.VerifyPresentations(presentation(decorator.AttachmentData{JSON: newPresentationJWS(t, otherKey, vc)}))
+		require.Contains(t, err.Error(), "presentation attachment 0:")
+	})
+
+	t.Run("JSON presentation with invalid proof", func(t *testing.T) {
+		client := newClient(t, ctrl, serviceMocks.NewMockDIDComm(ctrl), vdriRegistry)
+
+		vpJSON := map[string]interface{}{
+			"@context":             []string{"https://www.w3.org/2018/credentials/v1"},
+			"type":                 "VerifiablePresentation",
+			"holder":               holderDID,
+			"verifiableCredential": []string{vc},
+			"proof": map[string]interface{}{
+				"type":       "Ed25519Signature2018",
+				"created":    "2020-01-21T12:59:31+02:00",
+				"creator":    keyID,
+				"jws":        "eyJhbGciOiJFZERTQSIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19..Zm9yZ2Vk",
+				"proofValue": base64.RawURLEncoding.EncodeToString([]byte("forged")),
+			},
+		}
+
+		_, err := client.VerifyPresentations(presentation(decorator.AttachmentData{JSON: vpJSON}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "presentation attachment 0: check presentation proof")
+	})
+
+	t.Run("unsecured JWT presentation", func(t *testing.T) {
+		client := newClient(t, ctrl, serviceMocks.NewMockDIDComm(ctrl), vdriRegistry)
+
+		vp := &verifiable.Presentation{
+			Context: []string{"https://www.w3.org/2018/credentials/v1"},
+			Type:    []string{"VerifiablePresentation"},
+			Holder:  holderDID,
+		}
+		require.NoError(t, vp.SetCredentials(vc))
+
+		claims, err := vp.JWTClaims([]string{"did:example:verifier"}, false)
+		require.NoError(t, err)
+
+		unsecuredJWT, err := claims.MarshalUnsecuredJWT()
+		require.NoError(t, err)
+
+		_, err = client.VerifyPresentations(presentation(decorator.AttachmentData{JSON: unsecuredJWT}))
+		require.EqualError(t, err, "presentation attachment 0: unsecured JWT presentation is not accepted")
+	})
+
+	t.Run("credential without proof", func(t *testing.T) {
+		client := newClient(t, ctrl, serviceMocks.NewMockDIDComm(ctrl), vdriRegistry)
+
+		unsigned, _, err := verifiable.NewCredential([]byte(vcJSON))
+		require.NoError(t, err)
+
+		_, err = client.VerifyPresentations(presentation(decorator.AttachmentData{
+			JSON: newPresentationJWS(t, privKey, unsigned),
+		}))
+		require.EqualError(t, err, "presentation attachment 0: credential 0: credential proof is missing")
+	})
+
+	t.Run("unsecured JWT credential", func(t *testing.T) {
+		client := newClient(t, ctrl, serviceMocks.NewMockDIDComm(ctrl), vdriRegistry)
+
+		unsigned, _, err := verifiable.NewCredential([]byte(vcJSON))
+		require.NoError(t, err)
+
+		claims, err := unsigned.JWTClaims(false)
+		require.NoError(t, err)
+
+		unsecuredJWT, err := claims.MarshalUnsecuredJWT()
+		require.NoError(t, err)
+
+		_, err = client.VerifyPresentations(presentation(decorator.AttachmentData{
+			JSON: newPresentationJWS(t, privKey, unsecuredJWT),
+		}))
+		require.EqualError(t, err, "presentation attachment 0: credential 0: unsecured JWT credential is not accepted")
+	})
+
+	t.Run("credential with invalid proof", func(t *testing.T) {
+		client := newClient(t, ctrl, serviceMocks.NewMockDIDComm(ctrl), vdriRegistry)
+
+		_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		_, err = client.VerifyPresentations(presentation(decorator.AttachmentData{
+			JSON: newPresentationJWS(t, privKey, newCredential(t, otherKey)),
+		}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "VC JWT signature verification")
+	})
+}
+
+// newCredential creates the credential signed by the issuer (JWS).
+func newCredential(t *testing.T, issuerPrivKey ed25519.PrivateKey) string {
+	vc, _, err := verifiable.NewCredential([]byte(vcJSON))
+	require.NoError(t, err)
+
+	claims, err := vc.JWTClaims(false)
+	require.NoError(t, err)
+
+	vcJWS, err := claims.MarshalJWS(verifiable.EdDSA, issuerPrivKey, issuerKeyID)
+	require.NoError(t, err)
+
+	return vcJWS
+}
+
+func newPresentationJWS(t *testing.T, privKey ed25519.PrivateKey, cred interface{}) string {
+	vp := &verifiable.Presentation{
+		Context: []string{"https://www.w3.org/2018/credentials/v1"},
+		Type:    []string{"VerifiablePresentation"},
+		Holder:  holderDID,
+	}
+
+	require.NoError(t, vp.SetCredentials(cred))
+
+	claims, err := vp.JWTClaims([]string{"did:example:verifier"}, false)
+	require.NoError(t, err)
+
+	vpJWS, err := claims.MarshalJWS(verifiable.EdDSA, privKey, keyID)
+	require.NoError(t, err)
+
+	return vpJWS
+}
+
+func newClient(t *testing.T, ctrl *gomock.Controller, svc service.DIDComm, vdri vdriapi.Registry) *Client {
+	provider := mocks.NewMockProvider(ctrl)
+	provider.EXPECT().Service(presentproof.Name).Return(svc, nil)
+	provider.EXPECT().VDRIRegistry().Return(vdri)
+
+	client, err := New(provider)
+	require.NoError(t, err)
+
+	return client
+}
@@ -0,0 +1,39 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package presentproof is responsible for the proof presentation between agents.
+// The protocol involves two participants: the Prover and the Verifier. The Prover may start the protocol by sending
+// a proposal (SendProposePresentation), the Verifier may start it by sending a request (SendRequestPresentation).
+// Incoming messages are handled through action events. The reply to the incoming message is provided
+// to the Continue function:
+// - ProposePresentation (Verifier) - *presentproof.RequestPresentation is required
+// - RequestPresentation (Prover) - *presentproof.Presentation is required
+// - Presentation (Verifier) - nothing, an ack is sent to the Prover
+// The Stop function abandons the protocol, a problem-report is sent to the other participant.
+// The simplest way to handle incoming messages (actions) on the Verifier side is:
+// 	client := presentproof.New(...)
+// 	client.RegisterActionEvent(actions)
+// 	for {
+// 	  select {
+// 	    case event := <-actions:
+// 	      if event.Message.Type() == presentproof.PresentationMsgType {
+// 	        if _, err := client.VerifyPresentations(event.Message); err != nil {
+// 	          event.Stop(err)
+// 	          continue
+// 	        }
+// 	      }
+// 	      event.Continue(nil)
+// 	  }
+// 	}
+//
+//  Basic Flow:
+//  1) Prepare client context
+//  2) Create client
+//  3) Register for action events
+//  4) Handle actions
+//  5) Send proposal or request
+//
+package presentproof
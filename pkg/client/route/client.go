@@ -31,6 +31,27 @@ type protocolService interface {
 
 	// Register registers the agent with the router
 	Register(connectionID string) error
+
+	// Unregister unregisters the agent from the router
+	Unregister(connectionID string) error
+
+	// Routers returns the connection IDs of the registered routers
+	Routers() ([]string, error)
+
+	// SetDefaultRouter sets the default router
+	SetDefaultRouter(connectionID string) error
+
+	// AddKey adds the recipient key to the router
+	AddKey(connectionID, recKey string) error
+
+	// RemoveKey removes the recipient key from the router
+	RemoveKey(connectionID, recKey string) error
+
+	// Keylist returns the recipient keys registered with the router
+	Keylist(connectionID string) ([]string, error)
+
+	// Config returns the router configuration
+	Config(connectionID string) (*route.Config, error)
 }
 
 // New return new instance of route client.
@@ -59,3 +80,73 @@ func (c *Client) Register(connectionID string) error {
 
 	return nil
 }
+
+// Unregister the agent from the router(passed in connectionID, the default router if empty). The recipient keys
+// added to the router are not removed.
+func (c *Client) Unregister(connectionID string) error {
+	if err := c.routeSvc.Unregister(connectionID); err != nil {
+		return fmt.Errorf("router unregistration : %w", err)
+	}
+
+	return nil
+}
+
+// Routers returns the connection IDs of the routers the agent is registered with.
+func (c *Client) Routers() ([]string, error) {
+	routers, err := c.routeSvc.Routers()
+	if err != nil {
+		return nil, fmt.Errorf("fetch routers : %w", err)
+	}
+
+	return routers, nil
+}
+
+// SetDefaultRouter sets the router(passed in connectionID) as the default router. The keys of the new
+// connections are advertised through the default router unless the router is chosen explicitly.
+func (c *Client) SetDefaultRouter(connectionID string) error {
+	if err := c.routeSvc.SetDefaultRouter(connectionID); err != nil {
+		return fmt.Errorf("set default router : %w", err)
+	}
+
+	return nil
+}
+
+// AddKey adds the recipient key to the router(passed in connectionID, the default router if empty).
+func (c *Client) AddKey(connectionID, recKey string) error {
+	if err := c.routeSvc.AddKey(connectionID, recKey); err != nil {
+		return fmt.Errorf("add key to the router : %w", err)
+	}
+
+	return nil
+}
+
+// RemoveKey removes the recipient key from the router(passed in connectionID, the default router if empty).
+// The router stops forwarding the messages sent to the key.
+func (c *Client) RemoveKey(connectionID, recKey string) error {
+	if err := c.routeSvc.RemoveKey(connectionID, recKey); err != nil {
+		return fmt.Errorf("remove key from the router : %w", err)
+	}
+
+	return nil
+}
+
+// Keylist queries the recipient keys of the agent registered with the router(passed in connectionID,
+// the default router if empty).
+func (c *Client) Keylist(connectionID string) ([]string, error) {
+	keys, err := c.routeSvc.Keylist(connectionID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch keylist from the router : %w", err)
+	}
+
+	return keys, nil
+}
+
+// Config returns the endpoint and routing keys of the router(passed in connectionID, the default router if empty).
+func (c *Client) Config(connectionID string) (*route.Config, error) {
+	conf, err := c.routeSvc.Config(connectionID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch router config : %w", err)
+	}
+
+	return conf, nil
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/route"
 	mockroute "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/route"
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
 )
@@ -63,3 +64,128 @@ func TestRegister(t *testing.T) {
 		require.Contains(t, err.Error(), "router registration")
 	})
 }
+
+func TestUnregister(t *testing.T) {
+	t.Run("test unregister - success", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{ServiceValue: &mockroute.MockRouteSvc{}})
+		require.NoError(t, err)
+
+		require.NoError(t, c.Unregister("conn1"))
+	})
+
+	t.Run("test unregister - error", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockroute.MockRouteSvc{UnregisterErr: errors.New("unregister error")}})
+		require.NoError(t, err)
+
+		err = c.Unregister("conn1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "router unregistration")
+	})
+}
+
+func TestRouters(t *testing.T) {
+	t.Run("test routers - success", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockroute.MockRouteSvc{RoutersValue: []string{"conn1", "conn2"}}})
+		require.NoError(t, err)
+
+		routers, err := c.Routers()
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn1", "conn2"}, routers)
+	})
+
+	t.Run("test routers - error", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockroute.MockRouteSvc{RoutersErr: errors.New("routers error")}})
+		require.NoError(t, err)
+
+		routers, err := c.Routers()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "fetch routers")
+		require.Nil(t, routers)
+	})
+}
+
+func TestSetDefaultRouter(t *testing.T) {
+	t.Run("test set default router - success", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{ServiceValue: &mockroute.MockRouteSvc{}})
+		require.NoError(t, err)
+
+		require.NoError(t, c.SetDefaultRouter("conn1"))
+	})
+
+	t.Run("test set default router - error", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockroute.MockRouteSvc{SetDefaultErr: route.ErrRouterNotRegistered}})
+		require.NoError(t, err)
+
+		err = c.SetDefaultRouter("conn1")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, route.ErrRouterNotRegistered))
+	})
+}
+
+func TestKeys(t *testing.T) {
+	t.Run("test keys - success", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockroute.MockRouteSvc{Keys: []string{"abc"}}})
+		require.NoError(t, err)
+
+		require.NoError(t, c.AddKey("conn1", "abc"))
+		require.NoError(t, c.RemoveKey("conn1", "abc"))
+
+		keys, err := c.Keylist("conn1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"abc"}, keys)
+	})
+
+	t.Run("test keys - error", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockroute.MockRouteSvc{
+				AddKeyErr:    errors.New("add error"),
+				RemoveKeyErr: errors.New("remove error"),
+				KeylistErr:   errors.New("keylist error"),
+			}})
+		require.NoError(t, err)
+
+		err = c.AddKey("conn1", "abc")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "add key to the router")
+
+		err = c.RemoveKey("conn1", "abc")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "remove key from the router")
+
+		keys, err := c.Keylist("conn1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "fetch keylist from the router")
+		require.Nil(t, keys)
+	})
+}
+
+func TestConfig(t *testing.T) {
+	t.Run("test config - success", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockroute.MockRouteSvc{
+				RouterEndpoint: "http://router.example.com",
+				RoutingKeys:    []string{"abc"},
+			}})
+		require.NoError(t, err)
+
+		conf, err := c.Config("")
+		require.NoError(t, err)
+		require.Equal(t, "http://router.example.com", conf.Endpoint())
+		require.Equal(t, []string{"abc"}, conf.Keys())
+	})
+
+	t.Run("test config - error", func(t *testing.T) {
+		c, err := New(&mockprovider.Provider{ServiceValue: &mockroute.MockRouteSvc{}})
+		require.NoError(t, err)
+
+		conf, err := c.Config("")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, route.ErrRouterNotRegistered))
+		require.Nil(t, conf)
+	})
+}
@@ -8,4 +8,8 @@ SPDX-License-Identifier: Apache-2.0
 // the Router is responsible for routing/forwarding the DIDComm messages to the agent. During
 // router registration, the agent recivies routers service endpoint and routing keys. These
 // details are used in DID Exchange Invitation or DID Document Service Descriptor.
+// The agent can be registered with multiple routers, the first registered router is the default
+// router. The keys of a new connection are advertised through the default router unless another
+// router is chosen (e.g. didexchange.WithRouterConnectionID). The recipient keys registered with a
+// router can be queried (Keylist) and removed (RemoveKey).
 package route
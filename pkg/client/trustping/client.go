@@ -0,0 +1,67 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/trustping"
+)
+
+// ErrPingTimeout is returned when no ping response is received within the given timeout
+var ErrPingTimeout = trustping.ErrPingTimeout
+
+// provider contains dependencies for the trust ping protocol and is typically created by using aries.Context()
+type provider interface {
+	Service(id string) (interface{}, error)
+}
+
+// Client enable access to trust ping api.
+type Client struct {
+	trustPingSvc protocolService
+}
+
+// protocolService defines Trust Ping service.
+type protocolService interface {
+	// DIDComm service
+	service.Handler
+
+	// Ping sends a ping to the other end of the connection and returns the round-trip time
+	Ping(connectionID string, timeout time.Duration) (time.Duration, error)
+}
+
+// New return new instance of trust ping client.
+func New(ctx provider) (*Client, error) {
+	svc, err := ctx.Service(trustping.TrustPing)
+	if err != nil {
+		return nil, err
+	}
+
+	trustPingSvc, ok := svc.(protocolService)
+	if !ok {
+		return nil, errors.New("cast service to trust ping service failed")
+	}
+
+	return &Client{
+		trustPingSvc: trustPingSvc,
+	}, nil
+}
+
+// Ping sends a ping to the agent on the other end of the connection (passed in connectionID) and waits
+// for the response. The round-trip time is returned, ErrPingTimeout is returned if no response is received
+// within the given timeout.
+func (c *Client) Ping(connectionID string, timeout time.Duration) (time.Duration, error) {
+	rtt, err := c.trustPingSvc.Ping(connectionID, timeout)
+	if err != nil {
+		return 0, fmt.Errorf("trust ping : %w", err)
+	}
+
+	return rtt, nil
+}
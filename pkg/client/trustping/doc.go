@@ -0,0 +1,11 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package trustping enables the agent to check whether a connection established through the DID Exchange
+// is still alive. A ping is sent to the agent on the other end of the connection and the round-trip
+// time is measured once the response is received. Inbound pings are answered automatically unless
+// the sender explicitly states that no response is requested.
+package trustping
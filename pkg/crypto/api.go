@@ -32,4 +32,86 @@ type Crypto interface {
 	// returns:
 	// 		error in case of errors or nil if signature verification was successful
 	Verify(signature, msg []byte, kh interface{}) error
+	// WrapKey will wrap cek for the recipient public key recPubKey using ECDH-ES key agreement. Setting the sender
+	// key handle with the WithSender() option switches to ECDH-1PU key agreement (authenticated sender).
+	// apu and apv are the Agreement PartyUInfo and PartyVInfo used in the key derivation.
+	// returns:
+	// 		RecipientWrappedKey containing the wrapped cek and the ephemeral public key
+	//		error in case of errors
+	WrapKey(cek, apu, apv []byte, recPubKey *PublicKey, opts ...WrapKeyOpts) (*RecipientWrappedKey, error)
+	// UnwrapKey will unwrap the cek in recWK using the recipient private key handle kh. ECDH-1PU wrapped keys
+	// require the sender public key (*PublicKey) set with the WithSender() option.
+	// returns:
+	// 		unwrapped cek in []byte
+	//		error in case of errors
+	UnwrapKey(recWK *RecipientWrappedKey, kh interface{}, opts ...WrapKeyOpts) ([]byte, error)
+	// ComputeMAC computes message authentication code (MAC) for code data
+	// using a matching MAC primitive in kh key handle
+	// returns:
+	// 		MAC in []byte
+	//		error in case of errors
+	ComputeMAC(data []byte, kh interface{}) ([]byte, error)
+	// VerifyMAC determines if mac is a correct authentication code (MAC) for data
+	// using a matching MAC primitive in kh key handle
+	// returns:
+	// 		error in case of errors or nil if MAC verification was successful
+	VerifyMAC(mac, data []byte, kh interface{}) error
+}
+
+const (
+	// ECKeyType is the JWK key type of NIST curves public keys
+	ECKeyType = "EC"
+	// OKPKeyType is the JWK key type of X25519 public keys
+	OKPKeyType = "OKP"
+	// P256Curve is the JWK name of the NIST P-256 curve
+	P256Curve = "P-256"
+	// P384Curve is the JWK name of the NIST P-384 curve
+	P384Curve = "P-384"
+	// X25519Curve is the JWK name of the X25519 curve
+	X25519Curve = "X25519"
+
+	// ECDHESA256KWAlg is the ECDH-ES key wrapping algorithm of NIST P-256 and P-384 keys
+	ECDHESA256KWAlg = "ECDH-ES+A256KW"
+	// ECDH1PUA256KWAlg is the ECDH-1PU key wrapping algorithm of NIST P-256 and P-384 keys
+	ECDH1PUA256KWAlg = "ECDH-1PU+A256KW"
+	// ECDHESXC20PKWAlg is the ECDH-ES key wrapping algorithm of X25519 keys
+	ECDHESXC20PKWAlg = "ECDH-ES+XC20PKW"
+	// ECDH1PUXC20PKWAlg is the ECDH-1PU key wrapping algorithm of X25519 keys
+	ECDH1PUXC20PKWAlg = "ECDH-1PU+XC20PKW"
+)
+
+// PublicKey is a key agreement public key. X (and Y for NIST curves) hold the raw coordinates of the key.
+type PublicKey struct {
+	KID   string `json:"kid,omitempty"`
+	X     []byte `json:"x,omitempty"`
+	Y     []byte `json:"y,omitempty"`
+	Curve string `json:"curve,omitempty"`
+	Type  string `json:"type,omitempty"`
+}
+
+// RecipientWrappedKey is the result of WrapKey, it contains the material needed by the recipient to unwrap the key.
+type RecipientWrappedKey struct {
+	KID          string    `json:"kid,omitempty"`
+	EncryptedCEK []byte    `json:"encryptedcek,omitempty"`
+	EPK          PublicKey `json:"epk,omitempty"`
+	Alg          string    `json:"alg,omitempty"`
+	APU          []byte    `json:"apu,omitempty"`
+	APV          []byte    `json:"apv,omitempty"`
+}
+
+// WrapKeyOptions holds the options of WrapKey and UnwrapKey
+type WrapKeyOptions struct {
+	// SenderKey is the sender private key handle in WrapKey and the sender *PublicKey in UnwrapKey
+	SenderKey interface{}
+}
+
+// WrapKeyOpts sets an option of WrapKey and UnwrapKey
+type WrapKeyOpts func(opts *WrapKeyOptions)
+
+// WithSender sets the sender key used by ECDH-1PU key wrapping: a private key handle to wrap a key
+// or a *PublicKey to unwrap it.
+func WithSender(senderKey interface{}) WrapKeyOpts {
+	return func(opts *WrapKeyOptions) {
+		opts.SenderKey = senderKey
+	}
 }
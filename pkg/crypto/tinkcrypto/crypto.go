@@ -17,8 +17,10 @@ import (
 	"github.com/google/tink/go/aead"
 	"github.com/google/tink/go/core/primitiveset"
 	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
 	"github.com/google/tink/go/signature"
 	aeadsubtle "github.com/google/tink/go/subtle/aead"
+	"github.com/google/tink/go/tink"
 	"golang.org/x/crypto/chacha20poly1305"
 )
 
@@ -151,3 +153,59 @@ func (t *Crypto) Verify(sig, msg []byte, kh interface{}) error {
 
 	return err
 }
+
+// ComputeMAC computes message authentication code (MAC) for code data
+// using a matching MAC primitive in kh key handle
+func (t *Crypto) ComputeMAC(data []byte, kh interface{}) ([]byte, error) {
+	macPrimitive, err := newMAC(kh)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := macPrimitive.ComputeMAC(data)
+	if err != nil {
+		return nil, fmt.Errorf("compute mac: %w", err)
+	}
+
+	return m, nil
+}
+
+// VerifyMAC determines if mac is a correct authentication code (MAC) for data
+// using a matching MAC primitive in kh key handle and returns nil if so, otherwise it returns an error.
+func (t *Crypto) VerifyMAC(macBytes, data []byte, kh interface{}) error {
+	macPrimitive, err := newMAC(kh)
+	if err != nil {
+		return err
+	}
+
+	err = macPrimitive.VerifyMAC(macBytes, data)
+	if err != nil {
+		err = fmt.Errorf("verify mac: %w", err)
+	}
+
+	return err
+}
+
+func newMAC(kh interface{}) (tink.MAC, error) {
+	keyHandle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errors.New("bad key handle format")
+	}
+
+	ps, err := keyHandle.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("get primitives: %w", err)
+	}
+
+	// the Tink MAC factory doesn't check the primitives of kh
+	if _, ok = ps.Primary.Primitive.(tink.MAC); !ok {
+		return nil, errors.New("create new mac: not a MAC key handle")
+	}
+
+	macPrimitive, err := mac.New(keyHandle)
+	if err != nil {
+		return nil, fmt.Errorf("create new mac: %w", err)
+	}
+
+	return macPrimitive, nil
+}
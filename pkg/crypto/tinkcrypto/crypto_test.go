@@ -11,6 +11,7 @@ import (
 
 	"github.com/google/tink/go/aead"
 	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
 	"github.com/google/tink/go/signature"
 	aeadsubtle "github.com/google/tink/go/subtle/aead"
 	"github.com/stretchr/testify/require"
@@ -163,3 +164,49 @@ func TestCrypto_SignVerify(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestCrypto_ComputeVerifyMAC(t *testing.T) {
+	t.Run("test with HMAC-SHA256 key", func(t *testing.T) {
+		kh, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+		require.NoError(t, err)
+
+		badKH, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+		require.NoError(t, err)
+
+		c := Crypto{}
+		data := []byte("test data")
+		m, err := c.ComputeMAC(data, kh)
+		require.NoError(t, err)
+		require.NotEmpty(t, m)
+
+		// compute MAC with nil key handle - should fail
+		_, err = c.ComputeMAC(data, nil)
+		require.EqualError(t, err, "bad key handle format")
+
+		// compute MAC with bad key handle - should fail
+		_, err = c.ComputeMAC(data, badKH)
+		require.Error(t, err)
+
+		err = c.VerifyMAC(m, data, kh)
+		require.NoError(t, err)
+
+		// verify MAC with altered data - should fail
+		err = c.VerifyMAC(m, []byte("other data"), kh)
+		require.Error(t, err)
+
+		// verify MAC with another key - should fail
+		otherKH, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+		require.NoError(t, err)
+
+		err = c.VerifyMAC(m, data, otherKH)
+		require.Error(t, err)
+
+		// verify MAC with nil key handle - should fail
+		err = c.VerifyMAC(m, data, nil)
+		require.EqualError(t, err, "bad key handle format")
+
+		// verify MAC with bad key handle - should fail
+		err = c.VerifyMAC(m, data, badKH)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,76 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package ecdhkw provides the Tink key managers of the ECDH key agreement keys (NIST P-256, P-384 and X25519) used by
+// crypto.Crypto to wrap and unwrap content encryption keys. Importing the package registers the key managers in the
+// Tink registry, keys are then created with the key templates of the package.
+package ecdhkw
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+)
+
+// nolint:gochecknoinits
+func init() {
+	if err := registry.RegisterKeyManager(new(privateKeyManager)); err != nil {
+		panic(fmt.Sprintf("ecdhkw.init() failed: %v", err))
+	}
+
+	if err := registry.RegisterKeyManager(new(publicKeyManager)); err != nil {
+		panic(fmt.Sprintf("ecdhkw.init() failed: %v", err))
+	}
+}
+
+// P256KeyTemplate is a KeyTemplate that generates an ECDH key wrapping private key on the NIST P-256 curve.
+func P256KeyTemplate() *tinkpb.KeyTemplate {
+	return createKeyTemplate(crypto.P256Curve)
+}
+
+// P384KeyTemplate is a KeyTemplate that generates an ECDH key wrapping private key on the NIST P-384 curve.
+func P384KeyTemplate() *tinkpb.KeyTemplate {
+	return createKeyTemplate(crypto.P384Curve)
+}
+
+// X25519KeyTemplate is a KeyTemplate that generates an ECDH key wrapping private key on the X25519 curve.
+func X25519KeyTemplate() *tinkpb.KeyTemplate {
+	return createKeyTemplate(crypto.X25519Curve)
+}
+
+func createKeyTemplate(curve string) *tinkpb.KeyTemplate {
+	// marshalling a string field can't fail
+	format, _ := proto.Marshal(&ecdhKwKeyFormat{Curve: curve}) //nolint:errcheck
+
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          PrivateKeyTypeURL,
+		Value:            format,
+		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+	}
+}
+
+// PublicKey returns the public key of the primary key of kh, a private or public ECDH key wrapping keyset handle.
+func PublicKey(kh *keyset.Handle) (*crypto.PublicKey, error) {
+	ps, err := kh.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("get primitives: %w", err)
+	}
+
+	switch k := ps.Primary.Primitive.(type) {
+	case KeyAgreement:
+		return k.PublicKey(), nil
+	case *crypto.PublicKey:
+		return k, nil
+	default:
+		return nil, errors.New("not an ECDH key wrapping key handle")
+	}
+}
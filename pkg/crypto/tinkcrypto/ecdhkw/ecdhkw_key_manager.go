@@ -0,0 +1,201 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ecdhkw
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+)
+
+const (
+	ecdhKwKeyVersion = 0
+
+	// PrivateKeyTypeURL is the Tink type URL of ECDH key wrapping private keys
+	PrivateKeyTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.EcdhKwPrivateKey"
+	// PublicKeyTypeURL is the Tink type URL of ECDH key wrapping public keys
+	PublicKeyTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.EcdhKwPublicKey"
+)
+
+// common errors
+var (
+	errInvalidPrivateKey       = errors.New("ecdhkw_private_key_manager: invalid key")
+	errInvalidPrivateKeyFormat = errors.New("ecdhkw_private_key_manager: invalid key format")
+	errInvalidPublicKey        = errors.New("ecdhkw_public_key_manager: invalid key")
+)
+
+// privateKeyManager is an implementation of the Tink PrivateKeyManager interface.
+// It generates new ECDH key wrapping private keys and produces KeyAgreement primitives.
+type privateKeyManager struct{}
+
+// Assert that privateKeyManager implements the PrivateKeyManager interface.
+var _ registry.PrivateKeyManager = (*privateKeyManager)(nil)
+
+// Primitive creates a KeyAgreement for the given serialized private key.
+func (km *privateKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	key := new(ecdhKwPrivateKey)
+
+	if err := proto.Unmarshal(serializedKey, key); err != nil || key.PublicKey == nil {
+		return nil, errInvalidPrivateKey
+	}
+
+	if err := keyset.ValidateKeyVersion(key.Version, ecdhKwKeyVersion); err != nil {
+		return nil, fmt.Errorf("ecdhkw_private_key_manager: %w", err)
+	}
+
+	switch key.PublicKey.Curve {
+	case crypto.P256Curve, crypto.P384Curve:
+		return &nistKeyAgreement{d: key.KeyValue, pub: publicKey(key.PublicKey)}, nil
+	case crypto.X25519Curve:
+		return newX25519KeyAgreement(key.KeyValue)
+	default:
+		return nil, fmt.Errorf("ecdhkw_private_key_manager: %w: '%s'", errUnsupportedCurve, key.PublicKey.Curve)
+	}
+}
+
+// NewKey creates a new private key according to the given serialized key format.
+func (km *privateKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	format := new(ecdhKwKeyFormat)
+
+	if err := proto.Unmarshal(serializedKeyFormat, format); err != nil {
+		return nil, errInvalidPrivateKeyFormat
+	}
+
+	k, err := newKeyAgreement(format.Curve)
+	if err != nil {
+		return nil, fmt.Errorf("ecdhkw_private_key_manager: %w", err)
+	}
+
+	pub := k.PublicKey()
+
+	return &ecdhKwPrivateKey{
+		Version: ecdhKwKeyVersion,
+		PublicKey: &ecdhKwPublicKey{
+			Version: ecdhKwKeyVersion,
+			Curve:   pub.Curve,
+			X:       pub.X,
+			Y:       pub.Y,
+		},
+		KeyValue: k.keyValue(),
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to the given serialized key format.
+// It should be used solely by the key management API.
+func (km *privateKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, errInvalidPrivateKeyFormat
+	}
+
+	return &tinkpb.KeyData{
+		TypeUrl:         PrivateKeyTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+	}, nil
+}
+
+// PublicKeyData extracts the public key data from the private key.
+func (km *privateKeyManager) PublicKeyData(serializedPrivKey []byte) (*tinkpb.KeyData, error) {
+	privKey := new(ecdhKwPrivateKey)
+
+	if err := proto.Unmarshal(serializedPrivKey, privKey); err != nil || privKey.PublicKey == nil {
+		return nil, errInvalidPrivateKey
+	}
+
+	serializedPubKey, err := proto.Marshal(privKey.PublicKey)
+	if err != nil {
+		return nil, errInvalidPrivateKey
+	}
+
+	return &tinkpb.KeyData{
+		TypeUrl:         PublicKeyTypeURL,
+		Value:           serializedPubKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *privateKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == PrivateKeyTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *privateKeyManager) TypeURL() string {
+	return PrivateKeyTypeURL
+}
+
+// publicKeyManager is an implementation of the Tink KeyManager interface for ECDH key wrapping public keys.
+// Its primitive is the *crypto.PublicKey of the key.
+type publicKeyManager struct{}
+
+// Assert that publicKeyManager implements the KeyManager interface.
+var _ registry.KeyManager = (*publicKeyManager)(nil)
+
+// Primitive returns the *crypto.PublicKey of the given serialized public key.
+func (km *publicKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	key := new(ecdhKwPublicKey)
+
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidPublicKey
+	}
+
+	if err := keyset.ValidateKeyVersion(key.Version, ecdhKwKeyVersion); err != nil {
+		return nil, fmt.Errorf("ecdhkw_public_key_manager: %w", err)
+	}
+
+	if key.Curve != crypto.P256Curve && key.Curve != crypto.P384Curve && key.Curve != crypto.X25519Curve {
+		return nil, fmt.Errorf("ecdhkw_public_key_manager: %w: '%s'", errUnsupportedCurve, key.Curve)
+	}
+
+	return publicKey(key), nil
+}
+
+// NewKey is not supported, public keys are extracted from private keys.
+func (km *publicKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	return nil, errors.New("ecdhkw_public_key_manager: not implemented")
+}
+
+// NewKeyData is not supported, public keys are extracted from private keys.
+func (km *publicKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	return nil, errors.New("ecdhkw_public_key_manager: not implemented")
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *publicKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == PublicKeyTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *publicKeyManager) TypeURL() string {
+	return PublicKeyTypeURL
+}
+
+func publicKey(key *ecdhKwPublicKey) *crypto.PublicKey {
+	keyType := crypto.ECKeyType
+	if key.Curve == crypto.X25519Curve {
+		keyType = crypto.OKPKeyType
+	}
+
+	return &crypto.PublicKey{
+		X:     key.X,
+		Y:     key.Y,
+		Curve: key.Curve,
+		Type:  keyType,
+	}
+}
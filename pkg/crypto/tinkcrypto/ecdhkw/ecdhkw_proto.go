@@ -0,0 +1,65 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ecdhkw
+
+import (
+	"github.com/golang/protobuf/proto"
+)
+
+// The messages below are the protobuf serialization of the ECDH key wrapping keys stored in Tink keysets.
+// They are declared by hand with the same wire tags protoc would generate for:
+//
+//	message EcdhKwKeyFormat { string curve = 1; }
+//	message EcdhKwPublicKey { uint32 version = 1; string curve = 2; bytes x = 3; bytes y = 4; }
+//	message EcdhKwPrivateKey { uint32 version = 1; EcdhKwPublicKey public_key = 2; bytes key_value = 3; }
+
+// ecdhKwKeyFormat is the key format of the ECDH key wrapping key templates.
+type ecdhKwKeyFormat struct {
+	Curve string `protobuf:"bytes,1,opt,name=curve,proto3" json:"curve,omitempty"`
+}
+
+// Reset the message
+func (m *ecdhKwKeyFormat) Reset() { *m = ecdhKwKeyFormat{} }
+
+// String representation of the message
+func (m *ecdhKwKeyFormat) String() string { return proto.CompactTextString(m) }
+
+// ProtoMessage marks the struct as a proto message
+func (*ecdhKwKeyFormat) ProtoMessage() {}
+
+// ecdhKwPublicKey is an ECDH key wrapping public key.
+type ecdhKwPublicKey struct {
+	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Curve   string `protobuf:"bytes,2,opt,name=curve,proto3" json:"curve,omitempty"`
+	X       []byte `protobuf:"bytes,3,opt,name=x,proto3" json:"x,omitempty"`
+	Y       []byte `protobuf:"bytes,4,opt,name=y,proto3" json:"y,omitempty"`
+}
+
+// Reset the message
+func (m *ecdhKwPublicKey) Reset() { *m = ecdhKwPublicKey{} }
+
+// String representation of the message
+func (m *ecdhKwPublicKey) String() string { return proto.CompactTextString(m) }
+
+// ProtoMessage marks the struct as a proto message
+func (*ecdhKwPublicKey) ProtoMessage() {}
+
+// ecdhKwPrivateKey is an ECDH key wrapping private key.
+type ecdhKwPrivateKey struct {
+	Version   uint32           `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	PublicKey *ecdhKwPublicKey `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	KeyValue  []byte           `protobuf:"bytes,3,opt,name=key_value,json=keyValue,proto3" json:"key_value,omitempty"`
+}
+
+// Reset the message
+func (m *ecdhKwPrivateKey) Reset() { *m = ecdhKwPrivateKey{} }
+
+// String representation of the message
+func (m *ecdhKwPrivateKey) String() string { return proto.CompactTextString(m) }
+
+// ProtoMessage marks the struct as a proto message
+func (*ecdhKwPrivateKey) ProtoMessage() {}
@@ -0,0 +1,211 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ecdhkw
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+)
+
+func TestKeyTemplates(t *testing.T) {
+	tests := []struct {
+		curve    string
+		keyType  string
+		template *tinkpb.KeyTemplate
+		keySize  int
+	}{
+		{curve: crypto.P256Curve, keyType: crypto.ECKeyType, template: P256KeyTemplate(), keySize: 32},
+		{curve: crypto.P384Curve, keyType: crypto.ECKeyType, template: P384KeyTemplate(), keySize: 48},
+		{curve: crypto.X25519Curve, keyType: crypto.OKPKeyType, template: X25519KeyTemplate(), keySize: 32},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run("test key template "+tc.curve, func(t *testing.T) {
+			kh, err := keyset.NewHandle(tc.template)
+			require.NoError(t, err)
+
+			pubKey, err := PublicKey(kh)
+			require.NoError(t, err)
+			require.Equal(t, tc.curve, pubKey.Curve)
+			require.Equal(t, tc.keyType, pubKey.Type)
+			require.Len(t, pubKey.X, tc.keySize)
+
+			// public keyset handles give the same public key
+			pubKH, err := kh.Public()
+			require.NoError(t, err)
+
+			exported := new(bytes.Buffer)
+			require.NoError(t, pubKH.WriteWithNoSecrets(keyset.NewBinaryWriter(exported)))
+
+			importedKH, err := keyset.ReadWithNoSecrets(keyset.NewBinaryReader(exported))
+			require.NoError(t, err)
+
+			importedPubKey, err := PublicKey(importedKH)
+			require.NoError(t, err)
+			require.Equal(t, pubKey, importedPubKey)
+
+			// both keys of a key agreement compute the same shared secret
+			ps, err := kh.Primitives()
+			require.NoError(t, err)
+
+			ka, ok := ps.Primary.Primitive.(KeyAgreement)
+			require.True(t, ok)
+
+			other, err := NewKeyAgreement(tc.curve)
+			require.NoError(t, err)
+
+			z1, err := ka.ComputeZ(other.PublicKey())
+			require.NoError(t, err)
+
+			z2, err := other.ComputeZ(pubKey)
+			require.NoError(t, err)
+			require.Equal(t, z1, z2)
+		})
+	}
+
+	t.Run("test unsupported curve", func(t *testing.T) {
+		_, err := keyset.NewHandle(createKeyTemplate("P-521"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ecdhkw_private_key_manager: unsupported curve: 'P-521'")
+
+		_, err = NewKeyAgreement("P-521")
+		require.EqualError(t, err, "unsupported curve: 'P-521'")
+	})
+
+	t.Run("test public key of a non ECDH key handle", func(t *testing.T) {
+		kh, err := keyset.NewHandle(signature.ED25519KeyTemplate())
+		require.NoError(t, err)
+
+		_, err = PublicKey(kh)
+		require.EqualError(t, err, "not an ECDH key wrapping key handle")
+	})
+}
+
+func TestKeyAgreement_ComputeZ(t *testing.T) {
+	p256Key, err := NewKeyAgreement(crypto.P256Curve)
+	require.NoError(t, err)
+
+	p384Key, err := NewKeyAgreement(crypto.P384Curve)
+	require.NoError(t, err)
+
+	x25519Key, err := NewKeyAgreement(crypto.X25519Curve)
+	require.NoError(t, err)
+
+	_, err = p256Key.ComputeZ(x25519Key.PublicKey())
+	require.EqualError(t, err, "public key must be on curve P-256")
+
+	_, err = p256Key.ComputeZ(p384Key.PublicKey())
+	require.EqualError(t, err, "public key must be on curve P-256")
+
+	_, err = p384Key.ComputeZ(p256Key.PublicKey())
+	require.EqualError(t, err, "public key must be on curve P-384")
+
+	_, err = p384Key.ComputeZ(&crypto.PublicKey{X: []byte{1}, Y: []byte{1}, Curve: crypto.P384Curve})
+	require.EqualError(t, err, "invalid P-384 public key")
+
+	_, err = p256Key.ComputeZ(&crypto.PublicKey{X: []byte{1}, Y: []byte{1}, Curve: crypto.P256Curve})
+	require.EqualError(t, err, "invalid P-256 public key")
+
+	_, err = x25519Key.ComputeZ(p256Key.PublicKey())
+	require.EqualError(t, err, "public key must be on curve X25519")
+
+	_, err = x25519Key.ComputeZ(nil)
+	require.EqualError(t, err, "public key must be on curve X25519")
+
+	_, err = x25519Key.ComputeZ(&crypto.PublicKey{X: make([]byte, 32), Curve: crypto.X25519Curve})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid X25519 public key")
+}
+
+func TestKeyManagers(t *testing.T) {
+	privKM := new(privateKeyManager)
+	pubKM := new(publicKeyManager)
+
+	require.True(t, privKM.DoesSupport(PrivateKeyTypeURL))
+	require.False(t, privKM.DoesSupport(PublicKeyTypeURL))
+	require.Equal(t, PrivateKeyTypeURL, privKM.TypeURL())
+	require.True(t, pubKM.DoesSupport(PublicKeyTypeURL))
+	require.False(t, pubKM.DoesSupport(PrivateKeyTypeURL))
+	require.Equal(t, PublicKeyTypeURL, pubKM.TypeURL())
+
+	t.Run("test invalid private keys", func(t *testing.T) {
+		_, err := privKM.Primitive([]byte("bad key"))
+		require.EqualError(t, err, errInvalidPrivateKey.Error())
+
+		_, err = privKM.PublicKeyData([]byte("bad key"))
+		require.EqualError(t, err, errInvalidPrivateKey.Error())
+
+		_, err = privKM.NewKeyData([]byte("bad format"))
+		require.EqualError(t, err, errInvalidPrivateKeyFormat.Error())
+
+		key := &ecdhKwPrivateKey{Version: 1, PublicKey: &ecdhKwPublicKey{Curve: crypto.X25519Curve}}
+		_, err = privKM.Primitive(marshal(t, key))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ecdhkw_private_key_manager:")
+
+		key = &ecdhKwPrivateKey{PublicKey: &ecdhKwPublicKey{Curve: "P-521"}}
+		_, err = privKM.Primitive(marshal(t, key))
+		require.EqualError(t, err, "ecdhkw_private_key_manager: unsupported curve: 'P-521'")
+	})
+
+	t.Run("test invalid public keys", func(t *testing.T) {
+		_, err := pubKM.Primitive([]byte("bad key"))
+		require.EqualError(t, err, errInvalidPublicKey.Error())
+
+		_, err = pubKM.Primitive(marshal(t, &ecdhKwPublicKey{Version: 1, Curve: crypto.X25519Curve}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ecdhkw_public_key_manager:")
+
+		_, err = pubKM.Primitive(marshal(t, &ecdhKwPublicKey{Curve: "P-521"}))
+		require.EqualError(t, err, "ecdhkw_public_key_manager: unsupported curve: 'P-521'")
+
+		_, err = pubKM.NewKey(nil)
+		require.Error(t, err)
+
+		_, err = pubKM.NewKeyData(nil)
+		require.Error(t, err)
+	})
+
+	t.Run("test private key serialization", func(t *testing.T) {
+		keyData, err := privKM.NewKeyData(P256KeyTemplate().Value)
+		require.NoError(t, err)
+		require.Equal(t, tinkpb.KeyData_ASYMMETRIC_PRIVATE, keyData.KeyMaterialType)
+
+		key := new(ecdhKwPrivateKey)
+		require.NoError(t, proto.Unmarshal(keyData.Value, key))
+		require.Equal(t, crypto.P256Curve, key.PublicKey.Curve)
+		require.NotEmpty(t, key.KeyValue)
+
+		p, err := privKM.Primitive(keyData.Value)
+		require.NoError(t, err)
+
+		pubKeyData, err := privKM.PublicKeyData(keyData.Value)
+		require.NoError(t, err)
+		require.Equal(t, tinkpb.KeyData_ASYMMETRIC_PUBLIC, pubKeyData.KeyMaterialType)
+
+		pubKey, err := pubKM.Primitive(pubKeyData.Value)
+		require.NoError(t, err)
+		require.Equal(t, p.(KeyAgreement).PublicKey(), pubKey)
+	})
+}
+
+func marshal(t *testing.T, m proto.Message) []byte {
+	b, err := proto.Marshal(m)
+	require.NoError(t, err)
+
+	return b
+}
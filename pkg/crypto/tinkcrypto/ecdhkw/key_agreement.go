@@ -0,0 +1,172 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ecdhkw
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"golang.org/x/crypto/curve25519"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+)
+
+// KeyAgreement is the primitive of ECDH key wrapping private keys. It computes ECDH shared secrets without exposing
+// the private key.
+type KeyAgreement interface {
+	// PublicKey returns the public key matching the private key
+	PublicKey() *crypto.PublicKey
+	// ComputeZ computes the ECDH shared secret Z of the private key with pub
+	ComputeZ(pub *crypto.PublicKey) ([]byte, error)
+}
+
+// privateKeyAgreement gives access to the private key of the KeyAgreement implementations to serialize them.
+type privateKeyAgreement interface {
+	KeyAgreement
+	keyValue() []byte
+}
+
+// errUnsupportedCurve is returned for curves other than P-256, P-384 and X25519
+var errUnsupportedCurve = errors.New("unsupported curve")
+
+// NewKeyAgreement generates a new private key on curve (crypto.P256Curve, crypto.P384Curve or crypto.X25519Curve),
+// it is used to create ephemeral keys.
+func NewKeyAgreement(curve string) (KeyAgreement, error) {
+	return newKeyAgreement(curve)
+}
+
+func newKeyAgreement(curve string) (privateKeyAgreement, error) {
+	switch curve {
+	case crypto.P256Curve, crypto.P384Curve:
+		c := nistCurve(curve)
+
+		d, x, y, err := elliptic.GenerateKey(c, rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate key: %w", err)
+		}
+
+		return &nistKeyAgreement{d: d, pub: &crypto.PublicKey{
+			X:     padLeft(x.Bytes(), coordinateSize(c)),
+			Y:     padLeft(y.Bytes(), coordinateSize(c)),
+			Curve: curve,
+			Type:  crypto.ECKeyType,
+		}}, nil
+	case crypto.X25519Curve:
+		d := make([]byte, curve25519.ScalarSize)
+
+		if _, err := rand.Read(d); err != nil {
+			return nil, fmt.Errorf("failed to generate key: %w", err)
+		}
+
+		return newX25519KeyAgreement(d)
+	default:
+		return nil, fmt.Errorf("%w: '%s'", errUnsupportedCurve, curve)
+	}
+}
+
+// nistCurve returns the elliptic curve of the NIST curve name, it must be called with a supported curve.
+func nistCurve(curve string) elliptic.Curve {
+	if curve == crypto.P384Curve {
+		return elliptic.P384()
+	}
+
+	return elliptic.P256()
+}
+
+func coordinateSize(c elliptic.Curve) int {
+	const numBitsPerByte = 8
+
+	return (c.Params().BitSize + numBitsPerByte - 1) / numBitsPerByte
+}
+
+type nistKeyAgreement struct {
+	d   []byte
+	pub *crypto.PublicKey
+}
+
+func (k *nistKeyAgreement) keyValue() []byte {
+	return k.d
+}
+
+// PublicKey returns the public key matching the private key
+func (k *nistKeyAgreement) PublicKey() *crypto.PublicKey {
+	return k.pub
+}
+
+// ComputeZ computes the ECDH shared secret Z of the private key with pub
+func (k *nistKeyAgreement) ComputeZ(pub *crypto.PublicKey) ([]byte, error) {
+	if pub == nil || pub.Curve != k.pub.Curve {
+		return nil, fmt.Errorf("public key must be on curve %s", k.pub.Curve)
+	}
+
+	c := nistCurve(pub.Curve)
+	x, y := new(big.Int).SetBytes(pub.X), new(big.Int).SetBytes(pub.Y)
+
+	if !c.IsOnCurve(x, y) {
+		return nil, fmt.Errorf("invalid %s public key", pub.Curve)
+	}
+
+	zX, _ := c.ScalarMult(x, y, k.d)
+
+	return padLeft(zX.Bytes(), coordinateSize(c)), nil
+}
+
+type x25519KeyAgreement struct {
+	d   []byte
+	pub *crypto.PublicKey
+}
+
+func newX25519KeyAgreement(d []byte) (*x25519KeyAgreement, error) {
+	x, err := curve25519.X25519(d, curve25519.Basepoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid X25519 private key: %w", err)
+	}
+
+	return &x25519KeyAgreement{d: d, pub: &crypto.PublicKey{
+		X:     x,
+		Curve: crypto.X25519Curve,
+		Type:  crypto.OKPKeyType,
+	}}, nil
+}
+
+func (k *x25519KeyAgreement) keyValue() []byte {
+	return k.d
+}
+
+// PublicKey returns the public key matching the private key
+func (k *x25519KeyAgreement) PublicKey() *crypto.PublicKey {
+	return k.pub
+}
+
+// ComputeZ computes the ECDH shared secret Z of the private key with pub
+func (k *x25519KeyAgreement) ComputeZ(pub *crypto.PublicKey) ([]byte, error) {
+	if pub == nil || pub.Curve != crypto.X25519Curve {
+		return nil, errors.New("public key must be on curve X25519")
+	}
+
+	// X25519 rejects low order points resulting in an all zero shared secret
+	z, err := curve25519.X25519(k.d, pub.X)
+	if err != nil {
+		return nil, fmt.Errorf("invalid X25519 public key: %w", err)
+	}
+
+	return z, nil
+}
+
+func padLeft(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+
+	padded := make([]byte, size)
+	copy(padded[size-len(b):], b)
+
+	return padded
+}
@@ -0,0 +1,292 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	gocrypto "crypto"
+	"crypto/aes"
+	"crypto/rand"
+	_ "crypto/sha256" // register SHA-256 used by the Concat KDF
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/tink/go/keyset"
+	josecipher "github.com/square/go-jose/v3/cipher"
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/ecdhkw"
+)
+
+const kekSize = 32
+
+// WrapKey will wrap cek for recPubKey using ECDH-ES, or ECDH-1PU if the sender key handle is set with
+// crypto.WithSender(). NIST P-256 and P-384 keys use AES key wrap (A256KW), X25519 keys use XChaCha20Poly1305
+// (XC20PKW).
+func (t *Crypto) WrapKey(cek, apu, apv []byte, recPubKey *crypto.PublicKey,
+	opts ...crypto.WrapKeyOpts) (*crypto.RecipientWrappedKey, error) {
+	if recPubKey == nil {
+		return nil, errors.New("wrapKey: recipient public key is required")
+	}
+
+	pOpts := &crypto.WrapKeyOptions{}
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	epk, err := ecdhkw.NewKeyAgreement(recPubKey.Curve)
+	if err != nil {
+		return nil, fmt.Errorf("wrapKey: %w", err)
+	}
+
+	z, err := epk.ComputeZ(recPubKey)
+	if err != nil {
+		return nil, fmt.Errorf("wrapKey: %w", err)
+	}
+
+	alg := wrapAlg(recPubKey.Curve, pOpts.SenderKey != nil)
+
+	if pOpts.SenderKey != nil {
+		senderKey, e := keyAgreement(pOpts.SenderKey)
+		if e != nil {
+			return nil, fmt.Errorf("wrapKey: sender key: %w", e)
+		}
+
+		zs, e := senderKey.ComputeZ(recPubKey)
+		if e != nil {
+			return nil, fmt.Errorf("wrapKey: sender key: %w", e)
+		}
+
+		// ECDH-1PU shared secret is Ze || Zs
+		z = append(z, zs...)
+	}
+
+	kek := deriveKEK(alg, z, apu, apv)
+
+	wrappedKey, err := wrap(alg, kek, cek)
+	if err != nil {
+		return nil, fmt.Errorf("wrapKey: %w", err)
+	}
+
+	return &crypto.RecipientWrappedKey{
+		KID:          recPubKey.KID,
+		EncryptedCEK: wrappedKey,
+		EPK:          *epk.PublicKey(),
+		Alg:          alg,
+		APU:          apu,
+		APV:          apv,
+	}, nil
+}
+
+// UnwrapKey will unwrap the cek in recWK using the recipient private key handle kh. ECDH-1PU wrapped keys require the
+// sender public key set with crypto.WithSender(). All the keys of kh are tried to unwrap the key, this allows
+// unwrapping keys wrapped for a key of kh that was rotated.
+func (t *Crypto) UnwrapKey(recWK *crypto.RecipientWrappedKey, kh interface{},
+	opts ...crypto.WrapKeyOpts) ([]byte, error) {
+	if recWK == nil {
+		return nil, errors.New("unwrapKey: RecipientWrappedKey is required")
+	}
+
+	pOpts := &crypto.WrapKeyOptions{}
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	keyHandle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errors.New("unwrapKey: bad key handle format")
+	}
+
+	if recWK.Alg != wrapAlg(recWK.EPK.Curve, isECDH1PU(recWK.Alg)) {
+		return nil, fmt.Errorf("unwrapKey: unsupported algorithm '%s' for curve '%s'", recWK.Alg, recWK.EPK.Curve)
+	}
+
+	var senderPubKey *crypto.PublicKey
+
+	if isECDH1PU(recWK.Alg) {
+		senderPubKey, ok = pOpts.SenderKey.(*crypto.PublicKey)
+		if !ok {
+			return nil, errors.New("unwrapKey: sender public key is required for ECDH-1PU")
+		}
+	}
+
+	recKeys, err := keyAgreements(keyHandle)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapKey: %w", err)
+	}
+
+	for _, recKey := range recKeys {
+		cek, e := unwrapWithKey(recWK, recKey, senderPubKey)
+		if e == nil {
+			return cek, nil
+		}
+
+		err = e
+	}
+
+	return nil, fmt.Errorf("unwrapKey: %w", err)
+}
+
+func unwrapWithKey(recWK *crypto.RecipientWrappedKey, recKey ecdhkw.KeyAgreement,
+	senderPubKey *crypto.PublicKey) ([]byte, error) {
+	z, err := recKey.ComputeZ(&recWK.EPK)
+	if err != nil {
+		return nil, err
+	}
+
+	if senderPubKey != nil {
+		zs, e := recKey.ComputeZ(senderPubKey)
+		if e != nil {
+			return nil, fmt.Errorf("sender key: %w", e)
+		}
+
+		z = append(z, zs...)
+	}
+
+	kek := deriveKEK(recWK.Alg, z, recWK.APU, recWK.APV)
+
+	return unwrap(recWK.Alg, kek, recWK.EncryptedCEK)
+}
+
+func wrapAlg(curve string, ecdh1PU bool) string {
+	nist := curve == crypto.P256Curve || curve == crypto.P384Curve
+
+	switch {
+	case nist && ecdh1PU:
+		return crypto.ECDH1PUA256KWAlg
+	case nist:
+		return crypto.ECDHESA256KWAlg
+	case ecdh1PU:
+		return crypto.ECDH1PUXC20PKWAlg
+	default:
+		return crypto.ECDHESXC20PKWAlg
+	}
+}
+
+func isECDH1PU(alg string) bool {
+	return alg == crypto.ECDH1PUA256KWAlg || alg == crypto.ECDH1PUXC20PKWAlg
+}
+
+// keyAgreement returns the primary key agreement primitive of the private key handle kh.
+func keyAgreement(kh interface{}) (ecdhkw.KeyAgreement, error) {
+	keyHandle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errors.New("bad key handle format")
+	}
+
+	ps, err := keyHandle.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("get primitives: %w", err)
+	}
+
+	k, ok := ps.Primary.Primitive.(ecdhkw.KeyAgreement)
+	if !ok {
+		return nil, errors.New("not an ECDH key wrapping private key handle")
+	}
+
+	return k, nil
+}
+
+// keyAgreements returns all the key agreement primitives of the private key handle kh.
+func keyAgreements(kh *keyset.Handle) ([]ecdhkw.KeyAgreement, error) {
+	ps, err := kh.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("get primitives: %w", err)
+	}
+
+	var keys []ecdhkw.KeyAgreement
+
+	for _, entries := range ps.Entries {
+		for _, entry := range entries {
+			if k, ok := entry.Primitive.(ecdhkw.KeyAgreement); ok {
+				keys = append(keys, k)
+			}
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("not an ECDH key wrapping private key handle")
+	}
+
+	return keys, nil
+}
+
+// deriveKEK derives the key encryption key from the shared secret z with the Concat KDF as per
+// https://tools.ietf.org/html/rfc7518#section-4.6.2
+func deriveKEK(alg string, z, apu, apv []byte) []byte {
+	const numBitsPerByte = 8
+
+	supPubInfo := make([]byte, 4)
+	binary.BigEndian.PutUint32(supPubInfo, kekSize*numBitsPerByte)
+
+	reader := josecipher.NewConcatKDF(gocrypto.SHA256, z,
+		lengthPrefix([]byte(alg)), lengthPrefix(apu), lengthPrefix(apv), supPubInfo, []byte{})
+
+	kek := make([]byte, kekSize)
+
+	// the concat KDF reader never fails
+	_, _ = reader.Read(kek) //nolint:errcheck
+
+	return kek
+}
+
+func lengthPrefix(data []byte) []byte {
+	out := make([]byte, len(data)+4)
+	binary.BigEndian.PutUint32(out, uint32(len(data)))
+	copy(out[4:], data)
+
+	return out
+}
+
+func wrap(alg string, kek, cek []byte) ([]byte, error) {
+	if alg == crypto.ECDHESA256KWAlg || alg == crypto.ECDH1PUA256KWAlg {
+		block, err := aes.NewCipher(kek)
+		if err != nil {
+			return nil, fmt.Errorf("create key wrapping cipher: %w", err)
+		}
+
+		return josecipher.KeyWrap(block, cek)
+	}
+
+	aead, err := chacha20poly1305.NewX(kek)
+	if err != nil {
+		return nil, fmt.Errorf("create key wrapping cipher: %w", err)
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	// the nonce is prepended to the wrapped key
+	return aead.Seal(nonce, nonce, cek, nil), nil
+}
+
+func unwrap(alg string, kek, wrappedKey []byte) ([]byte, error) {
+	if alg == crypto.ECDHESA256KWAlg || alg == crypto.ECDH1PUA256KWAlg {
+		block, err := aes.NewCipher(kek)
+		if err != nil {
+			return nil, fmt.Errorf("create key wrapping cipher: %w", err)
+		}
+
+		return josecipher.KeyUnwrap(block, wrappedKey)
+	}
+
+	aead, err := chacha20poly1305.NewX(kek)
+	if err != nil {
+		return nil, fmt.Errorf("create key wrapping cipher: %w", err)
+	}
+
+	if len(wrappedKey) < aead.NonceSize() {
+		return nil, errors.New("invalid wrapped key")
+	}
+
+	return aead.Open(nil, wrappedKey[:aead.NonceSize()], wrappedKey[aead.NonceSize():], nil)
+}
@@ -0,0 +1,235 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+	josecipher "github.com/square/go-jose/v3/cipher"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/ecdhkw"
+)
+
+func TestCrypto_WrapUnwrapKey(t *testing.T) {
+	c := Crypto{}
+	cek := random(t, 32)
+	apu := []byte("sender")
+	apv := []byte("recipient")
+
+	tests := []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+		esAlg    string
+		onePUAlg string
+	}{
+		{name: "P-256", template: ecdhkw.P256KeyTemplate(), esAlg: crypto.ECDHESA256KWAlg,
+			onePUAlg: crypto.ECDH1PUA256KWAlg},
+		{name: "P-384", template: ecdhkw.P384KeyTemplate(), esAlg: crypto.ECDHESA256KWAlg,
+			onePUAlg: crypto.ECDH1PUA256KWAlg},
+		{name: "X25519", template: ecdhkw.X25519KeyTemplate(), esAlg: crypto.ECDHESXC20PKWAlg,
+			onePUAlg: crypto.ECDH1PUXC20PKWAlg},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		recKH, recPubKey := newKeyWrappingKey(t, tc.template)
+		recPubKey.KID = "recipient-kid"
+
+		senderKH, senderPubKey := newKeyWrappingKey(t, tc.template)
+
+		t.Run("test ECDH-ES key wrapping with "+tc.name, func(t *testing.T) {
+			wk, err := c.WrapKey(cek, apu, apv, recPubKey)
+			require.NoError(t, err)
+			require.Equal(t, tc.esAlg, wk.Alg)
+			require.Equal(t, "recipient-kid", wk.KID)
+			require.Equal(t, recPubKey.Curve, wk.EPK.Curve)
+			require.Equal(t, recPubKey.Type, wk.EPK.Type)
+			require.NotEqual(t, cek, wk.EncryptedCEK)
+
+			unwrapped, err := c.UnwrapKey(wk, recKH)
+			require.NoError(t, err)
+			require.Equal(t, cek, unwrapped)
+
+			// unwrapping with another key fails
+			_, err = c.UnwrapKey(wk, senderKH)
+			require.Error(t, err)
+
+			// unwrapping with altered party info fails
+			wk.APU = []byte("other sender")
+			_, err = c.UnwrapKey(wk, recKH)
+			require.Error(t, err)
+		})
+
+		t.Run("test ECDH-1PU key wrapping with "+tc.name, func(t *testing.T) {
+			wk, err := c.WrapKey(cek, apu, apv, recPubKey, crypto.WithSender(senderKH))
+			require.NoError(t, err)
+			require.Equal(t, tc.onePUAlg, wk.Alg)
+
+			unwrapped, err := c.UnwrapKey(wk, recKH, crypto.WithSender(senderPubKey))
+			require.NoError(t, err)
+			require.Equal(t, cek, unwrapped)
+
+			// the sender public key is mandatory
+			_, err = c.UnwrapKey(wk, recKH)
+			require.EqualError(t, err, "unwrapKey: sender public key is required for ECDH-1PU")
+
+			// unwrapping with another sender key fails
+			_, otherPubKey := newKeyWrappingKey(t, tc.template)
+			_, err = c.UnwrapKey(wk, recKH, crypto.WithSender(otherPubKey))
+			require.Error(t, err)
+
+			// ECDH-ES unwrapping fails
+			wk.Alg = tc.esAlg
+			_, err = c.UnwrapKey(wk, recKH)
+			require.Error(t, err)
+		})
+
+		t.Run("test unwrap key after recipient key rotation with "+tc.name, func(t *testing.T) {
+			wk, err := c.WrapKey(cek, apu, apv, recPubKey)
+			require.NoError(t, err)
+
+			km := keyset.NewManagerFromHandle(recKH)
+			require.NoError(t, km.Rotate(tc.template))
+
+			rotatedKH, err := km.Handle()
+			require.NoError(t, err)
+
+			rotatedPubKey, err := ecdhkw.PublicKey(rotatedKH)
+			require.NoError(t, err)
+			require.NotEqual(t, recPubKey.X, rotatedPubKey.X)
+
+			unwrapped, err := c.UnwrapKey(wk, rotatedKH)
+			require.NoError(t, err)
+			require.Equal(t, cek, unwrapped)
+		})
+	}
+
+	t.Run("test wrap key failures", func(t *testing.T) {
+		_, p256PubKey := newKeyWrappingKey(t, ecdhkw.P256KeyTemplate())
+		x25519KH, _ := newKeyWrappingKey(t, ecdhkw.X25519KeyTemplate())
+
+		_, err := c.WrapKey(cek, apu, apv, nil)
+		require.EqualError(t, err, "wrapKey: recipient public key is required")
+
+		_, err = c.WrapKey(cek, apu, apv, &crypto.PublicKey{Curve: "P-521"})
+		require.EqualError(t, err, "wrapKey: unsupported curve: 'P-521'")
+
+		_, err = c.WrapKey(cek, apu, apv, &crypto.PublicKey{
+			X: []byte{1}, Y: []byte{2}, Curve: crypto.P256Curve, Type: crypto.ECKeyType,
+		})
+		require.EqualError(t, err, "wrapKey: invalid P-256 public key")
+
+		_, err = c.WrapKey(cek, apu, apv, p256PubKey, crypto.WithSender("bad key handle"))
+		require.EqualError(t, err, "wrapKey: sender key: bad key handle format")
+
+		// sender and recipient keys must be on the same curve
+		_, err = c.WrapKey(cek, apu, apv, p256PubKey, crypto.WithSender(x25519KH))
+		require.EqualError(t, err, "wrapKey: sender key: public key must be on curve X25519")
+
+		aeadKH, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+		require.NoError(t, err)
+
+		_, err = c.WrapKey(cek, apu, apv, p256PubKey, crypto.WithSender(aeadKH))
+		require.EqualError(t, err, "wrapKey: sender key: not an ECDH key wrapping private key handle")
+
+		// AES key wrap requires a cek size multiple of 8 bytes
+		_, err = c.WrapKey([]byte("bad cek"), apu, apv, p256PubKey)
+		require.Error(t, err)
+	})
+
+	t.Run("test unwrap key failures", func(t *testing.T) {
+		recKH, recPubKey := newKeyWrappingKey(t, ecdhkw.X25519KeyTemplate())
+
+		wk, err := c.WrapKey(cek, apu, apv, recPubKey)
+		require.NoError(t, err)
+
+		_, err = c.UnwrapKey(nil, recKH)
+		require.EqualError(t, err, "unwrapKey: RecipientWrappedKey is required")
+
+		_, err = c.UnwrapKey(wk, "bad key handle")
+		require.EqualError(t, err, "unwrapKey: bad key handle format")
+
+		aeadKH, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+		require.NoError(t, err)
+
+		_, err = c.UnwrapKey(wk, aeadKH)
+		require.EqualError(t, err, "unwrapKey: not an ECDH key wrapping private key handle")
+
+		badAlgWK := *wk
+		badAlgWK.Alg = crypto.ECDHESA256KWAlg
+		_, err = c.UnwrapKey(&badAlgWK, recKH)
+		require.EqualError(t, err, "unwrapKey: unsupported algorithm 'ECDH-ES+A256KW' for curve 'X25519'")
+
+		badCEKWK := *wk
+		badCEKWK.EncryptedCEK = []byte("short")
+		_, err = c.UnwrapKey(&badCEKWK, recKH)
+		require.EqualError(t, err, "unwrapKey: invalid wrapped key")
+
+		// low order X25519 point
+		badEPKWK := *wk
+		badEPKWK.EPK = crypto.PublicKey{X: make([]byte, 32), Curve: crypto.X25519Curve, Type: crypto.OKPKeyType}
+		_, err = c.UnwrapKey(&badEPKWK, recKH)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid X25519 public key")
+	})
+}
+
+func TestDeriveKEK(t *testing.T) {
+	// ECDH-ES key derivation must match the go-jose implementation of RFC7518
+	recKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ephemeralKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	zX, _ := elliptic.P256().ScalarMult(recKey.X, recKey.Y, ephemeralKey.D.Bytes())
+	z := make([]byte, 32)
+	zBytes := zX.Bytes()
+	copy(z[32-len(zBytes):], zBytes)
+
+	expected := josecipher.DeriveECDHES(crypto.ECDHESA256KWAlg, []byte("apu"), []byte("apv"), ephemeralKey,
+		&recKey.PublicKey, kekSize)
+
+	require.Equal(t, expected, deriveKEK(crypto.ECDHESA256KWAlg, z, []byte("apu"), []byte("apv")))
+
+	// the shared secret is the same from the recipient side
+	zX2, _ := elliptic.P256().ScalarMult(ephemeralKey.X, ephemeralKey.Y, recKey.D.Bytes())
+	require.Equal(t, 0, zX.Cmp(zX2))
+	require.Equal(t, 0, new(big.Int).SetBytes(z).Cmp(zX2))
+}
+
+func newKeyWrappingKey(t *testing.T, template *tinkpb.KeyTemplate) (*keyset.Handle, *crypto.PublicKey) {
+	kh, err := keyset.NewHandle(template)
+	require.NoError(t, err)
+
+	pubKH, err := kh.Public()
+	require.NoError(t, err)
+
+	pubKey, err := ecdhkw.PublicKey(pubKH)
+	require.NoError(t, err)
+
+	return kh, pubKey
+}
+
+func random(t *testing.T, size int) []byte {
+	b := make([]byte, size)
+
+	_, err := rand.Read(b)
+	require.NoError(t, err)
+
+	return b
+}
@@ -7,8 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package service
 
 import (
+	"crypto/elliptic"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
+	"strings"
 
+	"github.com/btcsuite/btcutil/base58"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
 	diddoc "github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 )
@@ -16,16 +24,45 @@ import (
 // Destination provides the recipientKeys, routingKeys, and serviceEndpoint for an outbound message.
 // Can be populated from an Invitation or DIDDoc.
 type Destination struct {
+	// RecipientKeys are base58 encoded, the NIST keys are base58 encoded JSON marshalled crypto.PublicKey
+	// (see CreateDestination). The same encoding is used for the sender key and the forward message recipient.
 	RecipientKeys        []string
 	ServiceEndpoint      string
 	RoutingKeys          []string
 	TransportReturnRoute string
+	// Alternatives are the lower priority endpoints of the recipient sorted by priority,
+	// the message is sent to them if the delivery to the ServiceEndpoint fails.
+	Alternatives []*Destination
+}
+
+// Endpoints returns the destination followed by its alternatives in the order the delivery
+// should be attempted. The returned destinations don't have alternatives.
+func (d *Destination) Endpoints() []*Destination {
+	primary := *d
+	primary.Alternatives = nil
+
+	endpoints := []*Destination{&primary}
+
+	for _, alt := range d.Alternatives {
+		dest := *alt
+		dest.Alternatives = nil
+
+		if dest.TransportReturnRoute == "" {
+			dest.TransportReturnRoute = d.TransportReturnRoute
+		}
+
+		endpoints = append(endpoints, &dest)
+	}
+
+	return endpoints
 }
 
 const (
 	didCommServiceType = "did-communication"
 	// TODO: hardcoded key type https://github.com/hyperledger/aries-framework-go/issues/1008
 	ed25519KeyType = "Ed25519VerificationKey2018"
+	p256KeyType    = "EcdsaSecp256r1VerificationKey2019"
+	p384KeyType    = "EcdsaSecp384r1VerificationKey2019"
 )
 
 // GetDestination constructs a Destination struct based on the given DID and parameters
@@ -39,21 +76,164 @@ func GetDestination(did string, vdr vdri.Registry) (*Destination, error) {
 	return CreateDestination(didDoc)
 }
 
-// CreateDestination makes a DIDComm Destination object from a DID Doc
+// CreateDestination makes a DIDComm Destination object from a DID Doc. The highest priority DIDComm service
+// with the Ed25519 recipient keys is the primary endpoint, the other ones are the alternatives.
+// Services without Ed25519 recipient keys use their NIST P-256 or P-384 recipient keys, the curve is chosen from the
+// key type of the first one (see nistRecipientKeys).
 func CreateDestination(didDoc *diddoc.Doc) (*Destination, error) {
-	didCommService, ok := diddoc.LookupService(didDoc, didCommServiceType)
-	if !ok {
+	services := diddoc.LookupServices(didDoc, didCommServiceType)
+	if len(services) == 0 {
 		return nil, fmt.Errorf("create destination: missing DID doc service")
 	}
 
-	recipientKeys, ok := diddoc.LookupRecipientKeys(didDoc, didCommServiceType, ed25519KeyType)
-	if !ok {
+	var dest *Destination
+
+	for _, didCommService := range services {
+		recipientKeys, ok := diddoc.LookupServiceRecipientKeys(didDoc, didCommService, ed25519KeyType)
+		if !ok {
+			recipientKeys, ok = nistRecipientKeys(didDoc, didCommService)
+		}
+
+		if !ok {
+			continue
+		}
+
+		endpoint := &Destination{
+			RecipientKeys:   recipientKeys,
+			ServiceEndpoint: didCommService.ServiceEndpoint,
+			RoutingKeys:     didCommService.RoutingKeys,
+		}
+
+		if dest == nil {
+			dest = endpoint
+			continue
+		}
+
+		dest.Alternatives = append(dest.Alternatives, endpoint)
+	}
+
+	if dest == nil {
 		return nil, fmt.Errorf("create destination: missing keys")
 	}
 
-	return &Destination{
-		RecipientKeys:   recipientKeys,
-		ServiceEndpoint: didCommService.ServiceEndpoint,
-		RoutingKeys:     didCommService.RoutingKeys,
-	}, nil
+	return dest, nil
+}
+
+// nistRecipientKeys returns the recipient keys of service having the NIST key type of its first NIST recipient key.
+// The keys are base58 encoded JSON marshalled crypto.PublicKey as expected by the authcrypt packer, their KID is the
+// fragment of the DID doc key ID which must be the ID of the recipient key in its KMS.
+func nistRecipientKeys(didDoc *diddoc.Doc, service *diddoc.Service) ([]string, bool) {
+	var (
+		recipientKeys []string
+		keyType       string
+	)
+
+	for _, keyID := range service.RecipientKeys {
+		key, ok := diddoc.LookupPublicKey(keyID, didDoc)
+		if !ok {
+			return nil, false
+		}
+
+		if keyType == "" && nistCurve(key.Type) != nil {
+			keyType = key.Type
+		}
+
+		if key.Type != keyType {
+			continue
+		}
+
+		pubKey, err := nistPublicKey(key)
+		if err != nil {
+			return nil, false
+		}
+
+		recipientKeys = append(recipientKeys, base58.Encode(pubKey))
+	}
+
+	return recipientKeys, len(recipientKeys) > 0
+}
+
+// nistCurve returns the curve of the NIST key type or nil if keyType is not a NIST key type.
+func nistCurve(keyType string) elliptic.Curve {
+	switch keyType {
+	case p256KeyType:
+		return elliptic.P256()
+	case p384KeyType:
+		return elliptic.P384()
+	default:
+		return nil
+	}
+}
+
+// nistPublicKey returns the JSON marshalled crypto.PublicKey of the DID doc key, its value is a compressed or
+// uncompressed point on the curve of the key type.
+func nistPublicKey(key *diddoc.PublicKey) ([]byte, error) {
+	curve := nistCurve(key.Type)
+
+	x, y, err := unmarshalPoint(curve, key.Value)
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", key.ID, err)
+	}
+
+	// the coordinates have the fixed size used by the KMS public keys, the packed and unpacked keys are the same
+	size := (curve.Params().BitSize + 7) / 8
+
+	return json.Marshal(&crypto.PublicKey{
+		KID:   key.ID[strings.LastIndex(key.ID, "#")+1:],
+		X:     padLeft(x.Bytes(), size),
+		Y:     padLeft(y.Bytes(), size),
+		Curve: curve.Params().Name,
+		Type:  crypto.ECKeyType,
+	})
+}
+
+// padLeft returns b left padded with zeros to size bytes.
+func padLeft(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+
+	padded := make([]byte, size)
+	copy(padded[size-len(b):], b)
+
+	return padded
+}
+
+// unmarshalPoint converts a compressed or uncompressed point on curve into its coordinates.
+func unmarshalPoint(curve elliptic.Curve, point []byte) (*big.Int, *big.Int, error) {
+	const (
+		compressedEven = 2
+		compressedOdd  = 3
+	)
+
+	params := curve.Params()
+	byteLen := (params.BitSize + 7) / 8
+
+	if len(point) != 1+byteLen || (point[0] != compressedEven && point[0] != compressedOdd) {
+		x, y := elliptic.Unmarshal(curve, point)
+		if x == nil {
+			return nil, nil, errors.New("invalid point")
+		}
+
+		return x, y, nil
+	}
+
+	// y² = x³ - 3x + b
+	x := new(big.Int).SetBytes(point[1:])
+	y2 := new(big.Int).Exp(x, big.NewInt(3), params.P)
+	y2.Sub(y2, new(big.Int).Lsh(x, 1))
+	y2.Sub(y2, x)
+	y2.Add(y2, params.B)
+	y2.Mod(y2, params.P)
+
+	y := new(big.Int).ModSqrt(y2, params.P)
+	if x.Cmp(params.P) >= 0 || y == nil {
+		return nil, nil, errors.New("invalid point")
+	}
+
+	if y.Bit(0) != uint(point[0]&1) {
+		y.Sub(params.P, y)
+	}
+
+	return x, y, nil
 }
@@ -6,8 +6,11 @@ SPDX-License-Identifier: Apache-2.0
 package service
 
 import (
+	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"testing"
@@ -16,6 +19,7 @@ import (
 	"github.com/btcsuite/btcutil/base58"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	mockdiddoc "github.com/hyperledger/aries-framework-go/pkg/internal/mock/diddoc"
 	mockvdri "github.com/hyperledger/aries-framework-go/pkg/internal/mock/vdri"
@@ -91,6 +95,147 @@ func TestPrepareDestination(t *testing.T) {
 	})
 }
 
+func TestCreateDestinationAlternatives(t *testing.T) {
+	t.Run("services are sorted by priority", func(t *testing.T) {
+		doc := createDIDDoc()
+		doc.Service = append(doc.Service,
+			did.Service{
+				ID:              doc.ID + "#endpoint-3",
+				Type:            "did-communication",
+				ServiceEndpoint: "ws://localhost:58418",
+				Priority:        2,
+				RecipientKeys:   doc.Service[0].RecipientKeys,
+			},
+			did.Service{
+				ID:              doc.ID + "#endpoint-2",
+				Type:            "did-communication",
+				ServiceEndpoint: "http://localhost:58417",
+				Priority:        1,
+				RecipientKeys:   doc.Service[0].RecipientKeys,
+				RoutingKeys:     []string{"routingKey"},
+			},
+		)
+
+		dest, err := CreateDestination(doc)
+		require.NoError(t, err)
+		require.Equal(t, "http://localhost:58416", dest.ServiceEndpoint)
+		require.Len(t, dest.Alternatives, 2)
+		require.Equal(t, "http://localhost:58417", dest.Alternatives[0].ServiceEndpoint)
+		require.Equal(t, []string{"routingKey"}, dest.Alternatives[0].RoutingKeys)
+		require.Equal(t, "ws://localhost:58418", dest.Alternatives[1].ServiceEndpoint)
+
+		dest.TransportReturnRoute = "all"
+
+		endpoints := dest.Endpoints()
+		require.Len(t, endpoints, 3)
+		require.Equal(t, "http://localhost:58416", endpoints[0].ServiceEndpoint)
+		require.Equal(t, "http://localhost:58417", endpoints[1].ServiceEndpoint)
+		require.Equal(t, "ws://localhost:58418", endpoints[2].ServiceEndpoint)
+
+		for _, endpoint := range endpoints {
+			require.Empty(t, endpoint.Alternatives)
+			require.Equal(t, "all", endpoint.TransportReturnRoute)
+		}
+	})
+
+	t.Run("services without Ed25519 keys are skipped", func(t *testing.T) {
+		doc := mockdiddoc.GetMockDIDDoc()
+		doc.Service[0].Priority = 2
+
+		dest, err := CreateDestination(doc)
+		require.NoError(t, err)
+		require.Equal(t, "https://localhost:8090", dest.ServiceEndpoint)
+		require.Empty(t, dest.Alternatives)
+	})
+
+	t.Run("destination without alternatives", func(t *testing.T) {
+		dest := &Destination{ServiceEndpoint: "http://localhost:58416"}
+		require.Equal(t, []*Destination{dest}, dest.Endpoints())
+	})
+}
+
+func TestCreateDestinationNISTKeys(t *testing.T) {
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	doc := createDIDDoc()
+	doc.PublicKey = []did.PublicKey{
+		{
+			ID:    doc.ID + "#p256-compressed",
+			Type:  "EcdsaSecp256r1VerificationKey2019",
+			Value: compressPoint(p256Key),
+		},
+		{
+			ID:    doc.ID + "#p384",
+			Type:  "EcdsaSecp384r1VerificationKey2019",
+			Value: elliptic.Marshal(elliptic.P384(), p384Key.X, p384Key.Y),
+		},
+		{
+			ID:    doc.ID + "#p256",
+			Type:  "EcdsaSecp256r1VerificationKey2019",
+			Value: elliptic.Marshal(elliptic.P256(), p256Key.X, p256Key.Y),
+		},
+	}
+	doc.Service[0].RecipientKeys = []string{doc.PublicKey[0].ID, doc.PublicKey[1].ID, doc.PublicKey[2].ID}
+
+	t.Run("recipient keys have the curve of the first NIST key type", func(t *testing.T) {
+		dest, err := CreateDestination(doc)
+		require.NoError(t, err)
+		require.Len(t, dest.RecipientKeys, 2)
+
+		for i, kid := range []string{"p256-compressed", "p256"} {
+			pubKey := &crypto.PublicKey{}
+			require.NoError(t, json.Unmarshal(base58.Decode(dest.RecipientKeys[i]), pubKey))
+			require.Equal(t, &crypto.PublicKey{
+				KID:   kid,
+				X:     padLeft(p256Key.X.Bytes(), 32),
+				Y:     padLeft(p256Key.Y.Bytes(), 32),
+				Curve: crypto.P256Curve,
+				Type:  crypto.ECKeyType,
+			}, pubKey)
+		}
+	})
+
+	t.Run("P-384 recipient keys", func(t *testing.T) {
+		p384Doc := *doc
+		p384Doc.Service = []did.Service{doc.Service[0]}
+		p384Doc.Service[0].RecipientKeys = []string{doc.PublicKey[1].ID}
+
+		dest, err := CreateDestination(&p384Doc)
+		require.NoError(t, err)
+		require.Len(t, dest.RecipientKeys, 1)
+
+		pubKey := &crypto.PublicKey{}
+		require.NoError(t, json.Unmarshal(base58.Decode(dest.RecipientKeys[0]), pubKey))
+		require.Equal(t, crypto.P384Curve, pubKey.Curve)
+		require.Equal(t, padLeft(p384Key.X.Bytes(), 48), pubKey.X)
+		require.Equal(t, padLeft(p384Key.Y.Bytes(), 48), pubKey.Y)
+	})
+
+	t.Run("service with an invalid NIST key is skipped", func(t *testing.T) {
+		badDoc := *doc
+		badDoc.PublicKey = append([]did.PublicKey{}, doc.PublicKey...)
+		badDoc.PublicKey[0].Value = []byte("bad key")
+
+		_, err := CreateDestination(&badDoc)
+		require.EqualError(t, err, "create destination: missing keys")
+	})
+}
+
+// compressPoint returns the compressed form of the public key point
+func compressPoint(key *ecdsa.PrivateKey) []byte {
+	byteLen := (key.Curve.Params().BitSize + 7) / 8
+	point := make([]byte, 1+byteLen)
+	point[0] = byte(2 + key.Y.Bit(0))
+	x := key.X.Bytes()
+	copy(point[len(point)-len(x):], x)
+
+	return point
+}
+
 func createDIDDoc() *did.Doc {
 	pubKey, _ := generateKeyPair()
 	return createDIDDocWithKey(pubKey)
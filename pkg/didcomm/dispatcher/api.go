@@ -36,4 +36,7 @@ type Outbound interface {
 
 	// Forward forwards the message without packing to the destination.
 	Forward(interface{}, *service.Destination) error
+
+	// DeliveryNotifier notifies about the delivery status of the messages queued for redelivery.
+	DeliveryNotifier
 }
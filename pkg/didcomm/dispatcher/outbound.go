@@ -10,10 +10,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/btcsuite/btcutil/base58"
 	"github.com/google/uuid"
 
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	commontransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
@@ -21,8 +23,11 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
 
+var logger = log.New("aries-framework/dispatcher")
+
 // provider interface for outbound ctx
 type provider interface {
 	Packager() commontransport.Packager
@@ -30,6 +35,8 @@ type provider interface {
 	TransportReturnRoute() string
 	VDRIRegistry() vdri.Registry
 	LegacyKMS() legacykms.KeyManager
+	StorageProvider() storage.Provider
+	OutboundRetryPolicy() *RetryPolicy
 }
 
 // OutboundDispatcher dispatch msgs to destination
@@ -39,17 +46,42 @@ type OutboundDispatcher struct {
 	transportReturnRoute string
 	vdRegistry           vdri.Registry
 	kms                  legacykms.KeyManager
+	outbox               *outbox
+}
+
+// messageMeta contains the fields of the outbound message used by the outbox.
+type messageMeta struct {
+	ID     string            `json:"@id,omitempty"`
+	Timing *decorator.Timing `json:"~timing,omitempty"`
 }
 
-// NewOutbound return new dispatcher outbound instance
-func NewOutbound(prov provider) *OutboundDispatcher {
-	return &OutboundDispatcher{
+// NewOutbound return new dispatcher outbound instance. If the retry policy is provided, the messages which
+// failed to be sent are persisted and redelivered according to the policy.
+func NewOutbound(prov provider) (*OutboundDispatcher, error) {
+	o := &OutboundDispatcher{
 		outboundTransports:   prov.OutboundTransports(),
 		packager:             prov.Packager(),
 		transportReturnRoute: prov.TransportReturnRoute(),
 		vdRegistry:           prov.VDRIRegistry(),
 		kms:                  prov.LegacyKMS(),
 	}
+
+	policy := prov.OutboundRetryPolicy()
+	if policy == nil {
+		return o, nil
+	}
+
+	store, err := prov.StorageProvider().OpenStore(OutboxStore)
+	if err != nil {
+		return nil, fmt.Errorf("open outbox store : %w", err)
+	}
+
+	o.outbox, err = newOutbox(store, policy, o.sendPacked)
+	if err != nil {
+		return nil, err
+	}
+
+	return o, nil
 }
 
 // SendToDID sends a message from myDID to the agent who owns theirDID
@@ -72,77 +104,202 @@ func (o *OutboundDispatcher) SendToDID(msg interface{}, myDID, theirDID string)
 	return o.Send(msg, key, dest)
 }
 
-// Send sends the message after packing with the sender key and recipient keys.
+// Send sends the message after packing with the sender key and recipient keys. The message is sent to the
+// first endpoint of the destination (Destination.Endpoints) which is reachable by any of the outbound transports
+// accepting it. If the retry policy is configured, the message which failed to be sent is queued for redelivery
+// and the delivery status is notified through the delivery events.
 func (o *OutboundDispatcher) Send(msg interface{}, senderVerKey string, des *service.Destination) error {
-	for _, v := range o.outboundTransports {
-		// check if outbound accepts routing keys, else use recipient keys
-		keys := des.RecipientKeys
-		if len(des.RoutingKeys) != 0 {
-			keys = des.RoutingKeys
-		}
+	req, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed marshal to bytes: %w", err)
+	}
 
-		if !v.AcceptRecipient(keys) {
-			if !v.Accept(des.ServiceEndpoint) {
-				continue
-			}
-		}
+	meta := &messageMeta{}
 
-		req, err := json.Marshal(msg)
-		if err != nil {
-			return fmt.Errorf("failed marshal to bytes: %w", err)
-		}
+	// the message may not be a JSON object (e.g. plain text)
+	if e := json.Unmarshal(req, meta); e != nil {
+		meta = &messageMeta{}
+	}
+
+	if meta.Timing != nil && !meta.Timing.ExpiresTime.IsZero() && time.Now().After(meta.Timing.ExpiresTime) {
+		return ErrMessageExpired
+	}
+
+	// update the outbound message with transport return route option [all or thread]
+	req, err = o.addTransportRouteOptions(req)
+	if err != nil {
+		return fmt.Errorf("add transport route options : %w", err)
+	}
+
+	// the message is packed only for the endpoints the delivery is attempted to
+	var (
+		deliveries []*delivery
+		sendErr    error
+	)
 
-		// update the outbound message with transport return route option [all or thread]
-		req, err = o.addTransportRouteOptions(req)
-		if err != nil {
-			return fmt.Errorf("add transport route options : %w", err)
+	for _, endpoint := range des.Endpoints() {
+		if !o.hasTransport(endpoint) {
+			continue
 		}
 
-		packedMsg, err := o.packager.PackMessage(
-			&commontransport.Envelope{Message: req, FromVerKey: base58.Decode(senderVerKey), ToVerKeys: des.RecipientKeys})
-		if err != nil {
-			return fmt.Errorf("failed to pack msg: %w", err)
+		d, e := o.pack(req, senderVerKey, endpoint)
+		if e != nil {
+			return e
 		}
 
-		// set the return route option
-		des.TransportReturnRoute = o.transportReturnRoute
+		deliveries = append(deliveries, d)
 
-		packedMsg, err = o.createForwardMessage(packedMsg, des)
-		if err != nil {
-			return fmt.Errorf("create forward msg : %w", err)
+		sendErr = o.sendPacked([]*delivery{d})
+		if sendErr == nil {
+			return nil
 		}
+	}
 
-		_, err = v.Send(packedMsg, des)
-		if err != nil {
-			return fmt.Errorf("failed to send msg using outbound transport: %w", err)
-		}
+	if len(deliveries) == 0 {
+		return fmt.Errorf("no outbound transport found for serviceEndpoint: %s", des.ServiceEndpoint)
+	}
 
-		return nil
+	if o.outbox == nil {
+		return sendErr
 	}
 
-	return fmt.Errorf("no outbound transport found for serviceEndpoint: %s", des.ServiceEndpoint)
+	return o.outbox.queue(deliveries, des, meta, sendErr)
 }
 
-// Forward forwards the message without packing to the destination.
-func (o *OutboundDispatcher) Forward(msg interface{}, des *service.Destination) error {
-	for _, v := range o.outboundTransports {
-		if !v.AcceptRecipient(des.RecipientKeys) {
-			if !v.Accept(des.ServiceEndpoint) {
+// pack packs the message for the endpoint, the message is wrapped in the forward message if the endpoint
+// has the routing keys. The endpoint of the returned delivery has the return route option of the dispatcher.
+func (o *OutboundDispatcher) pack(req []byte, senderVerKey string, des *service.Destination) (*delivery, error) {
+	packedMsg, err := o.packager.PackMessage(
+		&commontransport.Envelope{Message: req, FromVerKey: base58.Decode(senderVerKey), ToVerKeys: des.RecipientKeys})
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack msg: %w", err)
+	}
+
+	packedMsg, err = o.createForwardMessage(packedMsg, des)
+	if err != nil {
+		return nil, fmt.Errorf("create forward msg : %w", err)
+	}
+
+	endpoint := *des
+	endpoint.TransportReturnRoute = o.transportReturnRoute
+
+	return &delivery{Message: packedMsg, Destination: &endpoint}, nil
+}
+
+// sendPacked sends the packed message to the endpoints in the given order until the message is delivered.
+// Every outbound transport accepting the endpoint is tried before failing over to the next endpoint.
+func (o *OutboundDispatcher) sendPacked(deliveries []*delivery) error {
+	var sendErr error
+
+	for _, d := range deliveries {
+		for _, v := range o.outboundTransports {
+			if !o.accept(v, d.Destination) {
 				continue
 			}
+
+			_, err := v.Send(d.Message, d.Destination)
+			if err != nil {
+				sendErr = fmt.Errorf("failed to send msg using outbound transport: %w", err)
+
+				logger.Debugf("send to serviceEndpoint %s : %s", d.Destination.ServiceEndpoint, err)
+
+				continue
+			}
+
+			return nil
 		}
+	}
+
+	if sendErr != nil {
+		return sendErr
+	}
+
+	return fmt.Errorf("no outbound transport found for serviceEndpoint: %s", deliveries[0].Destination.ServiceEndpoint)
+}
 
-		req, err := json.Marshal(msg)
-		if err != nil {
-			return fmt.Errorf("failed marshal to bytes: %w", err)
+// hasTransport checks whether any outbound transport can send the message to the destination.
+func (o *OutboundDispatcher) hasTransport(des *service.Destination) bool {
+	for _, v := range o.outboundTransports {
+		if o.accept(v, des) {
+			return true
 		}
+	}
+
+	return false
+}
+
+// accept checks whether the outbound transport can send the message to the destination.
+func (o *OutboundDispatcher) accept(v transport.OutboundTransport, des *service.Destination) bool {
+	// check if outbound accepts routing keys, else use recipient keys
+	keys := des.RecipientKeys
+	if len(des.RoutingKeys) != 0 {
+		keys = des.RoutingKeys
+	}
 
-		_, err = v.Send(req, des)
-		if err != nil {
-			return fmt.Errorf("failed to send msg using outbound transport: %w", err)
+	return v.AcceptRecipient(keys) || v.Accept(des.ServiceEndpoint)
+}
+
+// RegisterDeliveryEvent registers the channel for the delivery events of the queued messages. The events are
+// sent only if the retry policy is configured, they are dropped while the channel is full.
+func (o *OutboundDispatcher) RegisterDeliveryEvent(ch chan<- DeliveryEvent) error {
+	if o.outbox == nil {
+		return ErrOutboxNotConfigured
+	}
+
+	return o.outbox.registerEvent(ch)
+}
+
+// UnregisterDeliveryEvent unregisters the channel for the delivery events. Refer RegisterDeliveryEvent().
+func (o *OutboundDispatcher) UnregisterDeliveryEvent(ch chan<- DeliveryEvent) error {
+	if o.outbox == nil {
+		return ErrOutboxNotConfigured
+	}
+
+	o.outbox.unregisterEvent(ch)
+
+	return nil
+}
+
+// Close stops the redelivery of the queued messages, the messages are redelivered after the restart.
+func (o *OutboundDispatcher) Close() error {
+	if o.outbox != nil {
+		o.outbox.close()
+	}
+
+	return nil
+}
+
+// Forward forwards the message without packing to the destination, failing over across the endpoints
+// of the destination like Send. The message is not queued for redelivery, the router stores the messages
+// for the recipients which are not reachable.
+func (o *OutboundDispatcher) Forward(msg interface{}, des *service.Destination) error {
+	req, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed marshal to bytes: %w", err)
+	}
+
+	var sendErr error
+
+	for _, endpoint := range des.Endpoints() {
+		for _, v := range o.outboundTransports {
+			if !v.AcceptRecipient(endpoint.RecipientKeys) {
+				if !v.Accept(endpoint.ServiceEndpoint) {
+					continue
+				}
+			}
+
+			_, err = v.Send(req, endpoint)
+			if err != nil {
+				sendErr = fmt.Errorf("failed to send msg using outbound transport: %w", err)
+				continue
+			}
+
+			return nil
 		}
+	}
 
-		return nil
+	if sendErr != nil {
+		return sendErr
 	}
 
 	return fmt.Errorf("no outbound transport found for serviceEndpoint: %s", des.ServiceEndpoint)
@@ -7,66 +7,89 @@ SPDX-License-Identifier: Apache-2.0
 package dispatcher
 
 import (
+	"crypto/elliptic"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
+	"strings"
 	"testing"
 
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/google/tink/go/keyset"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/ecdhkw"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	commontransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packager"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/jwe/authcrypt"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	mockdidcomm "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm"
 	mockpackager "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/packager"
 	mockdiddoc "github.com/hyperledger/aries-framework-go/pkg/internal/mock/diddoc"
 	mockvdri "github.com/hyperledger/aries-framework-go/pkg/internal/mock/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
 )
 
 func TestOutboundDispatcher_Send(t *testing.T) {
 	t.Run("test success", func(t *testing.T) {
-		o := NewOutbound(&mockProvider{
+		o, err := NewOutbound(&mockProvider{
 			packagerValue:           &mockpackager.Packager{},
 			outboundTransportsValue: []transport.OutboundTransport{&mockdidcomm.MockOutboundTransport{AcceptValue: true}},
 		})
+		require.NoError(t, err)
 		require.NoError(t, o.Send("data", "", &service.Destination{ServiceEndpoint: "url"}))
 	})
 
 	t.Run("test no outbound transport found", func(t *testing.T) {
-		o := NewOutbound(&mockProvider{packagerValue: &mockpackager.Packager{},
+		o, err := NewOutbound(&mockProvider{packagerValue: &mockpackager.Packager{},
 			outboundTransportsValue: []transport.OutboundTransport{&mockdidcomm.MockOutboundTransport{AcceptValue: false}}})
-		err := o.Send("data", "", &service.Destination{ServiceEndpoint: "url"})
+		require.NoError(t, err)
+		err = o.Send("data", "", &service.Destination{ServiceEndpoint: "url"})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "no outbound transport found for serviceEndpoint: url")
 	})
 
 	t.Run("test pack msg failure", func(t *testing.T) {
-		o := NewOutbound(&mockProvider{packagerValue: &mockpackager.Packager{PackErr: fmt.Errorf("pack error")},
+		o, err := NewOutbound(&mockProvider{packagerValue: &mockpackager.Packager{PackErr: fmt.Errorf("pack error")},
 			outboundTransportsValue: []transport.OutboundTransport{&mockdidcomm.MockOutboundTransport{AcceptValue: true}}})
-		err := o.Send("data", "", &service.Destination{ServiceEndpoint: "url"})
+		require.NoError(t, err)
+		err = o.Send("data", "", &service.Destination{ServiceEndpoint: "url"})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "pack error")
 	})
 
 	t.Run("test outbound send failure", func(t *testing.T) {
-		o := NewOutbound(&mockProvider{packagerValue: &mockpackager.Packager{},
+		o, err := NewOutbound(&mockProvider{packagerValue: &mockpackager.Packager{},
 			outboundTransportsValue: []transport.OutboundTransport{
 				&mockdidcomm.MockOutboundTransport{AcceptValue: true, SendErr: fmt.Errorf("send error")}}})
-		err := o.Send("data", "", &service.Destination{ServiceEndpoint: "url"})
+		require.NoError(t, err)
+		err = o.Send("data", "", &service.Destination{ServiceEndpoint: "url"})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "send error")
 	})
 
 	t.Run("test send with forward message - success", func(t *testing.T) {
-		o := NewOutbound(&mockProvider{
+		o, err := NewOutbound(&mockProvider{
 			packagerValue:           &mockpackager.Packager{PackValue: createPackedMsgForForward(t)},
 			outboundTransportsValue: []transport.OutboundTransport{&mockdidcomm.MockOutboundTransport{AcceptValue: true}},
 		})
+		require.NoError(t, err)
 
 		require.NoError(t, o.Send("data", "", &service.Destination{
 			ServiceEndpoint: "url",
@@ -76,15 +99,16 @@ func TestOutboundDispatcher_Send(t *testing.T) {
 	})
 
 	t.Run("test send with forward message - create key failure", func(t *testing.T) {
-		o := NewOutbound(&mockProvider{
+		o, err := NewOutbound(&mockProvider{
 			packagerValue:           &mockpackager.Packager{PackValue: createPackedMsgForForward(t)},
 			outboundTransportsValue: []transport.OutboundTransport{&mockdidcomm.MockOutboundTransport{AcceptValue: true}},
 			legacyKMS: &mockKMS{
 				CreateKeyErr: errors.New("create key error"),
 			},
 		})
+		require.NoError(t, err)
 
-		err := o.Send("data", "", &service.Destination{
+		err = o.Send("data", "", &service.Destination{
 			ServiceEndpoint: "url",
 			RecipientKeys:   []string{"abc"},
 			RoutingKeys:     []string{"xyz"},
@@ -94,12 +118,13 @@ func TestOutboundDispatcher_Send(t *testing.T) {
 	})
 
 	t.Run("test send with forward message - packer error", func(t *testing.T) {
-		o := NewOutbound(&mockProvider{
+		o, err := NewOutbound(&mockProvider{
 			packagerValue:           &mockpackager.Packager{PackErr: errors.New("pack error")},
 			outboundTransportsValue: []transport.OutboundTransport{&mockdidcomm.MockOutboundTransport{AcceptValue: true}},
 		})
+		require.NoError(t, err)
 
-		_, err := o.createForwardMessage(createPackedMsgForForward(t), &service.Destination{
+		_, err = o.createForwardMessage(createPackedMsgForForward(t), &service.Destination{
 			ServiceEndpoint: "url",
 			RecipientKeys:   []string{"abc"},
 			RoutingKeys:     []string{"xyz"},
@@ -109,12 +134,13 @@ func TestOutboundDispatcher_Send(t *testing.T) {
 	})
 
 	t.Run("test send with forward message - envelop unmarshal error", func(t *testing.T) {
-		o := NewOutbound(&mockProvider{
+		o, err := NewOutbound(&mockProvider{
 			packagerValue:           &mockpackager.Packager{},
 			outboundTransportsValue: []transport.OutboundTransport{},
 		})
+		require.NoError(t, err)
 
-		_, err := o.createForwardMessage([]byte("invalid json"), &service.Destination{
+		_, err = o.createForwardMessage([]byte("invalid json"), &service.Destination{
 			ServiceEndpoint: "url",
 			RecipientKeys:   []string{"abc"},
 			RoutingKeys:     []string{"xyz"},
@@ -124,11 +150,147 @@ func TestOutboundDispatcher_Send(t *testing.T) {
 	})
 }
 
+func TestOutboundDispatcher_Failover(t *testing.T) {
+	des := &service.Destination{
+		ServiceEndpoint: "http://primary",
+		Alternatives: []*service.Destination{
+			{ServiceEndpoint: "ws://secondary"},
+			{ServiceEndpoint: "http://tertiary"},
+		},
+	}
+
+	t.Run("test send - fail over to the next endpoint", func(t *testing.T) {
+		http := &endpointTransport{scheme: "http", failing: map[string]bool{"http://primary": true}}
+		ws := &endpointTransport{scheme: "ws"}
+
+		o, err := NewOutbound(&mockProvider{
+			packagerValue:           &mockpackager.Packager{},
+			outboundTransportsValue: []transport.OutboundTransport{http, ws},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, o.Send("data", "", des))
+		require.Equal(t, []string{"http://primary"}, http.sent)
+		require.Equal(t, []string{"ws://secondary"}, ws.sent)
+	})
+
+	t.Run("test send - fail over to the next transport", func(t *testing.T) {
+		failing := &endpointTransport{scheme: "http", failing: map[string]bool{"http://primary": true}}
+		http := &endpointTransport{scheme: "http"}
+
+		o, err := NewOutbound(&mockProvider{
+			packagerValue:           &mockpackager.Packager{},
+			outboundTransportsValue: []transport.OutboundTransport{failing, http},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, o.Send("data", "", des))
+		require.Equal(t, []string{"http://primary"}, failing.sent)
+		require.Equal(t, []string{"http://primary"}, http.sent)
+	})
+
+	t.Run("test send - skip endpoints without transport", func(t *testing.T) {
+		http := &endpointTransport{scheme: "http", failing: map[string]bool{"http://primary": true}}
+
+		o, err := NewOutbound(&mockProvider{
+			packagerValue:           &mockpackager.Packager{},
+			outboundTransportsValue: []transport.OutboundTransport{http},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, o.Send("data", "", des))
+		require.Equal(t, []string{"http://primary", "http://tertiary"}, http.sent)
+	})
+
+	t.Run("test send - all endpoints failed", func(t *testing.T) {
+		http := &endpointTransport{scheme: "http", failing: map[string]bool{
+			"http://primary":  true,
+			"http://tertiary": true,
+		}}
+
+		o, err := NewOutbound(&mockProvider{
+			packagerValue:           &mockpackager.Packager{},
+			outboundTransportsValue: []transport.OutboundTransport{http},
+		})
+		require.NoError(t, err)
+
+		err = o.Send("data", "", des)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to send msg using outbound transport")
+		require.Equal(t, []string{"http://primary", "http://tertiary"}, http.sent)
+	})
+
+	t.Run("test send - pack only for the attempted endpoints", func(t *testing.T) {
+		routed := &service.Destination{
+			ServiceEndpoint: "http://primary",
+			RecipientKeys:   []string{"recipient-key"},
+			RoutingKeys:     []string{"routing-key"},
+			Alternatives: []*service.Destination{
+				{
+					ServiceEndpoint: "http://secondary",
+					RecipientKeys:   []string{"recipient-key"},
+					RoutingKeys:     []string{"routing-key"},
+				},
+			},
+		}
+
+		packager := &countingPackager{Packager: mockpackager.Packager{PackValue: createPackedMsgForForward(t)}}
+		kms := &mockKMS{}
+		http := &endpointTransport{scheme: "http"}
+
+		o, err := NewOutbound(&mockProvider{
+			packagerValue:           packager,
+			legacyKMS:               kms,
+			transportReturnRoute:    decorator.TransportReturnRouteAll,
+			outboundTransportsValue: []transport.OutboundTransport{http},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, o.Send(map[string]string{"@id": "id"}, "", routed))
+		require.Equal(t, []string{"http://primary"}, http.sent)
+
+		// the message and the forward message are packed for the primary endpoint only
+		require.Equal(t, 2, packager.packed)
+		require.Equal(t, 1, kms.createKeySetCalls)
+
+		// the destination is not updated
+		require.Empty(t, routed.TransportReturnRoute)
+	})
+
+	t.Run("test forward - fail over to the next endpoint", func(t *testing.T) {
+		http := &endpointTransport{scheme: "http", failing: map[string]bool{"http://primary": true}}
+		ws := &endpointTransport{scheme: "ws", failing: map[string]bool{"ws://secondary": true}}
+
+		o, err := NewOutbound(&mockProvider{
+			packagerValue:           &mockpackager.Packager{},
+			outboundTransportsValue: []transport.OutboundTransport{http, ws},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, o.Forward("data", des))
+		require.Equal(t, []string{"http://primary", "http://tertiary"}, http.sent)
+		require.Equal(t, []string{"ws://secondary"}, ws.sent)
+	})
+
+	t.Run("test forward - all endpoints failed", func(t *testing.T) {
+		o, err := NewOutbound(&mockProvider{
+			packagerValue: &mockpackager.Packager{},
+			outboundTransportsValue: []transport.OutboundTransport{
+				&mockdidcomm.MockOutboundTransport{AcceptValue: true, SendErr: fmt.Errorf("send error")}},
+		})
+		require.NoError(t, err)
+
+		err = o.Forward("data", des)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "send error")
+	})
+}
+
 func TestOutboundDispatcher_SendToDID(t *testing.T) {
 	mockDoc := mockdiddoc.GetMockDIDDoc()
 
 	t.Run("success", func(t *testing.T) {
-		o := NewOutbound(&mockProvider{
+		o, err := NewOutbound(&mockProvider{
 			packagerValue: &mockpackager.Packager{PackValue: createPackedMsgForForward(t)},
 			vdriRegistry: &mockvdri.MockVDRIRegistry{
 				ResolveValue: mockDoc,
@@ -137,12 +299,13 @@ func TestOutboundDispatcher_SendToDID(t *testing.T) {
 				&mockdidcomm.MockOutboundTransport{AcceptValue: true},
 			},
 		})
+		require.NoError(t, err)
 
 		require.NoError(t, o.SendToDID("data", "", ""))
 	})
 
 	t.Run("resolve err", func(t *testing.T) {
-		o := NewOutbound(&mockProvider{
+		o, err := NewOutbound(&mockProvider{
 			packagerValue: &mockpackager.Packager{},
 			vdriRegistry: &mockvdri.MockVDRIRegistry{
 				ResolveErr: fmt.Errorf("resolve error"),
@@ -151,13 +314,64 @@ func TestOutboundDispatcher_SendToDID(t *testing.T) {
 				&mockdidcomm.MockOutboundTransport{AcceptValue: true},
 			},
 		})
+		require.NoError(t, err)
 
-		err := o.SendToDID("data", "", "")
+		err = o.SendToDID("data", "", "")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "resolve error")
 	})
 }
 
+func TestOutboundDispatcher_SendToDIDNIST(t *testing.T) {
+	for _, kt := range []struct {
+		kmsKeyType kms.KeyType
+		didKeyType string
+	}{
+		{kms.ECDHP256KWType, "EcdsaSecp256r1VerificationKey2019"},
+		{kms.ECDHP384KWType, "EcdsaSecp384r1VerificationKey2019"},
+	} {
+		kt := kt
+
+		t.Run("success with "+kt.didKeyType, func(t *testing.T) {
+			docs := map[string]*did.Doc{}
+			vdr := &mockvdri.MockVDRIRegistry{ResolveFunc: func(id string, _ ...vdri.ResolveOpts) (*did.Doc, error) {
+				return docs[id], nil
+			}}
+
+			sender := newNISTAgent(t, "did:example:sender", kt.kmsKeyType, kt.didKeyType, vdr)
+			recipient := newNISTAgent(t, "did:example:recipient", kt.kmsKeyType, kt.didKeyType, vdr)
+			docs[sender.doc.ID] = sender.doc
+			docs[recipient.doc.ID] = recipient.doc
+
+			outbound := &unpackingTransport{packager: recipient.packager}
+
+			o, err := NewOutbound(&mockProvider{
+				packagerValue:           sender.packager,
+				vdriRegistry:            vdr,
+				outboundTransportsValue: []transport.OutboundTransport{outbound},
+			})
+			require.NoError(t, err)
+
+			msg := map[string]string{"@id": uuid.New().String(), "@type": "test"}
+			require.NoError(t, o.SendToDID(msg, sender.doc.ID, recipient.doc.ID))
+			require.NotNil(t, outbound.envelope)
+
+			expected, err := json.Marshal(msg)
+			require.NoError(t, err)
+			require.JSONEq(t, string(expected), string(outbound.envelope.Message))
+
+			// the unpacked keys are the keys of the DID docs, the recipient can reply with the sender key
+			senderDest, err := service.CreateDestination(sender.doc)
+			require.NoError(t, err)
+			require.Equal(t, senderDest.RecipientKeys[0], base58.Encode(outbound.envelope.FromVerKey))
+
+			recipientDest, err := service.CreateDestination(recipient.doc)
+			require.NoError(t, err)
+			require.Equal(t, recipientDest.RecipientKeys[0], base58.Encode(outbound.envelope.ToVerKey))
+		})
+	}
+}
+
 func TestOutboundDispatcherTransportReturnRoute(t *testing.T) {
 	t.Run("transport route option - value set all", func(t *testing.T) {
 		transportReturnRoute := "all"
@@ -176,13 +390,14 @@ func TestOutboundDispatcherTransportReturnRoute(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, expectedRequest)
 
-		o := NewOutbound(&mockProvider{
+		o, err := NewOutbound(&mockProvider{
 			packagerValue: &mockPackager{},
 			outboundTransportsValue: []transport.OutboundTransport{&mockOutboundTransport{
 				expectedRequest: string(expectedRequest)},
 			},
 			transportReturnRoute: transportReturnRoute,
 		})
+		require.NoError(t, err)
 
 		require.NoError(t, o.Send(req, "", &service.Destination{ServiceEndpoint: "url"}))
 	})
@@ -204,13 +419,14 @@ func TestOutboundDispatcherTransportReturnRoute(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, expectedRequest)
 
-		o := NewOutbound(&mockProvider{
+		o, err := NewOutbound(&mockProvider{
 			packagerValue: &mockPackager{},
 			outboundTransportsValue: []transport.OutboundTransport{&mockOutboundTransport{
 				expectedRequest: string(expectedRequest)},
 			},
 			transportReturnRoute: transportReturnRoute,
 		})
+		require.NoError(t, err)
 
 		require.NoError(t, o.Send(req, "", &service.Destination{ServiceEndpoint: "url"}))
 	})
@@ -224,13 +440,14 @@ func TestOutboundDispatcherTransportReturnRoute(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, expectedRequest)
 
-		o := NewOutbound(&mockProvider{
+		o, err := NewOutbound(&mockProvider{
 			packagerValue: &mockPackager{},
 			outboundTransportsValue: []transport.OutboundTransport{&mockOutboundTransport{
 				expectedRequest: string(expectedRequest)},
 			},
 			transportReturnRoute: "",
 		})
+		require.NoError(t, err)
 
 		require.NoError(t, o.Send(req, "", &service.Destination{ServiceEndpoint: "url"}))
 	})
@@ -238,26 +455,29 @@ func TestOutboundDispatcherTransportReturnRoute(t *testing.T) {
 
 func TestOutboundDispatcher_Forward(t *testing.T) {
 	t.Run("test forward - success", func(t *testing.T) {
-		o := NewOutbound(&mockProvider{
+		o, err := NewOutbound(&mockProvider{
 			packagerValue:           &mockpackager.Packager{},
 			outboundTransportsValue: []transport.OutboundTransport{&mockdidcomm.MockOutboundTransport{AcceptValue: true}},
 		})
+		require.NoError(t, err)
 		require.NoError(t, o.Forward("data", &service.Destination{ServiceEndpoint: "url"}))
 	})
 
 	t.Run("test forward - no outbound transport found", func(t *testing.T) {
-		o := NewOutbound(&mockProvider{packagerValue: &mockpackager.Packager{},
+		o, err := NewOutbound(&mockProvider{packagerValue: &mockpackager.Packager{},
 			outboundTransportsValue: []transport.OutboundTransport{&mockdidcomm.MockOutboundTransport{AcceptValue: false}}})
-		err := o.Forward("data", &service.Destination{ServiceEndpoint: "url"})
+		require.NoError(t, err)
+		err = o.Forward("data", &service.Destination{ServiceEndpoint: "url"})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "no outbound transport found for serviceEndpoint: url")
 	})
 
 	t.Run("test forward - outbound send failure", func(t *testing.T) {
-		o := NewOutbound(&mockProvider{packagerValue: &mockpackager.Packager{},
+		o, err := NewOutbound(&mockProvider{packagerValue: &mockpackager.Packager{},
 			outboundTransportsValue: []transport.OutboundTransport{
 				&mockdidcomm.MockOutboundTransport{AcceptValue: true, SendErr: fmt.Errorf("send error")}}})
-		err := o.Forward("data", &service.Destination{ServiceEndpoint: "url"})
+		require.NoError(t, err)
+		err = o.Forward("data", &service.Destination{ServiceEndpoint: "url"})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "send error")
 	})
@@ -279,6 +499,8 @@ type mockProvider struct {
 	transportReturnRoute    string
 	vdriRegistry            vdri.Registry
 	legacyKMS               legacykms.KMS
+	storageProvider         storage.Provider
+	retryPolicy             *RetryPolicy
 }
 
 func (p *mockProvider) Packager() commontransport.Packager {
@@ -297,6 +519,14 @@ func (p *mockProvider) VDRIRegistry() vdri.Registry {
 	return p.vdriRegistry
 }
 
+func (p *mockProvider) StorageProvider() storage.Provider {
+	return p.storageProvider
+}
+
+func (p *mockProvider) OutboundRetryPolicy() *RetryPolicy {
+	return p.retryPolicy
+}
+
 func (p *mockProvider) LegacyKMS() legacykms.KeyManager {
 	if p.legacyKMS != nil {
 		return p.legacyKMS
@@ -331,6 +561,170 @@ func (o *mockOutboundTransport) Accept(url string) bool {
 	return true
 }
 
+// endpointTransport accepts the endpoints with the given scheme and fails to send to the failing endpoints.
+type endpointTransport struct {
+	scheme  string
+	failing map[string]bool
+	sent    []string
+}
+
+func (e *endpointTransport) Start(transport.Provider) error {
+	return nil
+}
+
+func (e *endpointTransport) Send(_ []byte, des *service.Destination) (string, error) {
+	e.sent = append(e.sent, des.ServiceEndpoint)
+
+	if e.failing[des.ServiceEndpoint] {
+		return "", errors.New("send error")
+	}
+
+	return "", nil
+}
+
+func (e *endpointTransport) AcceptRecipient([]string) bool {
+	return false
+}
+
+func (e *endpointTransport) Accept(url string) bool {
+	return strings.HasPrefix(url, e.scheme+"://")
+}
+
+// unpackingTransport unpacks the sent messages with the recipient packager
+type unpackingTransport struct {
+	packager commontransport.Packager
+	envelope *commontransport.Envelope
+}
+
+func (u *unpackingTransport) Start(transport.Provider) error {
+	return nil
+}
+
+func (u *unpackingTransport) Send(data []byte, _ *service.Destination) (string, error) {
+	env, err := u.packager.UnpackMessage(data)
+	if err != nil {
+		return "", err
+	}
+
+	u.envelope = env
+
+	return "", nil
+}
+
+func (u *unpackingTransport) AcceptRecipient([]string) bool {
+	return false
+}
+
+func (u *unpackingTransport) Accept(string) bool {
+	return true
+}
+
+// nistAgent is an agent with a NIST key in its KMS and a DID doc having this key as the recipient key
+type nistAgent struct {
+	storageProvider storage.Provider
+	vdriRegistry    vdri.Registry
+	kms             kms.KeyManager
+	crypto          crypto.Crypto
+	packer          packer.Packer
+	packager        commontransport.Packager
+	doc             *did.Doc
+}
+
+func newNISTAgent(t *testing.T, id string, kmsKeyType kms.KeyType, didKeyType string,
+	vdr vdri.Registry) *nistAgent {
+	a := &nistAgent{storageProvider: mem.NewProvider(), vdriRegistry: vdr}
+
+	var err error
+
+	a.kms, err = localkms.New(localkms.PrimaryKeyURI, a)
+	require.NoError(t, err)
+
+	a.crypto, err = tinkcrypto.New()
+	require.NoError(t, err)
+
+	a.packer, err = authcrypt.New(a, authcrypt.XC20P)
+	require.NoError(t, err)
+
+	a.packager, err = packager.New(a)
+	require.NoError(t, err)
+
+	kid, kh, err := a.kms.Create(kmsKeyType)
+	require.NoError(t, err)
+
+	pubKey, err := ecdhkw.PublicKey(kh.(*keyset.Handle))
+	require.NoError(t, err)
+
+	curve := elliptic.P256()
+	if pubKey.Curve == elliptic.P384().Params().Name {
+		curve = elliptic.P384()
+	}
+
+	// the fragment of the DID doc key ID is the KMS key ID
+	key := did.PublicKey{
+		ID:         id + "#" + kid,
+		Type:       didKeyType,
+		Controller: id,
+		Value:      elliptic.Marshal(curve, new(big.Int).SetBytes(pubKey.X), new(big.Int).SetBytes(pubKey.Y)),
+	}
+
+	a.doc = &did.Doc{
+		ID:        id,
+		PublicKey: []did.PublicKey{key},
+		Service: []did.Service{{
+			ID:              id + "#didcomm",
+			Type:            "did-communication",
+			ServiceEndpoint: "http://" + strings.TrimPrefix(id, "did:example:"),
+			RecipientKeys:   []string{key.ID},
+		}},
+	}
+
+	return a
+}
+
+func (a *nistAgent) StorageProvider() storage.Provider {
+	return a.storageProvider
+}
+
+func (a *nistAgent) SecretLock() secretlock.Service {
+	return &noop.NoLock{}
+}
+
+func (a *nistAgent) LegacyKMS() legacykms.KeyManager {
+	return nil
+}
+
+func (a *nistAgent) KMS() kms.KeyManager {
+	return a.kms
+}
+
+func (a *nistAgent) Crypto() crypto.Crypto {
+	return a.crypto
+}
+
+func (a *nistAgent) Packers() []packer.Packer {
+	return nil
+}
+
+func (a *nistAgent) PrimaryPacker() packer.Packer {
+	return a.packer
+}
+
+func (a *nistAgent) VDRIRegistry() vdri.Registry {
+	return a.vdriRegistry
+}
+
+// countingPackager counts the packed messages
+type countingPackager struct {
+	mockpackager.Packager
+	packed int
+}
+
+func (c *countingPackager) PackMessage(e *commontransport.Envelope) ([]byte, error) {
+	c.packed++
+
+	return c.Packager.PackMessage(e)
+}
+
 // mockPackager mock packager
 type mockPackager struct {
 }
@@ -348,6 +742,7 @@ type mockKMS struct {
 	CreateEncryptionKeyValue string
 	CreateSigningKeyValue    string
 	CreateKeyErr             error
+	createKeySetCalls        int
 }
 
 func (m *mockKMS) Close() error {
@@ -355,6 +750,8 @@ func (m *mockKMS) Close() error {
 }
 
 func (m *mockKMS) CreateKeySet() (string, string, error) {
+	m.createKeySetCalls++
+
 	return m.CreateEncryptionKeyValue, m.CreateSigningKeyValue, m.CreateKeyErr
 }
 
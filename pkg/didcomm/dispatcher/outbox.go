@@ -0,0 +1,376 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dispatcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const (
+	// OutboxStore is the name of the store holding the outbound messages queued for redelivery.
+	OutboxStore = "outbox"
+
+	outboxKeyPrefix = "outbox_"
+
+	// limitPattern with `~` at the end for lte of given prefix (less than or equal)
+	limitPattern = "%s~"
+)
+
+var (
+	// ErrMessageExpired is returned when the message is sent after its `~timing.expires_time`.
+	ErrMessageExpired = errors.New("message expired")
+
+	// ErrOutboxNotConfigured is returned when the delivery events are requested without the retry policy.
+	ErrOutboxNotConfigured = errors.New("outbound retry policy is not configured")
+)
+
+// RetryPolicy configures the redelivery of the outbound messages which failed to be sent.
+// The delay before the nth retry is InitialBackoff * Multiplier^(n-1), capped by MaxBackoff and
+// randomized by +/- Jitter * delay.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of delivery attempts, including the first one.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between the retries, not capped if zero.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the delay is multiplied by after each retry.
+	Multiplier float64
+	// Jitter is the randomization factor of the delay (0 <= Jitter <= 1).
+	Jitter float64
+}
+
+// DefaultRetryPolicy returns the retry policy with 5 attempts and exponential backoff from 1s to 1m.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// backoff returns the delay before the next delivery attempt, attempts is the number of failed attempts.
+func (p *RetryPolicy) backoff(attempts int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempts-1))
+
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		// jitter doesn't need a cryptographically secure random number
+		delay += delay * p.Jitter * (2*rand.Float64() - 1) // nolint: gosec
+	}
+
+	return time.Duration(delay)
+}
+
+// DeliveryStatus is the delivery status of the outbound message.
+type DeliveryStatus string
+
+const (
+	// DeliveryQueued means the first delivery attempt failed and the message is queued for redelivery.
+	DeliveryQueued DeliveryStatus = "queued"
+	// DeliveryDelivered means the queued message was delivered.
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryFailed means the queued message was not delivered within the maximum number of attempts.
+	DeliveryFailed DeliveryStatus = "failed"
+	// DeliveryExpired means the queued message expired (`~timing.expires_time`) before it was delivered.
+	DeliveryExpired DeliveryStatus = "expired"
+)
+
+// DeliveryEvent is sent when the delivery status of a queued outbound message changes.
+type DeliveryEvent struct {
+	// MessageID is the ID (@id) of the DIDComm message.
+	MessageID string
+	// Status is the delivery status of the message.
+	Status DeliveryStatus
+	// Destination is the destination of the message.
+	Destination *service.Destination
+	// Attempts is the number of delivery attempts.
+	Attempts int
+	// Err is the error of the last failed delivery attempt.
+	Err error
+}
+
+// DeliveryNotifier notifies about the delivery status of the queued outbound messages.
+type DeliveryNotifier interface {
+	// RegisterDeliveryEvent registers the channel for the delivery events. The events are sent without blocking,
+	// the channel should be buffered, the events are dropped while it is full.
+	RegisterDeliveryEvent(ch chan<- DeliveryEvent) error
+
+	// UnregisterDeliveryEvent unregisters the channel for the delivery events.
+	UnregisterDeliveryEvent(ch chan<- DeliveryEvent) error
+}
+
+// delivery is the message packed for the endpoint of the destination.
+type delivery struct {
+	Message     []byte               `json:"message"`
+	Destination *service.Destination `json:"destination"`
+}
+
+// outboxRecord is the outbound message persisted for redelivery. The message is packed for each endpoint
+// of the destination in the order of the delivery attempts.
+type outboxRecord struct {
+	ID          string               `json:"id"`
+	MessageID   string               `json:"message_id,omitempty"`
+	Deliveries  []*delivery          `json:"deliveries"`
+	Destination *service.Destination `json:"destination"`
+	Attempts    int                  `json:"attempts"`
+	ExpiresTime *time.Time           `json:"expires_time,omitempty"`
+}
+
+func (r *outboxRecord) expired(now time.Time) bool {
+	return r.ExpiresTime != nil && now.After(*r.ExpiresTime)
+}
+
+// outbox persists the packed outbound messages which failed to be sent and redelivers them.
+type outbox struct {
+	store  storage.Store
+	policy *RetryPolicy
+	send   func(deliveries []*delivery) error
+
+	mu     sync.Mutex
+	timers map[string]*time.Timer
+	closed bool
+
+	eventsLock sync.RWMutex
+	events     []chan<- DeliveryEvent
+}
+
+func newOutbox(store storage.Store, policy *RetryPolicy,
+	send func(deliveries []*delivery) error) (*outbox, error) {
+	o := &outbox{
+		store:  store,
+		policy: policy,
+		send:   send,
+		timers: make(map[string]*time.Timer),
+	}
+
+	// resume the redelivery of the messages queued before the restart
+	itr := store.Iterator(outboxKeyPrefix, fmt.Sprintf(limitPattern, outboxKeyPrefix))
+	defer itr.Release()
+
+	for itr.Next() {
+		rec := &outboxRecord{}
+
+		if err := json.Unmarshal(itr.Value(), rec); err != nil {
+			return nil, fmt.Errorf("unmarshal outbox record : %w", err)
+		}
+
+		o.schedule(rec)
+	}
+
+	if err := itr.Error(); err != nil {
+		return nil, fmt.Errorf("fetch outbox records : %w", err)
+	}
+
+	return o, nil
+}
+
+// queue persists the message for redelivery after the first delivery attempt failed.
+func (o *outbox) queue(deliveries []*delivery, des *service.Destination, meta *messageMeta, sendErr error) error {
+	rec := &outboxRecord{
+		ID:          uuid.New().String(),
+		MessageID:   meta.ID,
+		Deliveries:  deliveries,
+		Destination: des,
+		Attempts:    1,
+	}
+
+	if meta.Timing != nil && !meta.Timing.ExpiresTime.IsZero() {
+		expires := meta.Timing.ExpiresTime
+		rec.ExpiresTime = &expires
+	}
+
+	if rec.Attempts >= o.policy.MaxAttempts {
+		return sendErr
+	}
+
+	if err := o.put(rec); err != nil {
+		return fmt.Errorf("queue outbound msg : %w", err)
+	}
+
+	logger.Warnf("outbound message %s queued for redelivery : %s", rec.MessageID, sendErr)
+
+	o.notify(rec, DeliveryQueued, sendErr)
+	o.schedule(rec)
+
+	return nil
+}
+
+// schedule schedules the next delivery attempt of the message, the attempt is not delayed past
+// the expiration time of the message.
+func (o *outbox) schedule(rec *outboxRecord) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return
+	}
+
+	delay := o.policy.backoff(rec.Attempts)
+
+	if rec.ExpiresTime != nil {
+		if untilExpiry := time.Until(*rec.ExpiresTime); untilExpiry < delay {
+			delay = untilExpiry
+		}
+	}
+
+	id := rec.ID
+	o.timers[id] = time.AfterFunc(delay, func() { o.retry(id) })
+}
+
+// retry performs the next delivery attempt of the queued message.
+func (o *outbox) retry(id string) {
+	o.mu.Lock()
+	delete(o.timers, id)
+	closed := o.closed
+	o.mu.Unlock()
+
+	if closed {
+		return
+	}
+
+	rec, err := o.get(id)
+	if err != nil {
+		logger.Errorf("fetch outbox record %s : %s", id, err)
+		return
+	}
+
+	if rec.expired(time.Now()) {
+		o.remove(rec, DeliveryExpired, ErrMessageExpired)
+		return
+	}
+
+	err = o.send(rec.Deliveries)
+	rec.Attempts++
+
+	switch {
+	case err == nil:
+		o.remove(rec, DeliveryDelivered, nil)
+	case rec.Attempts >= o.policy.MaxAttempts:
+		o.remove(rec, DeliveryFailed, err)
+	default:
+		if e := o.put(rec); e != nil {
+			logger.Errorf("update outbox record %s : %s", id, e)
+			return
+		}
+
+		o.schedule(rec)
+	}
+}
+
+func (o *outbox) remove(rec *outboxRecord, status DeliveryStatus, err error) {
+	if e := o.store.Delete(outboxKeyPrefix + rec.ID); e != nil {
+		logger.Errorf("delete outbox record %s : %s", rec.ID, e)
+	}
+
+	o.notify(rec, status, err)
+}
+
+func (o *outbox) get(id string) (*outboxRecord, error) {
+	bytes, err := o.store.Get(outboxKeyPrefix + id)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &outboxRecord{}
+
+	err = json.Unmarshal(bytes, rec)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal outbox record : %w", err)
+	}
+
+	return rec, nil
+}
+
+func (o *outbox) put(rec *outboxRecord) error {
+	bytes, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshal outbox record : %w", err)
+	}
+
+	return o.store.Put(outboxKeyPrefix+rec.ID, bytes)
+}
+
+func (o *outbox) notify(rec *outboxRecord, status DeliveryStatus, err error) {
+	o.eventsLock.RLock()
+	events := append(o.events[:0:0], o.events...)
+	o.eventsLock.RUnlock()
+
+	event := DeliveryEvent{
+		MessageID:   rec.MessageID,
+		Status:      status,
+		Destination: rec.Destination,
+		Attempts:    rec.Attempts,
+		Err:         err,
+	}
+
+	// the redelivery is not blocked by the slow consumers, the event is dropped if the channel is full
+	for _, ch := range events {
+		select {
+		case ch <- event:
+		default:
+			logger.Warnf("delivery event %s of outbound message %s dropped : channel is full", status, rec.MessageID)
+		}
+	}
+}
+
+func (o *outbox) registerEvent(ch chan<- DeliveryEvent) error {
+	if ch == nil {
+		return service.ErrNilChannel
+	}
+
+	o.eventsLock.Lock()
+	o.events = append(o.events, ch)
+	o.eventsLock.Unlock()
+
+	return nil
+}
+
+func (o *outbox) unregisterEvent(ch chan<- DeliveryEvent) {
+	o.eventsLock.Lock()
+	for i := 0; i < len(o.events); i++ {
+		if o.events[i] == ch {
+			o.events = append(o.events[:i], o.events[i+1:]...)
+			i--
+		}
+	}
+	o.eventsLock.Unlock()
+}
+
+// close stops the redelivery, the queued messages are redelivered after the restart.
+func (o *outbox) close() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.closed = true
+
+	for id, timer := range o.timers {
+		timer.Stop()
+		delete(o.timers, id)
+	}
+}
@@ -0,0 +1,364 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dispatcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+	mockpackager "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/packager"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/internal/mock/storage"
+)
+
+type testMessage struct {
+	ID     string            `json:"@id"`
+	Type   string            `json:"@type"`
+	Timing *decorator.Timing `json:"~timing,omitempty"`
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := &RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second, Multiplier: 2}
+
+	require.Equal(t, time.Second, policy.backoff(1))
+	require.Equal(t, 2*time.Second, policy.backoff(2))
+	require.Equal(t, 4*time.Second, policy.backoff(3))
+	require.Equal(t, 5*time.Second, policy.backoff(4))
+
+	// the delay is constant if the multiplier is not set
+	policy = &RetryPolicy{InitialBackoff: time.Second}
+	require.Equal(t, time.Second, policy.backoff(3))
+
+	policy = &RetryPolicy{InitialBackoff: time.Second, Multiplier: 2, Jitter: 0.5}
+
+	for i := 0; i < 100; i++ {
+		delay := policy.backoff(2)
+		require.True(t, delay >= time.Second && delay <= 3*time.Second, delay)
+	}
+}
+
+func TestOutbox_Redelivery(t *testing.T) {
+	t.Run("test redelivery - delivered", func(t *testing.T) {
+		outbound := &flakyTransport{failures: 2}
+		store := mockstore.NewMockStoreProvider()
+
+		o := newRetryDispatcher(t, outbound, store, &RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond})
+
+		events := make(chan DeliveryEvent, 2)
+		require.NoError(t, o.RegisterDeliveryEvent(events))
+
+		require.NoError(t, o.Send(&testMessage{ID: "msg-1"}, "", &service.Destination{ServiceEndpoint: "url"}))
+
+		event := receiveEvent(t, events)
+		require.Equal(t, DeliveryQueued, event.Status)
+		require.Equal(t, "msg-1", event.MessageID)
+		require.Equal(t, 1, event.Attempts)
+		require.Contains(t, event.Err.Error(), "send error")
+
+		event = receiveEvent(t, events)
+		require.Equal(t, DeliveryDelivered, event.Status)
+		require.Equal(t, "msg-1", event.MessageID)
+		require.Equal(t, 3, event.Attempts)
+		require.NoError(t, event.Err)
+		require.Equal(t, "url", event.Destination.ServiceEndpoint)
+
+		require.Equal(t, 1, outbound.delivered())
+		require.Empty(t, store.Store.Store)
+	})
+
+	t.Run("test redelivery - failed", func(t *testing.T) {
+		outbound := &flakyTransport{failures: -1}
+		store := mockstore.NewMockStoreProvider()
+
+		o := newRetryDispatcher(t, outbound, store, &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+		events := make(chan DeliveryEvent, 2)
+		require.NoError(t, o.RegisterDeliveryEvent(events))
+
+		require.NoError(t, o.Send(&testMessage{ID: "msg-1"}, "", &service.Destination{ServiceEndpoint: "url"}))
+
+		require.Equal(t, DeliveryQueued, receiveEvent(t, events).Status)
+
+		event := receiveEvent(t, events)
+		require.Equal(t, DeliveryFailed, event.Status)
+		require.Equal(t, 3, event.Attempts)
+		require.Contains(t, event.Err.Error(), "send error")
+
+		require.Empty(t, store.Store.Store)
+	})
+
+	t.Run("test redelivery - expired", func(t *testing.T) {
+		outbound := &flakyTransport{failures: -1}
+		store := mockstore.NewMockStoreProvider()
+
+		o := newRetryDispatcher(t, outbound, store, &RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour})
+
+		events := make(chan DeliveryEvent, 2)
+		require.NoError(t, o.RegisterDeliveryEvent(events))
+
+		msg := &testMessage{ID: "msg-1", Timing: &decorator.Timing{ExpiresTime: time.Now().Add(50 * time.Millisecond)}}
+
+		require.NoError(t, o.Send(msg, "", &service.Destination{ServiceEndpoint: "url"}))
+
+		require.Equal(t, DeliveryQueued, receiveEvent(t, events).Status)
+
+		// the redelivery is not delayed past the expiration time
+		event := receiveEvent(t, events)
+		require.Equal(t, DeliveryExpired, event.Status)
+		require.True(t, errors.Is(event.Err, ErrMessageExpired))
+
+		require.Empty(t, store.Store.Store)
+	})
+
+	t.Run("test redelivery - not blocked by the full event channel", func(t *testing.T) {
+		outbound := &flakyTransport{failures: 1}
+		store := mockstore.NewMockStoreProvider()
+
+		o := newRetryDispatcher(t, outbound, store, &RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond})
+
+		// nobody reads the events
+		require.NoError(t, o.RegisterDeliveryEvent(make(chan DeliveryEvent)))
+		require.NoError(t, o.Send(&testMessage{ID: "msg-1"}, "", &service.Destination{ServiceEndpoint: "url"}))
+
+		require.Eventually(t, func() bool { return outbound.delivered() == 1 }, time.Second, time.Millisecond)
+		require.Eventually(t, func() bool {
+			itr := store.Store.Iterator(outboxKeyPrefix, fmt.Sprintf(limitPattern, outboxKeyPrefix))
+			defer itr.Release()
+
+			return !itr.Next()
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("test redelivery - resumed after restart", func(t *testing.T) {
+		outbound := &flakyTransport{}
+		store := mockstore.NewMockStoreProvider()
+
+		rec, err := json.Marshal(&outboxRecord{
+			ID:        "record-1",
+			MessageID: "msg-1",
+			Deliveries: []*delivery{{
+				Message:     []byte("packed message"),
+				Destination: &service.Destination{ServiceEndpoint: "url"},
+			}},
+			Destination: &service.Destination{ServiceEndpoint: "url"},
+			Attempts:    1,
+		})
+		require.NoError(t, err)
+		require.NoError(t, store.Store.Put(outboxKeyPrefix+"record-1", rec))
+
+		newRetryDispatcher(t, outbound, store, &RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond})
+
+		require.Eventually(t, func() bool { return outbound.delivered() == 1 }, time.Second, time.Millisecond)
+		require.Eventually(t, func() bool {
+			_, err := store.Store.Get(outboxKeyPrefix + "record-1")
+			return err != nil
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("test redelivery - stopped on close", func(t *testing.T) {
+		outbound := &flakyTransport{failures: 1}
+		store := mockstore.NewMockStoreProvider()
+
+		o := newRetryDispatcher(t, outbound, store, &RetryPolicy{MaxAttempts: 5, InitialBackoff: 50 * time.Millisecond})
+
+		require.NoError(t, o.Send(&testMessage{ID: "msg-1"}, "", &service.Destination{ServiceEndpoint: "url"}))
+		require.NoError(t, o.Close())
+
+		time.Sleep(100 * time.Millisecond)
+
+		// the message is kept for the redelivery after the restart
+		require.Equal(t, 0, outbound.delivered())
+		require.Len(t, store.Store.Store, 1)
+	})
+}
+
+func TestOutbox_Send(t *testing.T) {
+	t.Run("test send - expired message", func(t *testing.T) {
+		outbound := &flakyTransport{}
+
+		o := newRetryDispatcher(t, outbound, mockstore.NewMockStoreProvider(), DefaultRetryPolicy())
+
+		msg := &testMessage{ID: "msg-1", Timing: &decorator.Timing{ExpiresTime: time.Now().Add(-time.Second)}}
+
+		err := o.Send(msg, "", &service.Destination{ServiceEndpoint: "url"})
+		require.True(t, errors.Is(err, ErrMessageExpired))
+		require.Equal(t, 0, outbound.delivered())
+	})
+
+	t.Run("test send - single attempt", func(t *testing.T) {
+		store := mockstore.NewMockStoreProvider()
+
+		o := newRetryDispatcher(t, &flakyTransport{failures: -1}, store, &RetryPolicy{MaxAttempts: 1})
+
+		err := o.Send(&testMessage{ID: "msg-1"}, "", &service.Destination{ServiceEndpoint: "url"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to send msg using outbound transport")
+		require.Empty(t, store.Store.Store)
+	})
+
+	t.Run("test send - queue error", func(t *testing.T) {
+		store := mockstore.NewMockStoreProvider()
+		store.Store.ErrPut = errors.New("put error")
+
+		o := newRetryDispatcher(t, &flakyTransport{failures: -1}, store, DefaultRetryPolicy())
+
+		err := o.Send(&testMessage{ID: "msg-1"}, "", &service.Destination{ServiceEndpoint: "url"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "queue outbound msg")
+	})
+
+	t.Run("test send - plain text message", func(t *testing.T) {
+		store := mockstore.NewMockStoreProvider()
+
+		o := newRetryDispatcher(t, &flakyTransport{failures: 1}, store, &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Hour})
+
+		require.NoError(t, o.Send("data", "", &service.Destination{ServiceEndpoint: "url"}))
+		require.Len(t, store.Store.Store, 1)
+		require.NoError(t, o.Close())
+	})
+}
+
+func TestNewOutbound_Outbox(t *testing.T) {
+	t.Run("test new outbound - open store error", func(t *testing.T) {
+		o, err := NewOutbound(&mockProvider{
+			packagerValue:   &mockpackager.Packager{},
+			storageProvider: &mockstore.MockStoreProvider{ErrOpenStoreHandle: errors.New("open error")},
+			retryPolicy:     DefaultRetryPolicy(),
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "open outbox store")
+		require.Nil(t, o)
+	})
+
+	t.Run("test new outbound - invalid record", func(t *testing.T) {
+		store := mockstore.NewMockStoreProvider()
+		require.NoError(t, store.Store.Put(outboxKeyPrefix+"record-1", []byte("invalid")))
+
+		o, err := NewOutbound(&mockProvider{
+			packagerValue:   &mockpackager.Packager{},
+			storageProvider: store,
+			retryPolicy:     DefaultRetryPolicy(),
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal outbox record")
+		require.Nil(t, o)
+	})
+
+	t.Run("test new outbound - iterator error", func(t *testing.T) {
+		store := mockstore.NewMockStoreProvider()
+		store.Store.ErrItr = errors.New("iterator error")
+
+		o, err := NewOutbound(&mockProvider{
+			packagerValue:   &mockpackager.Packager{},
+			storageProvider: store,
+			retryPolicy:     DefaultRetryPolicy(),
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "fetch outbox records")
+		require.Nil(t, o)
+	})
+}
+
+func TestOutboundDispatcher_DeliveryEvents(t *testing.T) {
+	t.Run("test delivery events - retry policy not configured", func(t *testing.T) {
+		o, err := NewOutbound(&mockProvider{packagerValue: &mockpackager.Packager{}})
+		require.NoError(t, err)
+
+		events := make(chan DeliveryEvent)
+		require.Equal(t, ErrOutboxNotConfigured, o.RegisterDeliveryEvent(events))
+		require.Equal(t, ErrOutboxNotConfigured, o.UnregisterDeliveryEvent(events))
+		require.NoError(t, o.Close())
+	})
+
+	t.Run("test delivery events - register and unregister", func(t *testing.T) {
+		o := newRetryDispatcher(t, &flakyTransport{}, mockstore.NewMockStoreProvider(), DefaultRetryPolicy())
+
+		require.Equal(t, service.ErrNilChannel, o.RegisterDeliveryEvent(nil))
+
+		events := make(chan DeliveryEvent)
+		require.NoError(t, o.RegisterDeliveryEvent(events))
+		require.NoError(t, o.RegisterDeliveryEvent(events))
+		require.Len(t, o.outbox.events, 2)
+
+		require.NoError(t, o.UnregisterDeliveryEvent(events))
+		require.Empty(t, o.outbox.events)
+	})
+}
+
+func newRetryDispatcher(t *testing.T, outbound transport.OutboundTransport, store *mockstore.MockStoreProvider,
+	policy *RetryPolicy) *OutboundDispatcher {
+	o, err := NewOutbound(&mockProvider{
+		packagerValue:           &mockpackager.Packager{},
+		outboundTransportsValue: []transport.OutboundTransport{outbound},
+		storageProvider:         store,
+		retryPolicy:             policy,
+	})
+	require.NoError(t, err)
+
+	return o
+}
+
+func receiveEvent(t *testing.T, events chan DeliveryEvent) DeliveryEvent {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		require.Fail(t, "timeout waiting for the delivery event")
+	}
+
+	return DeliveryEvent{}
+}
+
+// flakyTransport fails the given number of sends (all of them if negative) before delivering the messages.
+type flakyTransport struct {
+	mu       sync.Mutex
+	failures int
+	sent     int
+}
+
+func (f *flakyTransport) Start(transport.Provider) error {
+	return nil
+}
+
+func (f *flakyTransport) Send([]byte, *service.Destination) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failures != 0 {
+		f.failures--
+		return "", errors.New("send error")
+	}
+
+	f.sent++
+
+	return "", nil
+}
+
+func (f *flakyTransport) delivered() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.sent
+}
+
+func (f *flakyTransport) AcceptRecipient([]string) bool {
+	return false
+}
+
+func (f *flakyTransport) Accept(string) bool {
+	return true
+}
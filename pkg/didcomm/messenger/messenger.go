@@ -10,6 +10,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -20,7 +22,14 @@ import (
 )
 
 const (
-	messengerStore = "messenger_store"
+	messengerStore  = "messenger_store"
+	recordKeyPrefix = "record_"
+	limitPattern    = "%s~"
+
+	// recordTTL is the time the received message can be replied to, the expired records are removed
+	recordTTL = 72 * time.Hour
+	// expiredRecordsCheckInterval is the minimal time between two removals of the expired records
+	expiredRecordsCheckInterval = time.Hour
 
 	jsonID             = "@id"
 	jsonThread         = "~thread"
@@ -32,9 +41,14 @@ var logger = log.New("aries-framework/didcomm/messenger")
 
 // record is an internal structure and keeps payload about inbound message
 type record struct {
-	MyDID    string
-	TheirDID string
-	ThreadID string
+	MyDID      string
+	TheirDID   string
+	ThreadID   string
+	ReceivedAt time.Time
+}
+
+func (r *record) expired() bool {
+	return time.Since(r.ReceivedAt) >= recordTTL
 }
 
 // Provider contains dependencies for the Messenger
@@ -47,6 +61,10 @@ type Provider interface {
 type Messenger struct {
 	store      storage.Store
 	dispatcher dispatcher.Outbound
+
+	// nextExpiryCheck is the time from which the expired records can be removed again
+	nextExpiryCheck time.Time
+	mu              sync.Mutex
 }
 
 // NewMessenger returns a new instance of the Messenger
@@ -62,7 +80,8 @@ func NewMessenger(ctx Provider) (*Messenger, error) {
 	}, nil
 }
 
-// HandleInbound handles all inbound messages
+// HandleInbound handles all inbound messages.
+// The message can be replied to (ReplyTo) for 72 hours, the records of the expired messages are removed.
 func (m *Messenger) HandleInbound(msg service.DIDCommMsgMap, myDID, theirDID string) error {
 	// an incoming message cannot be without id
 	if msg.ID() == "" {
@@ -78,7 +97,14 @@ func (m *Messenger) HandleInbound(msg service.DIDCommMsgMap, myDID, theirDID str
 	}
 
 	// saves message payload
-	return m.saveRecord(msg.ID(), record{MyDID: myDID, TheirDID: theirDID, ThreadID: thID})
+	err = m.saveRecord(msg.ID(), record{MyDID: myDID, TheirDID: theirDID, ThreadID: thID, ReceivedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	m.removeExpiredRecords()
+
+	return nil
 }
 
 // Send sends the message by starting a new thread.
@@ -97,6 +123,7 @@ func (m *Messenger) Send(msg service.DIDCommMsgMap, myDID, theirDID string) erro
 // ReplyTo replies to the message by given msgID.
 // The function adds ~thread decorator to the message according to the given msgID.
 // Do not provide a message with ~thread decorator. It will be rewritten.
+// The message can be replied to more than once until its record expires (see HandleInbound).
 func (m *Messenger) ReplyTo(msgID string, msg service.DIDCommMsgMap) error {
 	// fills missing fields
 	fillIfMissing(msg)
@@ -142,18 +169,26 @@ func fillIfMissing(msg service.DIDCommMsgMap) {
 	}
 }
 
-// getRecord returns message payload by msgID
+// getRecord returns message payload by msgID, the expired record is removed
 func (m *Messenger) getRecord(msgID string) (*record, error) {
-	src, err := m.store.Get(msgID)
+	src, err := m.store.Get(recordKey(msgID))
 	if err != nil {
 		return nil, fmt.Errorf("store get: %w", err)
 	}
 
-	var r *record
-	if err = json.Unmarshal(src, &r); err != nil {
+	r := &record{}
+	if err = json.Unmarshal(src, r); err != nil {
 		return nil, fmt.Errorf("unmarshal record: %w", err)
 	}
 
+	if r.expired() {
+		if err = m.store.Delete(recordKey(msgID)); err != nil {
+			return nil, fmt.Errorf("store delete: %w", err)
+		}
+
+		return nil, fmt.Errorf("record expired: %w", storage.ErrDataNotFound)
+	}
+
 	return r, nil
 }
 
@@ -164,5 +199,49 @@ func (m *Messenger) saveRecord(msgID string, rec record) error {
 		return fmt.Errorf("marshal record: %w", err)
 	}
 
-	return m.store.Put(msgID, src)
+	return m.store.Put(recordKey(msgID), src)
+}
+
+// removeExpiredRecords removes the records of the messages which can't be replied to anymore, they are looked up
+// at most once per expiredRecordsCheckInterval.
+func (m *Messenger) removeExpiredRecords() {
+	m.mu.Lock()
+
+	if time.Now().Before(m.nextExpiryCheck) {
+		m.mu.Unlock()
+		return
+	}
+
+	m.nextExpiryCheck = time.Now().Add(expiredRecordsCheckInterval)
+
+	m.mu.Unlock()
+
+	itr := m.store.Iterator(recordKeyPrefix, fmt.Sprintf(limitPattern, recordKeyPrefix))
+	defer itr.Release()
+
+	var expired []storage.Operation
+
+	for itr.Next() {
+		r := &record{}
+		if err := json.Unmarshal(itr.Value(), r); err != nil || r.expired() {
+			expired = append(expired, storage.Operation{Key: string(itr.Key())})
+		}
+	}
+
+	if err := itr.Error(); err != nil {
+		logger.Errorf("iterate messenger records: %s", err)
+		return
+	}
+
+	if len(expired) == 0 {
+		return
+	}
+
+	if err := m.store.Batch(expired); err != nil {
+		logger.Errorf("remove %d expired messenger records: %s", len(expired), err)
+	}
+}
+
+func recordKey(msgID string) string {
+	return recordKeyPrefix + msgID
 }
@@ -7,9 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package messenger_test
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -19,6 +21,8 @@ import (
 	dispatcherMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/dispatcher"
 	messengerMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/messenger"
 	storageMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/storage"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/internal/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
 
 // makes sure it satisfies the interface
@@ -58,11 +62,15 @@ func TestMessenger_HandleInbound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	t.Run("success", func(t *testing.T) {
-		const ID = "ID"
+	const (
+		ID     = "ID"
+		errMsg = "test error"
+	)
 
+	t.Run("success", func(t *testing.T) {
 		store := storageMocks.NewMockStore(ctrl)
-		store.EXPECT().Put(ID, gomock.Any()).Return(nil)
+		store.EXPECT().Put("record_"+ID, gomock.Any()).Return(nil)
+		store.EXPECT().Iterator(gomock.Any(), gomock.Any()).Return(mockstore.NewMockIteratorWithError(nil))
 
 		storageProvider := storageMocks.NewMockProvider(ctrl)
 		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
@@ -78,6 +86,57 @@ func TestMessenger_HandleInbound(t *testing.T) {
 		require.NoError(t, msgr.HandleInbound(service.DIDCommMsgMap{"@id": ID}, "myDID", "theirDID"))
 	})
 
+	t.Run("removes the expired records", func(t *testing.T) {
+		store := &mockstore.MockStore{Store: map[string][]byte{
+			"record_expired": recordJSON(t, time.Now().Add(-73*time.Hour)),
+			"record_invalid": []byte("{"),
+			"record_valid":   recordJSON(t, time.Now().Add(-time.Hour)),
+		}}
+
+		msgr, err := messenger.NewMessenger(newProvider(ctrl, store, nil))
+		require.NoError(t, err)
+
+		require.NoError(t, msgr.HandleInbound(service.DIDCommMsgMap{"@id": ID}, "myDID", "theirDID"))
+		require.Len(t, store.Store, 2)
+		require.Contains(t, store.Store, "record_valid")
+		require.Contains(t, store.Store, "record_"+ID)
+
+		// the expired records are looked up at most once per hour
+		store.Store["record_expired"] = recordJSON(t, time.Now().Add(-73*time.Hour))
+
+		require.NoError(t, msgr.HandleInbound(service.DIDCommMsgMap{"@id": "ID2"}, "myDID", "theirDID"))
+		require.Contains(t, store.Store, "record_expired")
+	})
+
+	t.Run("the expired records removal errors are ignored", func(t *testing.T) {
+		store := &mockstore.MockStore{Store: map[string][]byte{}, ErrItr: errors.New(errMsg)}
+
+		msgr, err := messenger.NewMessenger(newProvider(ctrl, store, nil))
+		require.NoError(t, err)
+
+		require.NoError(t, msgr.HandleInbound(service.DIDCommMsgMap{"@id": ID}, "myDID", "theirDID"))
+
+		store = &mockstore.MockStore{Store: map[string][]byte{
+			"record_expired": recordJSON(t, time.Now().Add(-73*time.Hour)),
+		}, ErrBatch: errors.New(errMsg)}
+
+		msgr, err = messenger.NewMessenger(newProvider(ctrl, store, nil))
+		require.NoError(t, err)
+
+		require.NoError(t, msgr.HandleInbound(service.DIDCommMsgMap{"@id": ID}, "myDID", "theirDID"))
+		require.Contains(t, store.Store, "record_expired")
+	})
+
+	t.Run("save error", func(t *testing.T) {
+		store := &mockstore.MockStore{Store: map[string][]byte{}, ErrPut: errors.New(errMsg)}
+
+		msgr, err := messenger.NewMessenger(newProvider(ctrl, store, nil))
+		require.NoError(t, err)
+
+		err = msgr.HandleInbound(service.DIDCommMsgMap{"@id": ID}, "myDID", "theirDID")
+		require.EqualError(t, err, errMsg)
+	})
+
 	t.Run("absent ID", func(t *testing.T) {
 		storageProvider := storageMocks.NewMockProvider(ctrl)
 		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(nil, nil)
@@ -162,7 +221,7 @@ func TestMessenger_ReplyTo(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		store := storageMocks.NewMockStore(ctrl)
-		store.EXPECT().Get(ID).Return([]byte(`{}`), nil)
+		store.EXPECT().Get("record_"+ID).Return(recordJSON(t, time.Now()), nil)
 
 		storageProvider := storageMocks.NewMockProvider(ctrl)
 		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
@@ -190,9 +249,64 @@ func TestMessenger_ReplyTo(t *testing.T) {
 		require.NoError(t, msgr.ReplyTo(ID, service.DIDCommMsgMap{"@id": ID}))
 	})
 
+	t.Run("success multiple replies until the record expires", func(t *testing.T) {
+		store := &mockstore.MockStore{Store: map[string][]byte{
+			"record_" + ID: recordJSON(t, time.Now().Add(-71*time.Hour)),
+		}}
+
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().SendToDID(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+		msgr, err := messenger.NewMessenger(newProvider(ctrl, store, outbound))
+		require.NoError(t, err)
+
+		require.NoError(t, msgr.ReplyTo(ID, service.DIDCommMsgMap{"@id": ID}))
+		require.NoError(t, msgr.ReplyTo(ID, service.DIDCommMsgMap{"@id": ID}))
+
+		store.Store["record_"+ID] = recordJSON(t, time.Now().Add(-72*time.Hour))
+
+		err = msgr.ReplyTo(ID, service.DIDCommMsgMap{"@id": ID})
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+		require.NotContains(t, store.Store, "record_"+ID)
+	})
+
+	t.Run("expired record delete error", func(t *testing.T) {
+		store := &mockstore.MockStore{Store: map[string][]byte{
+			"record_" + ID: recordJSON(t, time.Now().Add(-73*time.Hour)),
+		}, ErrDelete: errors.New(errMsg)}
+
+		msgr, err := messenger.NewMessenger(newProvider(ctrl, store, nil))
+		require.NoError(t, err)
+
+		err = msgr.ReplyTo(ID, service.DIDCommMsgMap{"@id": ID})
+		require.EqualError(t, err, "get record: store delete: "+errMsg)
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+		store.EXPECT().Get("record_"+ID).Return(recordJSON(t, time.Now()), nil)
+
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
+
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().SendToDID(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New(errMsg))
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(outbound)
+
+		msgr, err := messenger.NewMessenger(provider)
+		require.NoError(t, err)
+		require.NotNil(t, msgr)
+
+		err = msgr.ReplyTo(ID, service.DIDCommMsgMap{"@id": ID})
+		require.Contains(t, fmt.Sprintf("%v", err), errMsg)
+	})
+
 	t.Run("the message was not received", func(t *testing.T) {
 		store := storageMocks.NewMockStore(ctrl)
-		store.EXPECT().Get(ID).Return(nil, errors.New(errMsg))
+		store.EXPECT().Get("record_"+ID).Return(nil, errors.New(errMsg))
 
 		storageProvider := storageMocks.NewMockProvider(ctrl)
 		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
@@ -211,7 +325,7 @@ func TestMessenger_ReplyTo(t *testing.T) {
 
 	t.Run("success msg without id", func(t *testing.T) {
 		store := storageMocks.NewMockStore(ctrl)
-		store.EXPECT().Get(ID).Return([]byte(`{}`), nil)
+		store.EXPECT().Get("record_"+ID).Return(recordJSON(t, time.Now()), nil)
 
 		storageProvider := storageMocks.NewMockProvider(ctrl)
 		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
@@ -311,3 +425,29 @@ func TestMessenger_ReplyToNested(t *testing.T) {
 		require.NoError(t, msgr.ReplyToNested(thID, service.DIDCommMsgMap{"~thread": ""}, myDID, theirDID))
 	})
 }
+
+// newProvider returns the messenger provider with the store and the outbound dispatcher
+func newProvider(ctrl *gomock.Controller, store storage.Store,
+	outbound *dispatcherMocks.MockOutbound) messenger.Provider {
+	storageProvider := storageMocks.NewMockProvider(ctrl)
+	storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
+
+	provider := messengerMocks.NewMockProvider(ctrl)
+	provider.EXPECT().StorageProvider().Return(storageProvider)
+	provider.EXPECT().OutboundDispatcher().Return(outbound)
+
+	return provider
+}
+
+// recordJSON returns the record of the message received at receivedAt
+func recordJSON(t *testing.T, receivedAt time.Time) []byte {
+	src, err := json.Marshal(map[string]interface{}{
+		"MyDID":      "myDID",
+		"TheirDID":   "theirDID",
+		"ThreadID":   "thID",
+		"ReceivedAt": receivedAt,
+	})
+	require.NoError(t, err)
+
+	return src
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	. "github.com/hyperledger/aries-framework-go/pkg/didcomm/packager"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/jwe/anoncrypt"
 	jwe "github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/jwe/authcrypt"
 	legacy "github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/legacy/authcrypt"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
@@ -253,6 +254,43 @@ func TestBaseKMSInPackager_UnpackMessage(t *testing.T) {
 		require.Equal(t, unpackedMsg.Message, []byte("msg2"))
 	})
 
+	t.Run("test Pack/Unpack anoncrypt success", func(t *testing.T) {
+		// create a mock LegacyKMS with storage as a map
+		w, err := legacykms.New(newMockKMSProvider(mockstorage.NewMockStoreProvider()))
+		require.NoError(t, err)
+		mockedProviders := &mockProvider{
+			storage:       mockstorage.NewMockStoreProvider(),
+			kms:           w,
+			primaryPacker: nil,
+			packers:       nil,
+		}
+
+		anonPacker, err := anoncrypt.New(mockedProviders)
+		require.NoError(t, err)
+
+		authPacker, err := jwe.New(mockedProviders, jwe.XC20P)
+		require.NoError(t, err)
+
+		// anoncrypt messages are detected by their encoding type when authcrypt is the primary packer
+		mockedProviders.primaryPacker = authPacker
+		mockedProviders.packers = []packer.Packer{anonPacker}
+
+		packager, err := New(mockedProviders)
+		require.NoError(t, err)
+
+		_, base58ToVerKey, err := w.CreateKeySet()
+		require.NoError(t, err)
+
+		packMsg, err := anonPacker.Pack([]byte("msg1"), nil, [][]byte{base58.Decode(base58ToVerKey)})
+		require.NoError(t, err)
+
+		unpackedMsg, err := packager.UnpackMessage(packMsg)
+		require.NoError(t, err)
+		require.Equal(t, []byte("msg1"), unpackedMsg.Message)
+		require.Empty(t, unpackedMsg.FromVerKey)
+		require.Empty(t, unpackedMsg.FromDID)
+	})
+
 	t.Run("test success - dids not found", func(t *testing.T) {
 		// create a mock LegacyKMS with storage as a map
 		w, err := legacykms.New(newMockKMSProvider(mockstorage.NewMockStoreProvider()))
@@ -163,11 +163,16 @@ func (bp *Packager) UnpackMessage(encMessage []byte) (*transport.Envelope, error
 		return nil, fmt.Errorf("unpack: %w", err)
 	}
 
-	//	ignore error - agents can communicate without using DIDs - for example, in DIDExchange
-	theirDID, err := bp.connectionStore.GetDID(base58.Encode(envelope.FromVerKey))
-	if errors.Is(err, did.ErrNotFound) {
-	} else if err != nil {
-		return nil, fmt.Errorf("failed to get their did: %w", err)
+	var theirDID string
+
+	// anonymous envelopes (eg: anoncrypt) don't have a sender key
+	if len(envelope.FromVerKey) > 0 {
+		//	ignore error - agents can communicate without using DIDs - for example, in DIDExchange
+		theirDID, err = bp.connectionStore.GetDID(base58.Encode(envelope.FromVerKey))
+		if errors.Is(err, did.ErrNotFound) {
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get their did: %w", err)
+		}
 	}
 
 	// ignore error - at beginning of DIDExchange, you might be about to generate a DID
@@ -0,0 +1,88 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anoncrypt
+
+import (
+	"crypto/rand"
+	"io"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+)
+
+// This package deals with Anoncrypt encryption for Packing/Unpacking DID Comm exchange.
+// The payload is encrypted with XChacha20Poly1305, the content encryption key is wrapped for each recipient with
+// a new ephemeral key (ECDH-ES) so the envelope doesn't reveal the sender.
+
+const (
+	// encodingType is the `typ` string identifier in a message that identifies the format as being JWE anoncrypt
+	encodingType = "prs.hyperledger.aries-anon-message"
+	// contentEncryption is the `enc` JWE header: XChacha20 encryption + Poly1305 authenticator (192 bits nonce)
+	contentEncryption = "XC20P"
+	// keyWrapAlg is the `alg` JWE header: ECDH-ES key agreement with the legacy KMS and XChacha20Poly1305 key
+	// wrapping, the wrapped key nonce and tag are in the recipient headers. It is distinct from
+	// crypto.ECDHESXC20PKWAlg as the KEK derivation and the wrapped key format are not the ones of crypto.WrapKey.
+	keyWrapAlg = "Anoncrypt-ECDH-ES+XC20PKW"
+)
+
+// Packer represents an Anoncrypt Packer/Unpacker that outputs/reads JWE envelopes without sender information
+type Packer struct {
+	legacyKMS  legacykms.KeyManager
+	randReader io.Reader
+}
+
+// Envelope represents a JWE envelope as per the Aries Encryption envelope specs
+type Envelope struct {
+	Protected  string      `json:"protected,omitempty"`
+	Recipients []Recipient `json:"recipients,omitempty"`
+	AAD        string      `json:"aad,omitempty"`
+	IV         string      `json:"iv,omitempty"`
+	Tag        string      `json:"tag,omitempty"`
+	CipherText string      `json:"ciphertext,omitempty"`
+}
+
+// jweHeaders are the Protected JWE headers in a map format
+type jweHeaders struct {
+	Typ string `json:"typ,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Enc string `json:"enc,omitempty"`
+}
+
+// Recipient is a recipient of an envelope including the shared encryption key
+type Recipient struct {
+	EncryptedKey string           `json:"encrypted_key,omitempty"`
+	Header       RecipientHeaders `json:"header,omitempty"`
+}
+
+// RecipientHeaders are the recipient headers, EPK is the ephemeral public key used to wrap the recipient's key
+type RecipientHeaders struct {
+	KID string `json:"kid,omitempty"`
+	EPK JWK    `json:"epk,omitempty"`
+	IV  string `json:"iv,omitempty"`
+	Tag string `json:"tag,omitempty"`
+}
+
+// JWK formatted X25519 public key
+type JWK struct {
+	Kty string `json:"kty,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// New will create a Packer instance to 'AnonCrypt' payloads for a list of recipients. The envelopes are encrypted
+// with XC20P (xchacha20-poly1305 ietf).
+func New(ctx packer.Provider) (*Packer, error) {
+	return &Packer{
+		legacyKMS:  ctx.LegacyKMS(),
+		randReader: rand.Reader,
+	}, nil
+}
+
+// EncodingType returns the type of the encoding, as in the `Typ` field of the envelope header
+func (p *Packer) EncodingType() string {
+	return encodingType
+}
@@ -0,0 +1,236 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anoncrypt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/internal/mock/kms/legacykms"
+)
+
+func TestEncodingType(t *testing.T) {
+	kmsProvider, err := mockkms.NewMockProvider()
+	require.NoError(t, err)
+
+	packer, err := New(kmsProvider)
+	require.NoError(t, err)
+	require.NotEmpty(t, packer)
+
+	require.Equal(t, encodingType, packer.EncodingType())
+}
+
+func TestPackUnpack(t *testing.T) {
+	recipient1 := newKeys(t)
+	recipient2 := newKeys(t)
+	other := newKeys(t)
+
+	senderProvider, err := mockkms.NewMockProvider()
+	require.NoError(t, err)
+
+	sender, err := New(senderProvider)
+	require.NoError(t, err)
+
+	rec1Provider, err := mockkms.NewMockProvider(recipient1)
+	require.NoError(t, err)
+
+	rec1Packer, err := New(rec1Provider)
+	require.NoError(t, err)
+
+	rec2Provider, err := mockkms.NewMockProvider(recipient2)
+	require.NoError(t, err)
+
+	rec2Packer, err := New(rec2Provider)
+	require.NoError(t, err)
+
+	otherProvider, err := mockkms.NewMockProvider(other)
+	require.NoError(t, err)
+
+	otherPacker, err := New(otherProvider)
+	require.NoError(t, err)
+
+	payload := []byte("lorem ipsum dolor sit amet")
+	recipients := [][]byte{recipient1.SigKeyPair.Pub, recipient2.SigKeyPair.Pub}
+
+	t.Run("Success test case: pack and unpack for each recipient", func(t *testing.T) {
+		envelope, err := sender.Pack(payload, []byte("ignored sender key"), recipients)
+		require.NoError(t, err)
+
+		jwe := &Envelope{}
+		require.NoError(t, json.Unmarshal(envelope, jwe))
+		require.Len(t, jwe.Recipients, 2)
+		// each recipient has its own ephemeral key
+		require.NotEqual(t, jwe.Recipients[0].Header.EPK.X, jwe.Recipients[1].Header.EPK.X)
+
+		h, err := base64.RawURLEncoding.DecodeString(jwe.Protected)
+		require.NoError(t, err)
+		require.JSONEq(t,
+			`{"typ":"prs.hyperledger.aries-anon-message","alg":"Anoncrypt-ECDH-ES+XC20PKW","enc":"XC20P"}`,
+			string(h))
+
+		env, err := rec1Packer.Unpack(envelope)
+		require.NoError(t, err)
+		require.Equal(t, payload, env.Message)
+		require.Empty(t, env.FromVerKey)
+		require.Equal(t, recipient1.EncKeyPair.Pub, env.ToVerKey)
+
+		env, err = rec2Packer.Unpack(envelope)
+		require.NoError(t, err)
+		require.Equal(t, payload, env.Message)
+		require.Equal(t, recipient2.EncKeyPair.Pub, env.ToVerKey)
+	})
+
+	t.Run("Failure test case: unpack by an agent which is not a recipient", func(t *testing.T) {
+		envelope, err := sender.Pack(payload, nil, recipients)
+		require.NoError(t, err)
+
+		_, err = otherPacker.Unpack(envelope)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unpack: ")
+	})
+
+	t.Run("Failure test case: pack with invalid recipients", func(t *testing.T) {
+		_, err := sender.Pack(payload, nil, nil)
+		require.EqualError(t, err, "failed to pack message: empty recipients")
+
+		_, err = sender.Pack(payload, nil, [][]byte{[]byte("bad key")})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, cryptoutil.ErrInvalidKey))
+	})
+
+	t.Run("Failure test case: pack with a failing random reader", func(t *testing.T) {
+		p, err := New(senderProvider)
+		require.NoError(t, err)
+
+		// content encryption key and payload nonce
+		for _, n := range []int{0, 1} {
+			p.randReader = &failingReader{reads: n}
+
+			_, err = p.Pack(payload, nil, recipients)
+			require.EqualError(t, err, "failing reader")
+		}
+
+		// ephemeral key and recipient nonce
+		for _, n := range []int{2, 3} {
+			p.randReader = &failingReader{reads: n}
+
+			_, err = p.Pack(payload, nil, recipients)
+			require.EqualError(t, err, "failed to pack message: failing reader")
+		}
+	})
+
+	t.Run("Failure test case: unpack invalid envelopes", func(t *testing.T) {
+		_, err := rec1Packer.Unpack([]byte("{"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unpack json:")
+
+		envelope, err := sender.Pack(payload, nil, recipients)
+		require.NoError(t, err)
+
+		tests := []struct {
+			name   string
+			update func(jwe *Envelope)
+			errMsg string
+		}{
+			{name: "bad protected headers encoding", update: func(jwe *Envelope) { jwe.Protected = "@" },
+				errMsg: "unpack: decode headers:"},
+			{name: "bad protected headers", update: func(jwe *Envelope) {
+				jwe.Protected = base64.RawURLEncoding.EncodeToString([]byte("{"))
+			}, errMsg: "unpack: parse headers:"},
+			{name: "unsupported alg", update: func(jwe *Envelope) {
+				jwe.Protected = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"A256KW","enc":"XC20P"}`))
+			}, errMsg: "unpack: unsupported alg 'A256KW' and enc 'XC20P'"},
+			{name: "crypto.WrapKey alg", update: func(jwe *Envelope) {
+				jwe.Protected = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ECDH-ES+XC20PKW","enc":"XC20P"}`))
+			}, errMsg: "unpack: unsupported alg 'ECDH-ES+XC20PKW' and enc 'XC20P'"},
+			{name: "unsupported epk", update: func(jwe *Envelope) { jwe.Recipients[0].Header.EPK.Crv = "P-256" },
+				errMsg: "unpack: decrypt shared key: unsupported ephemeral key type"},
+			{name: "bad epk encoding", update: func(jwe *Envelope) { jwe.Recipients[0].Header.EPK.X = "@" },
+				errMsg: "unpack: decrypt shared key: illegal base64 data"},
+			{name: "bad epk size", update: func(jwe *Envelope) { jwe.Recipients[0].Header.EPK.X = "AQ" },
+				errMsg: "unpack: decrypt shared key: invalid ephemeral key"},
+			{name: "bad encrypted key", update: func(jwe *Envelope) { jwe.Recipients[0].EncryptedKey = "@" },
+				errMsg: "unpack: decrypt shared key: illegal base64 data"},
+			{name: "bad recipient tag", update: func(jwe *Envelope) { jwe.Recipients[0].Header.Tag = "@" },
+				errMsg: "unpack: decrypt shared key: illegal base64 data"},
+			{name: "bad recipient nonce", update: func(jwe *Envelope) { jwe.Recipients[0].Header.IV = "@" },
+				errMsg: "unpack: decrypt shared key: illegal base64 data"},
+			{name: "bad recipient nonce size", update: func(jwe *Envelope) { jwe.Recipients[0].Header.IV = "AQ" },
+				errMsg: "unpack: decrypt shared key: bad nonce size"},
+			{name: "other epk", update: func(jwe *Envelope) {
+				jwe.Recipients[0].Header.EPK.X = jwe.Recipients[1].Header.EPK.X
+			}, errMsg: "unpack: decrypt shared key: chacha20poly1305: message authentication failed"},
+			{name: "altered aad", update: func(jwe *Envelope) {
+				jwe.AAD = base64.RawURLEncoding.EncodeToString([]byte("aad"))
+			}, errMsg: "unpack: decrypt payload: chacha20poly1305: message authentication failed"},
+			{name: "altered cipher text", update: func(jwe *Envelope) { jwe.CipherText = "AAAA" },
+				errMsg: "unpack: decrypt payload: chacha20poly1305: message authentication failed"},
+		}
+
+		for _, tc := range tests {
+			tc := tc
+
+			t.Run(tc.name, func(t *testing.T) {
+				jwe := &Envelope{}
+				require.NoError(t, json.Unmarshal(envelope, jwe))
+
+				tc.update(jwe)
+
+				altered, err := json.Marshal(jwe)
+				require.NoError(t, err)
+
+				_, err = rec1Packer.Unpack(altered)
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+}
+
+func newKeys(t *testing.T) *cryptoutil.MessagingKeys {
+	sigPubKey, sigPrivKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	encPubKey, err := cryptoutil.PublicEd25519toCurve25519(sigPubKey)
+	require.NoError(t, err)
+
+	encPrivKey, err := cryptoutil.SecretEd25519toCurve25519(sigPrivKey)
+	require.NoError(t, err)
+
+	return &cryptoutil.MessagingKeys{
+		SigKeyPair: &cryptoutil.SigKeyPair{
+			KeyPair: cryptoutil.KeyPair{Pub: sigPubKey, Priv: sigPrivKey},
+			Alg:     cryptoutil.EdDSA,
+		},
+		EncKeyPair: &cryptoutil.EncKeyPair{
+			KeyPair: cryptoutil.KeyPair{Pub: encPubKey, Priv: encPrivKey},
+			Alg:     cryptoutil.Curve25519,
+		},
+	}
+}
+
+// failingReader fails after a number of successful reads
+type failingReader struct {
+	reads int
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.reads == 0 {
+		return 0, errors.New("failing reader")
+	}
+
+	r.reads--
+
+	return rand.Read(p)
+}
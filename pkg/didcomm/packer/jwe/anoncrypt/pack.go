@@ -0,0 +1,183 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anoncrypt
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	chacha "golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/crypto/poly1305"
+
+	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
+)
+
+// Pack will JWE encode the payload argument for the recipients using XChacha20 encryption algorithm and
+// Poly1305 authenticator. The sender key is ignored: the content encryption key is wrapped for each recipient
+// using a new ephemeral key, the envelope can't be linked to its sender.
+func (p *Packer) Pack(payload, _ []byte, recipientsVerKeys [][]byte) ([]byte, error) {
+	if len(recipientsVerKeys) == 0 {
+		return nil, errors.New("failed to pack message: empty recipients")
+	}
+
+	recipients, err := convertRecipients(recipientsVerKeys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack message: %w", err)
+	}
+
+	h, err := json.Marshal(jweHeaders{
+		Typ: encodingType,
+		Alg: keyWrapAlg,
+		Enc: contentEncryption,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	encHeaders := base64.RawURLEncoding.EncodeToString(h)
+	aadEncoded := base64.RawURLEncoding.EncodeToString(buildAAD(recipients))
+
+	cek := &[chacha.KeySize]byte{}
+
+	// generate a cek for encryption (it will be treated as a symmetric key)
+	_, err = p.randReader.Read(cek[:])
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, tag, nonce, err := p.encrypt(cek[:], payload, []byte(encHeaders+"."+aadEncoded))
+	if err != nil {
+		return nil, err
+	}
+
+	var encRecipients []Recipient
+
+	for _, r := range recipients {
+		rec, e := p.encodeRecipient(cek, r)
+		if e != nil {
+			return nil, fmt.Errorf("failed to pack message: %w", e)
+		}
+
+		encRecipients = append(encRecipients, *rec)
+	}
+
+	return json.Marshal(Envelope{
+		Protected:  encHeaders,
+		Recipients: encRecipients,
+		AAD:        aadEncoded,
+		IV:         nonce,
+		Tag:        tag,
+		CipherText: ciphertext,
+	})
+}
+
+// encodeRecipient will wrap the cek (content encryption key) for recipientPubKey with a new ephemeral key
+// and return a JWE compliant Recipient
+func (p *Packer) encodeRecipient(cek, recipientPubKey *[chacha.KeySize]byte) (*Recipient, error) {
+	// generate ephemeral asymmetric keys
+	epk, esk, err := box.GenerateKey(p.randReader)
+	if err != nil {
+		return nil, err
+	}
+
+	kek, err := cryptoutil.Derive25519KEK([]byte(keyWrapAlg), nil, esk, recipientPubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedKey, tag, nonce, err := p.encrypt(kek, cek[:], nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Recipient{
+		EncryptedKey: encryptedKey,
+		Header: RecipientHeaders{
+			KID: base58.Encode(recipientPubKey[:]),
+			EPK: JWK{
+				Kty: "OKP",
+				Crv: "X25519",
+				X:   base64.RawURLEncoding.EncodeToString(epk[:]),
+			},
+			IV:  nonce,
+			Tag: tag,
+		},
+	}, nil
+}
+
+// encrypt will encrypt msg with key and a newly generated nonce
+// returns:
+// 		base64 encoded cipher text of msg
+//		base64 encoded tag of the encryption
+//		base64 encoded nonce used by the encryption
+//		error in case of failure
+func (p *Packer) encrypt(key, msg, aad []byte) (string, string, string, error) {
+	cipher, err := chacha.NewX(key)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	nonce := make([]byte, chacha.NonceSizeX)
+
+	_, err = p.randReader.Read(nonce)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	// the output is a []byte containing the cipherText + tag
+	symOutput := cipher.Seal(nil, nonce, msg, aad)
+	cipherText := symOutput[:len(symOutput)-poly1305.TagSize]
+	tag := symOutput[len(symOutput)-poly1305.TagSize:]
+
+	return base64.RawURLEncoding.EncodeToString(cipherText), base64.RawURLEncoding.EncodeToString(tag),
+		base64.RawURLEncoding.EncodeToString(nonce), nil
+}
+
+// convertRecipients is a utility function that converts keys from signature keys ([][]byte type)
+// into encryption keys ([]*[chacha.KeySize]byte type)
+func convertRecipients(recipients [][]byte) ([]*[chacha.KeySize]byte, error) {
+	var chachaRecipients []*[chacha.KeySize]byte
+
+	for i, rVer := range recipients {
+		if !cryptoutil.IsChachaKeyValid(rVer) {
+			return nil, fmt.Errorf("%w - for recipient %d", cryptoutil.ErrInvalidKey, i+1)
+		}
+
+		rEnc, err := cryptoutil.PublicEd25519toCurve25519(rVer)
+		if err != nil {
+			return nil, err
+		}
+
+		chachaRec := new([chacha.KeySize]byte)
+		copy(chachaRec[:], rEnc)
+		chachaRecipients = append(chachaRecipients, chachaRec)
+	}
+
+	return chachaRecipients, nil
+}
+
+// buildAAD is a utility function to build the Additional Authentication Data for the AEAD (chach20poly1305) cipher.
+// the build takes the list of recipients keys base58 encoded and sorted then SHA256 hash
+// the concatenation of these keys with a '.' separator
+func buildAAD(recipients []*[chacha.KeySize]byte) []byte {
+	var keys []string
+	for _, r := range recipients {
+		keys = append(keys, base58.Encode(r[:]))
+	}
+
+	sort.Strings(keys)
+	sha := sha256.Sum256([]byte(strings.Join(keys, ".")))
+
+	return sha[:]
+}
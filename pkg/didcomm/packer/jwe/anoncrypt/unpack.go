@@ -0,0 +1,142 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package anoncrypt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+	chacha "golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
+)
+
+// Unpack will JWE decode the envelope argument for the first recipient of the envelope found in the LegacyKMS.
+// The content encryption key of the recipient is unwrapped with the ephemeral key of its header.
+// The returned envelope has no sender key.
+func (p *Packer) Unpack(envelope []byte) (*transport.Envelope, error) {
+	jwe := &Envelope{}
+
+	err := json.Unmarshal(envelope, jwe)
+	if err != nil {
+		return nil, fmt.Errorf("unpack json: %w", err)
+	}
+
+	if err = checkHeaders(jwe.Protected); err != nil {
+		return nil, fmt.Errorf("unpack: %w", err)
+	}
+
+	recipientPubKey, recipient, err := p.findRecipient(jwe.Recipients)
+	if err != nil {
+		return nil, fmt.Errorf("unpack: %w", err)
+	}
+
+	cek, err := p.decryptCEK(recipientPubKey, recipient)
+	if err != nil {
+		return nil, fmt.Errorf("unpack: decrypt shared key: %w", err)
+	}
+
+	payload, err := decrypt(cek, jwe.CipherText, jwe.Tag, jwe.IV, []byte(jwe.Protected+"."+jwe.AAD))
+	if err != nil {
+		return nil, fmt.Errorf("unpack: decrypt payload: %w", err)
+	}
+
+	return &transport.Envelope{
+		Message:  payload,
+		ToVerKey: recipientPubKey,
+	}, nil
+}
+
+func checkHeaders(protected string) error {
+	h, err := base64.RawURLEncoding.DecodeString(protected)
+	if err != nil {
+		return fmt.Errorf("decode headers: %w", err)
+	}
+
+	headers := &jweHeaders{}
+
+	err = json.Unmarshal(h, headers)
+	if err != nil {
+		return fmt.Errorf("parse headers: %w", err)
+	}
+
+	if headers.Alg != keyWrapAlg || headers.Enc != contentEncryption {
+		return fmt.Errorf("unsupported alg '%s' and enc '%s'", headers.Alg, headers.Enc)
+	}
+
+	return nil
+}
+
+// findRecipient will loop through jweRecipients and returns the first matching key from the legacyKMS
+func (p *Packer) findRecipient(jweRecipients []Recipient) ([]byte, *Recipient, error) {
+	var recipientsKeys []string
+	for _, recipient := range jweRecipients {
+		recipientsKeys = append(recipientsKeys, recipient.Header.KID)
+	}
+
+	i, err := p.legacyKMS.FindVerKey(recipientsKeys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return base58.Decode(recipientsKeys[i]), &jweRecipients[i], nil
+}
+
+// decryptCEK will unwrap the CEK found in recipient using the recipient's private key and the ephemeral public key
+func (p *Packer) decryptCEK(recipientPubKey []byte, recipient *Recipient) ([]byte, error) {
+	if recipient.Header.EPK.Kty != "OKP" || recipient.Header.EPK.Crv != "X25519" {
+		return nil, errors.New("unsupported ephemeral key type")
+	}
+
+	epk, err := base64.RawURLEncoding.DecodeString(recipient.Header.EPK.X)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(epk) != chacha.KeySize {
+		return nil, errors.New("invalid ephemeral key")
+	}
+
+	kek, err := p.legacyKMS.DeriveKEK([]byte(keyWrapAlg), nil, recipientPubKey, epk)
+	if err != nil {
+		return nil, err
+	}
+
+	return decrypt(kek, recipient.EncryptedKey, recipient.Header.Tag, recipient.Header.IV, nil)
+}
+
+// decrypt the base64 encoded cipherText and tag with key and the base64 encoded nonce
+func decrypt(key []byte, cipherText, tag, nonce string, aad []byte) ([]byte, error) {
+	cipher, err := chacha.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ct, err := base64.RawURLEncoding.DecodeString(cipherText)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := base64.RawURLEncoding.DecodeString(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := base64.RawURLEncoding.DecodeString(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(n) != chacha.NonceSizeX {
+		return nil, errors.New("bad nonce size")
+	}
+
+	return cipher.Open(nil, n, append(ct, t...), aad)
+}
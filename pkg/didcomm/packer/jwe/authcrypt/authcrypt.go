@@ -13,12 +13,14 @@ import (
 
 	chacha "golang.org/x/crypto/chacha20poly1305"
 
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
 )
 
 // This package deals with Authcrypt encryption for Packing/Unpacking DID Comm exchange
-// Using Chacha20Poly1305 encryption/authentication
+// Using Chacha20Poly1305 encryption/authentication for Ed25519 keys and AES-GCM for NIST P-256 and P-384 keys
 
 // ContentEncryption represents a content encryption algorithm.
 type ContentEncryption string
@@ -30,6 +32,8 @@ const (
 	XC20P = ContentEncryption("XC20P") // XChacha20 encryption + Poly1305 authenticator cipher (192 bits nonce)
 	// encodingType is the `typ` string identifier in a message that identifies the format as being JWE
 	encodingType string = "prs.hyperledger.aries-auth-message"
+	// a256GCM is the content encryption of envelopes packed for NIST P-256 and P-384 keys (AES-GCM 256 bits key)
+	a256GCM = "A256GCM"
 )
 
 // errUnsupportedAlg is used when a bad encryption algorithm is used
@@ -43,6 +47,8 @@ type Packer struct {
 	alg        ContentEncryption
 	nonceSize  int
 	legacyKMS  legacykms.KeyManager
+	kms        kms.KeyManager
+	crypto     crypto.Crypto
 	randReader io.Reader
 }
 
@@ -76,6 +82,7 @@ type RecipientHeaders struct {
 	Tag string `json:"tag,omitempty"`
 	KID string `json:"kid,omitempty"`
 	SPK string `json:"spk,omitempty"`
+	EPK *jwk   `json:"epk,omitempty"`
 }
 
 // recipientSPKJWEHeaders are the Protected JWE headers of a recipient's SPK field (which is a JWE with a JWK payload)
@@ -94,12 +101,24 @@ type jwk struct {
 	Kty string `json:"kty,omitempty"`
 	Crv string `json:"crv,omitempty"`
 	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	KID string `json:"kid,omitempty"`
+}
+
+// Provider contains the dependencies of the Packer for NIST P-256 and P-384 keys. When the packer.Provider passed to
+// New implements it, the Packer uses its KMS and Crypto to pack and unpack envelopes for NIST keys.
+type Provider interface {
+	packer.Provider
+	KMS() kms.KeyManager
+	Crypto() crypto.Crypto
 }
 
 // New will create an Packer instance to 'AuthCrypt' payloads for the given sender and recipients arguments
 // and the encryption alg argument. Possible algorithms supported are:
 // C20P (chacha20-poly1305 ietf)
 // XC20P (xchacha20-poly1305 ietf)
+// The algorithm is used for Ed25519 keys, envelopes for NIST P-256 and P-384 keys are always encrypted with A256GCM
+// using the KMS and Crypto of ctx if it implements Provider.
 // The returned Packer contains all the information required to pack and unpack payloads.
 func New(ctx packer.Provider, alg ContentEncryption) (*Packer, error) {
 	k := ctx.LegacyKMS()
@@ -115,12 +134,19 @@ func New(ctx packer.Provider, alg ContentEncryption) (*Packer, error) {
 		return nil, errUnsupportedAlg
 	}
 
-	return &Packer{
+	p := &Packer{
 		alg:        alg,
 		nonceSize:  nonceSize,
 		legacyKMS:  k,
 		randReader: rand.Reader,
-	}, nil
+	}
+
+	if nistCtx, ok := ctx.(Provider); ok {
+		p.kms = nistCtx.KMS()
+		p.crypto = nistCtx.Crypto()
+	}
+
+	return p, nil
 }
 
 // EncodingType returns the type of the encoding, as in the `Typ` field of the envelope header
@@ -25,7 +25,19 @@ import (
 // Using (X)Chacha20 encryption algorithm and Poly1305 authenticator
 // It will encrypt by fetching the sender's encryption key corresponding to senderVerKey and converting the list
 // of recipientsVerKeys into a list of encryption keys
+// If the recipients keys are NIST P-256 or P-384 keys (JSON marshalled crypto.PublicKey), the payload is encrypted
+// with A256GCM and senderVerKey must be the NIST public key of the sender in the same encoding, its KID is used to
+// find the sender key in the KMS. All the recipients keys must have the same key type.
 func (p *Packer) Pack(payload, senderVerKey []byte, recipientsVerKeys [][]byte) ([]byte, error) { //nolint:funlen
+	nist, err := isNISTRecipients(recipientsVerKeys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack message: %w", err)
+	}
+
+	if nist {
+		return p.packNIST(payload, senderVerKey, recipientsVerKeys)
+	}
+
 	senderPubKey, err := p.getSenderPubEncKey(senderVerKey)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,328 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package authcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/google/tink/go/keyset"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/ecdhkw"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
+)
+
+const (
+	cekSize      = 32
+	gcmNonceSize = 12
+	apuSize      = 64
+)
+
+// errNISTKeysNotSupported is returned when NIST keys are used with a Packer created without KMS or Crypto
+var errNISTKeysNotSupported = errors.New("KMS and Crypto are required for NIST P-256 and P-384 keys")
+
+// errMixedKeyTypes is returned when the recipients keys are not all NIST keys or all Ed25519 keys
+var errMixedKeyTypes = errors.New("recipients keys must have the same key type")
+
+// nistPublicKey parses key, a JSON marshalled crypto.PublicKey. It returns false if key is not a NIST P-256 or P-384
+// public key.
+func nistPublicKey(key []byte) (*crypto.PublicKey, bool) {
+	pubKey := &crypto.PublicKey{}
+
+	if err := json.Unmarshal(key, pubKey); err != nil {
+		return nil, false
+	}
+
+	if pubKey.Type != crypto.ECKeyType || (pubKey.Curve != crypto.P256Curve && pubKey.Curve != crypto.P384Curve) {
+		return nil, false
+	}
+
+	return pubKey, true
+}
+
+// isNISTRecipients returns true if recipientsKeys are NIST keys, it fails if only some of them are NIST keys.
+func isNISTRecipients(recipientsKeys [][]byte) (bool, error) {
+	nistKeys := 0
+
+	for _, key := range recipientsKeys {
+		if _, ok := nistPublicKey(key); ok {
+			nistKeys++
+		}
+	}
+
+	if nistKeys > 0 && nistKeys != len(recipientsKeys) {
+		return false, errMixedKeyTypes
+	}
+
+	return nistKeys > 0, nil
+}
+
+// packNIST will JWE encode the payload argument with A256GCM for recipients NIST keys. The content encryption key is
+// wrapped with ECDH-1PU+A256KW using the sender key handle found in the KMS with the KID of the sender key.
+// senderKey and recipientsKeys are JSON marshalled crypto.PublicKey having the KID of their KMS keys.
+func (p *Packer) packNIST(payload, senderKey []byte, recipientsKeys [][]byte) ([]byte, error) {
+	if len(senderKey) == 0 {
+		return nil, errors.New("failed to pack message: empty sender key")
+	}
+
+	if p.kms == nil || p.crypto == nil {
+		return nil, fmt.Errorf("failed to pack message: %w", errNISTKeysNotSupported)
+	}
+
+	senderKH, senderPubKey, err := p.nistSenderKey(senderKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack message: %w", err)
+	}
+
+	recipients, err := convertNISTRecipients(recipientsKeys, senderPubKey.Curve)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack message: %w", err)
+	}
+
+	var kids []string
+	for _, r := range recipients {
+		kids = append(kids, r.KID)
+	}
+
+	aadEncoded := base64.RawURLEncoding.EncodeToString(hashAAD(kids))
+
+	h, err := json.Marshal(jweHeaders{
+		Typ: encodingType,
+		Alg: crypto.ECDH1PUA256KWAlg,
+		Enc: a256GCM,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	encHeaders := base64.RawURLEncoding.EncodeToString(h)
+
+	cek := make([]byte, cekSize)
+
+	_, err = p.randReader.Read(cek)
+	if err != nil {
+		return nil, err
+	}
+
+	cipherText, tag, nonce, err := p.encryptGCM(cek, payload, []byte(encHeaders+"."+aadEncoded))
+	if err != nil {
+		return nil, err
+	}
+
+	var encRecipients []Recipient
+
+	for _, r := range recipients {
+		rec, e := p.encodeNISTRecipient(cek, r, senderKH, senderPubKey)
+		if e != nil {
+			return nil, fmt.Errorf("failed to pack message: %w", e)
+		}
+
+		encRecipients = append(encRecipients, *rec)
+	}
+
+	return p.buildJWE(encHeaders, encRecipients, aadEncoded, nonce, tag, cipherText)
+}
+
+// nistSenderKey returns the key handle of senderKey found in the KMS with its KID and its public key. senderKey must
+// be the public key of the KMS key.
+func (p *Packer) nistSenderKey(senderKey []byte) (interface{}, *crypto.PublicKey, error) {
+	key, ok := nistPublicKey(senderKey)
+	if !ok || key.KID == "" {
+		return nil, nil, fmt.Errorf("%w - for sender", cryptoutil.ErrInvalidKey)
+	}
+
+	kh, err := p.kms.Get(key.KID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pubKey, err := keyHandlePublicKey(kh, key.KID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if pubKey.Curve != key.Curve ||
+		new(big.Int).SetBytes(pubKey.X).Cmp(new(big.Int).SetBytes(key.X)) != 0 ||
+		new(big.Int).SetBytes(pubKey.Y).Cmp(new(big.Int).SetBytes(key.Y)) != 0 {
+		return nil, nil, fmt.Errorf("sender key doesn't match the KMS key '%s'", key.KID)
+	}
+
+	return kh, pubKey, nil
+}
+
+// keyHandlePublicKey returns the NIST public key of the key handle kh with kid as KID
+func keyHandlePublicKey(kh interface{}, kid string) (*crypto.PublicKey, error) {
+	keyHandle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errors.New("bad key handle format")
+	}
+
+	pubKey, err := ecdhkw.PublicKey(keyHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	if pubKey.Type != crypto.ECKeyType {
+		return nil, errors.New("not a NIST P-256 or P-384 key")
+	}
+
+	pubKey.KID = kid
+
+	return pubKey, nil
+}
+
+// convertNISTRecipients parses recipientsKeys, all the recipients keys must be NIST keys on curve
+func convertNISTRecipients(recipientsKeys [][]byte, curve string) ([]*crypto.PublicKey, error) {
+	var recipients []*crypto.PublicKey
+
+	for i, key := range recipientsKeys {
+		pubKey, ok := nistPublicKey(key)
+		if !ok || pubKey.KID == "" {
+			return nil, fmt.Errorf("%w - for recipient %d", cryptoutil.ErrInvalidKey, i+1)
+		}
+
+		if pubKey.Curve != curve {
+			return nil, fmt.Errorf("recipient %d key curve '%s' doesn't match sender key curve '%s'",
+				i+1, pubKey.Curve, curve)
+		}
+
+		recipients = append(recipients, pubKey)
+	}
+
+	return recipients, nil
+}
+
+// encodeNISTRecipient wraps cek for recipientPubKey with ECDH-1PU using the sender key handle and adds the sender
+// public key to the recipient headers as an SPK encrypted for the recipient with ECDH-ES
+func (p *Packer) encodeNISTRecipient(cek []byte, recipientPubKey *crypto.PublicKey, senderKH interface{},
+	senderPubKey *crypto.PublicKey) (*Recipient, error) {
+	// generate a random APU value (Agreement PartyUInfo: https://tools.ietf.org/html/rfc7518#section-4.6.1.2)
+	apu := make([]byte, apuSize)
+
+	_, err := p.randReader.Read(apu)
+	if err != nil {
+		return nil, err
+	}
+
+	wk, err := p.crypto.WrapKey(cek, apu, nil, recipientPubKey, crypto.WithSender(senderKH))
+	if err != nil {
+		return nil, err
+	}
+
+	spk, err := p.generateNISTSPK(recipientPubKey, senderPubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Recipient{
+		EncryptedKey: base64.RawURLEncoding.EncodeToString(wk.EncryptedCEK),
+		Header: RecipientHeaders{
+			APU: base64.RawURLEncoding.EncodeToString(apu),
+			KID: recipientPubKey.KID,
+			SPK: spk,
+			EPK: toJWK(&wk.EPK),
+		},
+	}, nil
+}
+
+// generateNISTSPK will encrypt the sender's public key for recipientPubKey, the output is a compact JWE wrapping
+// a JWK containing the sender's public key
+func (p *Packer) generateNISTSPK(recipientPubKey, senderPubKey *crypto.PublicKey) (string, error) {
+	cek := make([]byte, cekSize)
+
+	_, err := p.randReader.Read(cek)
+	if err != nil {
+		return "", err
+	}
+
+	wk, err := p.crypto.WrapKey(cek, nil, nil, recipientPubKey)
+	if err != nil {
+		return "", err
+	}
+
+	headersJSON, err := json.Marshal(recipientSPKJWEHeaders{
+		Typ: "jose",
+		CTY: "jwk+json",
+		Alg: wk.Alg,
+		Enc: a256GCM,
+		EPK: *toJWK(&wk.EPK),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	headers := base64.RawURLEncoding.EncodeToString(headersJSON)
+
+	senderJWKJSON, err := json.Marshal(toJWK(senderPubKey))
+	if err != nil {
+		return "", err
+	}
+
+	cipherJWK, tag, nonce, err := p.encryptGCM(cek, senderJWKJSON, []byte(headers))
+	if err != nil {
+		return "", err
+	}
+
+	return headers + "." +
+			base64.RawURLEncoding.EncodeToString(wk.EncryptedCEK) + "." +
+			nonce + "." +
+			cipherJWK + "." +
+			tag,
+		nil
+}
+
+// encryptGCM will encrypt msg with AES-GCM using key and a newly generated nonce
+// returns:
+// 		base64 encoded cipher text of msg
+//		base64 encoded tag of the encryption
+//		base64 encoded nonce used by the encryption
+//		error in case of failure
+func (p *Packer) encryptGCM(key, msg, aad []byte) (string, string, string, error) {
+	gcm, err := createGCMCipher(key)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	nonce := make([]byte, gcmNonceSize)
+
+	_, err = p.randReader.Read(nonce)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	// the output is a []byte containing the cipherText + tag
+	symOutput := gcm.Seal(nil, nonce, msg, aad)
+
+	return extractCipherText(symOutput), extractTag(symOutput), base64.RawURLEncoding.EncodeToString(nonce), nil
+}
+
+// createGCMCipher will create and return a new AES-GCM cipher for the given symmetric key
+func createGCMCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// toJWK converts a NIST public key to a JWK
+func toJWK(pubKey *crypto.PublicKey) *jwk {
+	return &jwk{
+		Kty: pubKey.Type,
+		Crv: pubKey.Curve,
+		X:   base64.RawURLEncoding.EncodeToString(pubKey.X),
+		Y:   base64.RawURLEncoding.EncodeToString(pubKey.Y),
+		KID: pubKey.KID,
+	}
+}
@@ -0,0 +1,440 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package authcrypt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/google/tink/go/keyset"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/ecdhkw"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
+	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/internal/mock/crypto"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+func TestPackUnpackNIST(t *testing.T) {
+	payload := []byte("lorem ipsum dolor sit amet")
+
+	for _, kt := range []kms.KeyType{kms.ECDHP256KWType, kms.ECDHP384KWType} {
+		kt := kt
+
+		sender := newNISTAgent(t)
+		senderKID, senderKey := sender.createKey(t, kt)
+
+		recipient1 := newNISTAgent(t)
+		_, recipient1Key := recipient1.createKey(t, kt)
+
+		recipient2 := newNISTAgent(t)
+		_, recipient2Key := recipient2.createKey(t, kt)
+
+		t.Run("Success test case: pack and unpack with "+string(kt), func(t *testing.T) {
+			envelope, err := sender.packer.Pack(payload, senderKey, [][]byte{recipient1Key, recipient2Key})
+			require.NoError(t, err)
+
+			jwe := &Envelope{}
+			require.NoError(t, json.Unmarshal(envelope, jwe))
+			require.Len(t, jwe.Recipients, 2)
+
+			headers, err := decodeHeaders(jwe.Protected)
+			require.NoError(t, err)
+			require.Equal(t, &jweHeaders{Typ: encodingType, Alg: crypto.ECDH1PUA256KWAlg, Enc: a256GCM}, headers)
+
+			for _, r := range []struct {
+				agent *nistAgent
+				key   []byte
+			}{{recipient1, recipient1Key}, {recipient2, recipient2Key}} {
+				env, e := r.agent.packer.Unpack(envelope)
+				require.NoError(t, e)
+				require.Equal(t, payload, env.Message)
+				require.JSONEq(t, string(r.key), string(env.ToVerKey))
+
+				// the sender key can be used to reply
+				senderPubKey, ok := nistPublicKey(env.FromVerKey)
+				require.True(t, ok)
+				require.Equal(t, senderKID, senderPubKey.KID)
+			}
+		})
+
+		t.Run("Failure test case: unpack by an agent which is not a recipient with "+string(kt), func(t *testing.T) {
+			envelope, err := sender.packer.Pack(payload, senderKey, [][]byte{recipient1Key})
+			require.NoError(t, err)
+
+			_, err = recipient2.packer.Unpack(envelope)
+			require.EqualError(t, err, "unpack: no recipient key found in the KMS")
+		})
+	}
+}
+
+func TestPackNISTFailures(t *testing.T) {
+	payload := []byte("lorem ipsum dolor sit amet")
+
+	sender := newNISTAgent(t)
+	senderKID, senderKey := sender.createKey(t, kms.ECDHP256KWType)
+
+	recipient := newNISTAgent(t)
+	_, recipientKey := recipient.createKey(t, kms.ECDHP256KWType)
+	_, p384RecipientKey := recipient.createKey(t, kms.ECDHP384KWType)
+
+	t.Run("Failure test case: pack without sender key", func(t *testing.T) {
+		_, err := sender.packer.Pack(payload, nil, [][]byte{recipientKey})
+		require.EqualError(t, err, "failed to pack message: empty sender key")
+	})
+
+	t.Run("Failure test case: pack without KMS", func(t *testing.T) {
+		p, err := New(&mockprovider.Provider{}, XC20P)
+		require.NoError(t, err)
+
+		_, err = p.Pack(payload, senderKey, [][]byte{recipientKey})
+		require.EqualError(t, err, "failed to pack message: "+errNISTKeysNotSupported.Error())
+	})
+
+	t.Run("Failure test case: pack with a legacy packer provider", func(t *testing.T) {
+		p, err := New(&legacyProvider{}, XC20P)
+		require.NoError(t, err)
+
+		_, err = p.Pack(payload, senderKey, [][]byte{recipientKey})
+		require.EqualError(t, err, "failed to pack message: "+errNISTKeysNotSupported.Error())
+	})
+
+	// withKID returns the sender key with the KID of another KMS key
+	withKID := func(kid string) []byte {
+		key := &crypto.PublicKey{}
+		require.NoError(t, json.Unmarshal(senderKey, key))
+		key.KID = kid
+
+		return marshalKey(t, key)
+	}
+
+	t.Run("Failure test case: pack with an invalid sender key", func(t *testing.T) {
+		for _, key := range [][]byte{[]byte(senderKID), withKID("")} {
+			_, err := sender.packer.Pack(payload, key, [][]byte{recipientKey})
+			require.Error(t, err)
+			require.True(t, errors.Is(err, cryptoutil.ErrInvalidKey))
+		}
+	})
+
+	t.Run("Failure test case: pack with an unknown sender key", func(t *testing.T) {
+		_, err := sender.packer.Pack(payload, withKID("unknown"), [][]byte{recipientKey})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+	})
+
+	t.Run("Failure test case: pack with a non ECDH sender key", func(t *testing.T) {
+		kid, _, err := sender.kms.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		_, err = sender.packer.Pack(payload, withKID(kid), [][]byte{recipientKey})
+		require.EqualError(t, err, "failed to pack message: not an ECDH key wrapping key handle")
+
+		kid, _, err = sender.kms.Create(kms.ECDHX25519KWType)
+		require.NoError(t, err)
+
+		_, err = sender.packer.Pack(payload, withKID(kid), [][]byte{recipientKey})
+		require.EqualError(t, err, "failed to pack message: not a NIST P-256 or P-384 key")
+	})
+
+	t.Run("Failure test case: pack with a sender key not matching the KMS key", func(t *testing.T) {
+		kid, _ := sender.createKey(t, kms.ECDHP256KWType)
+
+		_, err := sender.packer.Pack(payload, withKID(kid), [][]byte{recipientKey})
+		require.EqualError(t, err, "failed to pack message: sender key doesn't match the KMS key '"+kid+"'")
+	})
+
+	t.Run("Failure test case: pack with a bad sender key handle", func(t *testing.T) {
+		_, err := keyHandlePublicKey("bad key handle", "")
+		require.EqualError(t, err, "bad key handle format")
+	})
+
+	t.Run("Failure test case: pack with invalid recipients", func(t *testing.T) {
+		noKIDKey := &crypto.PublicKey{}
+		require.NoError(t, json.Unmarshal(recipientKey, noKIDKey))
+		noKIDKey.KID = ""
+
+		_, err := sender.packer.Pack(payload, senderKey, [][]byte{marshalKey(t, noKIDKey)})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, cryptoutil.ErrInvalidKey))
+
+		_, err = sender.packer.Pack(payload, senderKey, [][]byte{p384RecipientKey})
+		require.EqualError(t, err, "failed to pack message: recipient 1 key curve 'P-384' doesn't match sender "+
+			"key curve 'P-256'")
+
+		_, err = sender.packer.Pack(payload, senderKey, [][]byte{recipientKey, p384RecipientKey})
+		require.EqualError(t, err, "failed to pack message: recipient 2 key curve 'P-384' doesn't match sender "+
+			"key curve 'P-256'")
+	})
+
+	t.Run("Failure test case: pack with mixed recipients key types", func(t *testing.T) {
+		edKey := []byte(base58.Encode(make([]byte, ed25519.PublicKeySize)))
+
+		for _, recipients := range [][][]byte{{recipientKey, []byte("bad key")}, {edKey, recipientKey}} {
+			_, err := sender.packer.Pack(payload, senderKey, recipients)
+			require.Error(t, err)
+			require.True(t, errors.Is(err, errMixedKeyTypes))
+		}
+	})
+
+	t.Run("Failure test case: pack with failing crypto", func(t *testing.T) {
+		p := *sender.packer
+		p.crypto = &mockcrypto.Crypto{WrapError: errors.New("wrap error")}
+
+		_, err := p.Pack(payload, senderKey, [][]byte{recipientKey})
+		require.EqualError(t, err, "failed to pack message: wrap error")
+	})
+
+	t.Run("Failure test case: pack with a failing random reader", func(t *testing.T) {
+		p := *sender.packer
+
+		// cek and payload nonce
+		for _, n := range []int{0, 1} {
+			p.randReader = &failingReader{reads: n}
+
+			_, err := p.Pack(payload, senderKey, [][]byte{recipientKey})
+			require.EqualError(t, err, "failing reader")
+		}
+
+		// apu, spk cek and spk nonce
+		for _, n := range []int{2, 3, 4} {
+			p.randReader = &failingReader{reads: n}
+
+			_, err := p.Pack(payload, senderKey, [][]byte{recipientKey})
+			require.EqualError(t, err, "failed to pack message: failing reader")
+		}
+	})
+
+	t.Run("Failure test case: bad AES-GCM key", func(t *testing.T) {
+		_, _, _, err := sender.packer.encryptGCM([]byte("bad key"), payload, nil)
+		require.Error(t, err)
+
+		_, err = decryptGCM([]byte("bad key"), "", "", "", nil)
+		require.Error(t, err)
+	})
+}
+
+func TestUnpackNISTFailures(t *testing.T) {
+	payload := []byte("lorem ipsum dolor sit amet")
+
+	sender := newNISTAgent(t)
+	_, senderKey := sender.createKey(t, kms.ECDHP256KWType)
+
+	recipient := newNISTAgent(t)
+	_, recipientKey := recipient.createKey(t, kms.ECDHP256KWType)
+
+	envelope, err := sender.packer.Pack(payload, senderKey, [][]byte{recipientKey})
+	require.NoError(t, err)
+
+	t.Run("Failure test case: unpack without KMS", func(t *testing.T) {
+		p, e := New(&mockprovider.Provider{}, XC20P)
+		require.NoError(t, e)
+
+		_, e = p.Unpack(envelope)
+		require.EqualError(t, e, "unpack: "+errNISTKeysNotSupported.Error())
+	})
+
+	t.Run("Failure test case: unpack with failing crypto", func(t *testing.T) {
+		p := *recipient.packer
+		p.crypto = &mockcrypto.Crypto{UnwrapError: errors.New("unwrap error")}
+
+		_, e := p.Unpack(envelope)
+		require.EqualError(t, e, "unpack: sender key: unwrap error")
+	})
+
+	spkHeaders := func(update func(h *recipientSPKJWEHeaders)) func(jwe *Envelope) {
+		return func(jwe *Envelope) {
+			spk := splitSPK(t, jwe.Recipients[0].Header.SPK)
+
+			h, e := base64.RawURLEncoding.DecodeString(spk[0])
+			require.NoError(t, e)
+
+			headers := &recipientSPKJWEHeaders{}
+			require.NoError(t, json.Unmarshal(h, headers))
+
+			update(headers)
+
+			h, e = json.Marshal(headers)
+			require.NoError(t, e)
+
+			spk[0] = base64.RawURLEncoding.EncodeToString(h)
+			jwe.Recipients[0].Header.SPK = joinSPK(spk)
+		}
+	}
+
+	spkPart := func(i int, value string) func(jwe *Envelope) {
+		return func(jwe *Envelope) {
+			spk := splitSPK(t, jwe.Recipients[0].Header.SPK)
+			spk[i] = value
+			jwe.Recipients[0].Header.SPK = joinSPK(spk)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		update func(jwe *Envelope)
+		errMsg string
+	}{
+		{name: "bad SPK format", update: func(jwe *Envelope) { jwe.Recipients[0].Header.SPK = "a.b" },
+			errMsg: "unpack: sender key: bad SPK format"},
+		{name: "bad SPK headers encoding", update: spkPart(0, "@"),
+			errMsg: "unpack: sender key: illegal base64 data"},
+		{name: "bad SPK headers", update: spkPart(0, base64.RawURLEncoding.EncodeToString([]byte("{"))),
+			errMsg: "unpack: sender key: unexpected end of JSON input"},
+		{name: "bad SPK encrypted key", update: spkPart(1, "@"),
+			errMsg: "unpack: sender key: illegal base64 data"},
+		{name: "bad SPK epk", update: spkHeaders(func(h *recipientSPKJWEHeaders) { h.EPK.Crv = "X25519" }),
+			errMsg: "unpack: sender key: unsupported key type 'EC' and curve 'X25519'"},
+		{name: "bad SPK epk x", update: spkHeaders(func(h *recipientSPKJWEHeaders) { h.EPK.X = "@" }),
+			errMsg: "unpack: sender key: illegal base64 data"},
+		{name: "bad SPK epk y", update: spkHeaders(func(h *recipientSPKJWEHeaders) { h.EPK.Y = "@" }),
+			errMsg: "unpack: sender key: illegal base64 data"},
+		{name: "bad SPK nonce", update: spkPart(2, "AQ"),
+			errMsg: "unpack: sender key: bad nonce size"},
+		{name: "bad SPK cipher text", update: spkPart(3, "@"),
+			errMsg: "unpack: sender key: illegal base64 data"},
+		{name: "bad SPK tag", update: spkPart(4, "@"),
+			errMsg: "unpack: sender key: illegal base64 data"},
+		{name: "altered SPK", update: spkPart(3, "AAAA"),
+			errMsg: "unpack: sender key: cipher: message authentication failed"},
+		{name: "missing epk", update: func(jwe *Envelope) { jwe.Recipients[0].Header.EPK = nil },
+			errMsg: "unpack: decrypt shared key: missing ephemeral key"},
+		{name: "bad epk", update: func(jwe *Envelope) { jwe.Recipients[0].Header.EPK.Kty = "OKP" },
+			errMsg: "unpack: decrypt shared key: unsupported key type 'OKP' and curve 'P-256'"},
+		{name: "bad apu", update: func(jwe *Envelope) { jwe.Recipients[0].Header.APU = "@" },
+			errMsg: "unpack: decrypt shared key: illegal base64 data"},
+		{name: "altered apu", update: func(jwe *Envelope) { jwe.Recipients[0].Header.APU = "AAAA" },
+			errMsg: "unpack: decrypt shared key: unwrapKey:"},
+		{name: "bad encrypted key", update: func(jwe *Envelope) { jwe.Recipients[0].EncryptedKey = "@" },
+			errMsg: "unpack: decrypt shared key: illegal base64 data"},
+		{name: "altered cipher text", update: func(jwe *Envelope) { jwe.CipherText = "AAAA" },
+			errMsg: "unpack: cipher: message authentication failed"},
+		{name: "altered aad", update: func(jwe *Envelope) { jwe.AAD = "AAAA" },
+			errMsg: "unpack: cipher: message authentication failed"},
+		{name: "bad nonce", update: func(jwe *Envelope) { jwe.IV = "AQ" },
+			errMsg: "unpack: bad nonce size"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run("Failure test case: unpack with "+tc.name, func(t *testing.T) {
+			jwe := &Envelope{}
+			require.NoError(t, json.Unmarshal(envelope, jwe))
+
+			tc.update(jwe)
+
+			altered, e := json.Marshal(jwe)
+			require.NoError(t, e)
+
+			_, e = recipient.packer.Unpack(altered)
+			require.Error(t, e)
+			require.Contains(t, e.Error(), tc.errMsg)
+		})
+	}
+}
+
+type nistAgent struct {
+	kms    kms.KeyManager
+	packer *Packer
+}
+
+func newNISTAgent(t *testing.T) *nistAgent {
+	k, err := localkms.New(localkms.PrimaryKeyURI, &kmsProvider{storage: mem.NewProvider()})
+	require.NoError(t, err)
+
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	p, err := New(&mockprovider.Provider{KeyManagerValue: k, CryptoValue: c}, XC20P)
+	require.NoError(t, err)
+
+	return &nistAgent{kms: k, packer: p}
+}
+
+// createKey creates a new key of type kt in the agent's KMS, it returns the KMS key ID and the JSON marshalled
+// public key to be used as a sender or recipient key
+func (a *nistAgent) createKey(t *testing.T, kt kms.KeyType) (string, []byte) {
+	kid, kh, err := a.kms.Create(kt)
+	require.NoError(t, err)
+
+	pubKey, err := ecdhkw.PublicKey(kh.(*keyset.Handle))
+	require.NoError(t, err)
+
+	pubKey.KID = kid
+
+	return kid, marshalKey(t, pubKey)
+}
+
+func marshalKey(t *testing.T, pubKey *crypto.PublicKey) []byte {
+	key, err := json.Marshal(pubKey)
+	require.NoError(t, err)
+
+	return key
+}
+
+func splitSPK(t *testing.T, spk string) []string {
+	parts := strings.Split(spk, ".")
+	require.Len(t, parts, 5)
+
+	return parts
+}
+
+func joinSPK(parts []string) string {
+	return strings.Join(parts, ".")
+}
+
+type kmsProvider struct {
+	storage storage.Provider
+}
+
+func (k *kmsProvider) StorageProvider() storage.Provider {
+	return k.storage
+}
+
+func (k *kmsProvider) SecretLock() secretlock.Service {
+	return &noop.NoLock{}
+}
+
+// failingReader fails after a number of successful reads
+// legacyProvider is a packer.Provider without KMS and Crypto
+type legacyProvider struct{}
+
+func (l *legacyProvider) LegacyKMS() legacykms.KeyManager {
+	return nil
+}
+
+type failingReader struct {
+	reads int
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.reads == 0 {
+		return 0, errors.New("failing reader")
+	}
+
+	r.reads--
+
+	for i := range p {
+		p[i] = byte(i)
+	}
+
+	return len(p), nil
+}
@@ -24,6 +24,8 @@ import (
 // encrypted CEK.
 // The current recipient is the one with the sender's encrypted key that successfully
 // decrypts with recipientKeyPair.Priv Key.
+// Envelopes packed for NIST P-256 and P-384 keys (A256GCM content encryption) are decrypted with the KMS key handle
+// of the recipient.
 func (p *Packer) Unpack(envelope []byte) (*transport.Envelope, error) {
 	jwe := &Envelope{}
 
@@ -32,6 +34,10 @@ func (p *Packer) Unpack(envelope []byte) (*transport.Envelope, error) {
 		return nil, fmt.Errorf("unpack json: %w", err)
 	}
 
+	if isNISTEnvelope(jwe.Protected) {
+		return p.unpackNIST(jwe)
+	}
+
 	recipientPubKey, recipient, err := p.findRecipient(jwe.Recipients)
 	if err != nil {
 		return nil, fmt.Errorf("unpack: %w", err)
@@ -0,0 +1,249 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package authcrypt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
+)
+
+// isNISTEnvelope returns true if the protected headers of the envelope have the A256GCM content encryption used
+// by envelopes packed for NIST keys
+func isNISTEnvelope(protected string) bool {
+	headers, err := decodeHeaders(protected)
+
+	return err == nil && headers.Enc == a256GCM
+}
+
+func decodeHeaders(protected string) (*jweHeaders, error) {
+	h, err := base64.RawURLEncoding.DecodeString(protected)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := &jweHeaders{}
+
+	err = json.Unmarshal(h, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	return headers, nil
+}
+
+// unpackNIST will JWE decode an envelope packed for NIST keys, the recipient key handle is the first one found in
+// the KMS with the recipients KIDs of the envelope.
+// The returned envelope's keys are JSON marshalled crypto.PublicKey.
+func (p *Packer) unpackNIST(jwe *Envelope) (*transport.Envelope, error) {
+	if p.kms == nil || p.crypto == nil {
+		return nil, fmt.Errorf("unpack: %w", errNISTKeysNotSupported)
+	}
+
+	headers, err := decodeHeaders(jwe.Protected)
+	if err != nil {
+		return nil, fmt.Errorf("unpack: %w", err)
+	}
+
+	recipientKH, recipient, err := p.findNISTRecipient(jwe.Recipients)
+	if err != nil {
+		return nil, fmt.Errorf("unpack: %w", err)
+	}
+
+	senderPubKey, err := p.decryptNISTSPK(recipientKH, recipient.Header.SPK)
+	if err != nil {
+		return nil, fmt.Errorf("unpack: sender key: %w", err)
+	}
+
+	cek, err := p.unwrapNISTCEK(recipientKH, senderPubKey, headers.Alg, recipient)
+	if err != nil {
+		return nil, fmt.Errorf("unpack: decrypt shared key: %w", err)
+	}
+
+	payload, err := decryptGCM(cek, jwe.CipherText, jwe.Tag, jwe.IV, []byte(jwe.Protected+"."+jwe.AAD))
+	if err != nil {
+		return nil, fmt.Errorf("unpack: %w", err)
+	}
+
+	recipientPubKey, err := keyHandlePublicKey(recipientKH, recipient.Header.KID)
+	if err != nil {
+		return nil, fmt.Errorf("unpack: recipient key: %w", err)
+	}
+
+	fromKey, err := json.Marshal(senderPubKey)
+	if err != nil {
+		return nil, fmt.Errorf("unpack: %w", err)
+	}
+
+	toKey, err := json.Marshal(recipientPubKey)
+	if err != nil {
+		return nil, fmt.Errorf("unpack: %w", err)
+	}
+
+	return &transport.Envelope{
+		Message:    payload,
+		FromVerKey: fromKey,
+		ToVerKey:   toKey,
+	}, nil
+}
+
+// findNISTRecipient will loop through jweRecipients and returns the first key handle found in the KMS
+func (p *Packer) findNISTRecipient(jweRecipients []Recipient) (interface{}, *Recipient, error) {
+	for i := range jweRecipients {
+		kh, err := p.kms.Get(jweRecipients[i].Header.KID)
+		if err == nil {
+			return kh, &jweRecipients[i], nil
+		}
+	}
+
+	return nil, nil, errors.New("no recipient key found in the KMS")
+}
+
+// decryptNISTSPK will decrypt the recipient's SPK with the recipient key handle, it returns the sender's public key
+func (p *Packer) decryptNISTSPK(recipientKH interface{}, spk string) (*crypto.PublicKey, error) {
+	const jweNumComponents = 5
+
+	jwe := strings.Split(spk, ".")
+	if len(jwe) != jweNumComponents {
+		return nil, fmt.Errorf("bad SPK format")
+	}
+
+	h, err := base64.RawURLEncoding.DecodeString(jwe[0])
+	if err != nil {
+		return nil, err
+	}
+
+	headers := &recipientSPKJWEHeaders{}
+
+	err = json.Unmarshal(h, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedKey, err := base64.RawURLEncoding.DecodeString(jwe[1])
+	if err != nil {
+		return nil, err
+	}
+
+	epk, err := fromJWK(&headers.EPK)
+	if err != nil {
+		return nil, err
+	}
+
+	cek, err := p.crypto.UnwrapKey(&crypto.RecipientWrappedKey{
+		EncryptedCEK: encryptedKey,
+		EPK:          *epk,
+		Alg:          headers.Alg,
+	}, recipientKH)
+	if err != nil {
+		return nil, err
+	}
+
+	senderJWKJSON, err := decryptGCM(cek, jwe[3], jwe[4], jwe[2], []byte(jwe[0]))
+	if err != nil {
+		return nil, err
+	}
+
+	senderJWK := &jwk{}
+
+	err = json.Unmarshal(senderJWKJSON, senderJWK)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromJWK(senderJWK)
+}
+
+// unwrapNISTCEK will unwrap the CEK found in recipient using the recipient key handle and the sender's public key
+func (p *Packer) unwrapNISTCEK(recipientKH interface{}, senderPubKey *crypto.PublicKey, alg string,
+	recipient *Recipient) ([]byte, error) {
+	if recipient.Header.EPK == nil {
+		return nil, errors.New("missing ephemeral key")
+	}
+
+	epk, err := fromJWK(recipient.Header.EPK)
+	if err != nil {
+		return nil, err
+	}
+
+	apu, err := base64.RawURLEncoding.DecodeString(recipient.Header.APU)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedCEK, err := base64.RawURLEncoding.DecodeString(recipient.EncryptedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.crypto.UnwrapKey(&crypto.RecipientWrappedKey{
+		KID:          recipient.Header.KID,
+		EncryptedCEK: encryptedCEK,
+		EPK:          *epk,
+		Alg:          alg,
+		APU:          apu,
+	}, recipientKH, crypto.WithSender(senderPubKey))
+}
+
+// decryptGCM will decrypt the base64 encoded cipherText and tag with key and the base64 encoded nonce
+func decryptGCM(key []byte, cipherText, tag, nonce string, aad []byte) ([]byte, error) {
+	gcm, err := createGCMCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ct, err := base64.RawURLEncoding.DecodeString(cipherText)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := base64.RawURLEncoding.DecodeString(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := base64.RawURLEncoding.DecodeString(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(n) != gcm.NonceSize() {
+		return nil, errors.New("bad nonce size")
+	}
+
+	return gcm.Open(nil, n, append(ct, t...), aad)
+}
+
+// fromJWK converts a NIST key JWK to a public key
+func fromJWK(key *jwk) (*crypto.PublicKey, error) {
+	if key.Kty != crypto.ECKeyType || (key.Crv != crypto.P256Curve && key.Crv != crypto.P384Curve) {
+		return nil, fmt.Errorf("unsupported key type '%s' and curve '%s'", key.Kty, key.Crv)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(key.X)
+	if err != nil {
+		return nil, err
+	}
+
+	y, err := base64.RawURLEncoding.DecodeString(key.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	return &crypto.PublicKey{
+		KID:   key.KID,
+		X:     x,
+		Y:     y,
+		Curve: key.Crv,
+		Type:  key.Kty,
+	}, nil
+}
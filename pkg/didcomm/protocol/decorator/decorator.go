@@ -42,3 +42,34 @@ type Transport struct {
 type ReturnRoute struct {
 	Value string `json:"~return_route,omitempty"`
 }
+
+// Attachment is intended to provide the possibility to include files, links or even JSON payload to the message.
+// https://github.com/hyperledger/aries-rfcs/tree/master/concepts/0017-attachments
+type Attachment struct {
+	// ID is a JSON-LD construct that uniquely identifies attached content within the scope of a given message.
+	ID string `json:"@id,omitempty"`
+	// MimeType describes the MIME type of the attached content. Optional but recommended.
+	MimeType string `json:"mime-type,omitempty"`
+	// LastModTime is a hint about when the content in this attachment was last modified.
+	LastModTime *time.Time `json:"lastmod_time,omitempty"`
+	// ByteCount is an optional, and mostly relevant when content is included by reference instead of by value.
+	// Lets the receiver guess how expensive it will be, in time, bandwidth, and storage, to fully fetch the attachment.
+	ByteCount int64 `json:"byte_count,omitempty"`
+	// Description is an optional human-readable description of the content.
+	Description string `json:"description,omitempty"`
+	// Data is a JSON object that gives access to the actual content of the attachment.
+	Data AttachmentData `json:"data,omitempty"`
+}
+
+// AttachmentData contains attachment payload
+type AttachmentData struct {
+	// Sha256 is a hash of the content. Optional. Used as an integrity check if content is inlined.
+	Sha256 string `json:"sha256,omitempty"`
+	// Links is a list of zero or more locations at which the content may be fetched. Optional.
+	Links []string `json:"links,omitempty"`
+	// Base64 encoded data, when representing arbitrary content inline instead of via links. Optional.
+	Base64 string `json:"base64,omitempty"`
+	// JSON is a directly embedded JSON data, when representing content inline instead of via links,
+	// and when the content is natively conveyable as JSON. Optional.
+	JSON interface{} `json:"json,omitempty"`
+}
@@ -196,6 +196,11 @@ func (s *Service) Accept(msgType string) bool {
 		msgType == AckMsgType
 }
 
+// MessageTypes returns the message types supported by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{InvitationMsgType, RequestMsgType, ResponseMsgType, AckMsgType}
+}
+
 // HandleOutbound handles outbound didexchange messages.
 func (s *Service) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) error {
 	return errors.New("not implemented")
@@ -380,6 +385,11 @@ func (s *Service) startInternalListener() {
 			msg.err = s.handleWithoutAction(msg)
 		}
 
+		// event data is only needed until the callback has been processed
+		if msg.ConnRecord != nil {
+			s.removeEventTransientData(msg.ConnRecord.ConnectionID)
+		}
+
 		// no error - continue
 		if msg.err == nil {
 			continue
@@ -419,7 +429,13 @@ func (s *Service) accept(connectionID, publicDID, label, stateID, errMsg string)
 
 	msg.Options = &options{publicDID: publicDID, label: label}
 
-	return s.handleWithoutAction(msg)
+	if err = s.handleWithoutAction(msg); err != nil {
+		return err
+	}
+
+	s.removeEventTransientData(connectionID)
+
+	return nil
 }
 
 func (s *Service) storeEventTransientData(msg *message) error {
@@ -447,6 +463,12 @@ func (s *Service) getEventTransientData(connectionID string) (*message, error) {
 	return msg, nil
 }
 
+func (s *Service) removeEventTransientData(connectionID string) {
+	if err := s.connectionStore.RemoveEvent(connectionID); err != nil {
+		logger.Warnf("remove transient data for connection %s : %s", connectionID, err)
+	}
+}
+
 // abandon updates the state to abandoned and trigger failure event.
 func (s *Service) abandon(thID string, msg service.DIDCommMsg, processErr error) error {
 	// update the state to abandoned
@@ -623,6 +623,16 @@ func (m *mockStore) Get(k string) ([]byte, error) {
 	return m.get(k)
 }
 
+// Delete removes the record for given key
+func (m *mockStore) Delete(k string) error {
+	return nil
+}
+
+// Batch applies given operations
+func (m *mockStore) Batch(operations []storage.Operation) error {
+	return nil
+}
+
 // Search returns storage iterator
 func (m *mockStore) Iterator(start, limit string) storage.StoreIterator {
 	return nil
@@ -1086,6 +1096,10 @@ func TestAcceptExchangeRequest(t *testing.T) {
 			prop, ok := e.Properties.(event)
 			require.True(t, ok, "Failed to cast the event properties to service.Event")
 			require.NoError(t, svc.AcceptExchangeRequest(prop.ConnectionID(), "", ""))
+
+			// event data is removed once the request has been accepted
+			_, e := svc.getEventTransientData(prop.ConnectionID())
+			require.True(t, errors.Is(e, storage.ErrDataNotFound))
 		}
 	}()
 
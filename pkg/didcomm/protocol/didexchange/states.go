@@ -26,6 +26,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/ed25519signature2018"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
 	connectionstore "github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
@@ -290,7 +291,7 @@ func (ctx *context) handleInboundInvitation(invitation *Invitation, thid string,
 	}
 
 	// get did document that will be used in exchange request
-	didDoc, conn, err := ctx.getDIDDocAndConnection(getPublicDID(options))
+	didDoc, conn, err := ctx.getDIDDocAndConnection(getPublicDID(options), "")
 	if err != nil {
 		return nil, nil, err
 	}
@@ -328,9 +329,15 @@ func (ctx *context) handleInboundRequest(request *Request, options *options,
 		return nil, nil, fmt.Errorf("resolve did doc from exchange request connection: %w", err)
 	}
 
+	// the keys of the connection use the router chosen for the invitation
+	routerConnID, err := ctx.invitationRouter(getInvitationID(request))
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// get did document that will be used in exchange response
 	// (my did doc)
-	responseDidDoc, connection, err := ctx.getDIDDocAndConnection(getPublicDID(options))
+	responseDidDoc, connection, err := ctx.getDIDDocAndConnection(getPublicDID(options), routerConnID)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -371,6 +378,15 @@ func (ctx *context) handleInboundRequest(request *Request, options *options,
 	}, connRec, nil
 }
 
+// getInvitationID returns the ID of the invitation the exchange request replies to
+func getInvitationID(request *Request) string {
+	if request.Thread == nil {
+		return ""
+	}
+
+	return request.Thread.PID
+}
+
 func getPublicDID(options *options) string {
 	if options == nil {
 		return ""
@@ -399,7 +415,28 @@ func (ctx *context) getDestination(invitation *Invitation) (*service.Destination
 	}, nil
 }
 
-func (ctx *context) getDIDDocAndConnection(pubDID string) (*did.Doc, *Connection, error) {
+// invitationRouter returns the connection ID of the router chosen for the invitation identified by invitationID,
+// it is empty if the invitation keys are advertised through the default router.
+func (ctx *context) invitationRouter(invitationID string) (string, error) {
+	if invitationID == "" {
+		return "", nil
+	}
+
+	routerConnID, err := ctx.connectionStore.GetInvitationRouter(invitationID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("get invitation router: %w", err)
+	}
+
+	return routerConnID, nil
+}
+
+// getDIDDocAndConnection returns the public DID doc or creates a peer DID doc whose keys are advertised through
+// the router identified by routerConnID (the default router if empty).
+func (ctx *context) getDIDDocAndConnection(pubDID, routerConnID string) (*did.Doc, *Connection, error) {
 	if pubDID != "" {
 		logger.Debugf("using public did[%s] for connection", pubDID)
 
@@ -418,8 +455,8 @@ func (ctx *context) getDIDDocAndConnection(pubDID string) (*did.Doc, *Connection
 
 	logger.Debugf("creating new '%s' did for connection", didMethod)
 
-	// get the route configs (pass empty service endpoint, as default servie endpoint added in VDRI)
-	serviceEndpoint, routingKeys, err := route.GetRouterConfig(ctx.routeSvc, "")
+	// get the router configs (pass empty service endpoint, as default servie endpoint added in VDRI)
+	serviceEndpoint, routingKeys, err := route.GetRouterConfig(ctx.routeSvc, routerConnID, "")
 	if err != nil {
 		return nil, nil, fmt.Errorf("did doc - fetch router config : %w", err)
 	}
@@ -439,7 +476,7 @@ func (ctx *context) getDIDDocAndConnection(pubDID string) (*did.Doc, *Connection
 		for _, recKey := range recipientKeys {
 			// TODO https://github.com/hyperledger/aries-framework-go/issues/1105 Support to Add multiple
 			//  recKeys to the Router
-			if err = route.AddKeyToRouter(ctx.routeSvc, recKey); err != nil {
+			if err = route.AddKeyToRouter(ctx.routeSvc, routerConnID, recKey); err != nil {
 				return nil, nil, fmt.Errorf("did doc - add key to the router : %w", err)
 			}
 		}
@@ -949,7 +949,7 @@ func TestGetDIDDocAndConnection(t *testing.T) {
 		ctx := context{
 			vdriRegistry:    &mockvdri.MockVDRIRegistry{ResolveValue: doc},
 			connectionStore: connectionStore}
-		didDoc, conn, err := ctx.getDIDDocAndConnection(doc.ID)
+		didDoc, conn, err := ctx.getDIDDocAndConnection(doc.ID, "")
 		require.NoError(t, err)
 		require.NotNil(t, didDoc)
 		require.NotNil(t, conn)
@@ -958,7 +958,7 @@ func TestGetDIDDocAndConnection(t *testing.T) {
 	t.Run("error getting public did doc from resolver", func(t *testing.T) {
 		ctx := context{
 			vdriRegistry: &mockvdri.MockVDRIRegistry{ResolveErr: errors.New("resolver error")}}
-		didDoc, conn, err := ctx.getDIDDocAndConnection("did-id")
+		didDoc, conn, err := ctx.getDIDDocAndConnection("did-id", "")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "resolver error")
 		require.Nil(t, didDoc)
@@ -981,7 +981,7 @@ func TestGetDIDDocAndConnection(t *testing.T) {
 		ctx := context{
 			vdriRegistry:    &mockvdri.MockVDRIRegistry{ResolveValue: doc},
 			connectionStore: connectionStore}
-		didDoc, conn, err := ctx.getDIDDocAndConnection(doc.ID)
+		didDoc, conn, err := ctx.getDIDDocAndConnection(doc.ID, "")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "did error")
 		require.Nil(t, didDoc)
@@ -992,7 +992,7 @@ func TestGetDIDDocAndConnection(t *testing.T) {
 			vdriRegistry: &mockvdri.MockVDRIRegistry{CreateErr: errors.New("creator error")},
 			routeSvc:     &mockroute.MockRouteSvc{},
 		}
-		didDoc, conn, err := ctx.getDIDDocAndConnection("")
+		didDoc, conn, err := ctx.getDIDDocAndConnection("", "")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "creator error")
 		require.Nil(t, didDoc)
@@ -1006,7 +1006,7 @@ func TestGetDIDDocAndConnection(t *testing.T) {
 			connectionStore: connectionStore,
 			routeSvc:        &mockroute.MockRouteSvc{},
 		}
-		didDoc, conn, err := ctx.getDIDDocAndConnection("")
+		didDoc, conn, err := ctx.getDIDDocAndConnection("", "")
 		require.NoError(t, err)
 		require.NotNil(t, didDoc)
 		require.NotNil(t, conn)
@@ -1029,7 +1029,7 @@ func TestGetDIDDocAndConnection(t *testing.T) {
 			connectionStore: connectionStore,
 			routeSvc:        &mockroute.MockRouteSvc{},
 		}
-		didDoc, conn, err := ctx.getDIDDocAndConnection("")
+		didDoc, conn, err := ctx.getDIDDocAndConnection("", "")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "did error")
 		require.Nil(t, didDoc)
@@ -1044,7 +1044,7 @@ func TestGetDIDDocAndConnection(t *testing.T) {
 			connectionStore: connectionStore,
 			routeSvc:        &mockroute.MockRouteSvc{ConfigErr: errors.New("router config error")},
 		}
-		didDoc, conn, err := ctx.getDIDDocAndConnection("")
+		didDoc, conn, err := ctx.getDIDDocAndConnection("", "")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "did doc - fetch router config")
 		require.Nil(t, didDoc)
@@ -1059,12 +1059,52 @@ func TestGetDIDDocAndConnection(t *testing.T) {
 			connectionStore: connectionStore,
 			routeSvc:        &mockroute.MockRouteSvc{AddKeyErr: errors.New("router add key error")},
 		}
-		didDoc, conn, err := ctx.getDIDDocAndConnection("")
+		didDoc, conn, err := ctx.getDIDDocAndConnection("", "")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "did doc - add key to the router")
 		require.Nil(t, didDoc)
 		require.Nil(t, conn)
 	})
+
+	t.Run("test create did doc - keys are added to the invitation router", func(t *testing.T) {
+		connectionStore, err := newConnectionStore(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		require.NoError(t, connectionStore.SaveInvitationRouter("inv-1", "router-1"))
+
+		var routerConnIDs []string
+
+		ctx := context{
+			vdriRegistry:    &mockvdri.MockVDRIRegistry{CreateValue: mockdiddoc.GetMockDIDDoc()},
+			connectionStore: connectionStore,
+			routeSvc: &mockroute.MockRouteSvc{
+				RouterEndpoint: "http://router.example.com",
+				RoutingKeys:    []string{"routing-key"},
+				AddKeyFunc: func(connectionID, recKey string) error {
+					routerConnIDs = append(routerConnIDs, connectionID)
+					return nil
+				},
+			},
+		}
+
+		routerConnID, err := ctx.invitationRouter("inv-1")
+		require.NoError(t, err)
+		require.Equal(t, "router-1", routerConnID)
+
+		didDoc, _, err := ctx.getDIDDocAndConnection("", routerConnID)
+		require.NoError(t, err)
+		require.NotNil(t, didDoc)
+		require.NotEmpty(t, routerConnIDs)
+
+		for _, id := range routerConnIDs {
+			require.Equal(t, "router-1", id)
+		}
+
+		// invitations without a router use the default router
+		routerConnID, err = ctx.invitationRouter("inv-2")
+		require.NoError(t, err)
+		require.Empty(t, routerConnID)
+	})
 }
 
 type mockSigner struct {
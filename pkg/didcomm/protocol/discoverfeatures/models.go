@@ -0,0 +1,37 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+)
+
+// Query discover features query message.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0031-discover-features#query-message-type
+type Query struct {
+	Type string `json:"@type,omitempty"`
+	ID   string `json:"@id,omitempty"`
+	// Query is a protocol identifier (PIURI) which may contain the * wildcard, e.g. https://didcomm.org/*
+	Query   string `json:"query,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// Disclose discover features disclose message.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0031-discover-features#disclose-message-type
+type Disclose struct {
+	Type      string               `json:"@type,omitempty"`
+	ID        string               `json:"@id,omitempty"`
+	Protocols []ProtocolDescriptor `json:"protocols"`
+	Thread    *decorator.Thread    `json:"~thread,omitempty"`
+}
+
+// ProtocolDescriptor describes a protocol supported by the agent.
+type ProtocolDescriptor struct {
+	// PID is the protocol identifier (PIURI), e.g. https://didcomm.org/trust_ping/1.0
+	PID   string   `json:"pid"`
+	Roles []string `json:"roles,omitempty"`
+}
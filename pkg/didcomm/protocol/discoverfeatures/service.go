@@ -0,0 +1,319 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+// constants for discover features spec types
+const (
+	// DiscoverFeatures discover features protocol
+	DiscoverFeatures = "discover-features"
+
+	// DiscoverFeaturesSpec defines the discover features spec
+	DiscoverFeaturesSpec = "https://didcomm.org/discover-features/1.0/"
+
+	// QueryMsgType defines the discover features query message type.
+	QueryMsgType = DiscoverFeaturesSpec + "query"
+
+	// DiscloseMsgType defines the discover features disclose message type.
+	DiscloseMsgType = DiscoverFeaturesSpec + "disclose"
+)
+
+// ErrConnectionNotFound connection not found error
+var ErrConnectionNotFound = errors.New("connection not found")
+
+// ErrQueryTimeout is returned when no disclose is received within the given timeout
+var ErrQueryTimeout = errors.New("timeout waiting for disclose")
+
+// provider contains dependencies for the Discover Features protocol and is typically created by using aries.Context()
+type provider interface {
+	OutboundDispatcher() dispatcher.Outbound
+	StorageProvider() storage.Provider
+	TransientStorageProvider() storage.Provider
+	ProtocolServices() []dispatcher.ProtocolService
+	MessageServiceProvider() api.MessageServiceProvider
+}
+
+// registry provides the services registered in the agent, the services are added to the context
+// after the discover features service is created, so they are fetched while handling the query.
+type registry interface {
+	ProtocolServices() []dispatcher.ProtocolService
+	MessageServiceProvider() api.MessageServiceProvider
+}
+
+// featureProvider is implemented by the protocol and message services which disclose their message types.
+type featureProvider interface {
+	MessageTypes() []string
+}
+
+// ProtocolService service interface for discover features.
+type ProtocolService interface {
+	// Query queries the features supported by the agent on the other end of the connection
+	Query(connectionID, query string, timeout time.Duration) ([]ProtocolDescriptor, error)
+}
+
+// Service for Discover Features protocol.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0031-discover-features
+type Service struct {
+	outbound         dispatcher.Outbound
+	connectionLookup *connection.Lookup
+	registry         registry
+	queryMap         map[string]chan *Disclose
+	queryMapLock     sync.RWMutex
+}
+
+// New return discover features service.
+func New(prov provider) (*Service, error) {
+	connectionLookup, err := connection.NewLookup(prov)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		outbound:         prov.OutboundDispatcher(),
+		connectionLookup: connectionLookup,
+		registry:         prov,
+		queryMap:         make(map[string]chan *Disclose),
+	}, nil
+}
+
+// HandleInbound handles inbound discover features messages.
+func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	switch msg.Type() {
+	case QueryMsgType:
+		if err := s.handleQuery(msg, myDID, theirDID); err != nil {
+			return "", fmt.Errorf("handle query : %w", err)
+		}
+	case DiscloseMsgType:
+		if err := s.handleDisclose(msg); err != nil {
+			return "", fmt.Errorf("handle disclose : %w", err)
+		}
+	default:
+		return "", fmt.Errorf("unrecognized msgType: %s", msg.Type())
+	}
+
+	return msg.ID(), nil
+}
+
+// HandleOutbound sends the query message, the disclose is not awaited.
+// Use Query to get the features of the other agent.
+func (s *Service) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) error {
+	if msg.Type() != QueryMsgType {
+		return fmt.Errorf("unsupported outbound msgType: %s", msg.Type())
+	}
+
+	return s.outbound.SendToDID(msg, myDID, theirDID)
+}
+
+// Accept checks whether the service can handle the message type.
+func (s *Service) Accept(msgType string) bool {
+	return msgType == QueryMsgType || msgType == DiscloseMsgType
+}
+
+// MessageTypes returns the message types supported by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{QueryMsgType, DiscloseMsgType}
+}
+
+// Name of the service
+func (s *Service) Name() string {
+	return DiscoverFeatures
+}
+
+func (s *Service) handleQuery(msg service.DIDCommMsg, myDID, theirDID string) error {
+	// unmarshal the payload
+	query := &Query{}
+
+	err := msg.Decode(query)
+	if err != nil {
+		return fmt.Errorf("query message unmarshal : %w", err)
+	}
+
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return fmt.Errorf("query message threadID : %w", err)
+	}
+
+	disclose := &Disclose{
+		Type:      DiscloseMsgType,
+		ID:        uuid.New().String(),
+		Protocols: s.features(query.Query),
+		Thread:    &decorator.Thread{ID: thID},
+	}
+
+	return s.outbound.SendToDID(disclose, myDID, theirDID)
+}
+
+func (s *Service) handleDisclose(msg service.DIDCommMsg) error {
+	// unmarshal the payload
+	disclose := &Disclose{}
+
+	err := msg.Decode(disclose)
+	if err != nil {
+		return fmt.Errorf("disclose message unmarshal : %w", err)
+	}
+
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return fmt.Errorf("disclose message threadID : %w", err)
+	}
+
+	// check if there are any channels registered for the query
+	queryCh := s.getQueryCh(thID)
+
+	if queryCh != nil {
+		// the channel is buffered, the disclose is dropped if the query was already answered
+		select {
+		case queryCh <- disclose:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// features returns the protocols of the registered protocol and message services matching the query.
+func (s *Service) features(query string) []ProtocolDescriptor {
+	var candidates []interface{}
+
+	for _, svc := range s.registry.ProtocolServices() {
+		candidates = append(candidates, svc)
+	}
+
+	if msgSvcProvider := s.registry.MessageServiceProvider(); msgSvcProvider != nil {
+		for _, svc := range msgSvcProvider.Services() {
+			candidates = append(candidates, svc)
+		}
+	}
+
+	matcher := globMatcher(query)
+	disclosed := make(map[string]struct{})
+	protocols := []ProtocolDescriptor{}
+
+	for _, candidate := range candidates {
+		svc, ok := candidate.(featureProvider)
+		if !ok {
+			continue
+		}
+
+		for _, msgType := range svc.MessageTypes() {
+			pid := protocolID(msgType)
+
+			if _, ok := disclosed[pid]; ok || !matcher.MatchString(pid) {
+				continue
+			}
+
+			disclosed[pid] = struct{}{}
+
+			protocols = append(protocols, ProtocolDescriptor{PID: pid})
+		}
+	}
+
+	return protocols
+}
+
+// Query sends a query to the agent on the other end of the connection identified by connectionID. This method
+// blocks until the disclose is received or it times out, the disclosed protocols are returned.
+func (s *Service) Query(connectionID, query string, timeout time.Duration) ([]ProtocolDescriptor, error) {
+	// get the connection record for the ID to fetch DID information
+	conn, err := s.getConnection(connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// generate message ID
+	msgID := uuid.New().String()
+
+	// register chan for callback processing
+	queryCh := make(chan *Disclose, 1)
+	s.setQueryCh(msgID, queryCh)
+
+	// remove the channel once its been processed
+	defer s.setQueryCh(msgID, nil)
+
+	req := &Query{
+		ID:    msgID,
+		Type:  QueryMsgType,
+		Query: query,
+	}
+
+	if err := s.outbound.SendToDID(req, conn.MyDID, conn.TheirDID); err != nil {
+		return nil, fmt.Errorf("send query : %w", err)
+	}
+
+	// callback processing (to make this function look like a sync function)
+	select {
+	case disclose := <-queryCh:
+		return disclose.Protocols, nil
+	case <-time.After(timeout):
+		return nil, ErrQueryTimeout
+	}
+}
+
+func (s *Service) getQueryCh(msgID string) chan *Disclose {
+	s.queryMapLock.RLock()
+	defer s.queryMapLock.RUnlock()
+
+	return s.queryMap[msgID]
+}
+
+func (s *Service) setQueryCh(msgID string, queryCh chan *Disclose) {
+	s.queryMapLock.Lock()
+	defer s.queryMapLock.Unlock()
+
+	if queryCh == nil {
+		delete(s.queryMap, msgID)
+	} else {
+		s.queryMap[msgID] = queryCh
+	}
+}
+
+func (s *Service) getConnection(connectionID string) (*connection.Record, error) {
+	conn, err := s.connectionLookup.GetConnectionRecord(connectionID)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrConnectionNotFound
+		}
+
+		return nil, fmt.Errorf("fetch connection record from store : %w", err)
+	}
+
+	return conn, nil
+}
+
+// protocolID returns the protocol identifier (PIURI) of the message type,
+// e.g. https://didcomm.org/trust_ping/1.0/ping -> https://didcomm.org/trust_ping/1.0
+func protocolID(msgType string) string {
+	if i := strings.LastIndex(msgType, "/"); i > 0 {
+		return msgType[:i]
+	}
+
+	return msgType
+}
+
+// globMatcher returns the matcher of the query, the * wildcard matches any sequence of characters
+func globMatcher(query string) *regexp.Regexp {
+	pattern := strings.ReplaceAll(regexp.QuoteMeta(query), `\*`, ".*")
+
+	return regexp.MustCompile("^" + pattern + "$")
+}
@@ -0,0 +1,331 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/messaging/service/basic"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/trustping"
+	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/msghandler"
+	mockdidexchange "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/didexchange"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/internal/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+const (
+	MYDID    = "myDID"
+	THEIRDID = "theirDID"
+)
+
+// this line checks that Service satisfies ProtocolService interface
+var _ ProtocolService = &Service{}
+
+func TestServiceNew(t *testing.T) {
+	t.Run("test new service - success", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, DiscoverFeatures, svc.Name())
+	})
+
+	t.Run("test new service - failure", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue: &mockstore.MockStoreProvider{
+				ErrOpenStoreHandle: errors.New("error opening the store")},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error opening the store")
+		require.Nil(t, svc)
+	})
+}
+
+func TestServiceAccept(t *testing.T) {
+	s := &Service{}
+
+	require.True(t, s.Accept(QueryMsgType))
+	require.True(t, s.Accept(DiscloseMsgType))
+	require.False(t, s.Accept("unsupported msg type"))
+	require.Equal(t, []string{QueryMsgType, DiscloseMsgType}, s.MessageTypes())
+}
+
+func TestServiceHandleInbound(t *testing.T) {
+	t.Run("test handle query - protocols are disclosed", func(t *testing.T) {
+		sent := make(chan *Disclose, 1)
+
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				require.Equal(t, MYDID, myDID)
+				require.Equal(t, THEIRDID, theirDID)
+
+				disclose, ok := msg.(*Disclose)
+				require.True(t, ok)
+
+				sent <- disclose
+
+				return nil
+			}})
+
+		id, err := svc.HandleInbound(service.NewDIDCommMsgMap(&Query{
+			Type: QueryMsgType, ID: "query-1", Query: "*",
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+		require.Equal(t, "query-1", id)
+
+		disclose := <-sent
+		require.Equal(t, DiscloseMsgType, disclose.Type)
+		require.NotEmpty(t, disclose.ID)
+		require.Equal(t, "query-1", disclose.Thread.ID)
+		require.Equal(t, []ProtocolDescriptor{
+			{PID: "https://didcomm.org/discover-features/1.0"},
+			{PID: "https://didcomm.org/trust_ping/1.0"},
+			{PID: "https://didcomm.org/basicmessage/1.0"},
+		}, disclose.Protocols)
+	})
+
+	t.Run("test handle query - protocols are filtered by the query", func(t *testing.T) {
+		sent := make(chan *Disclose, 1)
+
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				sent <- msg.(*Disclose)
+
+				return nil
+			}})
+
+		for query, expected := range map[string][]ProtocolDescriptor{
+			"https://didcomm.org/trust_ping/*":   {{PID: "https://didcomm.org/trust_ping/1.0"}},
+			"https://didcomm.org/trust_ping/1.0": {{PID: "https://didcomm.org/trust_ping/1.0"}},
+			"*/basicmessage/*":                   {{PID: "https://didcomm.org/basicmessage/1.0"}},
+			"https://didcomm.org/unknown/*":      {},
+			"https://didcomm.org/trust_ping/1.":  {},
+		} {
+			_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&Query{
+				Type: QueryMsgType, ID: "query-1", Query: query,
+			}), MYDID, THEIRDID)
+			require.NoError(t, err)
+			require.Equal(t, expected, (<-sent).Protocols, query)
+		}
+	})
+
+	t.Run("test handle query - no message service provider", func(t *testing.T) {
+		sent := make(chan *Disclose, 1)
+
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
+				ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+					sent <- msg.(*Disclose)
+
+					return nil
+				}},
+			// the services which do not disclose their message types are ignored
+			ProtocolServicesValue: []dispatcher.ProtocolService{&mockdidexchange.MockDIDExchangeSvc{}},
+		})
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Query{
+			Type: QueryMsgType, ID: "query-1", Query: "*",
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+		require.Empty(t, (<-sent).Protocols)
+	})
+
+	t.Run("test handle query - send error", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{SendErr: errors.New("send error")})
+
+		_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&Query{
+			Type: QueryMsgType, ID: "query-1", Query: "*",
+		}), MYDID, THEIRDID)
+		require.EqualError(t, err, "handle query : send error")
+	})
+
+	t.Run("test handle query - decode error", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{})
+
+		_, err := svc.HandleInbound(service.DIDCommMsgMap{
+			"@type": QueryMsgType, "@id": "query-1", "query": map[string]interface{}{"pid": "*"},
+		}, MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "query message unmarshal")
+	})
+
+	t.Run("test handle disclose - decode error", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{})
+
+		_, err := svc.HandleInbound(service.DIDCommMsgMap{
+			"@type": DiscloseMsgType, "@id": "disclose-1", "protocols": "all",
+		}, MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "disclose message unmarshal")
+	})
+
+	t.Run("test handle disclose - no pending query", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{})
+
+		_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&Disclose{
+			Type:   DiscloseMsgType,
+			ID:     "disclose-1",
+			Thread: &decorator.Thread{ID: "query-1"},
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+	})
+
+	t.Run("test handle - unrecognized msgType", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{})
+
+		_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&Query{Type: "unknown", ID: "query-1"}), MYDID, THEIRDID)
+		require.EqualError(t, err, "unrecognized msgType: unknown")
+	})
+}
+
+func TestServiceHandleOutbound(t *testing.T) {
+	t.Run("test handle outbound - query is sent", func(t *testing.T) {
+		sent := false
+
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				require.Equal(t, MYDID, myDID)
+				require.Equal(t, THEIRDID, theirDID)
+
+				sent = true
+
+				return nil
+			}})
+
+		require.NoError(t, svc.HandleOutbound(service.NewDIDCommMsgMap(&Query{Type: QueryMsgType}), MYDID, THEIRDID))
+		require.True(t, sent)
+	})
+
+	t.Run("test handle outbound - unsupported msgType", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{})
+
+		err := svc.HandleOutbound(service.NewDIDCommMsgMap(&Disclose{Type: DiscloseMsgType}), MYDID, THEIRDID)
+		require.EqualError(t, err, "unsupported outbound msgType: "+DiscloseMsgType)
+	})
+}
+
+func TestServiceQuery(t *testing.T) {
+	t.Run("test query - success", func(t *testing.T) {
+		var svc *Service
+
+		svc = newService(t, map[string][]byte{"conn_conn1": connectionRecord(t)}, &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				require.Equal(t, MYDID, myDID)
+				require.Equal(t, THEIRDID, theirDID)
+
+				query, ok := msg.(*Query)
+				require.True(t, ok)
+				require.Equal(t, "https://didcomm.org/*", query.Query)
+
+				// the other agent discloses its features
+				go func() {
+					_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&Disclose{
+						Type:      DiscloseMsgType,
+						ID:        "disclose-1",
+						Protocols: []ProtocolDescriptor{{PID: "https://didcomm.org/trust_ping/1.0"}},
+						Thread:    &decorator.Thread{ID: query.ID},
+					}), MYDID, THEIRDID)
+					require.NoError(t, err)
+				}()
+
+				return nil
+			}})
+
+		protocols, err := svc.Query("conn1", "https://didcomm.org/*", time.Second)
+		require.NoError(t, err)
+		require.Equal(t, []ProtocolDescriptor{{PID: "https://didcomm.org/trust_ping/1.0"}}, protocols)
+		require.Empty(t, svc.queryMap)
+	})
+
+	t.Run("test query - timeout", func(t *testing.T) {
+		svc := newService(t, map[string][]byte{"conn_conn1": connectionRecord(t)}, &mockdispatcher.MockOutbound{})
+
+		protocols, err := svc.Query("conn1", "*", time.Millisecond)
+		require.True(t, errors.Is(err, ErrQueryTimeout))
+		require.Nil(t, protocols)
+		require.Empty(t, svc.queryMap)
+	})
+
+	t.Run("test query - send error", func(t *testing.T) {
+		svc := newService(t, map[string][]byte{"conn_conn1": connectionRecord(t)}, &mockdispatcher.MockOutbound{
+			SendErr: errors.New("send error"),
+		})
+
+		_, err := svc.Query("conn1", "*", time.Second)
+		require.EqualError(t, err, "send query : send error")
+	})
+
+	t.Run("test query - connection not found", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{})
+
+		_, err := svc.Query("conn1", "*", time.Second)
+		require.True(t, errors.Is(err, ErrConnectionNotFound))
+	})
+
+	t.Run("test query - connection store error", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue: &mockstore.MockStoreProvider{Store: &mockstore.MockStore{
+				Store:  make(map[string][]byte),
+				ErrGet: errors.New("get error"),
+			}},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.NoError(t, err)
+
+		_, err = svc.Query("conn1", "*", time.Second)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "fetch connection record from store")
+	})
+}
+
+func newService(t *testing.T, s map[string][]byte, outbound *mockdispatcher.MockOutbound) *Service {
+	if s == nil {
+		s = make(map[string][]byte)
+	}
+
+	prov := &mockprovider.Provider{
+		StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
+		TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		OutboundDispatcherValue:       outbound,
+		MessageServiceProviderValue:   msghandler.NewMockMsgServiceProvider(),
+	}
+
+	svc, err := New(prov)
+	require.NoError(t, err)
+
+	msgSvc, err := basic.NewMessageService("basic", func(basic.Message, string, string) error { return nil })
+	require.NoError(t, err)
+
+	// the services are registered after the discover features service is created
+	prov.ProtocolServicesValue = []dispatcher.ProtocolService{svc, &trustping.Service{}}
+	require.NoError(t, prov.MessageServiceProviderValue.(*msghandler.MockMsgSvcProvider).Register(msgSvc))
+
+	return svc
+}
+
+func connectionRecord(t *testing.T) []byte {
+	connBytes, err := json.Marshal(&connection.Record{
+		ConnectionID: "conn1", MyDID: MYDID, TheirDID: THEIRDID, State: "completed"})
+	require.NoError(t, err)
+
+	return connBytes
+}
@@ -611,3 +611,8 @@ func (s *Service) Accept(msgType string) bool {
 
 	return false
 }
+
+// MessageTypes returns the message types supported by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{ProposalMsgType, RequestMsgType, ResponseMsgType, AckMsgType, ProblemReportMsgType}
+}
@@ -28,6 +28,7 @@ import (
 	messengerMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/messenger"
 	introduceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/protocol/introduce"
 	storageMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/storage"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/internal/mock/storage"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
 
@@ -2546,6 +2547,15 @@ func fakeStore(ctrl *gomock.Controller) storage.Store {
 
 		return v, nil
 	}).AnyTimes()
+	store.EXPECT().Delete(gomock.Any()).DoAndReturn(func(k string) error {
+		delete(data, k)
+
+		return nil
+	}).AnyTimes()
+	// the messenger looks up its expired records
+	store.EXPECT().Iterator(gomock.Any(), gomock.Any()).DoAndReturn(func(start, limit string) storage.StoreIterator {
+		return mockstore.NewMockIteratorWithError(nil)
+	}).AnyTimes()
 
 	return store
 }
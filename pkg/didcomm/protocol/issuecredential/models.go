@@ -0,0 +1,76 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+)
+
+// ProposeCredential is an optional message sent by the potential Holder to the Issuer
+// to initiate the protocol or in response to an offer-credential message when the Holder
+// wishes some adjustments to be made to the credential offer.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0036-issue-credential#propose-credential
+type ProposeCredential struct {
+	Type    string `json:"@type,omitempty"`
+	ID      string `json:"@id,omitempty"`
+	Comment string `json:"comment,omitempty"`
+	// CredentialProposal is an optional JSON-LD object that represents the credential data that the Holder wants
+	CredentialProposal *PreviewCredential `json:"credential_proposal,omitempty"`
+	Thread             *decorator.Thread  `json:"~thread,omitempty"`
+}
+
+// OfferCredential is a message sent by the Issuer to the potential Holder, describing the credential they intend
+// to offer and possibly the price they expect to be paid.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0036-issue-credential#offer-credential
+type OfferCredential struct {
+	Type    string `json:"@type,omitempty"`
+	ID      string `json:"@id,omitempty"`
+	Comment string `json:"comment,omitempty"`
+	// CredentialPreview is a JSON-LD object that represents the credential data that the Issuer is willing to issue
+	CredentialPreview *PreviewCredential `json:"credential_preview,omitempty"`
+	// OffersAttach is an array of attachments that further define the credential being offered
+	OffersAttach []decorator.Attachment `json:"offers~attach,omitempty"`
+	Thread       *decorator.Thread      `json:"~thread,omitempty"`
+}
+
+// RequestCredential is a message sent by the potential Holder to the Issuer,
+// to request the issuance of a credential.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0036-issue-credential#request-credential
+type RequestCredential struct {
+	Type    string `json:"@type,omitempty"`
+	ID      string `json:"@id,omitempty"`
+	Comment string `json:"comment,omitempty"`
+	// RequestsAttach is an array of attachments defining the requested formats for the credential
+	RequestsAttach []decorator.Attachment `json:"requests~attach,omitempty"`
+	Thread         *decorator.Thread      `json:"~thread,omitempty"`
+}
+
+// IssueCredential contains as attached payload the credentials being issued and is
+// sent in response to a valid RequestCredential message.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0036-issue-credential#issue-credential
+type IssueCredential struct {
+	Type    string `json:"@type,omitempty"`
+	ID      string `json:"@id,omitempty"`
+	Comment string `json:"comment,omitempty"`
+	// CredentialsAttach is an array of attachments containing the issued credentials
+	CredentialsAttach []decorator.Attachment `json:"credentials~attach,omitempty"`
+	Thread            *decorator.Thread      `json:"~thread,omitempty"`
+}
+
+// PreviewCredential is used to construct a preview of the data for the credential that is to be issued.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0036-issue-credential#preview-credential
+type PreviewCredential struct {
+	Type       string      `json:"@type,omitempty"`
+	Attributes []Attribute `json:"attributes,omitempty"`
+}
+
+// Attribute describes an attribute of the credential preview
+type Attribute struct {
+	Name     string `json:"name,omitempty"`
+	MimeType string `json:"mime-type,omitempty"`
+	Value    string `json:"value,omitempty"`
+}
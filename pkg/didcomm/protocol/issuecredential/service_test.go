@@ -0,0 +1,418 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/messenger"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	dispatcherMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/dispatcher"
+	messengerMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/messenger"
+	issuecredentialMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/protocol/issuecredential"
+	storageMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+const (
+	Alice = "Alice"
+	Bob   = "Bob"
+)
+
+// this line checks that Service satisfies service.Handler interface
+var _ service.Handler = &issuecredential.Service{}
+
+func TestService_New(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("OpenStore Error", func(t *testing.T) {
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(issuecredential.Name).Return(nil, errors.New("test err"))
+
+		provider := issuecredentialMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+
+		svc, err := issuecredential.New(provider)
+		require.EqualError(t, err, "test err")
+		require.Nil(t, svc)
+	})
+}
+
+func TestService_Stop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := newService(t, ctrl, nil)
+
+	require.NoError(t, svc.Stop())
+	require.EqualError(t, svc.Stop(), "server was already stopped")
+}
+
+func TestService_Name(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := newService(t, ctrl, nil)
+	defer stop(t, svc)
+
+	require.Equal(t, issuecredential.Name, svc.Name())
+}
+
+func TestService_Accept(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := newService(t, ctrl, nil)
+	defer stop(t, svc)
+
+	require.False(t, svc.Accept(""))
+	require.True(t, svc.Accept(issuecredential.ProposeCredentialMsgType))
+	require.True(t, svc.Accept(issuecredential.OfferCredentialMsgType))
+	require.True(t, svc.Accept(issuecredential.RequestCredentialMsgType))
+	require.True(t, svc.Accept(issuecredential.IssueCredentialMsgType))
+	require.True(t, svc.Accept(issuecredential.AckMsgType))
+	require.True(t, svc.Accept(issuecredential.ProblemReportMsgType))
+}
+
+func TestService_HandleInbound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("No clients", func(t *testing.T) {
+		svc := newService(t, ctrl, nil)
+		defer stop(t, svc)
+
+		_, err := svc.HandleInbound(newMsg(issuecredential.OfferCredentialMsgType, ""), Alice, Bob)
+		require.EqualError(t, err, "no clients are registered to handle the message")
+	})
+
+	t.Run("Unrecognized msgType", func(t *testing.T) {
+		svc := newService(t, ctrl, nil)
+		defer stop(t, svc)
+
+		require.NoError(t, svc.RegisterActionEvent(make(chan service.DIDCommAction)))
+
+		_, err := svc.HandleInbound(newMsg("unknown", ""), Alice, Bob)
+		require.EqualError(t, err, "unrecognized msgType: unknown")
+	})
+
+	t.Run("Invalid state transition", func(t *testing.T) {
+		svc := newService(t, ctrl, nil)
+		defer stop(t, svc)
+
+		require.NoError(t, svc.RegisterActionEvent(make(chan service.DIDCommAction)))
+
+		_, err := svc.HandleInbound(newMsg(issuecredential.AckMsgType, ""), Alice, Bob)
+		require.EqualError(t, err, "invalid state transition: start -> done")
+	})
+
+	t.Run("Storage error", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+		store.EXPECT().Get(gomock.Any()).Return(nil, errors.New("test err"))
+
+		svc := newService(t, ctrl, store)
+		defer stop(t, svc)
+
+		require.NoError(t, svc.RegisterActionEvent(make(chan service.DIDCommAction)))
+
+		_, err := svc.HandleInbound(newMsg(issuecredential.OfferCredentialMsgType, ""), Alice, Bob)
+		require.Contains(t, err.Error(), "cannot fetch state from store")
+	})
+
+	t.Run("Storage JSON error", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+		store.EXPECT().Get(gomock.Any()).Return([]byte("{"), nil)
+
+		svc := newService(t, ctrl, store)
+		defer stop(t, svc)
+
+		require.NoError(t, svc.RegisterActionEvent(make(chan service.DIDCommAction)))
+
+		_, err := svc.HandleInbound(newMsg(issuecredential.OfferCredentialMsgType, ""), Alice, Bob)
+		require.EqualError(t, err, "unexpected end of JSON input")
+	})
+}
+
+func TestService_HandleOutbound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("Issue credential without request", func(t *testing.T) {
+		svc := newService(t, ctrl, nil)
+		defer stop(t, svc)
+
+		err := svc.HandleOutbound(newMsg(issuecredential.IssueCredentialMsgType, ""), Alice, Bob)
+		require.EqualError(t, err, "issue-credential must be sent in response to a request-credential")
+	})
+
+	t.Run("Send error", func(t *testing.T) {
+		holder := newAgent(t, ctrl, errors.New("send error"))
+		defer stop(t, holder.svc)
+
+		err := holder.svc.HandleOutbound(newMsg(issuecredential.ProposeCredentialMsgType, ""), Alice, Bob)
+		require.EqualError(t, err, "execute: execute state proposal-sent send error")
+	})
+}
+
+// This test describes the following flow :
+// 1. Holder sends propose-credential to the Issuer
+// 2. Issuer sends offer-credential to the Holder
+// 3. Holder sends request-credential to the Issuer
+// 4. Issuer sends issue-credential to the Holder
+// 5. Holder sends ack to the Issuer
+func TestService_Flow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	holder := newAgent(t, ctrl, nil)
+	defer stop(t, holder.svc)
+
+	issuer := newAgent(t, ctrl, nil)
+	defer stop(t, issuer.svc)
+
+	require.NoError(t, holder.svc.HandleOutbound(service.NewDIDCommMsgMap(issuecredential.ProposeCredential{
+		Type: issuecredential.ProposeCredentialMsgType,
+		ID:   uuid.New().String(),
+	}), Alice, Bob))
+
+	propose := deliver(t, holder, issuer)
+	requireAction(t, issuer, issuecredential.ProposeCredentialMsgType).Continue(&issuecredential.OfferCredential{})
+
+	offer := deliver(t, issuer, holder)
+	requireThread(t, propose, offer)
+	requireAction(t, holder, issuecredential.OfferCredentialMsgType).Continue(nil)
+
+	request := deliver(t, holder, issuer)
+	requireThread(t, propose, request)
+	requireAction(t, issuer, issuecredential.RequestCredentialMsgType).Continue(&issuecredential.IssueCredential{
+		CredentialsAttach: []decorator.Attachment{{Data: decorator.AttachmentData{JSON: "credential"}}},
+	})
+
+	issue := deliver(t, issuer, holder)
+	requireThread(t, propose, issue)
+
+	credential := issuecredential.IssueCredential{}
+	require.NoError(t, issue.Decode(&credential))
+	require.Equal(t, "credential", credential.CredentialsAttach[0].Data.JSON)
+
+	requireAction(t, holder, issuecredential.IssueCredentialMsgType).Continue(nil)
+
+	ack := deliver(t, holder, issuer)
+	requireThread(t, propose, ack)
+	require.Equal(t, issuecredential.AckMsgType, ack.Type())
+
+	requireState(t, holder, "done")
+	requireState(t, issuer, "done")
+}
+
+func TestService_FlowStop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("Holder declines the offer", func(t *testing.T) {
+		holder := newAgent(t, ctrl, nil)
+		defer stop(t, holder.svc)
+
+		issuer := newAgent(t, ctrl, nil)
+		defer stop(t, issuer.svc)
+
+		require.NoError(t, issuer.svc.HandleOutbound(newMsg(issuecredential.OfferCredentialMsgType, ""), Bob, Alice))
+
+		deliver(t, issuer, holder)
+		requireAction(t, holder, issuecredential.OfferCredentialMsgType).Stop(errors.New("declined"))
+
+		problem := deliver(t, holder, issuer)
+		require.Equal(t, issuecredential.ProblemReportMsgType, problem.Type())
+
+		requireState(t, holder, "done")
+		requireState(t, issuer, "done")
+	})
+
+	t.Run("Issuer continues without an offer", func(t *testing.T) {
+		holder := newAgent(t, ctrl, nil)
+		defer stop(t, holder.svc)
+
+		issuer := newAgent(t, ctrl, nil)
+		defer stop(t, issuer.svc)
+
+		require.NoError(t, holder.svc.HandleOutbound(newMsg(issuecredential.ProposeCredentialMsgType, ""), Alice, Bob))
+
+		deliver(t, holder, issuer)
+		requireAction(t, issuer, issuecredential.ProposeCredentialMsgType).Continue(nil)
+
+		problem := deliver(t, issuer, holder)
+		require.Equal(t, issuecredential.ProblemReportMsgType, problem.Type())
+
+		requireState(t, issuer, "done")
+		requireState(t, holder, "done")
+	})
+
+	t.Run("Issuer continues without a credential", func(t *testing.T) {
+		holder := newAgent(t, ctrl, nil)
+		defer stop(t, holder.svc)
+
+		issuer := newAgent(t, ctrl, nil)
+		defer stop(t, issuer.svc)
+
+		require.NoError(t, holder.svc.HandleOutbound(newMsg(issuecredential.RequestCredentialMsgType, ""), Alice, Bob))
+
+		deliver(t, holder, issuer)
+		requireAction(t, issuer, issuecredential.RequestCredentialMsgType).Continue(&issuecredential.OfferCredential{})
+
+		problem := deliver(t, issuer, holder)
+		require.Equal(t, issuecredential.ProblemReportMsgType, problem.Type())
+
+		requireState(t, issuer, "done")
+		requireState(t, holder, "done")
+	})
+}
+
+type agent struct {
+	svc       *issuecredential.Service
+	messenger *messenger.Messenger
+	outbound  chan service.DIDCommMsgMap
+	actions   chan service.DIDCommAction
+	states    chan service.StateMsg
+}
+
+// newAgent creates an issue credential service using a real messenger, outbound messages are sent to agent.outbound
+func newAgent(t *testing.T, ctrl *gomock.Controller, sendErr error) *agent {
+	a := &agent{
+		outbound: make(chan service.DIDCommMsgMap, 1),
+		actions:  make(chan service.DIDCommAction, 1),
+		states:   make(chan service.StateMsg, 100),
+	}
+
+	dispatcher := dispatcherMocks.NewMockOutbound(ctrl)
+	dispatcher.EXPECT().SendToDID(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(msg service.DIDCommMsgMap, _, _ string) error {
+			if sendErr != nil {
+				return sendErr
+			}
+
+			a.outbound <- msg
+
+			return nil
+		}).AnyTimes()
+
+	messengerProvider := messengerMocks.NewMockProvider(ctrl)
+	messengerProvider.EXPECT().StorageProvider().Return(mem.NewProvider())
+	messengerProvider.EXPECT().OutboundDispatcher().Return(dispatcher)
+
+	msgr, err := messenger.NewMessenger(messengerProvider)
+	require.NoError(t, err)
+
+	provider := issuecredentialMocks.NewMockProvider(ctrl)
+	provider.EXPECT().StorageProvider().Return(mem.NewProvider())
+	provider.EXPECT().Messenger().Return(msgr)
+
+	a.svc, err = issuecredential.New(provider)
+	require.NoError(t, err)
+
+	require.NoError(t, a.svc.RegisterActionEvent(a.actions))
+	require.NoError(t, a.svc.RegisterMsgEvent(a.states))
+
+	a.messenger = msgr
+
+	return a
+}
+
+// newService creates an issue credential service using the given store, a memory store is used if nil
+func newService(t *testing.T, ctrl *gomock.Controller, store storage.Store) *issuecredential.Service {
+	if store == nil {
+		var err error
+
+		store, err = mem.NewProvider().OpenStore(issuecredential.Name)
+		require.NoError(t, err)
+	}
+
+	storageProvider := storageMocks.NewMockProvider(ctrl)
+	storageProvider.EXPECT().OpenStore(issuecredential.Name).Return(store, nil)
+
+	provider := issuecredentialMocks.NewMockProvider(ctrl)
+	provider.EXPECT().StorageProvider().Return(storageProvider)
+	provider.EXPECT().Messenger().Return(nil)
+
+	svc, err := issuecredential.New(provider)
+	require.NoError(t, err)
+
+	return svc
+}
+
+// deliver passes the message sent by an agent to another one
+func deliver(t *testing.T, from, to *agent) service.DIDCommMsgMap {
+	select {
+	case msg := <-from.outbound:
+		require.NoError(t, to.messenger.HandleInbound(msg, Bob, Alice))
+
+		_, err := to.svc.HandleInbound(msg, Bob, Alice)
+		require.NoError(t, err)
+
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the message")
+	}
+
+	return nil
+}
+
+func requireAction(t *testing.T, a *agent, msgType string) service.DIDCommAction {
+	select {
+	case action := <-a.actions:
+		require.Equal(t, issuecredential.Name, action.ProtocolName)
+		require.Equal(t, msgType, action.Message.Type())
+
+		return action
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the action")
+	}
+
+	return service.DIDCommAction{}
+}
+
+func requireThread(t *testing.T, first, msg service.DIDCommMsg) {
+	thID, err := msg.ThreadID()
+	require.NoError(t, err)
+	require.Equal(t, first.ID(), thID)
+}
+
+// requireState checks that the agent reaches the given state
+func requireState(t *testing.T, a *agent, stateID string) {
+	for {
+		select {
+		case msg := <-a.states:
+			if msg.Type == service.PostState && msg.StateID == stateID {
+				return
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timeout waiting for the %s state", stateID)
+		}
+	}
+}
+
+func newMsg(msgType, thID string) service.DIDCommMsgMap {
+	return service.NewDIDCommMsgMap(model.Ack{
+		Type:   msgType,
+		ID:     uuid.New().String(),
+		Thread: &decorator.Thread{ID: thID},
+	})
+}
+
+func stop(t *testing.T, s interface{ Stop() error }) {
+	require.NoError(t, s.Stop())
+}
@@ -0,0 +1,157 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func notTransition(t *testing.T, st state) {
+	t.Helper()
+
+	var allState = [...]state{
+		&noOp{}, &start{}, &abandoning{}, &done{},
+		&proposalReceived{}, &offerSent{}, &requestReceived{}, &credentialIssued{},
+		&proposalSent{}, &offerReceived{}, &requestSent{}, &credentialReceived{},
+	}
+
+	for _, s := range allState {
+		require.False(t, st.CanTransitionTo(s))
+	}
+}
+
+func TestNoOp_CanTransitionTo(t *testing.T) {
+	noop := &noOp{}
+	require.Equal(t, stateNameNoop, noop.Name())
+	notTransition(t, noop)
+}
+
+func TestNoOp_Execute(t *testing.T) {
+	followup, err := (&noOp{}).ExecuteInbound(nil, &metaData{})
+	require.EqualError(t, err, "cannot execute no-op")
+	require.Nil(t, followup)
+
+	followup, err = (&noOp{}).ExecuteOutbound(nil, &metaData{})
+	require.EqualError(t, err, "cannot execute no-op")
+	require.Nil(t, followup)
+}
+
+func TestStart_CanTransitionTo(t *testing.T) {
+	st := &start{}
+	require.Equal(t, stateNameStart, st.Name())
+
+	require.True(t, st.CanTransitionTo(&proposalReceived{}))
+	require.True(t, st.CanTransitionTo(&offerSent{}))
+	require.True(t, st.CanTransitionTo(&requestReceived{}))
+	require.True(t, st.CanTransitionTo(&proposalSent{}))
+	require.True(t, st.CanTransitionTo(&offerReceived{}))
+	require.True(t, st.CanTransitionTo(&requestSent{}))
+	require.True(t, st.CanTransitionTo(&abandoning{}))
+
+	require.False(t, st.CanTransitionTo(&noOp{}))
+	require.False(t, st.CanTransitionTo(&start{}))
+	require.False(t, st.CanTransitionTo(&done{}))
+	require.False(t, st.CanTransitionTo(&credentialIssued{}))
+	require.False(t, st.CanTransitionTo(&credentialReceived{}))
+}
+
+func TestStart_Execute(t *testing.T) {
+	followup, err := (&start{}).ExecuteInbound(nil, &metaData{})
+	require.Contains(t, err.Error(), "is not supposed to be used")
+	require.Nil(t, followup)
+
+	followup, err = (&start{}).ExecuteOutbound(nil, &metaData{})
+	require.Contains(t, err.Error(), "is not supposed to be used")
+	require.Nil(t, followup)
+}
+
+func TestAbandoning_CanTransitionTo(t *testing.T) {
+	st := &abandoning{}
+	require.Equal(t, stateNameAbandoning, st.Name())
+
+	require.True(t, st.CanTransitionTo(&done{}))
+	require.False(t, st.CanTransitionTo(&abandoning{}))
+	require.False(t, st.CanTransitionTo(&start{}))
+}
+
+func TestDone_CanTransitionTo(t *testing.T) {
+	st := &done{}
+	require.Equal(t, stateNameDone, st.Name())
+	notTransition(t, st)
+}
+
+func TestIssuerStates_CanTransitionTo(t *testing.T) {
+	require.Equal(t, stateNameProposalReceived, (&proposalReceived{}).Name())
+	require.True(t, (&proposalReceived{}).CanTransitionTo(&offerSent{}))
+	require.True(t, (&proposalReceived{}).CanTransitionTo(&abandoning{}))
+	require.False(t, (&proposalReceived{}).CanTransitionTo(&requestReceived{}))
+
+	require.Equal(t, stateNameOfferSent, (&offerSent{}).Name())
+	require.True(t, (&offerSent{}).CanTransitionTo(&proposalReceived{}))
+	require.True(t, (&offerSent{}).CanTransitionTo(&requestReceived{}))
+	require.True(t, (&offerSent{}).CanTransitionTo(&abandoning{}))
+	require.False(t, (&offerSent{}).CanTransitionTo(&credentialIssued{}))
+
+	require.Equal(t, stateNameRequestReceived, (&requestReceived{}).Name())
+	require.True(t, (&requestReceived{}).CanTransitionTo(&credentialIssued{}))
+	require.True(t, (&requestReceived{}).CanTransitionTo(&abandoning{}))
+	require.False(t, (&requestReceived{}).CanTransitionTo(&done{}))
+
+	require.Equal(t, stateNameCredentialIssued, (&credentialIssued{}).Name())
+	require.True(t, (&credentialIssued{}).CanTransitionTo(&done{}))
+	require.True(t, (&credentialIssued{}).CanTransitionTo(&abandoning{}))
+	require.False(t, (&credentialIssued{}).CanTransitionTo(&offerSent{}))
+}
+
+func TestHolderStates_CanTransitionTo(t *testing.T) {
+	require.Equal(t, stateNameProposalSent, (&proposalSent{}).Name())
+	require.True(t, (&proposalSent{}).CanTransitionTo(&offerReceived{}))
+	require.True(t, (&proposalSent{}).CanTransitionTo(&abandoning{}))
+	require.False(t, (&proposalSent{}).CanTransitionTo(&requestSent{}))
+
+	require.Equal(t, stateNameOfferReceived, (&offerReceived{}).Name())
+	require.True(t, (&offerReceived{}).CanTransitionTo(&requestSent{}))
+	require.True(t, (&offerReceived{}).CanTransitionTo(&abandoning{}))
+	require.False(t, (&offerReceived{}).CanTransitionTo(&credentialReceived{}))
+
+	require.Equal(t, stateNameRequestSent, (&requestSent{}).Name())
+	require.True(t, (&requestSent{}).CanTransitionTo(&credentialReceived{}))
+	require.True(t, (&requestSent{}).CanTransitionTo(&abandoning{}))
+	require.False(t, (&requestSent{}).CanTransitionTo(&done{}))
+
+	require.Equal(t, stateNameCredentialReceived, (&credentialReceived{}).Name())
+	require.True(t, (&credentialReceived{}).CanTransitionTo(&done{}))
+	require.True(t, (&credentialReceived{}).CanTransitionTo(&abandoning{}))
+	require.False(t, (&credentialReceived{}).CanTransitionTo(&requestSent{}))
+}
+
+func TestStates_ExecuteNotSupposedToBeUsed(t *testing.T) {
+	for _, st := range []state{&abandoning{}, &done{}, &proposalReceived{}, &requestReceived{},
+		&credentialIssued{}, &offerReceived{}, &credentialReceived{}} {
+		followup, err := st.ExecuteOutbound(nil, &metaData{})
+		require.Contains(t, err.Error(), "ExecuteOutbound function is not supposed to be used")
+		require.Nil(t, followup)
+	}
+
+	followup, err := (&proposalSent{}).ExecuteInbound(nil, &metaData{})
+	require.Contains(t, err.Error(), "ExecuteInbound function is not supposed to be used")
+	require.Nil(t, followup)
+}
+
+func Test_stateFromName(t *testing.T) {
+	for _, st := range []state{
+		&noOp{}, &start{}, &abandoning{}, &done{},
+		&proposalReceived{}, &offerSent{}, &requestReceived{}, &credentialIssued{},
+		&proposalSent{}, &offerReceived{}, &requestSent{}, &credentialReceived{},
+	} {
+		require.Equal(t, st, stateFromName(st.Name()))
+	}
+
+	require.Equal(t, &noOp{}, stateFromName("unknown"))
+}
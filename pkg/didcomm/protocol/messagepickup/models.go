@@ -0,0 +1,70 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package messagepickup
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+)
+
+// StatusRequest message pickup status request message.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0212-pickup#status-request
+type StatusRequest struct {
+	Type   string            `json:"@type,omitempty"`
+	ID     string            `json:"@id,omitempty"`
+	Thread *decorator.Thread `json:"~thread,omitempty"`
+}
+
+// Status message pickup status message.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0212-pickup#status
+type Status struct {
+	Type              string            `json:"@type,omitempty"`
+	ID                string            `json:"@id,omitempty"`
+	MessageCount      int               `json:"message_count"`
+	DurationWaited    int               `json:"duration_waited,omitempty"`
+	LastAddedTime     *time.Time        `json:"last_added_time,omitempty"`
+	LastDeliveredTime *time.Time        `json:"last_delivered_time,omitempty"`
+	LastRemovedTime   *time.Time        `json:"last_removed_time,omitempty"`
+	TotalSize         int               `json:"total_size,omitempty"`
+	Thread            *decorator.Thread `json:"~thread,omitempty"`
+}
+
+// BatchPickup message pickup batch pickup message.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0212-pickup#batch-pickup
+type BatchPickup struct {
+	Type      string            `json:"@type,omitempty"`
+	ID        string            `json:"@id,omitempty"`
+	BatchSize int               `json:"batch_size"`
+	Thread    *decorator.Thread `json:"~thread,omitempty"`
+}
+
+// Batch message pickup batch message.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0212-pickup#batch
+type Batch struct {
+	Type     string            `json:"@type,omitempty"`
+	ID       string            `json:"@id,omitempty"`
+	Messages []*Message        `json:"messages~attach"`
+	Thread   *decorator.Thread `json:"~thread,omitempty"`
+}
+
+// Message is a queued message, the message is the packed message forwarded to the recipient.
+type Message struct {
+	ID        string          `json:"@id"`
+	AddedTime time.Time       `json:"added_time"`
+	Message   json.RawMessage `json:"message"`
+}
+
+// Noop message pickup noop message, it is sent with the return route option to receive the queued messages
+// over the open connection.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0212-pickup#noop
+type Noop struct {
+	Type string `json:"@type,omitempty"`
+	ID   string `json:"@id,omitempty"`
+	decorator.Transport
+}
@@ -0,0 +1,735 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package messagepickup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	commontransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+var logger = log.New("aries-framework/messagepickup/service")
+
+// constants for message pickup spec types
+const (
+	// MessagePickup message pickup protocol
+	MessagePickup = "messagepickup"
+
+	// MessagePickupSpec defines the message pickup spec
+	MessagePickupSpec = "https://didcomm.org/messagepickup/1.0/"
+
+	// StatusRequestMsgType defines the message pickup status request message type.
+	StatusRequestMsgType = MessagePickupSpec + "status-request"
+
+	// StatusMsgType defines the message pickup status message type.
+	StatusMsgType = MessagePickupSpec + "status"
+
+	// BatchPickupMsgType defines the message pickup batch pickup message type.
+	BatchPickupMsgType = MessagePickupSpec + "batch-pickup"
+
+	// BatchMsgType defines the message pickup batch message type.
+	BatchMsgType = MessagePickupSpec + "batch"
+
+	// NoopMsgType defines the message pickup noop message type.
+	NoopMsgType = MessagePickupSpec + "noop"
+)
+
+const (
+	pickupTimeout = 5 * time.Second
+
+	// maxInboxMessages is the maximum number of the messages queued for a recipient
+	maxInboxMessages = 1000
+
+	// inboxMessageTTL is the time the message is queued for, the expired messages are removed
+	inboxMessageTTL = 72 * time.Hour
+
+	inboxKeyPrefix = "inbox_"
+
+	// limitPattern with `~` at the end for lte of given prefix (less than or equal)
+	limitPattern = "%s~"
+)
+
+var (
+	// ErrConnectionNotFound connection not found error
+	ErrConnectionNotFound = errors.New("connection not found")
+
+	// ErrInboxFull is returned when the recipient has the maximum number of the messages queued
+	ErrInboxFull = errors.New("inbox is full")
+)
+
+// provider contains dependencies for the Message Pickup protocol and is typically created by using aries.Context()
+type provider interface {
+	OutboundDispatcher() dispatcher.Outbound
+	StorageProvider() storage.Provider
+	TransientStorageProvider() storage.Provider
+	Packager() commontransport.Packager
+	InboundMessageHandler() transport.InboundMessageHandler
+	VDRIRegistry() vdri.Registry
+}
+
+// ProtocolService service interface for message pickup.
+type ProtocolService interface {
+	// AddMessage queues the message for the recipient identified by theirDID
+	AddMessage(message []byte, theirDID string) error
+
+	// StatusRequest requests the status of the queued messages from the router on the other end of the connection
+	StatusRequest(connectionID string) (*Status, error)
+
+	// BatchPickup picks up a batch of the queued messages from the router on the other end of the connection
+	BatchPickup(connectionID string, size int) (int, error)
+
+	// Noop requests the router on the other end of the connection to deliver the queued messages
+	Noop(connectionID string) error
+}
+
+// inbox holds the messages queued for a recipient. The inbox is stored under the inbox_<theirDID> key,
+// every message is stored as the separate record under the <theirDID>_<message ID> key.
+type inbox struct {
+	Messages          []*Message `json:"-"`
+	LastAddedTime     time.Time  `json:"last_added_time,omitempty"`
+	LastDeliveredTime time.Time  `json:"last_delivered_time,omitempty"`
+	LastRemovedTime   time.Time  `json:"last_removed_time,omitempty"`
+}
+
+// Service for Message Pickup protocol.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0212-pickup
+type Service struct {
+	outbound         dispatcher.Outbound
+	packager         commontransport.Packager
+	msgHandler       transport.InboundMessageHandler
+	vdRegistry       vdri.Registry
+	connectionLookup *connection.Lookup
+	inboxStore       storage.Store
+	inboxLocks       map[string]*sync.Mutex
+	inboxLocksLock   sync.Mutex
+	maxMessages      int
+	messageTTL       time.Duration
+	statusMap        map[string]chan *Status
+	statusMapLock    sync.RWMutex
+	batchMap         map[string]chan *Batch
+	batchMapLock     sync.RWMutex
+	timeout          time.Duration
+}
+
+// New return message pickup service.
+func New(prov provider) (*Service, error) {
+	store, err := prov.StorageProvider().OpenStore(MessagePickup)
+	if err != nil {
+		return nil, fmt.Errorf("open message pickup store : %w", err)
+	}
+
+	connectionLookup, err := connection.NewLookup(prov)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		outbound:         prov.OutboundDispatcher(),
+		packager:         prov.Packager(),
+		msgHandler:       prov.InboundMessageHandler(),
+		vdRegistry:       prov.VDRIRegistry(),
+		connectionLookup: connectionLookup,
+		inboxStore:       store,
+		inboxLocks:       make(map[string]*sync.Mutex),
+		maxMessages:      maxInboxMessages,
+		messageTTL:       inboxMessageTTL,
+		statusMap:        make(map[string]chan *Status),
+		batchMap:         make(map[string]chan *Batch),
+		timeout:          pickupTimeout,
+	}, nil
+}
+
+// HandleInbound handles inbound message pickup messages.
+func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	var err error
+
+	switch msg.Type() {
+	case StatusRequestMsgType:
+		err = s.handleStatusRequest(msg, myDID, theirDID)
+	case StatusMsgType:
+		err = s.handleStatus(msg)
+	case BatchPickupMsgType:
+		err = s.handleBatchPickup(msg, myDID, theirDID)
+	case BatchMsgType:
+		err = s.handleBatch(msg)
+	case NoopMsgType:
+		err = s.handleNoop(theirDID)
+	default:
+		return "", fmt.Errorf("unrecognized msgType: %s", msg.Type())
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("handle %s : %w", msg.Type(), err)
+	}
+
+	return msg.ID(), nil
+}
+
+// HandleOutbound handles outbound message pickup messages.
+func (s *Service) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) error {
+	return errors.New("not implemented")
+}
+
+// Accept checks whether the service can handle the message type.
+func (s *Service) Accept(msgType string) bool {
+	switch msgType {
+	case StatusRequestMsgType, StatusMsgType, BatchPickupMsgType, BatchMsgType, NoopMsgType:
+		return true
+	}
+
+	return false
+}
+
+// MessageTypes returns the message types supported by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{StatusRequestMsgType, StatusMsgType, BatchPickupMsgType, BatchMsgType, NoopMsgType}
+}
+
+// Name of the service
+func (s *Service) Name() string {
+	return MessagePickup
+}
+
+// AddMessage queues the message for the recipient identified by theirDID (the DID of the agent which
+// registered the recipient key with the router). The queued messages are retrieved by the recipient
+// through the batch pickup or delivered when the recipient sends a noop with the return route option.
+// At most 1000 messages are queued for the recipient (ErrInboxFull), the messages expire after 72 hours.
+func (s *Service) AddMessage(message []byte, theirDID string) error {
+	lock := s.inboxLock(theirDID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	box, err := s.getInbox(theirDID)
+	if err != nil {
+		return err
+	}
+
+	if len(box.Messages) >= s.maxMessages {
+		return fmt.Errorf("queue message for %s : %w", theirDID, ErrInboxFull)
+	}
+
+	// the messages are ordered by the added time
+	now := time.Now().UTC()
+	if !now.After(box.LastAddedTime) {
+		now = box.LastAddedTime.Add(time.Nanosecond)
+	}
+
+	msg := &Message{
+		ID:        uuid.New().String(),
+		AddedTime: now,
+		Message:   message,
+	}
+
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal inbox message : %w", err)
+	}
+
+	box.LastAddedTime = now
+
+	return s.saveInbox(theirDID, box, storage.Operation{Key: messageKey(theirDID, msg.ID), Value: msgBytes})
+}
+
+func (s *Service) handleStatusRequest(msg service.DIDCommMsg, myDID, theirDID string) error {
+	// unmarshal the payload
+	request := &StatusRequest{}
+
+	err := msg.Decode(request)
+	if err != nil {
+		return fmt.Errorf("status request message unmarshal : %w", err)
+	}
+
+	lock := s.inboxLock(theirDID)
+	lock.Lock()
+	box, err := s.getInbox(theirDID)
+	lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	status := box.status()
+	status.Type = StatusMsgType
+	status.ID = uuid.New().String()
+	status.Thread = &decorator.Thread{ID: msg.ID()}
+
+	return s.outbound.SendToDID(status, myDID, theirDID)
+}
+
+func (s *Service) handleStatus(msg service.DIDCommMsg) error {
+	// unmarshal the payload
+	status := &Status{}
+
+	err := msg.Decode(status)
+	if err != nil {
+		return fmt.Errorf("status message unmarshal : %w", err)
+	}
+
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return fmt.Errorf("status message threadID : %w", err)
+	}
+
+	// check if there are any channels registered for the status request
+	statusCh := s.getStatusCh(thID)
+
+	if statusCh != nil {
+		select {
+		case statusCh <- status:
+		default:
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) handleBatchPickup(msg service.DIDCommMsg, myDID, theirDID string) error {
+	// unmarshal the payload
+	request := &BatchPickup{}
+
+	err := msg.Decode(request)
+	if err != nil {
+		return fmt.Errorf("batch pickup message unmarshal : %w", err)
+	}
+
+	// the inbox is not locked while the batch is sent
+	lock := s.inboxLock(theirDID)
+	lock.Lock()
+	box, err := s.getInbox(theirDID)
+	lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	size := request.BatchSize
+	if size <= 0 || size > len(box.Messages) {
+		size = len(box.Messages)
+	}
+
+	batch := &Batch{
+		Type:     BatchMsgType,
+		ID:       uuid.New().String(),
+		Messages: box.Messages[:size],
+		Thread:   &decorator.Thread{ID: msg.ID()},
+	}
+
+	if err := s.outbound.SendToDID(batch, myDID, theirDID); err != nil {
+		return fmt.Errorf("send batch : %w", err)
+	}
+
+	// the messages are removed from the inbox once the batch is sent
+	return s.removeMessages(theirDID, batch.Messages, false)
+}
+
+func (s *Service) handleBatch(msg service.DIDCommMsg) error {
+	// unmarshal the payload
+	batch := &Batch{}
+
+	err := msg.Decode(batch)
+	if err != nil {
+		return fmt.Errorf("batch message unmarshal : %w", err)
+	}
+
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return fmt.Errorf("batch message threadID : %w", err)
+	}
+
+	// check if there are any channels registered for the batch pickup
+	batchCh := s.getBatchCh(thID)
+
+	if batchCh != nil {
+		select {
+		case batchCh <- batch:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// handleNoop delivers the queued messages to the recipient, the messages are sent over the connection
+// kept open by the transport when the noop is sent with the return route option.
+func (s *Service) handleNoop(theirDID string) error {
+	// the inbox is not locked while the messages are delivered
+	lock := s.inboxLock(theirDID)
+	lock.Lock()
+	box, err := s.getInbox(theirDID)
+	lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	if len(box.Messages) == 0 {
+		return nil
+	}
+
+	dest, err := service.GetDestination(theirDID, s.vdRegistry)
+	if err != nil {
+		return fmt.Errorf("get destination : %w", err)
+	}
+
+	delivered := 0
+
+	for _, m := range box.Messages {
+		if err = s.outbound.Forward(m.Message, dest); err != nil {
+			logger.Warnf("deliver queued message %s : %s", m.ID, err)
+
+			break
+		}
+
+		delivered++
+	}
+
+	if delivered == 0 {
+		return fmt.Errorf("deliver queued messages : %w", err)
+	}
+
+	return s.removeMessages(theirDID, box.Messages[:delivered], true)
+}
+
+// StatusRequest requests the status of the messages queued by the router on the other end of the connection
+// identified by connectionID. This method blocks until the status is received or it times out.
+func (s *Service) StatusRequest(connectionID string) (*Status, error) {
+	// get the connection record for the ID to fetch DID information
+	conn, err := s.getConnection(connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// generate message ID
+	msgID := uuid.New().String()
+
+	// register chan for callback processing
+	statusCh := make(chan *Status, 1)
+	s.setStatusCh(msgID, statusCh)
+
+	// remove the channel once its been processed
+	defer s.setStatusCh(msgID, nil)
+
+	req := &StatusRequest{
+		ID:   msgID,
+		Type: StatusRequestMsgType,
+	}
+
+	if err := s.outbound.SendToDID(req, conn.MyDID, conn.TheirDID); err != nil {
+		return nil, fmt.Errorf("send status request : %w", err)
+	}
+
+	// callback processing (to make this function look like a sync function)
+	select {
+	case status := <-statusCh:
+		return status, nil
+	case <-time.After(s.timeout):
+		return nil, errors.New("timeout waiting for status from the router")
+	}
+}
+
+// BatchPickup picks up a batch of at most size messages queued by the router on the other end of the connection
+// identified by connectionID. This method blocks until the batch is received or it times out. The messages in
+// the batch are processed by the agent as any other inbound message, the number of messages is returned.
+func (s *Service) BatchPickup(connectionID string, size int) (int, error) {
+	// get the connection record for the ID to fetch DID information
+	conn, err := s.getConnection(connectionID)
+	if err != nil {
+		return 0, err
+	}
+
+	// generate message ID
+	msgID := uuid.New().String()
+
+	// register chan for callback processing
+	batchCh := make(chan *Batch, 1)
+	s.setBatchCh(msgID, batchCh)
+
+	// remove the channel once its been processed
+	defer s.setBatchCh(msgID, nil)
+
+	req := &BatchPickup{
+		ID:        msgID,
+		Type:      BatchPickupMsgType,
+		BatchSize: size,
+	}
+
+	if err := s.outbound.SendToDID(req, conn.MyDID, conn.TheirDID); err != nil {
+		return 0, fmt.Errorf("send batch pickup request : %w", err)
+	}
+
+	// callback processing (to make this function look like a sync function)
+	select {
+	case batch := <-batchCh:
+		for _, m := range batch.Messages {
+			if err := s.handleMessage(m.Message); err != nil {
+				logger.Errorf("handle queued message %s : %s", m.ID, err)
+			}
+		}
+
+		return len(batch.Messages), nil
+	case <-time.After(s.timeout):
+		return 0, errors.New("timeout waiting for batch from the router")
+	}
+}
+
+// Noop sends a noop to the router on the other end of the connection identified by connectionID. The noop
+// is sent with the return route option, the router delivers the queued messages over the open connection.
+func (s *Service) Noop(connectionID string) error {
+	// get the connection record for the ID to fetch DID information
+	conn, err := s.getConnection(connectionID)
+	if err != nil {
+		return err
+	}
+
+	noop := &Noop{
+		ID:   uuid.New().String(),
+		Type: NoopMsgType,
+		Transport: decorator.Transport{
+			ReturnRoute: &decorator.ReturnRoute{Value: decorator.TransportReturnRouteAll},
+		},
+	}
+
+	if err := s.outbound.SendToDID(noop, conn.MyDID, conn.TheirDID); err != nil {
+		return fmt.Errorf("send noop : %w", err)
+	}
+
+	return nil
+}
+
+// handleMessage unpacks the queued message and dispatches it as an inbound message.
+func (s *Service) handleMessage(message []byte) error {
+	unpackMsg, err := s.packager.UnpackMessage(message)
+	if err != nil {
+		return fmt.Errorf("unpack message : %w", err)
+	}
+
+	return s.msgHandler(unpackMsg.Message, unpackMsg.ToDID, unpackMsg.FromDID)
+}
+
+// removeMessages removes the sent messages from the inbox, the messages which expired or were removed
+// in the meantime are skipped.
+func (s *Service) removeMessages(theirDID string, messages []*Message, delivered bool) error {
+	lock := s.inboxLock(theirDID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	box, err := s.getInbox(theirDID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	if delivered {
+		box.LastDeliveredTime = now
+	}
+
+	var ops []storage.Operation
+
+	for _, m := range messages {
+		ops = append(ops, storage.Operation{Key: messageKey(theirDID, m.ID)})
+	}
+
+	if len(ops) > 0 {
+		box.LastRemovedTime = now
+	}
+
+	return s.saveInbox(theirDID, box, ops...)
+}
+
+// inboxLock returns the lock of the inbox of the recipient identified by theirDID.
+func (s *Service) inboxLock(theirDID string) *sync.Mutex {
+	s.inboxLocksLock.Lock()
+	defer s.inboxLocksLock.Unlock()
+
+	lock, ok := s.inboxLocks[theirDID]
+	if !ok {
+		lock = &sync.Mutex{}
+		s.inboxLocks[theirDID] = lock
+	}
+
+	return lock
+}
+
+// getInbox returns the inbox with the queued messages ordered by the added time, the expired messages
+// are removed. The caller holds the lock of the inbox.
+func (s *Service) getInbox(theirDID string) (*inbox, error) {
+	box := &inbox{}
+
+	val, err := s.inboxStore.Get(inboxKeyPrefix + theirDID)
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return nil, fmt.Errorf("get inbox : %w", err)
+	}
+
+	if err == nil {
+		if err := json.Unmarshal(val, box); err != nil {
+			return nil, fmt.Errorf("unmarshal inbox : %w", err)
+		}
+	}
+
+	box.Messages, err = s.getMessages(theirDID)
+	if err != nil {
+		return nil, err
+	}
+
+	return box, nil
+}
+
+func (s *Service) getMessages(theirDID string) ([]*Message, error) {
+	prefix := messageKey(theirDID, "")
+
+	itr := s.inboxStore.Iterator(prefix, fmt.Sprintf(limitPattern, prefix))
+	defer itr.Release()
+
+	var (
+		messages []*Message
+		expired  []storage.Operation
+	)
+
+	for itr.Next() {
+		// the messages of the DID which has theirDID_ prefix are skipped
+		if strings.Contains(strings.TrimPrefix(string(itr.Key()), prefix), "_") {
+			continue
+		}
+
+		m := &Message{}
+
+		if err := json.Unmarshal(itr.Value(), m); err != nil {
+			return nil, fmt.Errorf("unmarshal inbox message : %w", err)
+		}
+
+		if time.Since(m.AddedTime) >= s.messageTTL {
+			expired = append(expired, storage.Operation{Key: string(itr.Key())})
+			continue
+		}
+
+		messages = append(messages, m)
+	}
+
+	if err := itr.Error(); err != nil {
+		return nil, fmt.Errorf("fetch inbox messages : %w", err)
+	}
+
+	if len(expired) > 0 {
+		logger.Debugf("remove %d expired messages queued for %s", len(expired), theirDID)
+
+		if err := s.inboxStore.Batch(expired); err != nil {
+			return nil, fmt.Errorf("remove expired inbox messages : %w", err)
+		}
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].AddedTime.Before(messages[j].AddedTime)
+	})
+
+	return messages, nil
+}
+
+// saveInbox saves the inbox along with the operations on its messages.
+func (s *Service) saveInbox(theirDID string, box *inbox, ops ...storage.Operation) error {
+	val, err := json.Marshal(box)
+	if err != nil {
+		return fmt.Errorf("marshal inbox : %w", err)
+	}
+
+	return s.inboxStore.Batch(append(ops, storage.Operation{Key: inboxKeyPrefix + theirDID, Value: val}))
+}
+
+func messageKey(theirDID, msgID string) string {
+	return theirDID + "_" + msgID
+}
+
+func (s *Service) getStatusCh(msgID string) chan *Status {
+	s.statusMapLock.RLock()
+	defer s.statusMapLock.RUnlock()
+
+	return s.statusMap[msgID]
+}
+
+func (s *Service) setStatusCh(msgID string, statusCh chan *Status) {
+	s.statusMapLock.Lock()
+	defer s.statusMapLock.Unlock()
+
+	if statusCh == nil {
+		delete(s.statusMap, msgID)
+	} else {
+		s.statusMap[msgID] = statusCh
+	}
+}
+
+func (s *Service) getBatchCh(msgID string) chan *Batch {
+	s.batchMapLock.RLock()
+	defer s.batchMapLock.RUnlock()
+
+	return s.batchMap[msgID]
+}
+
+func (s *Service) setBatchCh(msgID string, batchCh chan *Batch) {
+	s.batchMapLock.Lock()
+	defer s.batchMapLock.Unlock()
+
+	if batchCh == nil {
+		delete(s.batchMap, msgID)
+	} else {
+		s.batchMap[msgID] = batchCh
+	}
+}
+
+func (s *Service) getConnection(connectionID string) (*connection.Record, error) {
+	conn, err := s.connectionLookup.GetConnectionRecord(connectionID)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrConnectionNotFound
+		}
+
+		return nil, fmt.Errorf("fetch connection record from store : %w", err)
+	}
+
+	return conn, nil
+}
+
+func (b *inbox) status() *Status {
+	status := &Status{MessageCount: len(b.Messages)}
+
+	for _, m := range b.Messages {
+		status.TotalSize += len(m.Message)
+	}
+
+	if len(b.Messages) > 0 {
+		status.DurationWaited = int(time.Since(b.Messages[0].AddedTime).Seconds())
+	}
+
+	status.LastAddedTime = timePtr(b.LastAddedTime)
+	status.LastDeliveredTime = timePtr(b.LastDeliveredTime)
+	status.LastRemovedTime = timePtr(b.LastRemovedTime)
+
+	return status
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
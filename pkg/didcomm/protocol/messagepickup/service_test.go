@@ -0,0 +1,604 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package messagepickup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	commontransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/dispatcher"
+	mockpackager "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/packager"
+	mockdiddoc "github.com/hyperledger/aries-framework-go/pkg/internal/mock/diddoc"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/internal/mock/storage"
+	mockvdri "github.com/hyperledger/aries-framework-go/pkg/internal/mock/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+const (
+	MYDID    = "myDID"
+	THEIRDID = "theirDID"
+)
+
+// this line checks that Service satisfies ProtocolService interface
+var _ ProtocolService = &Service{}
+
+func TestServiceNew(t *testing.T) {
+	t.Run("test new service - success", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, MessagePickup, svc.Name())
+	})
+
+	t.Run("test new service - failure", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue: &mockstore.MockStoreProvider{
+				ErrOpenStoreHandle: errors.New("error opening the store")},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error opening the store")
+		require.Nil(t, svc)
+	})
+}
+
+func TestServiceAccept(t *testing.T) {
+	s := &Service{}
+
+	for _, msgType := range s.MessageTypes() {
+		require.True(t, s.Accept(msgType))
+	}
+
+	require.False(t, s.Accept("unsupported msg type"))
+}
+
+func TestServiceHandleOutbound(t *testing.T) {
+	s := &Service{}
+
+	err := s.HandleOutbound(service.NewDIDCommMsgMap(&Noop{Type: NoopMsgType}), MYDID, THEIRDID)
+	require.EqualError(t, err, "not implemented")
+}
+
+func TestServiceAddMessage(t *testing.T) {
+	t.Run("test add message - every message is stored as the separate record", func(t *testing.T) {
+		store := make(map[string][]byte)
+		svc := newService(t, store, &mockdispatcher.MockOutbound{})
+
+		for _, m := range []string{`{"protected":"1"}`, `{"protected":"2"}`, `{"protected":"3"}`} {
+			require.NoError(t, svc.AddMessage([]byte(m), THEIRDID))
+		}
+
+		// the messages of the DID which has the same prefix are not in the inbox
+		require.NoError(t, svc.AddMessage([]byte(`{"protected":"4"}`), THEIRDID+"_other"))
+
+		box, err := svc.getInbox(THEIRDID)
+		require.NoError(t, err)
+		require.Len(t, box.Messages, 3)
+
+		for i, m := range box.Messages {
+			require.JSONEq(t, fmt.Sprintf(`{"protected":"%d"}`, i+1), string(m.Message))
+			require.Contains(t, store, messageKey(THEIRDID, m.ID))
+		}
+
+		require.Contains(t, store, inboxKeyPrefix+THEIRDID)
+		require.NotContains(t, string(store[inboxKeyPrefix+THEIRDID]), "protected")
+	})
+
+	t.Run("test add message - inbox is full", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{})
+		svc.maxMessages = 2
+
+		require.NoError(t, svc.AddMessage([]byte(`{"protected":"1"}`), THEIRDID))
+		require.NoError(t, svc.AddMessage([]byte(`{"protected":"2"}`), THEIRDID))
+
+		err := svc.AddMessage([]byte(`{"protected":"3"}`), THEIRDID)
+		require.True(t, errors.Is(err, ErrInboxFull))
+
+		// the inboxes of the other recipients are not affected
+		require.NoError(t, svc.AddMessage([]byte(`{"protected":"3"}`), "otherDID"))
+	})
+
+	t.Run("test add message - expired messages are removed", func(t *testing.T) {
+		store := make(map[string][]byte)
+		svc := newService(t, store, &mockdispatcher.MockOutbound{})
+
+		expired, err := json.Marshal(&Message{
+			ID:        "1",
+			AddedTime: time.Now().Add(-2 * inboxMessageTTL),
+			Message:   json.RawMessage(`{"protected":"1"}`),
+		})
+		require.NoError(t, err)
+
+		store[messageKey(THEIRDID, "1")] = expired
+
+		require.NoError(t, svc.AddMessage([]byte(`{"protected":"2"}`), THEIRDID))
+
+		box, err := svc.getInbox(THEIRDID)
+		require.NoError(t, err)
+		require.Len(t, box.Messages, 1)
+		require.JSONEq(t, `{"protected":"2"}`, string(box.Messages[0].Message))
+		require.NotContains(t, store, messageKey(THEIRDID, "1"))
+	})
+}
+
+func TestServiceStatusRequest(t *testing.T) {
+	t.Run("test handle status request - status of the inbox is sent", func(t *testing.T) {
+		sent := make(chan *Status, 1)
+
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				require.Equal(t, MYDID, myDID)
+				require.Equal(t, THEIRDID, theirDID)
+
+				status, ok := msg.(*Status)
+				require.True(t, ok)
+
+				sent <- status
+
+				return nil
+			}})
+
+		request := service.NewDIDCommMsgMap(&StatusRequest{Type: StatusRequestMsgType, ID: "request-1"})
+
+		_, err := svc.HandleInbound(request, MYDID, THEIRDID)
+		require.NoError(t, err)
+
+		status := <-sent
+		require.Equal(t, StatusMsgType, status.Type)
+		require.Equal(t, "request-1", status.Thread.ID)
+		require.Zero(t, status.MessageCount)
+		require.Nil(t, status.LastAddedTime)
+
+		require.NoError(t, svc.AddMessage([]byte(`{"protected":"1"}`), THEIRDID))
+		require.NoError(t, svc.AddMessage([]byte(`{"protected":"2"}`), THEIRDID))
+		require.NoError(t, svc.AddMessage([]byte(`{"protected":"3"}`), "otherDID"))
+
+		_, err = svc.HandleInbound(request, MYDID, THEIRDID)
+		require.NoError(t, err)
+
+		status = <-sent
+		require.Equal(t, 2, status.MessageCount)
+		require.Equal(t, 2*len(`{"protected":"1"}`), status.TotalSize)
+		require.NotNil(t, status.LastAddedTime)
+		require.Nil(t, status.LastRemovedTime)
+	})
+
+	t.Run("test status request - success", func(t *testing.T) {
+		var svc *Service
+
+		svc = newService(t, map[string][]byte{"conn_conn1": connectionRecord(t)}, &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				request, ok := msg.(*StatusRequest)
+				require.True(t, ok)
+
+				// the router responds with the status
+				go func() {
+					_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&Status{
+						Type:         StatusMsgType,
+						ID:           "status-1",
+						MessageCount: 5,
+						Thread:       &decorator.Thread{ID: request.ID},
+					}), MYDID, THEIRDID)
+					require.NoError(t, err)
+				}()
+
+				return nil
+			}})
+
+		status, err := svc.StatusRequest("conn1")
+		require.NoError(t, err)
+		require.Equal(t, 5, status.MessageCount)
+		require.Empty(t, svc.statusMap)
+	})
+
+	t.Run("test status request - timeout", func(t *testing.T) {
+		svc := newService(t, map[string][]byte{"conn_conn1": connectionRecord(t)}, &mockdispatcher.MockOutbound{})
+		svc.timeout = time.Millisecond
+
+		_, err := svc.StatusRequest("conn1")
+		require.EqualError(t, err, "timeout waiting for status from the router")
+	})
+
+	t.Run("test status request - send error", func(t *testing.T) {
+		svc := newService(t, map[string][]byte{"conn_conn1": connectionRecord(t)}, &mockdispatcher.MockOutbound{
+			SendErr: errors.New("send error"),
+		})
+
+		_, err := svc.StatusRequest("conn1")
+		require.EqualError(t, err, "send status request : send error")
+	})
+
+	t.Run("test status request - connection not found", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{})
+
+		_, err := svc.StatusRequest("conn1")
+		require.True(t, errors.Is(err, ErrConnectionNotFound))
+	})
+}
+
+func TestServiceBatchPickup(t *testing.T) {
+	t.Run("test handle batch pickup - messages are sent and removed", func(t *testing.T) {
+		sent := make(chan *Batch, 1)
+
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				batch, ok := msg.(*Batch)
+				require.True(t, ok)
+
+				sent <- batch
+
+				return nil
+			}})
+
+		for _, m := range []string{`{"protected":"1"}`, `{"protected":"2"}`, `{"protected":"3"}`} {
+			require.NoError(t, svc.AddMessage([]byte(m), THEIRDID))
+		}
+
+		_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&BatchPickup{
+			Type: BatchPickupMsgType, ID: "pickup-1", BatchSize: 2,
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+
+		batch := <-sent
+		require.Equal(t, BatchMsgType, batch.Type)
+		require.Equal(t, "pickup-1", batch.Thread.ID)
+		require.Len(t, batch.Messages, 2)
+		require.JSONEq(t, `{"protected":"1"}`, string(batch.Messages[0].Message))
+		require.JSONEq(t, `{"protected":"2"}`, string(batch.Messages[1].Message))
+
+		box, err := svc.getInbox(THEIRDID)
+		require.NoError(t, err)
+		require.Len(t, box.Messages, 1)
+		require.NotNil(t, box.status().LastRemovedTime)
+
+		// all the remaining messages are sent
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&BatchPickup{
+			Type: BatchPickupMsgType, ID: "pickup-2", BatchSize: 10,
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+		require.Len(t, (<-sent).Messages, 1)
+	})
+
+	t.Run("test handle batch pickup - inbox is not locked while the batch is sent", func(t *testing.T) {
+		var svc *Service
+
+		svc = newService(t, nil, &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				return svc.AddMessage([]byte(`{"protected":"2"}`), THEIRDID)
+			}})
+
+		require.NoError(t, svc.AddMessage([]byte(`{"protected":"1"}`), THEIRDID))
+
+		_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&BatchPickup{
+			Type: BatchPickupMsgType, ID: "pickup-1", BatchSize: 10,
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+
+		// the message queued while the batch was sent is kept
+		box, err := svc.getInbox(THEIRDID)
+		require.NoError(t, err)
+		require.Len(t, box.Messages, 1)
+		require.JSONEq(t, `{"protected":"2"}`, string(box.Messages[0].Message))
+	})
+
+	t.Run("test handle batch pickup - send error", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{SendErr: errors.New("send error")})
+
+		require.NoError(t, svc.AddMessage([]byte(`{"protected":"1"}`), THEIRDID))
+
+		_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&BatchPickup{
+			Type: BatchPickupMsgType, ID: "pickup-1", BatchSize: 1,
+		}), MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "send batch : send error")
+
+		// the message is kept in the inbox
+		box, err := svc.getInbox(THEIRDID)
+		require.NoError(t, err)
+		require.Len(t, box.Messages, 1)
+	})
+
+	t.Run("test batch pickup - queued messages are handled", func(t *testing.T) {
+		var (
+			svc     *Service
+			handled []string
+		)
+
+		svc = newService(t, map[string][]byte{"conn_conn1": connectionRecord(t)}, &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				request, ok := msg.(*BatchPickup)
+				require.True(t, ok)
+				require.Equal(t, 2, request.BatchSize)
+
+				// the router responds with the batch
+				go func() {
+					_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&Batch{
+						Type: BatchMsgType,
+						ID:   "batch-1",
+						Messages: []*Message{
+							{ID: "1", Message: json.RawMessage(`{"protected":"1"}`)},
+							{ID: "2", Message: json.RawMessage(`{"protected":"2"}`)},
+						},
+						Thread: &decorator.Thread{ID: request.ID},
+					}), MYDID, THEIRDID)
+					require.NoError(t, err)
+				}()
+
+				return nil
+			}})
+
+		svc.packager = &mockpackager.Packager{UnpackValue: &commontransport.Envelope{Message: []byte("unpacked")}}
+		svc.msgHandler = func(message []byte, myDID, theirDID string) error {
+			handled = append(handled, string(message))
+
+			return nil
+		}
+
+		count, err := svc.BatchPickup("conn1", 2)
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+		require.Equal(t, []string{"unpacked", "unpacked"}, handled)
+		require.Empty(t, svc.batchMap)
+	})
+
+	t.Run("test batch pickup - timeout", func(t *testing.T) {
+		svc := newService(t, map[string][]byte{"conn_conn1": connectionRecord(t)}, &mockdispatcher.MockOutbound{})
+		svc.timeout = time.Millisecond
+
+		_, err := svc.BatchPickup("conn1", 1)
+		require.EqualError(t, err, "timeout waiting for batch from the router")
+	})
+
+	t.Run("test batch pickup - send error", func(t *testing.T) {
+		svc := newService(t, map[string][]byte{"conn_conn1": connectionRecord(t)}, &mockdispatcher.MockOutbound{
+			SendErr: errors.New("send error"),
+		})
+
+		_, err := svc.BatchPickup("conn1", 1)
+		require.EqualError(t, err, "send batch pickup request : send error")
+	})
+
+	t.Run("test batch pickup - connection not found", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{})
+
+		_, err := svc.BatchPickup("conn1", 1)
+		require.True(t, errors.Is(err, ErrConnectionNotFound))
+	})
+
+	t.Run("test handle message - unpack error", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{})
+		svc.packager = &mockpackager.Packager{UnpackErr: errors.New("unpack error")}
+
+		err := svc.handleMessage([]byte(`{"protected":"1"}`))
+		require.EqualError(t, err, "unpack message : unpack error")
+	})
+}
+
+func TestServiceNoop(t *testing.T) {
+	t.Run("test handle noop - queued messages are delivered", func(t *testing.T) {
+		var forwarded []string
+
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{
+			ValidateForward: func(msg interface{}, des *service.Destination) error {
+				require.NotEmpty(t, des.ServiceEndpoint)
+
+				message := string(msg.(json.RawMessage))
+
+				// the recipient is not reachable anymore
+				if message == `{"protected":"2"}` {
+					return errors.New("forward error")
+				}
+
+				forwarded = append(forwarded, message)
+
+				return nil
+			}})
+
+		for _, m := range []string{`{"protected":"1"}`, `{"protected":"2"}`} {
+			require.NoError(t, svc.AddMessage([]byte(m), THEIRDID))
+		}
+
+		_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&Noop{Type: NoopMsgType, ID: "noop-1"}), MYDID, THEIRDID)
+		require.NoError(t, err)
+
+		// the message which is not delivered is kept in the inbox
+		box, err := svc.getInbox(THEIRDID)
+		require.NoError(t, err)
+		require.Len(t, box.Messages, 1)
+		require.JSONEq(t, `{"protected":"2"}`, string(box.Messages[0].Message))
+		require.NotNil(t, box.status().LastDeliveredTime)
+		require.Equal(t, []string{`{"protected":"1"}`}, forwarded)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Noop{Type: NoopMsgType, ID: "noop-2"}), MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "deliver queued messages : forward error")
+	})
+
+	t.Run("test handle noop - inbox is not locked while the messages are delivered", func(t *testing.T) {
+		var svc *Service
+
+		svc = newService(t, nil, &mockdispatcher.MockOutbound{
+			ValidateForward: func(msg interface{}, des *service.Destination) error {
+				return svc.AddMessage([]byte(`{"protected":"2"}`), THEIRDID)
+			}})
+
+		require.NoError(t, svc.AddMessage([]byte(`{"protected":"1"}`), THEIRDID))
+
+		_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&Noop{Type: NoopMsgType, ID: "noop-1"}), MYDID, THEIRDID)
+		require.NoError(t, err)
+
+		box, err := svc.getInbox(THEIRDID)
+		require.NoError(t, err)
+		require.Len(t, box.Messages, 1)
+		require.JSONEq(t, `{"protected":"2"}`, string(box.Messages[0].Message))
+		require.NotNil(t, box.status().LastDeliveredTime)
+	})
+
+	t.Run("test handle noop - empty inbox", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{
+			ValidateForward: func(msg interface{}, des *service.Destination) error {
+				return errors.New("unexpected forward")
+			}})
+
+		_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&Noop{Type: NoopMsgType, ID: "noop-1"}), MYDID, THEIRDID)
+		require.NoError(t, err)
+	})
+
+	t.Run("test handle noop - get destination error", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{})
+		svc.vdRegistry = &mockvdri.MockVDRIRegistry{ResolveErr: errors.New("resolve error")}
+
+		require.NoError(t, svc.AddMessage([]byte(`{"protected":"1"}`), THEIRDID))
+
+		_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&Noop{Type: NoopMsgType, ID: "noop-1"}), MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get destination")
+	})
+
+	t.Run("test noop - sent with return route", func(t *testing.T) {
+		svc := newService(t, map[string][]byte{"conn_conn1": connectionRecord(t)}, &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				require.Equal(t, MYDID, myDID)
+				require.Equal(t, THEIRDID, theirDID)
+
+				noop, ok := msg.(*Noop)
+				require.True(t, ok)
+				require.Equal(t, decorator.TransportReturnRouteAll, noop.ReturnRoute.Value)
+
+				return nil
+			}})
+
+		require.NoError(t, svc.Noop("conn1"))
+	})
+
+	t.Run("test noop - send error", func(t *testing.T) {
+		svc := newService(t, map[string][]byte{"conn_conn1": connectionRecord(t)}, &mockdispatcher.MockOutbound{
+			SendErr: errors.New("send error"),
+		})
+
+		require.EqualError(t, svc.Noop("conn1"), "send noop : send error")
+	})
+
+	t.Run("test noop - connection not found", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{})
+
+		require.True(t, errors.Is(svc.Noop("conn1"), ErrConnectionNotFound))
+	})
+}
+
+func TestServiceHandleInbound(t *testing.T) {
+	t.Run("test handle - decode errors", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{})
+
+		for _, msgType := range []string{StatusRequestMsgType, StatusMsgType, BatchPickupMsgType, BatchMsgType} {
+			_, err := svc.HandleInbound(service.DIDCommMsgMap{
+				"@type": msgType, "@id": "msg-1", "~thread": "invalid", "batch_size": "invalid",
+				"message_count": "invalid", "messages~attach": "invalid",
+			}, MYDID, THEIRDID)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "message unmarshal")
+		}
+	})
+
+	t.Run("test handle - inbox store error", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue: &mockstore.MockStoreProvider{Store: &mockstore.MockStore{
+				Store:  make(map[string][]byte),
+				ErrGet: errors.New("get error"),
+			}},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.NoError(t, err)
+
+		require.EqualError(t, svc.AddMessage([]byte(`{}`), THEIRDID), "get inbox : get error")
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Noop{Type: NoopMsgType, ID: "noop-1"}), MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get inbox : get error")
+	})
+
+	t.Run("test handle - invalid inbox", func(t *testing.T) {
+		svc := newService(t, map[string][]byte{inboxKeyPrefix + THEIRDID: []byte("invalid")},
+			&mockdispatcher.MockOutbound{})
+
+		_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&StatusRequest{
+			Type: StatusRequestMsgType, ID: "request-1",
+		}), MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal inbox")
+
+		svc = newService(t, map[string][]byte{messageKey(THEIRDID, "1"): []byte("invalid")},
+			&mockdispatcher.MockOutbound{})
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Noop{Type: NoopMsgType, ID: "noop-1"}), MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal inbox message")
+	})
+
+	t.Run("test handle - inbox messages store errors", func(t *testing.T) {
+		store := &mockstore.MockStore{Store: make(map[string][]byte)}
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          &mockstore.MockStoreProvider{Store: store},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.NoError(t, err)
+
+		store.ErrBatch = errors.New("batch error")
+		require.EqualError(t, svc.AddMessage([]byte(`{}`), THEIRDID), "batch error")
+
+		store.ErrItr = errors.New("iterator error")
+		require.EqualError(t, svc.AddMessage([]byte(`{}`), THEIRDID), "fetch inbox messages : iterator error")
+	})
+
+	t.Run("test handle - unrecognized msgType", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{})
+
+		_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&Noop{Type: "unknown", ID: "noop-1"}), MYDID, THEIRDID)
+		require.EqualError(t, err, "unrecognized msgType: unknown")
+	})
+}
+
+func newService(t *testing.T, s map[string][]byte, outbound *mockdispatcher.MockOutbound) *Service {
+	if s == nil {
+		s = make(map[string][]byte)
+	}
+
+	svc, err := New(&mockprovider.Provider{
+		StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
+		TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		OutboundDispatcherValue:       outbound,
+		VDRIRegistryValue: &mockvdri.MockVDRIRegistry{
+			ResolveFunc: func(didID string, opts ...vdri.ResolveOpts) (*did.Doc, error) {
+				return mockdiddoc.GetMockDIDDoc(), nil
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	return svc
+}
+
+func connectionRecord(t *testing.T) []byte {
+	connBytes, err := json.Marshal(&connection.Record{
+		ConnectionID: "conn1", MyDID: MYDID, TheirDID: THEIRDID, State: "completed"})
+	require.NoError(t, err)
+
+	return connBytes
+}
@@ -0,0 +1,81 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outofband
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+)
+
+// Invitation model
+//
+// Invitation defines the out-of-band invitation message
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0434-outofband#messages
+//
+type Invitation struct {
+	// the ID of the invitation
+	ID string `json:"@id,omitempty"`
+
+	// the Type of the invitation
+	Type string `json:"@type,omitempty"`
+
+	// the Label of the invitation
+	Label string `json:"label,omitempty"`
+
+	// the Goal of the invitation (human readable)
+	Goal string `json:"goal,omitempty"`
+
+	// the GoalCode of the invitation (machine readable)
+	GoalCode string `json:"goal_code,omitempty"`
+
+	// the Protocols which can be used to establish the connection, e.g. didexchange.DIDExchangeSpec
+	Protocols []string `json:"handshake_protocols,omitempty"`
+
+	// the Requests (messages) the recipient is expected to handle once the connection is established
+	Requests []*decorator.Attachment `json:"request~attach,omitempty"`
+
+	// the Service entries in the order of preference, an entry is either a DID (string) or an *InlineService
+	Service []interface{} `json:"service,omitempty"`
+}
+
+// InlineService defines the inline service block of the invitation.
+type InlineService struct {
+	ID              string   `json:"id,omitempty"`
+	Type            string   `json:"type,omitempty"`
+	RecipientKeys   []string `json:"recipientKeys,omitempty"`
+	RoutingKeys     []string `json:"routingKeys,omitempty"`
+	ServiceEndpoint string   `json:"serviceEndpoint,omitempty"`
+}
+
+// record keeps the requests of the accepted invitation until the connection is established.
+type record struct {
+	InvitationID string                  `json:"invitation_id,omitempty"`
+	Requests     []*decorator.Attachment `json:"requests,omitempty"`
+}
+
+// Event is the properties of the out-of-band message events.
+type Event interface {
+	// ConnectionID returns the ID of the connection established through the DID exchange protocol.
+	ConnectionID() string
+	// InvitationID returns the ID of the invitation.
+	InvitationID() string
+}
+
+// event implements Event interface.
+type event struct {
+	connectionID string
+	invitationID string
+}
+
+// ConnectionID returns the ID of the connection established through the DID exchange protocol.
+func (e *event) ConnectionID() string {
+	return e.connectionID
+}
+
+// InvitationID returns the ID of the invitation.
+func (e *event) InvitationID() string {
+	return e.invitationID
+}
@@ -0,0 +1,453 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outofband
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+const (
+	// OutOfBand out-of-band protocol name
+	OutOfBand = "outofband"
+	// OutOfBandSpec defines the out-of-band spec
+	OutOfBandSpec = "https://didcomm.org/out-of-band/1.0/"
+	// InvitationMsgType defines the out-of-band invitation message type.
+	InvitationMsgType = OutOfBandSpec + "invitation"
+	// DIDCommServiceType is the type of the inline service block of the invitation.
+	DIDCommServiceType = "did-communication"
+	// StateAccepted is the state of the invitation passed to the DID exchange protocol.
+	StateAccepted = "accepted"
+	// stateCompleted the didexchange protocol state name to determine the established connection
+	stateCompleted = "completed"
+)
+
+var logger = log.New("aries-framework/outofband/service")
+
+var (
+	// ErrServerWasStopped an error message to determine whether service was stopped or not
+	ErrServerWasStopped = errors.New("server was already stopped")
+
+	// ErrNoHandshakeProtocols is returned when the invitation doesn't request a connection, the requests are
+	// handled over the established connection only.
+	ErrNoHandshakeProtocols = errors.New("invitation without handshake protocols is not supported")
+
+	// ErrUnsupportedHandshakeProtocol is returned when none of the handshake protocols of the invitation is supported.
+	ErrUnsupportedHandshakeProtocol = errors.New("no supported handshake protocol in the invitation")
+
+	// ErrNoSupportedService is returned when none of the service entries of the invitation can be used.
+	ErrNoSupportedService = errors.New("no supported service in the invitation")
+)
+
+// provider contains dependencies for the out-of-band protocol and is typically created by using aries.Context()
+type provider interface {
+	Service(id string) (interface{}, error)
+	StorageProvider() storage.Provider
+	TransientStorageProvider() storage.Provider
+	OutboundDispatcher() dispatcher.Outbound
+	InboundMessageHandler() transport.InboundMessageHandler
+}
+
+// didExchange is the DID exchange service the connections are established with.
+type didExchange interface {
+	service.InboundHandler
+	RegisterMsgEvent(ch chan<- service.StateMsg) error
+	UnregisterMsgEvent(ch chan<- service.StateMsg) error
+}
+
+// didExchangeEvent is the properties of the DID exchange state events, refer didexchange.Event.
+type didExchangeEvent interface {
+	ConnectionID() string
+	InvitationID() string
+}
+
+// Service for the out-of-band protocol.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0434-outofband
+type Service struct {
+	service.Action
+	service.Message
+	didExchange    didExchange
+	outbound       dispatcher.Outbound
+	inboundHandler transport.InboundMessageHandler
+	connections    *connection.Recorder
+	store          storage.Store
+	didEvents      chan service.StateMsg
+	wg             sync.WaitGroup
+	stop           chan struct{}
+	closedMutex    sync.Mutex
+	closed         bool
+}
+
+// New returns the out-of-band service.
+func New(p provider) (*Service, error) {
+	store, err := p.StorageProvider().OpenStore(OutOfBand)
+	if err != nil {
+		return nil, err
+	}
+
+	didSvc, err := p.Service(didexchange.DIDExchange)
+	if err != nil {
+		return nil, fmt.Errorf("load the DIDExchange service: %w", err)
+	}
+
+	didService, ok := didSvc.(didExchange)
+	if !ok {
+		return nil, errors.New("cast service to DIDExchange Service failed")
+	}
+
+	connections, err := connection.NewRecorder(p)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := &Service{
+		didExchange:    didService,
+		outbound:       p.OutboundDispatcher(),
+		inboundHandler: p.InboundMessageHandler(),
+		connections:    connections,
+		store:          store,
+		didEvents:      make(chan service.StateMsg),
+		stop:           make(chan struct{}),
+	}
+
+	if err = svc.didExchange.RegisterMsgEvent(svc.didEvents); err != nil {
+		return nil, fmt.Errorf("did register msg event: %w", err)
+	}
+
+	svc.wg.Add(1)
+
+	go svc.startInternalListener()
+
+	return svc, nil
+}
+
+// Stop stops the service (DID exchange events listener).
+func (s *Service) Stop() error {
+	s.closedMutex.Lock()
+	defer s.closedMutex.Unlock()
+
+	if s.closed {
+		return ErrServerWasStopped
+	}
+
+	if err := s.didExchange.UnregisterMsgEvent(s.didEvents); err != nil {
+		return fmt.Errorf("stop unregister msg event: %w", err)
+	}
+
+	close(s.stop)
+	s.closed = true
+	s.wg.Wait()
+
+	return nil
+}
+
+func (s *Service) startInternalListener() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case event := <-s.didEvents:
+			if err := s.connectionEstablished(event); err != nil {
+				logger.Errorf("listener connection established: %s", err)
+			}
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// HandleInbound handles the invitation received over an established connection. The invitation is passed
+// to the action event, Continue accepts the invitation (refer AcceptInvitation) and Stop declines it.
+func (s *Service) HandleInbound(msg service.DIDCommMsg, _, _ string) (string, error) {
+	if msg.Type() != InvitationMsgType {
+		return "", fmt.Errorf("unrecognized msgType: %s", msg.Type())
+	}
+
+	aEvent := s.ActionEvent()
+
+	// throw error if there is no action event registered for inbound messages
+	if aEvent == nil {
+		return "", errors.New("no clients are registered to handle the message")
+	}
+
+	invitation := &Invitation{}
+
+	if err := msg.Decode(invitation); err != nil {
+		return "", fmt.Errorf("invitation unmarshal : %w", err)
+	}
+
+	aEvent <- service.DIDCommAction{
+		ProtocolName: OutOfBand,
+		Message:      msg,
+		Continue: func(interface{}) {
+			if _, err := s.AcceptInvitation(invitation); err != nil {
+				logger.Errorf("accept invitation %s : %s", invitation.ID, err)
+			}
+		},
+		Stop: func(err error) {
+			logger.Infof("invitation %s declined : %v", invitation.ID, err)
+		},
+	}
+
+	return invitation.ID, nil
+}
+
+// HandleOutbound sends the invitation over the established connection.
+func (s *Service) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) error {
+	if msg.Type() != InvitationMsgType {
+		return fmt.Errorf("unsupported outbound msgType: %s", msg.Type())
+	}
+
+	return s.outbound.SendToDID(msg, myDID, theirDID)
+}
+
+// Accept checks whether the service can handle the message type.
+func (s *Service) Accept(msgType string) bool {
+	return msgType == InvitationMsgType
+}
+
+// MessageTypes returns the message types supported by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{InvitationMsgType}
+}
+
+// Name of the service
+func (s *Service) Name() string {
+	return OutOfBand
+}
+
+// SaveInvitation saves the invitation created by the agent, the invitation is cross referenced during
+// the DID exchange protocol started by the recipient of the invitation.
+func (s *Service) SaveInvitation(invitation *Invitation) error {
+	exchangeInvitation, err := didExchangeInvitation(invitation)
+	if err != nil {
+		return err
+	}
+
+	if err = s.connections.SaveInvitation(invitation.ID, exchangeInvitation); err != nil {
+		return fmt.Errorf("save invitation : %w", err)
+	}
+
+	return nil
+}
+
+// SaveInvitationRouter saves the connection ID of the router the keys of the invitation identified by invitationID
+// are advertised through, the keys of the connection established from the invitation use the same router.
+func (s *Service) SaveInvitationRouter(invitationID, routerConnectionID string) error {
+	if err := s.connections.SaveInvitationRouter(invitationID, routerConnectionID); err != nil {
+		return fmt.Errorf("save invitation router : %w", err)
+	}
+
+	return nil
+}
+
+// AcceptInvitation accepts the invitation and returns the ID of the connection established through
+// the DID exchange protocol. The requests attached to the invitation are handled by the protocol services
+// once the connection is completed. The StateAccepted message event is sent with the connection ID
+// (refer Event) once the invitation is passed to the DID exchange protocol.
+func (s *Service) AcceptInvitation(invitation *Invitation) (string, error) {
+	if len(invitation.Protocols) == 0 {
+		return "", ErrNoHandshakeProtocols
+	}
+
+	if !supportsDIDExchange(invitation.Protocols) {
+		return "", ErrUnsupportedHandshakeProtocol
+	}
+
+	exchangeInvitation, err := didExchangeInvitation(invitation)
+	if err != nil {
+		return "", err
+	}
+
+	if len(invitation.Requests) != 0 {
+		err = s.saveRecord(&record{InvitationID: invitation.ID, Requests: invitation.Requests})
+		if err != nil {
+			return "", fmt.Errorf("save out-of-band record : %w", err)
+		}
+	}
+
+	connectionID, err := s.didExchange.HandleInbound(service.NewDIDCommMsgMap(exchangeInvitation), "", "")
+	if err != nil {
+		return "", fmt.Errorf("didexchange handle invitation : %w", err)
+	}
+
+	for _, handler := range s.MsgEvents() {
+		handler <- service.StateMsg{
+			ProtocolName: OutOfBand,
+			Type:         service.PostState,
+			StateID:      StateAccepted,
+			Msg:          service.NewDIDCommMsgMap(invitation),
+			Properties:   &event{connectionID: connectionID, invitationID: invitation.ID},
+		}
+	}
+
+	return connectionID, nil
+}
+
+// connectionEstablished handles the requests of the invitation once the connection is completed.
+func (s *Service) connectionEstablished(event service.StateMsg) error {
+	if event.Type != service.PostState || event.StateID != stateCompleted {
+		return nil
+	}
+
+	props, ok := event.Properties.(didExchangeEvent)
+	if !ok || props.InvitationID() == "" {
+		return nil
+	}
+
+	rec, err := s.getRecord(props.InvitationID())
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("fetch out-of-band record : %w", err)
+	}
+
+	conn, err := s.connections.GetConnectionRecord(props.ConnectionID())
+	if err != nil {
+		return fmt.Errorf("fetch connection record : %w", err)
+	}
+
+	if err = s.store.Delete(rec.InvitationID); err != nil {
+		return fmt.Errorf("delete out-of-band record : %w", err)
+	}
+
+	for i, request := range rec.Requests {
+		payload, err := attachmentData(request)
+		if err != nil {
+			logger.Errorf("invitation %s request %d : %s", rec.InvitationID, i, err)
+			continue
+		}
+
+		if err = s.inboundHandler(payload, conn.MyDID, conn.TheirDID); err != nil {
+			logger.Errorf("invitation %s handle request %d : %s", rec.InvitationID, i, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) saveRecord(rec *record) error {
+	bytes, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Put(rec.InvitationID, bytes)
+}
+
+func (s *Service) getRecord(invitationID string) (*record, error) {
+	bytes, err := s.store.Get(invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &record{}
+
+	if err := json.Unmarshal(bytes, rec); err != nil {
+		return nil, err
+	}
+
+	return rec, nil
+}
+
+func supportsDIDExchange(protocols []string) bool {
+	for _, protocol := range protocols {
+		if strings.TrimSuffix(protocol, "/") == strings.TrimSuffix(didexchange.DIDExchangeSpec, "/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// didExchangeInvitation converts the invitation to the DID exchange invitation using the first supported
+// service entry, the DID exchange invitation has the ID of the out-of-band invitation.
+func didExchangeInvitation(invitation *Invitation) (*didexchange.Invitation, error) {
+	for _, entry := range invitation.Service {
+		did, svc, err := decodeService(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		if did != "" {
+			return &didexchange.Invitation{
+				ID:    invitation.ID,
+				Type:  didexchange.InvitationMsgType,
+				Label: invitation.Label,
+				DID:   did,
+			}, nil
+		}
+
+		if svc.Type != DIDCommServiceType || len(svc.RecipientKeys) == 0 {
+			continue
+		}
+
+		return &didexchange.Invitation{
+			ID:              invitation.ID,
+			Type:            didexchange.InvitationMsgType,
+			Label:           invitation.Label,
+			RecipientKeys:   svc.RecipientKeys,
+			ServiceEndpoint: svc.ServiceEndpoint,
+			RoutingKeys:     svc.RoutingKeys,
+		}, nil
+	}
+
+	return nil, ErrNoSupportedService
+}
+
+// decodeService decodes the service entry of the invitation, the entry is either a DID or an inline service.
+func decodeService(entry interface{}) (string, *InlineService, error) {
+	switch v := entry.(type) {
+	case string:
+		return v, nil, nil
+	case *InlineService:
+		return "", v, nil
+	case InlineService:
+		return "", &v, nil
+	}
+
+	bytes, err := json.Marshal(entry)
+	if err != nil {
+		return "", nil, fmt.Errorf("marshal service entry : %w", err)
+	}
+
+	svc := &InlineService{}
+
+	if err := json.Unmarshal(bytes, svc); err != nil {
+		return "", nil, fmt.Errorf("unmarshal service entry : %w", err)
+	}
+
+	return "", svc, nil
+}
+
+// attachmentData returns the message of the request attachment.
+func attachmentData(attachment *decorator.Attachment) ([]byte, error) {
+	if attachment.Data.JSON != nil {
+		return json.Marshal(attachment.Data.JSON)
+	}
+
+	if attachment.Data.Base64 != "" {
+		return base64.StdEncoding.DecodeString(attachment.Data.Base64)
+	}
+
+	return nil, errors.New("no inline data")
+}
@@ -0,0 +1,562 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outofband
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/dispatcher"
+	mockdidexchange "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/didexchange"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/internal/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test new - success", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdidexchange.MockDIDExchangeSvc{}))
+		require.NoError(t, err)
+		require.Equal(t, OutOfBand, svc.Name())
+		require.Equal(t, []string{InvitationMsgType}, svc.MessageTypes())
+		require.True(t, svc.Accept(InvitationMsgType))
+		require.False(t, svc.Accept(didexchange.InvitationMsgType))
+
+		require.NoError(t, svc.Stop())
+		require.Equal(t, ErrServerWasStopped, svc.Stop())
+	})
+
+	t.Run("test new - open store error", func(t *testing.T) {
+		prov := newProvider(&mockdidexchange.MockDIDExchangeSvc{})
+		prov.StorageProviderValue = &mockstore.MockStoreProvider{ErrOpenStoreHandle: errors.New("open error")}
+
+		_, err := New(prov)
+		require.EqualError(t, err, "open error")
+	})
+
+	t.Run("test new - load didexchange service error", func(t *testing.T) {
+		prov := newProvider(&mockdidexchange.MockDIDExchangeSvc{})
+		prov.ServiceErr = errors.New("service error")
+
+		_, err := New(prov)
+		require.EqualError(t, err, "load the DIDExchange service: service error")
+	})
+
+	t.Run("test new - cast didexchange service error", func(t *testing.T) {
+		prov := newProvider(&mockdidexchange.MockDIDExchangeSvc{})
+		prov.ServiceValue = struct{}{}
+
+		_, err := New(prov)
+		require.EqualError(t, err, "cast service to DIDExchange Service failed")
+	})
+
+	t.Run("test new - register msg event error", func(t *testing.T) {
+		_, err := New(newProvider(&mockdidexchange.MockDIDExchangeSvc{RegisterMsgEventErr: errors.New("register error")}))
+		require.EqualError(t, err, "did register msg event: register error")
+	})
+
+	t.Run("test stop - unregister msg event error", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdidexchange.MockDIDExchangeSvc{
+			UnregisterMsgEventErr: errors.New("unregister error"),
+		}))
+		require.NoError(t, err)
+		require.EqualError(t, svc.Stop(), "stop unregister msg event: unregister error")
+	})
+}
+
+func TestService_AcceptInvitation(t *testing.T) {
+	t.Run("test accept invitation - inline service", func(t *testing.T) {
+		var received *didexchange.Invitation
+
+		svc := newService(t, &mockdidexchange.MockDIDExchangeSvc{
+			HandleFunc: func(msg service.DIDCommMsg) (string, error) {
+				require.Equal(t, didexchange.InvitationMsgType, msg.Type())
+
+				received = &didexchange.Invitation{}
+				require.NoError(t, msg.Decode(received))
+
+				return "connection-1", nil
+			},
+		})
+
+		invitation := newInvitation()
+		invitation.Service = []interface{}{
+			&InlineService{ID: "#other", Type: "other-type", RecipientKeys: []string{"key-0"}},
+			&InlineService{
+				ID:              "#inline",
+				Type:            DIDCommServiceType,
+				RecipientKeys:   []string{"key-1"},
+				RoutingKeys:     []string{"routing-key-1"},
+				ServiceEndpoint: "http://localhost:8080",
+			},
+		}
+
+		events := make(chan service.StateMsg, 1)
+		require.NoError(t, svc.RegisterMsgEvent(events))
+
+		connectionID, err := svc.AcceptInvitation(invitation)
+		require.NoError(t, err)
+		require.Equal(t, "connection-1", connectionID)
+
+		event := <-events
+		require.Equal(t, OutOfBand, event.ProtocolName)
+		require.Equal(t, StateAccepted, event.StateID)
+		require.Equal(t, invitation.ID, event.Msg.ID())
+
+		props, ok := event.Properties.(Event)
+		require.True(t, ok)
+		require.Equal(t, "connection-1", props.ConnectionID())
+		require.Equal(t, invitation.ID, props.InvitationID())
+
+		require.Equal(t, invitation.ID, received.ID)
+		require.Equal(t, invitation.Label, received.Label)
+		require.Equal(t, []string{"key-1"}, received.RecipientKeys)
+		require.Equal(t, []string{"routing-key-1"}, received.RoutingKeys)
+		require.Equal(t, "http://localhost:8080", received.ServiceEndpoint)
+
+		// no requests - nothing is stored
+		_, err = svc.getRecord(invitation.ID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("test accept invitation - DID service and requests", func(t *testing.T) {
+		var received *didexchange.Invitation
+
+		svc := newService(t, &mockdidexchange.MockDIDExchangeSvc{
+			HandleFunc: func(msg service.DIDCommMsg) (string, error) {
+				received = &didexchange.Invitation{}
+				require.NoError(t, msg.Decode(received))
+
+				return "connection-1", nil
+			},
+		})
+
+		invitation := newInvitation()
+		invitation.Service = []interface{}{"did:example:123"}
+		invitation.Requests = []*decorator.Attachment{{ID: "request-1"}}
+
+		// the invitation received as JSON has the service entries decoded as maps
+		bytes, err := json.Marshal(invitation)
+		require.NoError(t, err)
+
+		decoded := &Invitation{}
+		require.NoError(t, json.Unmarshal(bytes, decoded))
+
+		_, err = svc.AcceptInvitation(decoded)
+		require.NoError(t, err)
+		require.Equal(t, "did:example:123", received.DID)
+
+		rec, err := svc.getRecord(invitation.ID)
+		require.NoError(t, err)
+		require.Len(t, rec.Requests, 1)
+		require.Equal(t, "request-1", rec.Requests[0].ID)
+	})
+
+	t.Run("test accept invitation - decoded inline service", func(t *testing.T) {
+		svc := newService(t, &mockdidexchange.MockDIDExchangeSvc{})
+
+		invitation := newInvitation()
+
+		bytes, err := json.Marshal(invitation)
+		require.NoError(t, err)
+
+		decoded := &Invitation{}
+		require.NoError(t, json.Unmarshal(bytes, decoded))
+
+		exchangeInvitation, err := didExchangeInvitation(decoded)
+		require.NoError(t, err)
+		require.Equal(t, []string{"key-1"}, exchangeInvitation.RecipientKeys)
+		require.Equal(t, "http://localhost:8080", exchangeInvitation.ServiceEndpoint)
+
+		_, err = svc.AcceptInvitation(decoded)
+		require.NoError(t, err)
+	})
+
+	t.Run("test accept invitation - no handshake protocols", func(t *testing.T) {
+		svc := newService(t, &mockdidexchange.MockDIDExchangeSvc{})
+
+		invitation := newInvitation()
+		invitation.Protocols = nil
+
+		_, err := svc.AcceptInvitation(invitation)
+		require.Equal(t, ErrNoHandshakeProtocols, err)
+	})
+
+	t.Run("test accept invitation - unsupported handshake protocol", func(t *testing.T) {
+		svc := newService(t, &mockdidexchange.MockDIDExchangeSvc{})
+
+		invitation := newInvitation()
+		invitation.Protocols = []string{"https://didcomm.org/connections/1.0"}
+
+		_, err := svc.AcceptInvitation(invitation)
+		require.Equal(t, ErrUnsupportedHandshakeProtocol, err)
+	})
+
+	t.Run("test accept invitation - no supported service", func(t *testing.T) {
+		svc := newService(t, &mockdidexchange.MockDIDExchangeSvc{})
+
+		invitation := newInvitation()
+		invitation.Service = []interface{}{&InlineService{Type: DIDCommServiceType}}
+
+		_, err := svc.AcceptInvitation(invitation)
+		require.Equal(t, ErrNoSupportedService, err)
+
+		invitation.Service = []interface{}{make(chan int)}
+
+		_, err = svc.AcceptInvitation(invitation)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "marshal service entry")
+	})
+
+	t.Run("test accept invitation - save record error", func(t *testing.T) {
+		store := mockstore.NewMockStoreProvider()
+		store.Store.ErrPut = errors.New("put error")
+
+		prov := newProvider(&mockdidexchange.MockDIDExchangeSvc{})
+		prov.StorageProviderValue = store
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		invitation := newInvitation()
+		invitation.Requests = []*decorator.Attachment{{ID: "request-1"}}
+
+		_, err = svc.AcceptInvitation(invitation)
+		require.EqualError(t, err, "save out-of-band record : put error")
+	})
+
+	t.Run("test accept invitation - didexchange error", func(t *testing.T) {
+		svc := newService(t, &mockdidexchange.MockDIDExchangeSvc{
+			HandleFunc: func(service.DIDCommMsg) (string, error) {
+				return "", errors.New("handle error")
+			},
+		})
+
+		_, err := svc.AcceptInvitation(newInvitation())
+		require.EqualError(t, err, "didexchange handle invitation : handle error")
+	})
+}
+
+func TestService_SaveInvitation(t *testing.T) {
+	svc := newService(t, &mockdidexchange.MockDIDExchangeSvc{})
+
+	invitation := newInvitation()
+	require.NoError(t, svc.SaveInvitation(invitation))
+
+	saved := &didexchange.Invitation{}
+	require.NoError(t, svc.connections.GetInvitation(invitation.ID, saved))
+	require.Equal(t, invitation.ID, saved.ID)
+	require.Equal(t, []string{"key-1"}, saved.RecipientKeys)
+
+	invitation.Service = nil
+	require.Equal(t, ErrNoSupportedService, svc.SaveInvitation(invitation))
+}
+
+func TestService_SaveInvitationRouter(t *testing.T) {
+	svc := newService(t, &mockdidexchange.MockDIDExchangeSvc{})
+
+	require.NoError(t, svc.SaveInvitationRouter("inv-1", "router-1"))
+
+	routerConnID, err := svc.connections.GetInvitationRouter("inv-1")
+	require.NoError(t, err)
+	require.Equal(t, "router-1", routerConnID)
+
+	require.EqualError(t, svc.SaveInvitationRouter("", "router-1"), "save invitation router : invalid key")
+}
+
+func TestService_ConnectionEstablished(t *testing.T) {
+	const (
+		myDID    = "did:example:mine"
+		theirDID = "did:example:theirs"
+	)
+
+	type handled struct {
+		msg      service.DIDCommMsgMap
+		myDID    string
+		theirDID string
+	}
+
+	setup := func(t *testing.T) (*Service, chan handled, *Invitation) {
+		messages := make(chan handled, 2)
+
+		prov := newProvider(&mockdidexchange.MockDIDExchangeSvc{})
+		prov.InboundMessageHandlerValue = func(message []byte, myDID, theirDID string) error {
+			msg, err := service.ParseDIDCommMsgMap(message)
+			require.NoError(t, err)
+
+			messages <- handled{msg: msg, myDID: myDID, theirDID: theirDID}
+
+			return nil
+		}
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		invitation := newInvitation()
+		invitation.Requests = []*decorator.Attachment{
+			{Data: decorator.AttachmentData{JSON: map[string]interface{}{"@type": "request-1"}}},
+			{Data: decorator.AttachmentData{Base64: base64.StdEncoding.EncodeToString([]byte(`{"@type":"request-2"}`))}},
+			{Data: decorator.AttachmentData{Links: []string{"http://localhost/request-3"}}},
+		}
+
+		_, err = svc.AcceptInvitation(invitation)
+		require.NoError(t, err)
+
+		require.NoError(t, svc.connections.SaveConnectionRecord(&connection.Record{
+			ConnectionID: "connection-1",
+			State:        stateCompleted,
+			MyDID:        myDID,
+			TheirDID:     theirDID,
+			InvitationID: invitation.ID,
+		}))
+
+		return svc, messages, invitation
+	}
+
+	t.Run("test connection established - requests handled", func(t *testing.T) {
+		svc, messages, invitation := setup(t)
+
+		svc.didEvents <- service.StateMsg{
+			Type:       service.PostState,
+			StateID:    stateCompleted,
+			Properties: &didEvent{connectionID: "connection-1", invitationID: invitation.ID},
+		}
+
+		for _, msgType := range []string{"request-1", "request-2"} {
+			select {
+			case msg := <-messages:
+				require.Equal(t, msgType, msg.msg.Type())
+				require.Equal(t, myDID, msg.myDID)
+				require.Equal(t, theirDID, msg.theirDID)
+			case <-time.After(time.Second):
+				require.Fail(t, "timeout waiting for the request")
+			}
+		}
+
+		require.NoError(t, svc.Stop())
+
+		// the requests are handled once
+		_, err := svc.getRecord(invitation.ID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("test connection established - ignored events", func(t *testing.T) {
+		svc, messages, invitation := setup(t)
+
+		require.NoError(t, svc.connectionEstablished(service.StateMsg{
+			Type:       service.PreState,
+			StateID:    stateCompleted,
+			Properties: &didEvent{connectionID: "connection-1", invitationID: invitation.ID},
+		}))
+		require.NoError(t, svc.connectionEstablished(service.StateMsg{
+			Type:       service.PostState,
+			StateID:    "requested",
+			Properties: &didEvent{connectionID: "connection-1", invitationID: invitation.ID},
+		}))
+		require.NoError(t, svc.connectionEstablished(service.StateMsg{
+			Type:    service.PostState,
+			StateID: stateCompleted,
+		}))
+		require.NoError(t, svc.connectionEstablished(service.StateMsg{
+			Type:       service.PostState,
+			StateID:    stateCompleted,
+			Properties: &didEvent{connectionID: "connection-1", invitationID: "unknown"},
+		}))
+
+		require.Empty(t, messages)
+
+		_, err := svc.getRecord(invitation.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("test connection established - connection not found", func(t *testing.T) {
+		svc, _, invitation := setup(t)
+
+		err := svc.connectionEstablished(service.StateMsg{
+			Type:       service.PostState,
+			StateID:    stateCompleted,
+			Properties: &didEvent{connectionID: "unknown", invitationID: invitation.ID},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "fetch connection record")
+	})
+}
+
+func TestService_HandleInbound(t *testing.T) {
+	invitationMsg := func(t *testing.T, invitation *Invitation) service.DIDCommMsgMap {
+		bytes, err := json.Marshal(invitation)
+		require.NoError(t, err)
+
+		msg, err := service.ParseDIDCommMsgMap(bytes)
+		require.NoError(t, err)
+
+		return msg
+	}
+
+	t.Run("test handle inbound - continue", func(t *testing.T) {
+		accepted := make(chan string, 1)
+
+		svc := newService(t, &mockdidexchange.MockDIDExchangeSvc{
+			HandleFunc: func(msg service.DIDCommMsg) (string, error) {
+				accepted <- msg.ID()
+				return "connection-1", nil
+			},
+		})
+
+		actions := make(chan service.DIDCommAction, 1)
+		require.NoError(t, svc.RegisterActionEvent(actions))
+
+		invitation := newInvitation()
+
+		id, err := svc.HandleInbound(invitationMsg(t, invitation), "", "")
+		require.NoError(t, err)
+		require.Equal(t, invitation.ID, id)
+
+		action := <-actions
+		require.Equal(t, OutOfBand, action.ProtocolName)
+		action.Continue(nil)
+
+		require.Equal(t, invitation.ID, <-accepted)
+	})
+
+	t.Run("test handle inbound - stop", func(t *testing.T) {
+		svc := newService(t, &mockdidexchange.MockDIDExchangeSvc{
+			HandleFunc: func(msg service.DIDCommMsg) (string, error) {
+				require.Fail(t, "the invitation is declined")
+				return "", nil
+			},
+		})
+
+		actions := make(chan service.DIDCommAction, 1)
+		require.NoError(t, svc.RegisterActionEvent(actions))
+
+		_, err := svc.HandleInbound(invitationMsg(t, newInvitation()), "", "")
+		require.NoError(t, err)
+
+		action := <-actions
+		action.Stop(errors.New("declined"))
+	})
+
+	t.Run("test handle inbound - accept error is logged", func(t *testing.T) {
+		svc := newService(t, &mockdidexchange.MockDIDExchangeSvc{})
+
+		actions := make(chan service.DIDCommAction, 1)
+		require.NoError(t, svc.RegisterActionEvent(actions))
+
+		invitation := newInvitation()
+		invitation.Protocols = nil
+
+		_, err := svc.HandleInbound(invitationMsg(t, invitation), "", "")
+		require.NoError(t, err)
+
+		action := <-actions
+		action.Continue(nil)
+	})
+
+	t.Run("test handle inbound - no clients registered", func(t *testing.T) {
+		svc := newService(t, &mockdidexchange.MockDIDExchangeSvc{})
+
+		_, err := svc.HandleInbound(invitationMsg(t, newInvitation()), "", "")
+		require.EqualError(t, err, "no clients are registered to handle the message")
+	})
+
+	t.Run("test handle inbound - unrecognized message type", func(t *testing.T) {
+		svc := newService(t, &mockdidexchange.MockDIDExchangeSvc{})
+
+		_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&didexchange.Invitation{
+			Type: didexchange.InvitationMsgType,
+		}), "", "")
+		require.EqualError(t, err, "unrecognized msgType: "+didexchange.InvitationMsgType)
+	})
+
+	t.Run("test handle inbound - invalid invitation", func(t *testing.T) {
+		svc := newService(t, &mockdidexchange.MockDIDExchangeSvc{})
+		require.NoError(t, svc.RegisterActionEvent(make(chan service.DIDCommAction)))
+
+		_, err := svc.HandleInbound(service.DIDCommMsgMap{"@type": InvitationMsgType, "service": "invalid"}, "", "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invitation unmarshal")
+	})
+}
+
+func TestService_HandleOutbound(t *testing.T) {
+	prov := newProvider(&mockdidexchange.MockDIDExchangeSvc{})
+	prov.OutboundDispatcherValue = &mockdispatcher.MockOutbound{
+		ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+			require.Equal(t, "did:example:mine", myDID)
+			require.Equal(t, "did:example:theirs", theirDID)
+
+			return nil
+		},
+	}
+
+	svc, err := New(prov)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.HandleOutbound(service.NewDIDCommMsgMap(newInvitation()),
+		"did:example:mine", "did:example:theirs"))
+
+	err = svc.HandleOutbound(service.NewDIDCommMsgMap(&didexchange.Invitation{Type: didexchange.InvitationMsgType}),
+		"did:example:mine", "did:example:theirs")
+	require.EqualError(t, err, "unsupported outbound msgType: "+didexchange.InvitationMsgType)
+}
+
+func newInvitation() *Invitation {
+	return &Invitation{
+		ID:        uuid.New().String(),
+		Type:      InvitationMsgType,
+		Label:     "Alice",
+		Protocols: []string{didexchange.DIDExchangeSpec},
+		Service: []interface{}{&InlineService{
+			ID:              "#inline",
+			Type:            DIDCommServiceType,
+			RecipientKeys:   []string{"key-1"},
+			ServiceEndpoint: "http://localhost:8080",
+		}},
+	}
+}
+
+func newService(t *testing.T, didexchangeSvc *mockdidexchange.MockDIDExchangeSvc) *Service {
+	svc, err := New(newProvider(didexchangeSvc))
+	require.NoError(t, err)
+
+	return svc
+}
+
+func newProvider(didexchangeSvc *mockdidexchange.MockDIDExchangeSvc) *mockprovider.Provider {
+	return &mockprovider.Provider{
+		ServiceValue:                  didexchangeSvc,
+		StorageProviderValue:          mem.NewProvider(),
+		TransientStorageProviderValue: mem.NewProvider(),
+		OutboundDispatcherValue:       &mockdispatcher.MockOutbound{},
+	}
+}
+
+// didEvent implements the properties of the DID exchange events.
+type didEvent struct {
+	connectionID string
+	invitationID string
+}
+
+func (e *didEvent) ConnectionID() string {
+	return e.connectionID
+}
+
+func (e *didEvent) InvitationID() string {
+	return e.invitationID
+}
@@ -0,0 +1,495 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package presentproof
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const (
+	// Name defines the protocol name
+	Name = "present-proof"
+	// PresentProofSpec defines the present proof spec
+	PresentProofSpec = "https://didcomm.org/present-proof/1.0/"
+	// ProposePresentationMsgType defines the present proof propose-presentation message type.
+	ProposePresentationMsgType = PresentProofSpec + "propose-presentation"
+	// RequestPresentationMsgType defines the present proof request-presentation message type.
+	RequestPresentationMsgType = PresentProofSpec + "request-presentation"
+	// PresentationMsgType defines the present proof presentation message type.
+	PresentationMsgType = PresentProofSpec + "presentation"
+	// PresentationPreviewMsgType defines the present proof presentation-preview inner object type.
+	PresentationPreviewMsgType = PresentProofSpec + "presentation-preview"
+	// AckMsgType defines the present proof ack message type.
+	AckMsgType = PresentProofSpec + "ack"
+	// ProblemReportMsgType defines the present proof problem-report message type.
+	ProblemReportMsgType = PresentProofSpec + "problem-report"
+)
+
+// ackStatusOK is the status of the ack sent by the Verifier once the presentation is received
+const ackStatusOK = "OK"
+
+var logger = log.New("aries-framework/presentproof/service")
+
+var (
+	// ErrServerWasStopped an error message to determine whether service was stopped or not
+	ErrServerWasStopped = errors.New("server was already stopped")
+
+	errMissingReply = errors.New("action reply message is missing")
+)
+
+// customError is a wrapper to determine custom error against internal error
+type customError struct{ error }
+
+// metaData type to store data for internal usage
+type metaData struct {
+	record
+	Msg      service.DIDCommMsg
+	ThreadID string
+	// reply keeps the message injected by the Continue() function,
+	// it is sent in response to the inbound message
+	reply    interface{}
+	inbound  bool
+	myDID    string
+	theirDID string
+	// err is used to determine whether callback was stopped
+	// e.g the user received an action event and executes Stop(err) function
+	// in that case `err` is equal to `err` which was passing to Stop function
+	err error
+}
+
+type record struct {
+	StateName string `json:"state_name,omitempty"`
+}
+
+// Service for present proof protocol
+type Service struct {
+	service.Action
+	service.Message
+	store       storage.Store
+	callbacks   chan *metaData
+	messenger   service.Messenger
+	wg          sync.WaitGroup
+	stop        chan struct{}
+	closedMutex sync.Mutex
+	closed      bool
+}
+
+// Provider contains dependencies for the present proof protocol and is typically created by using aries.Context()
+type Provider interface {
+	Messenger() service.Messenger
+	StorageProvider() storage.Provider
+}
+
+// New returns present proof service
+func New(p Provider) (*Service, error) {
+	store, err := p.StorageProvider().OpenStore(Name)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := &Service{
+		messenger: p.Messenger(),
+		store:     store,
+		callbacks: make(chan *metaData),
+		stop:      make(chan struct{}),
+	}
+
+	// start the listener
+	svc.wg.Add(1)
+
+	go svc.startInternalListener()
+
+	return svc, nil
+}
+
+// Stop stops service (callback listener)
+func (s *Service) Stop() error {
+	s.closedMutex.Lock()
+	defer s.closedMutex.Unlock()
+
+	if s.closed {
+		return ErrServerWasStopped
+	}
+
+	close(s.stop)
+	s.closed = true
+	s.wg.Wait()
+
+	return nil
+}
+
+// startInternalListener listens to messages in gochannel for callback messages from clients.
+func (s *Service) startInternalListener() {
+	for {
+		select {
+		case msg := <-s.callbacks:
+			// if no error - do handle
+			if msg.err == nil {
+				msg.err = s.handle(msg)
+			}
+
+			// no error - continue
+			if msg.err == nil {
+				continue
+			}
+
+			msg.StateName = stateNameAbandoning
+
+			logInternalError(msg.err)
+
+			if err := s.handle(msg); err != nil {
+				logger.Errorf("listener handle: %s", err)
+			}
+		case <-s.stop:
+			s.wg.Done()
+
+			return
+		}
+	}
+}
+
+func logInternalError(err error) {
+	if _, ok := err.(*customError); !ok {
+		logger.Errorf("go to abandoning: %v", err)
+	}
+}
+
+func (s *Service) doHandle(msg service.DIDCommMsg, outbound bool) (*metaData, error) {
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return nil, err
+	}
+
+	rec, err := s.currentStateRecord(thID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := stateFromName(rec.StateName)
+
+	next, err := nextState(msg, outbound)
+	if err != nil {
+		return nil, err
+	}
+
+	if !current.CanTransitionTo(next) {
+		return nil, fmt.Errorf("invalid state transition: %s -> %s", current.Name(), next.Name())
+	}
+
+	// sets the next state name
+	rec.StateName = next.Name()
+
+	return &metaData{
+		record:   *rec,
+		Msg:      msg,
+		ThreadID: thID,
+	}, nil
+}
+
+// HandleInbound handles inbound message (present proof protocol)
+func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	aEvent := s.ActionEvent()
+
+	// throw error if there is no action event registered for inbound messages
+	if aEvent == nil {
+		return "", errors.New("no clients are registered to handle the message")
+	}
+
+	mData, err := s.doHandle(msg, false)
+	if err != nil {
+		return "", err
+	}
+
+	// sets inbound payload
+	mData.inbound = true
+	mData.myDID = myDID
+	mData.theirDID = theirDID
+
+	// trigger action event based on message type for inbound messages
+	if canTriggerActionEvents(msg) {
+		aEvent <- s.newDIDCommActionMsg(mData)
+		return "", nil
+	}
+
+	// if no action event is triggered, continue the execution
+	return "", s.handle(mData)
+}
+
+// HandleOutbound handles outbound message (present proof protocol)
+func (s *Service) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) error {
+	mData, err := s.doHandle(msg, true)
+	if err != nil {
+		return err
+	}
+
+	// sets outbound payload
+	mData.myDID = myDID
+	mData.theirDID = theirDID
+
+	return s.handle(mData)
+}
+
+// sendMsgEvents triggers the message events.
+func (s *Service) sendMsgEvents(msg *service.StateMsg) {
+	// trigger the message events
+	for _, handler := range s.MsgEvents() {
+		handler <- *msg
+	}
+}
+
+// newDIDCommActionMsg creates new DIDCommAction message
+func (s *Service) newDIDCommActionMsg(msg *metaData) service.DIDCommAction {
+	// create the message for the channel
+	// trigger the registered action event
+	actionStop := func(err error) {
+		msg.err = err
+		s.processCallback(msg)
+	}
+
+	return service.DIDCommAction{
+		ProtocolName: Name,
+		Message:      msg.Msg,
+		Continue: func(args interface{}) {
+			reply, err := replyFromArgs(msg.Msg, args)
+			if err != nil {
+				// sets an error to the message
+				actionStop(err)
+				return
+			}
+
+			msg.reply = reply
+			s.processCallback(msg)
+		},
+		Stop: func(err error) { actionStop(&customError{error: err}) },
+	}
+}
+
+// replyFromArgs returns the message provided through the Continue(args) function
+// which must be sent in response to the inbound message:
+// - ProposePresentation expects *RequestPresentation (required)
+// - RequestPresentation expects *Presentation (required)
+// - Presentation does not expect anything (ack is sent)
+func replyFromArgs(msg service.DIDCommMsg, args interface{}) (interface{}, error) {
+	switch msg.Type() {
+	case ProposePresentationMsgType:
+		request, ok := args.(*RequestPresentation)
+		if !ok || request == nil {
+			return nil, fmt.Errorf("%w: expected *RequestPresentation", errMissingReply)
+		}
+
+		request.Type = RequestPresentationMsgType
+
+		return request, nil
+	case RequestPresentationMsgType:
+		presentation, ok := args.(*Presentation)
+		if !ok || presentation == nil {
+			return nil, fmt.Errorf("%w: expected *Presentation", errMissingReply)
+		}
+
+		presentation.Type = PresentationMsgType
+
+		return presentation, nil
+	}
+
+	return nil, nil
+}
+
+func (s *Service) processCallback(msg *metaData) {
+	// pass the callback data to internal channel. This is created to unblock consumer go routine and wrap the callback
+	// channel internally.
+	s.callbacks <- msg
+}
+
+func nextState(msg service.DIDCommMsg, outbound bool) (state, error) {
+	switch msg.Type() {
+	case ProposePresentationMsgType:
+		if outbound {
+			return &proposalSent{}, nil
+		}
+
+		return &proposalReceived{}, nil
+	case RequestPresentationMsgType:
+		if outbound {
+			return &requestSent{}, nil
+		}
+
+		return &requestReceived{}, nil
+	case PresentationMsgType:
+		if outbound {
+			return nil, errors.New("presentation must be sent in response to a request-presentation")
+		}
+
+		return &presentationReceived{}, nil
+	case ProblemReportMsgType:
+		return &abandoning{}, nil
+	case AckMsgType:
+		return &done{}, nil
+	default:
+		return nil, fmt.Errorf("unrecognized msgType: %s", msg.Type())
+	}
+}
+
+func (s *Service) currentStateRecord(thID string) (*record, error) {
+	src, err := s.store.Get(thID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return &record{StateName: stateNameStart}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch state from store: thid=%s : %w", thID, err)
+	}
+
+	var r *record
+	if err := json.Unmarshal(src, &r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (s *Service) save(id string, data interface{}) error {
+	src, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("service save: %w", err)
+	}
+
+	return s.store.Put(id, src)
+}
+
+// nolint: gocyclo
+// stateFromName returns the state by given name.
+func stateFromName(name string) state {
+	switch name {
+	case stateNameNoop:
+		return &noOp{}
+	case stateNameStart:
+		return &start{}
+	case stateNameAbandoning:
+		return &abandoning{}
+	case stateNameDone:
+		return &done{}
+	case stateNameRequestSent:
+		return &requestSent{}
+	case stateNameProposalReceived:
+		return &proposalReceived{}
+	case stateNamePresentationReceived:
+		return &presentationReceived{}
+	case stateNameRequestReceived:
+		return &requestReceived{}
+	case stateNameProposalSent:
+		return &proposalSent{}
+	case stateNamePresentationSent:
+		return &presentationSent{}
+	default:
+		return &noOp{}
+	}
+}
+
+// canTriggerActionEvents checks if the incoming message can trigger an action event
+func canTriggerActionEvents(msg service.DIDCommMsg) bool {
+	switch msg.Type() {
+	case ProposePresentationMsgType, RequestPresentationMsgType, PresentationMsgType:
+		return true
+	}
+
+	return false
+}
+
+func isNoOp(s state) bool {
+	_, ok := s.(*noOp)
+	return ok
+}
+
+func (s *Service) handle(msg *metaData) error {
+	current := stateFromName(msg.StateName)
+
+	for !isNoOp(current) {
+		next, err := s.execute(current, msg)
+		if err != nil {
+			return fmt.Errorf("execute: %w", err)
+		}
+
+		if !isNoOp(next) && !current.CanTransitionTo(next) {
+			return fmt.Errorf("invalid state transition: %s -> %s", current.Name(), next.Name())
+		}
+
+		current = next
+	}
+
+	return nil
+}
+
+func (s *Service) execute(next state, msg *metaData) (state, error) {
+	s.sendMsgEvents(&service.StateMsg{
+		ProtocolName: Name,
+		Type:         service.PreState,
+		Msg:          msg.Msg,
+		StateID:      next.Name(),
+	})
+
+	var (
+		followup state
+		err      error
+	)
+
+	if msg.inbound {
+		followup, err = next.ExecuteInbound(s.messenger, msg)
+	} else {
+		followup, err = next.ExecuteOutbound(s.messenger, msg)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("execute state %s %w", next.Name(), err)
+	}
+
+	// sets the next state name
+	msg.StateName = next.Name()
+
+	if err = s.save(msg.ThreadID, msg.record); err != nil {
+		return nil, fmt.Errorf("failed to persist state %s: %w", next.Name(), err)
+	}
+
+	s.sendMsgEvents(&service.StateMsg{
+		ProtocolName: Name,
+		Type:         service.PostState,
+		Msg:          msg.Msg,
+		StateID:      next.Name(),
+	})
+
+	return followup, nil
+}
+
+// Name returns service name
+func (s *Service) Name() string {
+	return Name
+}
+
+// Accept msg checks the msg type
+func (s *Service) Accept(msgType string) bool {
+	switch msgType {
+	case ProposePresentationMsgType, RequestPresentationMsgType, PresentationMsgType,
+		AckMsgType, ProblemReportMsgType:
+		return true
+	}
+
+	return false
+}
+
+// MessageTypes returns the message types supported by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{
+		ProposePresentationMsgType, RequestPresentationMsgType, PresentationMsgType,
+		AckMsgType, ProblemReportMsgType,
+	}
+}
@@ -0,0 +1,412 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package presentproof_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/messenger"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
+	dispatcherMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/dispatcher"
+	messengerMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/messenger"
+	presentproofMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/protocol/presentproof"
+	storageMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+const (
+	Alice = "Alice"
+	Bob   = "Bob"
+)
+
+// this line checks that Service satisfies service.Handler interface
+var _ service.Handler = &presentproof.Service{}
+
+func TestService_New(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("OpenStore Error", func(t *testing.T) {
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(presentproof.Name).Return(nil, errors.New("test err"))
+
+		provider := presentproofMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+
+		svc, err := presentproof.New(provider)
+		require.EqualError(t, err, "test err")
+		require.Nil(t, svc)
+	})
+}
+
+func TestService_Stop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := newService(t, ctrl, nil)
+
+	require.NoError(t, svc.Stop())
+	require.EqualError(t, svc.Stop(), "server was already stopped")
+}
+
+func TestService_Name(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := newService(t, ctrl, nil)
+	defer stop(t, svc)
+
+	require.Equal(t, presentproof.Name, svc.Name())
+}
+
+func TestService_Accept(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := newService(t, ctrl, nil)
+	defer stop(t, svc)
+
+	require.False(t, svc.Accept(""))
+	require.True(t, svc.Accept(presentproof.ProposePresentationMsgType))
+	require.True(t, svc.Accept(presentproof.RequestPresentationMsgType))
+	require.True(t, svc.Accept(presentproof.PresentationMsgType))
+	require.True(t, svc.Accept(presentproof.AckMsgType))
+	require.True(t, svc.Accept(presentproof.ProblemReportMsgType))
+}
+
+func TestService_HandleInbound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("No clients", func(t *testing.T) {
+		svc := newService(t, ctrl, nil)
+		defer stop(t, svc)
+
+		_, err := svc.HandleInbound(newMsg(presentproof.RequestPresentationMsgType, ""), Alice, Bob)
+		require.EqualError(t, err, "no clients are registered to handle the message")
+	})
+
+	t.Run("Unrecognized msgType", func(t *testing.T) {
+		svc := newService(t, ctrl, nil)
+		defer stop(t, svc)
+
+		require.NoError(t, svc.RegisterActionEvent(make(chan service.DIDCommAction)))
+
+		_, err := svc.HandleInbound(newMsg("unknown", ""), Alice, Bob)
+		require.EqualError(t, err, "unrecognized msgType: unknown")
+	})
+
+	t.Run("Invalid state transition", func(t *testing.T) {
+		svc := newService(t, ctrl, nil)
+		defer stop(t, svc)
+
+		require.NoError(t, svc.RegisterActionEvent(make(chan service.DIDCommAction)))
+
+		_, err := svc.HandleInbound(newMsg(presentproof.AckMsgType, ""), Alice, Bob)
+		require.EqualError(t, err, "invalid state transition: start -> done")
+	})
+
+	t.Run("Storage error", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+		store.EXPECT().Get(gomock.Any()).Return(nil, errors.New("test err"))
+
+		svc := newService(t, ctrl, store)
+		defer stop(t, svc)
+
+		require.NoError(t, svc.RegisterActionEvent(make(chan service.DIDCommAction)))
+
+		_, err := svc.HandleInbound(newMsg(presentproof.RequestPresentationMsgType, ""), Alice, Bob)
+		require.Contains(t, err.Error(), "cannot fetch state from store")
+	})
+
+	t.Run("Storage JSON error", func(t *testing.T) {
+		store := storageMocks.NewMockStore(ctrl)
+		store.EXPECT().Get(gomock.Any()).Return([]byte("{"), nil)
+
+		svc := newService(t, ctrl, store)
+		defer stop(t, svc)
+
+		require.NoError(t, svc.RegisterActionEvent(make(chan service.DIDCommAction)))
+
+		_, err := svc.HandleInbound(newMsg(presentproof.RequestPresentationMsgType, ""), Alice, Bob)
+		require.EqualError(t, err, "unexpected end of JSON input")
+	})
+}
+
+func TestService_HandleOutbound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("Presentation without request", func(t *testing.T) {
+		svc := newService(t, ctrl, nil)
+		defer stop(t, svc)
+
+		err := svc.HandleOutbound(newMsg(presentproof.PresentationMsgType, ""), Alice, Bob)
+		require.EqualError(t, err, "presentation must be sent in response to a request-presentation")
+	})
+
+	t.Run("Send error", func(t *testing.T) {
+		prover := newAgent(t, ctrl, errors.New("send error"))
+		defer stop(t, prover.svc)
+
+		err := prover.svc.HandleOutbound(newMsg(presentproof.ProposePresentationMsgType, ""), Alice, Bob)
+		require.EqualError(t, err, "execute: execute state proposal-sent send error")
+	})
+}
+
+// This test describes the following flow :
+// 1. Prover sends propose-presentation to the Verifier
+// 2. Verifier sends request-presentation to the Prover
+// 3. Prover sends presentation to the Verifier
+// 4. Verifier sends ack to the Prover
+func TestService_Flow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prover := newAgent(t, ctrl, nil)
+	defer stop(t, prover.svc)
+
+	verifier := newAgent(t, ctrl, nil)
+	defer stop(t, verifier.svc)
+
+	require.NoError(t, prover.svc.HandleOutbound(service.NewDIDCommMsgMap(presentproof.ProposePresentation{
+		Type: presentproof.ProposePresentationMsgType,
+		ID:   uuid.New().String(),
+	}), Alice, Bob))
+
+	propose := deliver(t, prover, verifier)
+	requireAction(t, verifier, presentproof.ProposePresentationMsgType).Continue(&presentproof.RequestPresentation{})
+
+	request := deliver(t, verifier, prover)
+	requireThread(t, propose, request)
+	requireAction(t, prover, presentproof.RequestPresentationMsgType).Continue(&presentproof.Presentation{
+		PresentationsAttach: []decorator.Attachment{{Data: decorator.AttachmentData{JSON: "presentation"}}},
+	})
+
+	presentation := deliver(t, prover, verifier)
+	requireThread(t, propose, presentation)
+
+	msg := presentproof.Presentation{}
+	require.NoError(t, presentation.Decode(&msg))
+	require.Equal(t, "presentation", msg.PresentationsAttach[0].Data.JSON)
+
+	requireAction(t, verifier, presentproof.PresentationMsgType).Continue(nil)
+
+	ack := deliver(t, verifier, prover)
+	requireThread(t, propose, ack)
+	require.Equal(t, presentproof.AckMsgType, ack.Type())
+
+	requireState(t, prover, "done")
+	requireState(t, verifier, "done")
+}
+
+func TestService_FlowStop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("Prover declines the request", func(t *testing.T) {
+		prover := newAgent(t, ctrl, nil)
+		defer stop(t, prover.svc)
+
+		verifier := newAgent(t, ctrl, nil)
+		defer stop(t, verifier.svc)
+
+		require.NoError(t, verifier.svc.HandleOutbound(newMsg(presentproof.RequestPresentationMsgType, ""), Bob, Alice))
+
+		deliver(t, verifier, prover)
+		requireAction(t, prover, presentproof.RequestPresentationMsgType).Stop(errors.New("declined"))
+
+		problem := deliver(t, prover, verifier)
+		require.Equal(t, presentproof.ProblemReportMsgType, problem.Type())
+
+		requireState(t, prover, "done")
+		requireState(t, verifier, "done")
+	})
+
+	t.Run("Verifier continues without a request", func(t *testing.T) {
+		prover := newAgent(t, ctrl, nil)
+		defer stop(t, prover.svc)
+
+		verifier := newAgent(t, ctrl, nil)
+		defer stop(t, verifier.svc)
+
+		require.NoError(t, prover.svc.HandleOutbound(newMsg(presentproof.ProposePresentationMsgType, ""), Alice, Bob))
+
+		deliver(t, prover, verifier)
+		requireAction(t, verifier, presentproof.ProposePresentationMsgType).Continue(nil)
+
+		problem := deliver(t, verifier, prover)
+		require.Equal(t, presentproof.ProblemReportMsgType, problem.Type())
+
+		requireState(t, verifier, "done")
+		requireState(t, prover, "done")
+	})
+
+	t.Run("Prover continues without a presentation", func(t *testing.T) {
+		prover := newAgent(t, ctrl, nil)
+		defer stop(t, prover.svc)
+
+		verifier := newAgent(t, ctrl, nil)
+		defer stop(t, verifier.svc)
+
+		require.NoError(t, verifier.svc.HandleOutbound(newMsg(presentproof.RequestPresentationMsgType, ""), Bob, Alice))
+
+		deliver(t, verifier, prover)
+		requireAction(t, prover, presentproof.RequestPresentationMsgType).Continue(&presentproof.ProposePresentation{})
+
+		problem := deliver(t, prover, verifier)
+		require.Equal(t, presentproof.ProblemReportMsgType, problem.Type())
+
+		requireState(t, prover, "done")
+		requireState(t, verifier, "done")
+	})
+}
+
+type agent struct {
+	svc       *presentproof.Service
+	messenger *messenger.Messenger
+	outbound  chan service.DIDCommMsgMap
+	actions   chan service.DIDCommAction
+	states    chan service.StateMsg
+}
+
+// newAgent creates a present proof service using a real messenger, outbound messages are sent to agent.outbound
+func newAgent(t *testing.T, ctrl *gomock.Controller, sendErr error) *agent {
+	a := &agent{
+		outbound: make(chan service.DIDCommMsgMap, 1),
+		actions:  make(chan service.DIDCommAction, 1),
+		states:   make(chan service.StateMsg, 100),
+	}
+
+	dispatcher := dispatcherMocks.NewMockOutbound(ctrl)
+	dispatcher.EXPECT().SendToDID(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(msg service.DIDCommMsgMap, _, _ string) error {
+			if sendErr != nil {
+				return sendErr
+			}
+
+			a.outbound <- msg
+
+			return nil
+		}).AnyTimes()
+
+	messengerProvider := messengerMocks.NewMockProvider(ctrl)
+	messengerProvider.EXPECT().StorageProvider().Return(mem.NewProvider())
+	messengerProvider.EXPECT().OutboundDispatcher().Return(dispatcher)
+
+	msgr, err := messenger.NewMessenger(messengerProvider)
+	require.NoError(t, err)
+
+	provider := presentproofMocks.NewMockProvider(ctrl)
+	provider.EXPECT().StorageProvider().Return(mem.NewProvider())
+	provider.EXPECT().Messenger().Return(msgr)
+
+	a.svc, err = presentproof.New(provider)
+	require.NoError(t, err)
+
+	require.NoError(t, a.svc.RegisterActionEvent(a.actions))
+	require.NoError(t, a.svc.RegisterMsgEvent(a.states))
+
+	a.messenger = msgr
+
+	return a
+}
+
+// newService creates a present proof service using the given store, a memory store is used if nil
+func newService(t *testing.T, ctrl *gomock.Controller, store storage.Store) *presentproof.Service {
+	if store == nil {
+		var err error
+
+		store, err = mem.NewProvider().OpenStore(presentproof.Name)
+		require.NoError(t, err)
+	}
+
+	storageProvider := storageMocks.NewMockProvider(ctrl)
+	storageProvider.EXPECT().OpenStore(presentproof.Name).Return(store, nil)
+
+	provider := presentproofMocks.NewMockProvider(ctrl)
+	provider.EXPECT().StorageProvider().Return(storageProvider)
+	provider.EXPECT().Messenger().Return(nil)
+
+	svc, err := presentproof.New(provider)
+	require.NoError(t, err)
+
+	return svc
+}
+
+// deliver passes the message sent by an agent to another one
+func deliver(t *testing.T, from, to *agent) service.DIDCommMsgMap {
+	select {
+	case msg := <-from.outbound:
+		require.NoError(t, to.messenger.HandleInbound(msg, Bob, Alice))
+
+		_, err := to.svc.HandleInbound(msg, Bob, Alice)
+		require.NoError(t, err)
+
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the message")
+	}
+
+	return nil
+}
+
+func requireAction(t *testing.T, a *agent, msgType string) service.DIDCommAction {
+	select {
+	case action := <-a.actions:
+		require.Equal(t, presentproof.Name, action.ProtocolName)
+		require.Equal(t, msgType, action.Message.Type())
+
+		return action
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the action")
+	}
+
+	return service.DIDCommAction{}
+}
+
+func requireThread(t *testing.T, first, msg service.DIDCommMsg) {
+	thID, err := msg.ThreadID()
+	require.NoError(t, err)
+	require.Equal(t, first.ID(), thID)
+}
+
+// requireState checks that the agent reaches the given state
+func requireState(t *testing.T, a *agent, stateID string) {
+	for {
+		select {
+		case msg := <-a.states:
+			if msg.Type == service.PostState && msg.StateID == stateID {
+				return
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timeout waiting for the %s state", stateID)
+		}
+	}
+}
+
+func newMsg(msgType, thID string) service.DIDCommMsgMap {
+	return service.NewDIDCommMsgMap(model.Ack{
+		Type:   msgType,
+		ID:     uuid.New().String(),
+		Thread: &decorator.Thread{ID: thID},
+	})
+}
+
+func stop(t *testing.T, s interface{ Stop() error }) {
+	require.NoError(t, s.Stop())
+}
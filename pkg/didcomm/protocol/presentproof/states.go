@@ -0,0 +1,283 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package presentproof
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+)
+
+const (
+	// common states
+	stateNameNoop       = "noop"
+	stateNameStart      = "start"
+	stateNameAbandoning = "abandoning"
+	stateNameDone       = "done"
+
+	// verifier states
+	stateNameRequestSent          = "request-sent"
+	stateNameProposalReceived     = "proposal-received"
+	stateNamePresentationReceived = "presentation-received"
+
+	// prover states
+	stateNameRequestReceived  = "request-received"
+	stateNameProposalSent     = "proposal-sent"
+	stateNamePresentationSent = "presentation-sent"
+)
+
+// The present proof protocol's state.
+type state interface {
+	// Name of this state.
+	Name() string
+	// Whether this state allows transitioning into the next state.
+	CanTransitionTo(next state) bool
+	// Executes this state, returning a followup state to be immediately executed as well.
+	// The 'noOp' state should be returned if the state has no followup.
+	ExecuteInbound(messenger service.Messenger, msg *metaData) (followup state, err error)
+	ExecuteOutbound(messenger service.Messenger, msg *metaData) (followup state, err error)
+}
+
+// noOp state
+type noOp struct {
+}
+
+func (s *noOp) Name() string {
+	return stateNameNoop
+}
+
+func (s *noOp) CanTransitionTo(_ state) bool {
+	return false
+}
+
+func (s *noOp) ExecuteInbound(_ service.Messenger, _ *metaData) (state, error) {
+	return nil, errors.New("cannot execute no-op")
+}
+
+func (s *noOp) ExecuteOutbound(_ service.Messenger, _ *metaData) (state, error) {
+	return nil, errors.New("cannot execute no-op")
+}
+
+// start state
+type start struct {
+}
+
+func (s *start) Name() string {
+	return stateNameStart
+}
+
+func (s *start) CanTransitionTo(next state) bool {
+	// Verifier can go to request-sent or proposal-received state
+	// Prover can go to request-received or proposal-sent state
+	switch next.Name() {
+	case stateNameRequestSent, stateNameProposalReceived,
+		stateNameRequestReceived, stateNameProposalSent, stateNameAbandoning:
+		return true
+	}
+
+	return false
+}
+
+func (s *start) ExecuteInbound(_ service.Messenger, _ *metaData) (state, error) {
+	return nil, errors.New("start: ExecuteInbound function is not supposed to be used")
+}
+
+func (s *start) ExecuteOutbound(_ service.Messenger, _ *metaData) (state, error) {
+	return nil, errors.New("start: ExecuteOutbound function is not supposed to be used")
+}
+
+// abandoning state
+type abandoning struct {
+}
+
+func (s *abandoning) Name() string {
+	return stateNameAbandoning
+}
+
+func (s *abandoning) CanTransitionTo(next state) bool {
+	return next.Name() == stateNameDone
+}
+
+func (s *abandoning) ExecuteInbound(messenger service.Messenger, m *metaData) (state, error) {
+	// the other side already knows about the problem
+	if m.Msg.Type() == ProblemReportMsgType {
+		return &done{}, nil
+	}
+
+	problem := service.NewDIDCommMsgMap(model.ProblemReport{Type: ProblemReportMsgType})
+
+	if err := messenger.ReplyTo(m.Msg.ID(), problem); err != nil {
+		return nil, fmt.Errorf("send problem-report: %w", err)
+	}
+
+	return &done{}, nil
+}
+
+func (s *abandoning) ExecuteOutbound(_ service.Messenger, _ *metaData) (state, error) {
+	return nil, errors.New("abandoning: ExecuteOutbound function is not supposed to be used")
+}
+
+// done state
+type done struct {
+}
+
+func (s *done) Name() string {
+	return stateNameDone
+}
+
+func (s *done) CanTransitionTo(_ state) bool {
+	// done is the last state there is no possibility for the next state
+	return false
+}
+
+func (s *done) ExecuteInbound(_ service.Messenger, _ *metaData) (state, error) {
+	return &noOp{}, nil
+}
+
+func (s *done) ExecuteOutbound(_ service.Messenger, _ *metaData) (state, error) {
+	return nil, errors.New("done: ExecuteOutbound function is not supposed to be used")
+}
+
+// requestSent the Verifier's state
+type requestSent struct {
+}
+
+func (s *requestSent) Name() string {
+	return stateNameRequestSent
+}
+
+func (s *requestSent) CanTransitionTo(next state) bool {
+	return next.Name() == stateNamePresentationReceived || next.Name() == stateNameProposalReceived ||
+		next.Name() == stateNameAbandoning
+}
+
+func (s *requestSent) ExecuteInbound(_ service.Messenger, _ *metaData) (state, error) {
+	return &noOp{}, nil
+}
+
+func (s *requestSent) ExecuteOutbound(messenger service.Messenger, m *metaData) (state, error) {
+	return &noOp{}, messenger.Send(m.Msg.(service.DIDCommMsgMap), m.myDID, m.theirDID)
+}
+
+// proposalReceived the Verifier's state
+type proposalReceived struct {
+}
+
+func (s *proposalReceived) Name() string {
+	return stateNameProposalReceived
+}
+
+func (s *proposalReceived) CanTransitionTo(next state) bool {
+	return next.Name() == stateNameRequestSent || next.Name() == stateNameAbandoning
+}
+
+func (s *proposalReceived) ExecuteInbound(messenger service.Messenger, m *metaData) (state, error) {
+	if err := messenger.ReplyTo(m.Msg.ID(), service.NewDIDCommMsgMap(m.reply)); err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+
+	return &requestSent{}, nil
+}
+
+func (s *proposalReceived) ExecuteOutbound(_ service.Messenger, _ *metaData) (state, error) {
+	return nil, errors.New("proposalReceived: ExecuteOutbound function is not supposed to be used")
+}
+
+// presentationReceived the Verifier's state
+type presentationReceived struct {
+}
+
+func (s *presentationReceived) Name() string {
+	return stateNamePresentationReceived
+}
+
+func (s *presentationReceived) CanTransitionTo(next state) bool {
+	return next.Name() == stateNameDone || next.Name() == stateNameAbandoning
+}
+
+func (s *presentationReceived) ExecuteInbound(messenger service.Messenger, m *metaData) (state, error) {
+	ack := service.NewDIDCommMsgMap(model.Ack{
+		Type:   AckMsgType,
+		Status: ackStatusOK,
+	})
+
+	if err := messenger.ReplyTo(m.Msg.ID(), ack); err != nil {
+		return nil, fmt.Errorf("send ack: %w", err)
+	}
+
+	return &done{}, nil
+}
+
+func (s *presentationReceived) ExecuteOutbound(_ service.Messenger, _ *metaData) (state, error) {
+	return nil, errors.New("presentationReceived: ExecuteOutbound function is not supposed to be used")
+}
+
+// requestReceived the Prover's state
+type requestReceived struct {
+}
+
+func (s *requestReceived) Name() string {
+	return stateNameRequestReceived
+}
+
+func (s *requestReceived) CanTransitionTo(next state) bool {
+	return next.Name() == stateNamePresentationSent || next.Name() == stateNameAbandoning
+}
+
+func (s *requestReceived) ExecuteInbound(messenger service.Messenger, m *metaData) (state, error) {
+	if err := messenger.ReplyTo(m.Msg.ID(), service.NewDIDCommMsgMap(m.reply)); err != nil {
+		return nil, fmt.Errorf("send presentation: %w", err)
+	}
+
+	return &presentationSent{}, nil
+}
+
+func (s *requestReceived) ExecuteOutbound(_ service.Messenger, _ *metaData) (state, error) {
+	return nil, errors.New("requestReceived: ExecuteOutbound function is not supposed to be used")
+}
+
+// proposalSent the Prover's state
+type proposalSent struct {
+}
+
+func (s *proposalSent) Name() string {
+	return stateNameProposalSent
+}
+
+func (s *proposalSent) CanTransitionTo(next state) bool {
+	return next.Name() == stateNameRequestReceived || next.Name() == stateNameAbandoning
+}
+
+func (s *proposalSent) ExecuteInbound(_ service.Messenger, _ *metaData) (state, error) {
+	return nil, errors.New("proposalSent: ExecuteInbound function is not supposed to be used")
+}
+
+func (s *proposalSent) ExecuteOutbound(messenger service.Messenger, m *metaData) (state, error) {
+	return &noOp{}, messenger.Send(m.Msg.(service.DIDCommMsgMap), m.myDID, m.theirDID)
+}
+
+// presentationSent the Prover's state
+type presentationSent struct {
+}
+
+func (s *presentationSent) Name() string {
+	return stateNamePresentationSent
+}
+
+func (s *presentationSent) CanTransitionTo(next state) bool {
+	return next.Name() == stateNameDone || next.Name() == stateNameAbandoning
+}
+
+func (s *presentationSent) ExecuteInbound(_ service.Messenger, _ *metaData) (state, error) {
+	return &noOp{}, nil
+}
+
+func (s *presentationSent) ExecuteOutbound(_ service.Messenger, _ *metaData) (state, error) {
+	return nil, errors.New("presentationSent: ExecuteOutbound function is not supposed to be used")
+}
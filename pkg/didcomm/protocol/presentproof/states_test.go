@@ -0,0 +1,145 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package presentproof
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func notTransition(t *testing.T, st state) {
+	t.Helper()
+
+	var allState = [...]state{
+		&noOp{}, &start{}, &abandoning{}, &done{},
+		&requestSent{}, &proposalReceived{}, &presentationReceived{},
+		&requestReceived{}, &proposalSent{}, &presentationSent{},
+	}
+
+	for _, s := range allState {
+		require.False(t, st.CanTransitionTo(s))
+	}
+}
+
+func TestNoOp_CanTransitionTo(t *testing.T) {
+	noop := &noOp{}
+	require.Equal(t, stateNameNoop, noop.Name())
+	notTransition(t, noop)
+}
+
+func TestNoOp_Execute(t *testing.T) {
+	followup, err := (&noOp{}).ExecuteInbound(nil, &metaData{})
+	require.EqualError(t, err, "cannot execute no-op")
+	require.Nil(t, followup)
+
+	followup, err = (&noOp{}).ExecuteOutbound(nil, &metaData{})
+	require.EqualError(t, err, "cannot execute no-op")
+	require.Nil(t, followup)
+}
+
+func TestStart_CanTransitionTo(t *testing.T) {
+	st := &start{}
+	require.Equal(t, stateNameStart, st.Name())
+
+	require.True(t, st.CanTransitionTo(&requestSent{}))
+	require.True(t, st.CanTransitionTo(&proposalReceived{}))
+	require.True(t, st.CanTransitionTo(&requestReceived{}))
+	require.True(t, st.CanTransitionTo(&proposalSent{}))
+	require.True(t, st.CanTransitionTo(&abandoning{}))
+
+	require.False(t, st.CanTransitionTo(&noOp{}))
+	require.False(t, st.CanTransitionTo(&start{}))
+	require.False(t, st.CanTransitionTo(&done{}))
+	require.False(t, st.CanTransitionTo(&presentationReceived{}))
+	require.False(t, st.CanTransitionTo(&presentationSent{}))
+}
+
+func TestStart_Execute(t *testing.T) {
+	followup, err := (&start{}).ExecuteInbound(nil, &metaData{})
+	require.Contains(t, err.Error(), "is not supposed to be used")
+	require.Nil(t, followup)
+
+	followup, err = (&start{}).ExecuteOutbound(nil, &metaData{})
+	require.Contains(t, err.Error(), "is not supposed to be used")
+	require.Nil(t, followup)
+}
+
+func TestAbandoning_CanTransitionTo(t *testing.T) {
+	st := &abandoning{}
+	require.Equal(t, stateNameAbandoning, st.Name())
+
+	require.True(t, st.CanTransitionTo(&done{}))
+	require.False(t, st.CanTransitionTo(&abandoning{}))
+	require.False(t, st.CanTransitionTo(&start{}))
+}
+
+func TestDone_CanTransitionTo(t *testing.T) {
+	st := &done{}
+	require.Equal(t, stateNameDone, st.Name())
+	notTransition(t, st)
+}
+
+func TestVerifierStates_CanTransitionTo(t *testing.T) {
+	require.Equal(t, stateNameRequestSent, (&requestSent{}).Name())
+	require.True(t, (&requestSent{}).CanTransitionTo(&presentationReceived{}))
+	require.True(t, (&requestSent{}).CanTransitionTo(&proposalReceived{}))
+	require.True(t, (&requestSent{}).CanTransitionTo(&abandoning{}))
+	require.False(t, (&requestSent{}).CanTransitionTo(&done{}))
+
+	require.Equal(t, stateNameProposalReceived, (&proposalReceived{}).Name())
+	require.True(t, (&proposalReceived{}).CanTransitionTo(&requestSent{}))
+	require.True(t, (&proposalReceived{}).CanTransitionTo(&abandoning{}))
+	require.False(t, (&proposalReceived{}).CanTransitionTo(&presentationReceived{}))
+
+	require.Equal(t, stateNamePresentationReceived, (&presentationReceived{}).Name())
+	require.True(t, (&presentationReceived{}).CanTransitionTo(&done{}))
+	require.True(t, (&presentationReceived{}).CanTransitionTo(&abandoning{}))
+	require.False(t, (&presentationReceived{}).CanTransitionTo(&requestSent{}))
+}
+
+func TestProverStates_CanTransitionTo(t *testing.T) {
+	require.Equal(t, stateNameRequestReceived, (&requestReceived{}).Name())
+	require.True(t, (&requestReceived{}).CanTransitionTo(&presentationSent{}))
+	require.True(t, (&requestReceived{}).CanTransitionTo(&abandoning{}))
+	require.False(t, (&requestReceived{}).CanTransitionTo(&done{}))
+
+	require.Equal(t, stateNameProposalSent, (&proposalSent{}).Name())
+	require.True(t, (&proposalSent{}).CanTransitionTo(&requestReceived{}))
+	require.True(t, (&proposalSent{}).CanTransitionTo(&abandoning{}))
+	require.False(t, (&proposalSent{}).CanTransitionTo(&presentationSent{}))
+
+	require.Equal(t, stateNamePresentationSent, (&presentationSent{}).Name())
+	require.True(t, (&presentationSent{}).CanTransitionTo(&done{}))
+	require.True(t, (&presentationSent{}).CanTransitionTo(&abandoning{}))
+	require.False(t, (&presentationSent{}).CanTransitionTo(&requestReceived{}))
+}
+
+func TestStates_ExecuteNotSupposedToBeUsed(t *testing.T) {
+	for _, st := range []state{&abandoning{}, &done{}, &proposalReceived{}, &presentationReceived{},
+		&requestReceived{}, &presentationSent{}} {
+		followup, err := st.ExecuteOutbound(nil, &metaData{})
+		require.Contains(t, err.Error(), "ExecuteOutbound function is not supposed to be used")
+		require.Nil(t, followup)
+	}
+
+	followup, err := (&proposalSent{}).ExecuteInbound(nil, &metaData{})
+	require.Contains(t, err.Error(), "ExecuteInbound function is not supposed to be used")
+	require.Nil(t, followup)
+}
+
+func Test_stateFromName(t *testing.T) {
+	for _, st := range []state{
+		&noOp{}, &start{}, &abandoning{}, &done{},
+		&requestSent{}, &proposalReceived{}, &presentationReceived{},
+		&requestReceived{}, &proposalSent{}, &presentationSent{},
+	} {
+		require.Equal(t, st, stateFromName(st.Name()))
+	}
+
+	require.Equal(t, &noOp{}, stateFromName("unknown"))
+}
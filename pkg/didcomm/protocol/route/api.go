@@ -8,9 +8,9 @@ package route
 
 // ProtocolService service interface for router.
 type ProtocolService interface {
-	// AddKey adds agents recKey to the router
-	AddKey(recKey string) error
+	// AddKey adds agents recKey to the router identified by connectionID (the default router if empty)
+	AddKey(connectionID, recKey string) error
 
-	// Config gives back the router configuration
-	Config() (*Config, error)
+	// Config gives back the configuration of the router identified by connectionID (the default router if empty)
+	Config(connectionID string) (*Config, error)
 }
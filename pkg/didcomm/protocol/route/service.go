@@ -10,6 +10,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -19,6 +21,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/messagepickup"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
@@ -46,6 +49,12 @@ const (
 
 	// KeyListUpdateResponseMsgType defines the route coordination key list update message response type.
 	KeylistUpdateResponseMsgType = CoordinationSpec + "keylist_update_response"
+
+	// KeylistQueryMsgType defines the route coordination key list query message type.
+	KeylistQueryMsgType = CoordinationSpec + "keylist_query"
+
+	// KeylistMsgType defines the route coordination key list message type.
+	KeylistMsgType = CoordinationSpec + "keylist"
 )
 
 // constants for key list update processing
@@ -60,16 +69,26 @@ const (
 	// server error while storing the key
 	serverError = "server_error"
 
+	// key not registered by the agent
+	noChange = "no_change"
+
 	// key save success
 	success = "success"
 )
 
 const (
-	// data key to store router connection ID
+	// data key to store the default router connection ID
 	routeConnIDDataKey = "route-connID"
 
-	// data key to store router config
+	// data key prefix to store the router configs (per router connection ID), the data key itself holds the
+	// config of the router saved before the agent could register with multiple routers
 	routeConfigDataKey = "route-config"
+
+	// data key prefix to store the keys registered by the agents (per agent DID)
+	routeKeylistDataKey = "route-keylist"
+
+	// limitPattern with `~` at the end for lte of given prefix (less than or equal)
+	limitPattern = "%s~"
 )
 
 const (
@@ -90,6 +109,12 @@ type provider interface {
 	InboundTransportEndpoint() string
 	LegacyKMS() legacykms.KeyManager
 	VDRIRegistry() vdri.Registry
+	Service(id string) (interface{}, error)
+}
+
+// messagePickup queues the forwarded messages for the recipients which are not reachable.
+type messagePickup interface {
+	AddMessage(message []byte, theirDID string) error
 }
 
 // Service for Route Coordination protocol.
@@ -103,10 +128,14 @@ type Service struct {
 	endpoint                 string
 	kms                      legacykms.KeyManager
 	vdRegistry               vdri.Registry
+	serviceLookup            func(id string) (interface{}, error)
 	routeRegistrationMap     map[string]chan Grant
 	routeRegistrationMapLock sync.RWMutex
 	keylistUpdateMap         map[string]chan *KeylistUpdateResponse
 	keylistUpdateMapLock     sync.RWMutex
+	keylistMap               map[string]chan *Keylist
+	keylistMapLock           sync.RWMutex
+	keylistLock              sync.Mutex
 }
 
 // New return route coordination service.
@@ -116,6 +145,10 @@ func New(prov provider) (*Service, error) {
 		return nil, fmt.Errorf("open route coordination store : %w", err)
 	}
 
+	if err = migrateRouterConfig(store); err != nil {
+		return nil, err
+	}
+
 	connectionLookup, err := connection.NewLookup(prov)
 	if err != nil {
 		return nil, err
@@ -127,14 +160,16 @@ func New(prov provider) (*Service, error) {
 		endpoint:             prov.InboundTransportEndpoint(),
 		kms:                  prov.LegacyKMS(),
 		vdRegistry:           prov.VDRIRegistry(),
+		serviceLookup:        prov.Service,
 		connectionLookup:     connectionLookup,
 		routeRegistrationMap: make(map[string]chan Grant),
 		keylistUpdateMap:     make(map[string]chan *KeylistUpdateResponse),
+		keylistMap:           make(map[string]chan *Keylist),
 	}, nil
 }
 
 // HandleInbound handles inbound route coordination messages.
-func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) { // nolint gocyclo (5 switch cases)
+func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) { // nolint gocyclo (7 switch cases)
 	// perform action on inbound message asynchronously
 	go func() {
 		switch msg.Type() {
@@ -154,6 +189,14 @@ func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string)
 			if err := s.handleKeylistUpdateResponse(msg); err != nil {
 				logger.Errorf("handle route keylist update response error : %s", err)
 			}
+		case KeylistQueryMsgType:
+			if err := s.handleKeylistQuery(msg, myDID, theirDID); err != nil {
+				logger.Errorf("handle route keylist query error : %s", err)
+			}
+		case KeylistMsgType:
+			if err := s.handleKeylist(msg); err != nil {
+				logger.Errorf("handle route keylist error : %s", err)
+			}
 		case service.ForwardMsgType:
 			if err := s.handleForward(msg); err != nil {
 				logger.Errorf("handle forward error : %s", err)
@@ -172,13 +215,22 @@ func (s *Service) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string)
 // Accept checks whether the service can handle the message type.
 func (s *Service) Accept(msgType string) bool {
 	switch msgType {
-	case RequestMsgType, GrantMsgType, KeylistUpdateMsgType, KeylistUpdateResponseMsgType, service.ForwardMsgType:
+	case RequestMsgType, GrantMsgType, KeylistUpdateMsgType, KeylistUpdateResponseMsgType,
+		KeylistQueryMsgType, KeylistMsgType, service.ForwardMsgType:
 		return true
 	}
 
 	return false
 }
 
+// MessageTypes returns the message types supported by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{
+		RequestMsgType, GrantMsgType, KeylistUpdateMsgType, KeylistUpdateResponseMsgType,
+		KeylistQueryMsgType, KeylistMsgType, service.ForwardMsgType,
+	}
+}
+
 // Name of the service
 func (s *Service) Name() string {
 	return Coordination
@@ -246,33 +298,23 @@ func (s *Service) handleKeylistUpdate(msg service.DIDCommMsg, myDID, theirDID st
 
 	// update the db
 	for _, v := range keyUpdate.Updates {
-		if v.Action == add {
-			val := theirDID
-			result := success
-
-			err = s.routeStore.Put(dataKey(v.RecipientKey), []byte(val))
-			if err != nil {
-				logger.Errorf("failed to add the route key to store : %s", err)
-
-				result = serverError
-			}
-
-			// construct the response doc
-			updates = append(updates, UpdateResponse{
-				RecipientKey: v.RecipientKey,
-				Action:       v.Action,
-				Result:       result,
-			})
-		} else if v.Action == remove {
-			// TODO remove from the store
-
-			// construct the response doc
-			updates = append(updates, UpdateResponse{
-				RecipientKey: v.RecipientKey,
-				Action:       v.Action,
-				Result:       serverError,
-			})
+		var result string
+
+		switch v.Action {
+		case add:
+			result = s.addRouteKey(v.RecipientKey, theirDID)
+		case remove:
+			result = s.removeRouteKey(v.RecipientKey, theirDID)
+		default:
+			continue
 		}
+
+		// construct the response doc
+		updates = append(updates, UpdateResponse{
+			RecipientKey: v.RecipientKey,
+			Action:       v.Action,
+			Result:       result,
+		})
 	}
 
 	// send the key update response
@@ -285,6 +327,52 @@ func (s *Service) handleKeylistUpdate(msg service.DIDCommMsg, myDID, theirDID st
 	return s.outbound.SendToDID(updateResponse, myDID, theirDID)
 }
 
+// addRouteKey registers the recipient key of the agent identified by theirDID, returns the update result.
+func (s *Service) addRouteKey(recKey, theirDID string) string {
+	if err := s.routeStore.Put(dataKey(recKey), []byte(theirDID)); err != nil {
+		logger.Errorf("failed to add the route key to store : %s", err)
+
+		return serverError
+	}
+
+	if err := s.updateKeylist(theirDID, recKey, add); err != nil {
+		logger.Errorf("failed to add the route key to the keylist : %s", err)
+
+		return serverError
+	}
+
+	return success
+}
+
+// removeRouteKey removes the recipient key of the agent identified by theirDID, returns the update result.
+// The key is not removed if it was registered by another agent.
+func (s *Service) removeRouteKey(recKey, theirDID string) string {
+	owner, err := s.routeStore.Get(dataKey(recKey))
+	if errors.Is(err, storage.ErrDataNotFound) || err == nil && string(owner) != theirDID {
+		return noChange
+	}
+
+	if err != nil {
+		logger.Errorf("failed to fetch the route key from store : %s", err)
+
+		return serverError
+	}
+
+	if err := s.routeStore.Delete(dataKey(recKey)); err != nil {
+		logger.Errorf("failed to remove the route key from store : %s", err)
+
+		return serverError
+	}
+
+	if err := s.updateKeylist(theirDID, recKey, remove); err != nil {
+		logger.Errorf("failed to remove the route key from the keylist : %s", err)
+
+		return serverError
+	}
+
+	return success
+}
+
 func (s *Service) handleKeylistUpdateResponse(msg service.DIDCommMsg) error {
 	// unmarshal the payload
 	respMsg := &KeylistUpdateResponse{}
@@ -305,6 +393,71 @@ func (s *Service) handleKeylistUpdateResponse(msg service.DIDCommMsg) error {
 	return nil
 }
 
+func (s *Service) handleKeylistQuery(msg service.DIDCommMsg, myDID, theirDID string) error {
+	// unmarshal the payload
+	query := &KeylistQuery{}
+
+	err := msg.Decode(query)
+	if err != nil {
+		return fmt.Errorf("route keylist query message unmarshal : %w", err)
+	}
+
+	keys, err := s.getKeylist(theirDID)
+	if err != nil {
+		return fmt.Errorf("route keylist query : %w", err)
+	}
+
+	offset, limit := 0, len(keys)
+
+	if query.Paginate != nil {
+		if query.Paginate.Offset > 0 {
+			offset = query.Paginate.Offset
+		}
+
+		if query.Paginate.Limit > 0 {
+			limit = query.Paginate.Limit
+		}
+	}
+
+	keylist := &Keylist{
+		Type:       KeylistMsgType,
+		ID:         msg.ID(),
+		Pagination: &Pagination{Offset: offset},
+	}
+
+	for i := offset; i < len(keys) && len(keylist.Keys) < limit; i++ {
+		keylist.Keys = append(keylist.Keys, Keys{RecipientKey: keys[i]})
+	}
+
+	keylist.Pagination.Count = len(keylist.Keys)
+
+	if remaining := len(keys) - offset - len(keylist.Keys); remaining > 0 {
+		keylist.Pagination.Remaining = remaining
+	}
+
+	return s.outbound.SendToDID(keylist, myDID, theirDID)
+}
+
+func (s *Service) handleKeylist(msg service.DIDCommMsg) error {
+	// unmarshal the payload
+	keylistMsg := &Keylist{}
+
+	err := msg.Decode(keylistMsg)
+	if err != nil {
+		return fmt.Errorf("route keylist message unmarshal : %w", err)
+	}
+
+	// check if there are any channels registered for the message ID
+	keylistCh := s.getKeylistCh(keylistMsg.ID)
+
+	if keylistCh != nil {
+		// invoke the channel for the incoming message
+		keylistCh <- keylistMsg
+	}
+
+	return nil
+}
+
 func (s *Service) handleForward(msg service.DIDCommMsg) error {
 	// unmarshal the payload
 	forward := &model.Forward{}
@@ -326,24 +479,58 @@ func (s *Service) handleForward(msg service.DIDCommMsg) error {
 		return fmt.Errorf("get destination : %w", err)
 	}
 
-	return s.outbound.Forward(forward.Msg, dest)
+	err = s.outbound.Forward(forward.Msg, dest)
+	if err == nil {
+		return nil
+	}
+
+	// the recipient is not reachable, the message is queued until it is picked up by the recipient
+	pickup, ok := s.messagePickup()
+	if !ok {
+		return fmt.Errorf("forward message : %w", err)
+	}
+
+	logger.Debugf("queue forward message for %s : %s", string(theirDID), err)
+
+	msgBytes, err := json.Marshal(forward.Msg)
+	if err != nil {
+		return fmt.Errorf("marshal forward message : %w", err)
+	}
+
+	if err := pickup.AddMessage(msgBytes, string(theirDID)); err != nil {
+		return fmt.Errorf("queue forward message : %w", err)
+	}
+
+	return nil
+}
+
+// messagePickup returns the message pickup service, the service is looked up when it is required as
+// it may be registered after the route service.
+func (s *Service) messagePickup() (messagePickup, bool) {
+	svc, err := s.serviceLookup(messagepickup.MessagePickup)
+	if err != nil {
+		return nil, false
+	}
+
+	pickup, ok := svc.(messagePickup)
+
+	return pickup, ok
 }
 
 // Register registers the agent with the router on the other end of the connection identified by
 // connectionID. This method blocks until a response is received from the router or it times out.
 // The agent is registered with the router and retrieves the router endpoint and routing keys.
-// This function throws an error if the agent is already registered against a router.
-// TODO https://github.com/hyperledger/aries-framework-go/issues/1076 Register agent with
-//  multiple routers
+// The agent can be registered with multiple routers, the first registered router is the default router.
+// This function throws an error if the agent is already registered against the router.
 func (s *Service) Register(connectionID string) error {
 	// check if router is already registered
-	routerConnID, err := s.getRouterConnectionID()
-	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
-		return fmt.Errorf("fetch router connection id : %w", err)
+	_, err := s.getRouterConfig(connectionID)
+	if err == nil {
+		return errors.New("router is already registered")
 	}
 
-	if routerConnID != "" {
-		return errors.New("router is already registered")
+	if !errors.Is(err, storage.ErrDataNotFound) {
+		return fmt.Errorf("fetch router config : %w", err)
 	}
 
 	// get the connection record for the ID to fetch DID information
@@ -359,6 +546,9 @@ func (s *Service) Register(connectionID string) error {
 	grantCh := make(chan Grant)
 	s.setRouteRegistrationCh(msgID, grantCh)
 
+	// remove the channel once its been processed
+	defer s.setRouteRegistrationCh(msgID, nil)
+
 	// create request message
 	req := &Request{
 		ID:   msgID,
@@ -378,7 +568,7 @@ func (s *Service) Register(connectionID string) error {
 			RoutingKeys:    grantResp.RoutingKeys,
 		}
 
-		if err := s.saveRouterConfig(conf); err != nil {
+		if err := s.saveRouterConfig(connectionID, conf); err != nil {
 			return fmt.Errorf("save route config : %w", err)
 		}
 	// TODO https://github.com/hyperledger/aries-framework-go/issues/1134 configure this timeout at decorator level
@@ -386,31 +576,104 @@ func (s *Service) Register(connectionID string) error {
 		return errors.New("timeout waiting for grant from the router")
 	}
 
-	// remove the channel once its been processed
-	s.setRouteRegistrationCh(msgID, nil)
+	// the first registered router is the default router
+	_, err = s.getRouterConnectionID()
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return s.saveRouterConnectionID(connectionID)
+	}
 
-	// save the connectionID of the router
-	return s.saveRouterConnectionID(connectionID)
+	return err
 }
 
-// AddKey adds a recKey of the agent to the registered router. This method blocks until a response is
-// received from the router or it times out.
-// TODO https://github.com/hyperledger/aries-framework-go/issues/1076 Support for multiple routers
-// TODO https://github.com/hyperledger/aries-framework-go/issues/1105 Support to Add multiple
-//  recKeys to the Router
-func (s *Service) AddKey(recKey string) error {
-	// check if router is already registered
+// Unregister unregisters the agent from the router on the other end of the connection identified by
+// connectionID (the default router if connectionID is empty). The keys added to the router are not removed.
+// If the router is the default router, one of the remaining routers becomes the default router.
+func (s *Service) Unregister(connectionID string) error {
+	connectionID, err := s.routerConnectionID(connectionID)
+	if err != nil {
+		return err
+	}
+
+	if _, err = s.Config(connectionID); err != nil {
+		return err
+	}
+
+	if err := s.routeStore.Delete(routerConfigKey(connectionID)); err != nil {
+		return fmt.Errorf("delete router config : %w", err)
+	}
+
 	routerConnID, err := s.getRouterConnectionID()
 	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
 		return fmt.Errorf("fetch router connection id : %w", err)
 	}
 
-	if routerConnID == "" {
-		return ErrRouterNotRegistered
+	if routerConnID != connectionID {
+		return nil
 	}
 
-	// get the connection record for the ID to fetch DID information
-	conn, err := s.getConnection(routerConnID)
+	routers, err := s.Routers()
+	if err != nil {
+		return err
+	}
+
+	if len(routers) == 0 {
+		return s.routeStore.Delete(routeConnIDDataKey)
+	}
+
+	return s.saveRouterConnectionID(routers[0])
+}
+
+// Routers returns the connection IDs of the routers the agent is registered with.
+func (s *Service) Routers() ([]string, error) {
+	searchKey := routerConfigKey("")
+
+	itr := s.routeStore.Iterator(searchKey, fmt.Sprintf(limitPattern, searchKey))
+	defer itr.Release()
+
+	var routers []string
+
+	for itr.Next() {
+		routers = append(routers, strings.TrimPrefix(string(itr.Key()), searchKey))
+	}
+
+	if err := itr.Error(); err != nil {
+		return nil, fmt.Errorf("fetch routers : %w", err)
+	}
+
+	sort.Strings(routers)
+
+	return routers, nil
+}
+
+// SetDefaultRouter sets the router on the other end of the connection identified by connectionID as the
+// default router. The keys of the new connections are advertised through the default router unless
+// the router is chosen explicitly.
+func (s *Service) SetDefaultRouter(connectionID string) error {
+	if _, err := s.Config(connectionID); err != nil {
+		return err
+	}
+
+	return s.saveRouterConnectionID(connectionID)
+}
+
+// AddKey adds a recKey of the agent to the router on the other end of the connection identified by
+// connectionID (the default router if connectionID is empty). This method blocks until a response is
+// received from the router or it times out.
+// TODO https://github.com/hyperledger/aries-framework-go/issues/1105 Support to Add multiple
+//  recKeys to the Router
+func (s *Service) AddKey(connectionID, recKey string) error {
+	return s.updateKey(connectionID, recKey, add)
+}
+
+// RemoveKey removes a recKey of the agent from the router on the other end of the connection identified by
+// connectionID (the default router if connectionID is empty). This method blocks until a response is
+// received from the router or it times out.
+func (s *Service) RemoveKey(connectionID, recKey string) error {
+	return s.updateKey(connectionID, recKey, remove)
+}
+
+func (s *Service) updateKey(connectionID, recKey, action string) error {
+	conn, err := s.getRouterConnection(connectionID)
 	if err != nil {
 		return err
 	}
@@ -422,13 +685,16 @@ func (s *Service) AddKey(recKey string) error {
 	keyUpdateCh := make(chan *KeylistUpdateResponse)
 	s.setKeyUpdateResponseCh(msgID, keyUpdateCh)
 
+	// remove the channel once its been processed
+	defer s.setKeyUpdateResponseCh(msgID, nil)
+
 	keyUpdate := &KeylistUpdate{
 		ID:   msgID,
 		Type: KeylistUpdateMsgType,
 		Updates: []Update{
 			{
 				RecipientKey: recKey,
-				Action:       add,
+				Action:       action,
 			},
 		},
 	}
@@ -439,37 +705,108 @@ func (s *Service) AddKey(recKey string) error {
 
 	select {
 	case keyUpdateResp := <-keyUpdateCh:
-		if err := processKeylistUpdateResp(recKey, keyUpdateResp); err != nil {
-			return err
-		}
+		return processKeylistUpdateResp(recKey, action, keyUpdateResp)
 	// TODO https://github.com/hyperledger/aries-framework-go/issues/1134 configure this timeout at decorator level
 	case <-time.After(updateTimeout):
 		return errors.New("timeout waiting for keylist update response from the router")
 	}
+}
+
+// Keylist queries the keys of the agent registered with the router on the other end of the connection
+// identified by connectionID (the default router if connectionID is empty). This method blocks until
+// a response is received from the router or it times out.
+func (s *Service) Keylist(connectionID string) ([]string, error) {
+	conn, err := s.getRouterConnection(connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// generate message ID
+	msgID := uuid.New().String()
+
+	// register chan for callback processing
+	keylistCh := make(chan *Keylist)
+	s.setKeylistCh(msgID, keylistCh)
 
 	// remove the channel once its been processed
-	s.setKeyUpdateResponseCh(msgID, nil)
+	defer s.setKeylistCh(msgID, nil)
 
-	return nil
+	query := &KeylistQuery{
+		ID:   msgID,
+		Type: KeylistQueryMsgType,
+	}
+
+	if err := s.outbound.SendToDID(query, conn.MyDID, conn.TheirDID); err != nil {
+		return nil, fmt.Errorf("send route keylist query: %w", err)
+	}
+
+	select {
+	case keylistResp := <-keylistCh:
+		keys := []string{}
+
+		for _, k := range keylistResp.Keys {
+			keys = append(keys, k.RecipientKey)
+		}
+
+		return keys, nil
+	// TODO https://github.com/hyperledger/aries-framework-go/issues/1134 configure this timeout at decorator level
+	case <-time.After(updateTimeout):
+		return nil, errors.New("timeout waiting for keylist from the router")
+	}
 }
 
-// Config fetches the router config - endpoint and routingKeys.
-func (s *Service) Config() (*Config, error) {
+// Config fetches the router config - endpoint and routingKeys of the router on the other end of the connection
+// identified by connectionID (the default router if connectionID is empty).
+func (s *Service) Config(connectionID string) (*Config, error) {
+	connectionID, err := s.routerConnectionID(connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	conf, err := s.getRouterConfig(connectionID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, ErrRouterNotRegistered
+	}
+
+	return conf, err
+}
+
+// routerConnectionID returns the connectionID, the default router connection ID if connectionID is empty.
+func (s *Service) routerConnectionID(connectionID string) (string, error) {
+	if connectionID != "" {
+		return connectionID, nil
+	}
+
 	// check if router is already registered
-	_, err := s.getRouterConnectionID()
+	routerConnID, err := s.getRouterConnectionID()
 	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
-		return nil, fmt.Errorf("fetch router connection id : %w", err)
+		return "", fmt.Errorf("fetch router connection id : %w", err)
 	} else if errors.Is(err, storage.ErrDataNotFound) {
-		return nil, ErrRouterNotRegistered
+		return "", ErrRouterNotRegistered
+	}
+
+	return routerConnID, nil
+}
+
+// getRouterConnection returns the connection record of the router the agent is registered with.
+func (s *Service) getRouterConnection(connectionID string) (*connection.Record, error) {
+	connectionID, err := s.routerConnectionID(connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = s.Config(connectionID); err != nil {
+		return nil, err
 	}
 
-	return s.getRouterConfig()
+	// get the connection record for the ID to fetch DID information
+	return s.getConnection(connectionID)
 }
 
-func processKeylistUpdateResp(recKey string, keyUpdateResp *KeylistUpdateResponse) error {
+func processKeylistUpdateResp(recKey, action string, keyUpdateResp *KeylistUpdateResponse) error {
 	for _, result := range keyUpdateResp.Updated {
-		if result.RecipientKey == recKey && result.Action == add && result.Result != success {
-			return errors.New("failed to update the recipient key with the router")
+		if result.RecipientKey == recKey && result.Action == action && result.Result != success {
+			return fmt.Errorf("failed to update the recipient key with the router : %s", result.Result)
 		}
 	}
 
@@ -512,6 +849,24 @@ func (s *Service) setKeyUpdateResponseCh(msgID string, keyUpdateCh chan *Keylist
 	}
 }
 
+func (s *Service) getKeylistCh(msgID string) chan *Keylist {
+	s.keylistMapLock.RLock()
+	defer s.keylistMapLock.RUnlock()
+
+	return s.keylistMap[msgID]
+}
+
+func (s *Service) setKeylistCh(msgID string, keylistCh chan *Keylist) {
+	s.keylistMapLock.Lock()
+	defer s.keylistMapLock.Unlock()
+
+	if keylistCh == nil {
+		delete(s.keylistMap, msgID)
+	} else {
+		s.keylistMap[msgID] = keylistCh
+	}
+}
+
 func (s *Service) getRouterConnectionID() (string, error) {
 	id, err := s.routeStore.Get(routeConnIDDataKey)
 	if err != nil {
@@ -530,8 +885,41 @@ type config struct {
 	RoutingKeys    []string
 }
 
-func (s *Service) getRouterConfig() (*Config, error) {
-	val, err := s.routeStore.Get(routeConfigDataKey)
+// migrateRouterConfig moves the config saved under the legacy routeConfigDataKey key to the config key of the
+// router identified by the routeConnIDDataKey key.
+func migrateRouterConfig(store storage.Store) error {
+	conf, err := store.Get(routeConfigDataKey)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("get legacy router config : %w", err)
+	}
+
+	operations := []storage.Operation{{Key: routeConfigDataKey}}
+
+	routerConnID, err := store.Get(routeConnIDDataKey)
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return fmt.Errorf("fetch router connection id : %w", err)
+	}
+
+	// the legacy config is dropped if there is no router connection to migrate it to
+	if len(routerConnID) != 0 {
+		operations = append(operations, storage.Operation{Key: routerConfigKey(string(routerConnID)), Value: conf})
+	}
+
+	if err = store.Batch(operations); err != nil {
+		return fmt.Errorf("migrate legacy router config : %w", err)
+	}
+
+	logger.Infof("migrated legacy router config to router connection '%s'", string(routerConnID))
+
+	return nil
+}
+
+func (s *Service) getRouterConfig(connectionID string) (*Config, error) {
+	val, err := s.routeStore.Get(routerConfigKey(connectionID))
 	if err != nil {
 		return nil, fmt.Errorf("get router config data : %w", err)
 	}
@@ -546,13 +934,64 @@ func (s *Service) getRouterConfig() (*Config, error) {
 	return NewConfig(conf.RouterEndpoint, conf.RoutingKeys), nil
 }
 
-func (s *Service) saveRouterConfig(conf *config) error {
+func (s *Service) saveRouterConfig(connectionID string, conf *config) error {
 	bytes, err := json.Marshal(conf)
 	if err != nil {
 		return fmt.Errorf("store router config data : %w", err)
 	}
 
-	return s.routeStore.Put(routeConfigDataKey, bytes)
+	return s.routeStore.Put(routerConfigKey(connectionID), bytes)
+}
+
+// getKeylist returns the keys registered by the agent identified by theirDID.
+func (s *Service) getKeylist(theirDID string) ([]string, error) {
+	val, err := s.routeStore.Get(keylistKey(theirDID))
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get keylist data : %w", err)
+	}
+
+	var keys []string
+
+	err = json.Unmarshal(val, &keys)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal keylist data : %w", err)
+	}
+
+	return keys, nil
+}
+
+// updateKeylist adds or removes the recKey from the keys registered by the agent identified by theirDID.
+func (s *Service) updateKeylist(theirDID, recKey, action string) error {
+	s.keylistLock.Lock()
+	defer s.keylistLock.Unlock()
+
+	keys, err := s.getKeylist(theirDID)
+	if err != nil {
+		return err
+	}
+
+	var updated []string
+
+	for _, k := range keys {
+		if k != recKey {
+			updated = append(updated, k)
+		}
+	}
+
+	if action == add {
+		updated = append(updated, recKey)
+	}
+
+	bytes, err := json.Marshal(updated)
+	if err != nil {
+		return fmt.Errorf("store keylist data : %w", err)
+	}
+
+	return s.routeStore.Put(keylistKey(theirDID), bytes)
 }
 
 func (s *Service) getConnection(routerConnID string) (*connection.Record, error) {
@@ -571,3 +1010,11 @@ func (s *Service) getConnection(routerConnID string) (*connection.Record, error)
 func dataKey(id string) string {
 	return "route-" + id
 }
+
+func routerConfigKey(connectionID string) string {
+	return routeConfigDataKey + "_" + connectionID
+}
+
+func keylistKey(theirDID string) string {
+	return routeKeylistDataKey + "_" + theirDID
+}
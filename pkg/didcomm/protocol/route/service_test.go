@@ -17,6 +17,7 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/messagepickup"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/dispatcher"
@@ -25,6 +26,7 @@ import (
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
 	mockstore "github.com/hyperledger/aries-framework-go/pkg/internal/mock/storage"
 	mockvdri "github.com/hyperledger/aries-framework-go/pkg/internal/mock/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
 	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
@@ -49,6 +51,66 @@ func TestServiceNew(t *testing.T) {
 		require.Equal(t, Coordination, svc.Name())
 	})
 
+	t.Run("test new service - legacy router config is migrated", func(t *testing.T) {
+		store := &mockstore.MockStore{Store: map[string][]byte{
+			routeConnIDDataKey: []byte("conn-1"),
+			routeConfigDataKey: []byte(`{"RouterEndpoint":"http://router.example.com","RoutingKeys":["key-1"]}`),
+		}}
+
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          mockstore.NewCustomMockStoreProvider(store),
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.NoError(t, err)
+
+		_, err = store.Get(routeConfigDataKey)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		conf, err := svc.Config("")
+		require.NoError(t, err)
+		require.Equal(t, ENDPOINT, conf.Endpoint())
+		require.Equal(t, []string{"key-1"}, conf.Keys())
+
+		routers, err := svc.Routers()
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn-1"}, routers)
+	})
+
+	t.Run("test new service - legacy router config without router connection", func(t *testing.T) {
+		store := &mockstore.MockStore{Store: map[string][]byte{
+			routeConfigDataKey: []byte(`{"RouterEndpoint":"http://router.example.com"}`),
+		}}
+
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          mockstore.NewCustomMockStoreProvider(store),
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.NoError(t, err)
+		require.Empty(t, store.Store)
+
+		_, err = svc.Config("")
+		require.Equal(t, ErrRouterNotRegistered, err)
+	})
+
+	t.Run("test new service - legacy router config migration failure", func(t *testing.T) {
+		store := &mockstore.MockStore{
+			Store:    map[string][]byte{routeConfigDataKey: []byte("{}")},
+			ErrBatch: errors.New("batch error"),
+		}
+
+		_, err := New(&mockprovider.Provider{
+			StorageProviderValue: mockstore.NewCustomMockStoreProvider(store),
+		})
+		require.EqualError(t, err, "migrate legacy router config : batch error")
+
+		store.ErrGet = errors.New("get error")
+
+		_, err = New(&mockprovider.Provider{
+			StorageProviderValue: mockstore.NewCustomMockStoreProvider(store),
+		})
+		require.EqualError(t, err, "get legacy router config : get error")
+	})
+
 	t.Run("test new service name - failure", func(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue: &mockstore.MockStoreProvider{
@@ -68,6 +130,10 @@ func TestServiceAccept(t *testing.T) {
 	require.Equal(t, true, s.Accept(KeylistUpdateResponseMsgType))
 	require.Equal(t, true, s.Accept(service.ForwardMsgType))
 	require.Equal(t, false, s.Accept("unsupported msg type"))
+
+	for _, msgType := range s.MessageTypes() {
+		require.True(t, s.Accept(msgType))
+	}
 }
 
 func TestServiceHandleInbound(t *testing.T) {
@@ -232,7 +298,7 @@ func TestServiceUpdateKeyListMsg(t *testing.T) {
 	t.Run("test service handle request msg - verify outbound message", func(t *testing.T) {
 		update := make(map[string]updateResult)
 		update["ABC"] = updateResult{action: add, result: success}
-		update["XYZ"] = updateResult{action: remove, result: serverError}
+		update["XYZ"] = updateResult{action: remove, result: noChange}
 		update[""] = updateResult{action: add, result: success}
 
 		svc, err := New(&mockprovider.Provider{
@@ -413,6 +479,64 @@ func TestServiceForwardMsg(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "get destination")
 	})
+
+	t.Run("test service handle forward msg - recipient not reachable", func(t *testing.T) {
+		to := randomID()
+		content := &model.Envelope{CipherText: "qQyzvajdvCDJbwxM"}
+		msg := generateForwardMsgPayload(t, randomID(), to, content)
+
+		prov := &mockprovider.Provider{
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                      &mockkms.CloseableKMS{},
+			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
+				ValidateForward: func(msg interface{}, des *service.Destination) error {
+					return errors.New("recipient not reachable")
+				},
+			},
+			VDRIRegistryValue: &mockvdri.MockVDRIRegistry{
+				ResolveFunc: func(didID string, opts ...vdri.ResolveOpts) (doc *did.Doc, e error) {
+					return mockdiddoc.GetMockDIDDoc(), nil
+				},
+			},
+		}
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		err = svc.routeStore.Put(dataKey(to), []byte("did:example:123"))
+		require.NoError(t, err)
+
+		// message pickup is not available
+		err = svc.handleForward(msg)
+		require.EqualError(t, err, "forward message : recipient not reachable")
+
+		pickup, err := messagepickup.New(prov)
+		require.NoError(t, err)
+
+		prov.ServiceMap = map[string]interface{}{messagepickup.MessagePickup: pickup}
+
+		// the message is queued for the recipient
+		err = svc.handleForward(msg)
+		require.NoError(t, err)
+
+		sent := make(chan interface{}, 1)
+
+		prov.OutboundDispatcherValue.(*mockdispatcher.MockOutbound).ValidateSendToDID = func(msg interface{},
+			myDID, theirDID string) error {
+			require.Equal(t, "did:example:123", theirDID)
+
+			sent <- msg
+
+			return nil
+		}
+
+		_, err = pickup.HandleInbound(service.NewDIDCommMsgMap(&messagepickup.StatusRequest{
+			Type: messagepickup.StatusRequestMsgType, ID: randomID(),
+		}), MYDID, "did:example:123")
+		require.NoError(t, err)
+		require.Equal(t, 1, (<-sent).(*messagepickup.Status).MessageCount)
+	})
 }
 
 func TestRegister(t *testing.T) {
@@ -451,7 +575,16 @@ func TestRegister(t *testing.T) {
 		err = svc.Register("conn1")
 		require.NoError(t, err)
 
-		err = svc.Register("")
+		// the first registered router is the default router
+		conf, err := svc.Config("")
+		require.NoError(t, err)
+		require.NotNil(t, conf)
+
+		routers, err := svc.Routers()
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn1"}, routers)
+
+		err = svc.Register("conn1")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "router is already registered")
 	})
@@ -529,9 +662,9 @@ func TestRegister(t *testing.T) {
 	})
 
 	t.Run("test register route - router connection fetch error", func(t *testing.T) {
+		store := &mockstore.MockStore{Store: make(map[string][]byte)}
 		svc, err := New(&mockprovider.Provider{
-			StorageProviderValue: &mockstore.MockStoreProvider{
-				Store: &mockstore.MockStore{ErrGet: fmt.Errorf("get error")}},
+			StorageProviderValue:          mockstore.NewCustomMockStoreProvider(store),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
 			KMSValue:                      &mockkms.CloseableKMS{},
 			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
@@ -540,9 +673,11 @@ func TestRegister(t *testing.T) {
 				}}})
 		require.NoError(t, err)
 
+		store.ErrGet = fmt.Errorf("get error")
+
 		err = svc.Register("conn1")
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "fetch router connection id")
+		require.Contains(t, err.Error(), "fetch router config")
 	})
 }
 
@@ -571,6 +706,7 @@ func TestKeylistUpdate(t *testing.T) {
 
 		// save router connID
 		require.NoError(t, svc.saveRouterConnectionID("conn1"))
+		require.NoError(t, svc.saveRouterConfig("conn1", &config{RouterEndpoint: ENDPOINT}))
 
 		// save connections
 		connRec := &connection.Record{
@@ -593,7 +729,7 @@ func TestKeylistUpdate(t *testing.T) {
 				t, updateMsg.ID, updates)))
 		}()
 
-		err = svc.AddKey(recKey)
+		err = svc.AddKey("", recKey)
 		require.NoError(t, err)
 	})
 
@@ -620,15 +756,16 @@ func TestKeylistUpdate(t *testing.T) {
 		require.NoError(t, err)
 
 		// no router registered
-		err = svc.AddKey(recKey)
+		err = svc.AddKey("", recKey)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "router not registered")
 
 		// save router connID
 		require.NoError(t, svc.saveRouterConnectionID("conn1"))
+		require.NoError(t, svc.saveRouterConfig("conn1", &config{RouterEndpoint: ENDPOINT}))
 
 		// no connections saved
-		err = svc.AddKey(recKey)
+		err = svc.AddKey("", recKey)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "connection not found")
 
@@ -653,7 +790,7 @@ func TestKeylistUpdate(t *testing.T) {
 				t, updateMsg.ID, updates)))
 		}()
 
-		err = svc.AddKey(recKey)
+		err = svc.AddKey("", recKey)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "failed to update the recipient key with the router")
 	})
@@ -673,24 +810,25 @@ func TestKeylistUpdate(t *testing.T) {
 		require.NoError(t, err)
 		s["conn_conn2"] = connBytes
 		require.NoError(t, svc.saveRouterConnectionID("conn2"))
+		require.NoError(t, svc.saveRouterConfig("conn2", &config{RouterEndpoint: ENDPOINT}))
 
-		err = svc.AddKey("recKey")
+		err = svc.AddKey("", "recKey")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "timeout waiting for keylist update response from the router")
 	})
 
 	t.Run("test keylist update - router connectionID fetch error", func(t *testing.T) {
-		s := make(map[string][]byte)
+		store := &mockstore.MockStore{Store: make(map[string][]byte)}
 		svc, err := New(&mockprovider.Provider{
-			StorageProviderValue: &mockstore.MockStoreProvider{
-				Store: &mockstore.MockStore{Store: s, ErrGet: errors.New("get error")},
-			},
+			StorageProviderValue:          mockstore.NewCustomMockStoreProvider(store),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
 			KMSValue:                      &mockkms.CloseableKMS{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
-		err = svc.AddKey("recKey")
+		store.ErrGet = errors.New("get error")
+
+		err = svc.AddKey("", "recKey")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "fetch router connection id")
 	})
@@ -709,12 +847,12 @@ func TestConfig(t *testing.T) {
 		require.NoError(t, err)
 
 		require.NoError(t, svc.saveRouterConnectionID("connID-123"))
-		require.NoError(t, svc.saveRouterConfig(&config{
+		require.NoError(t, svc.saveRouterConfig("connID-123", &config{
 			RouterEndpoint: ENDPOINT,
 			RoutingKeys:    routingKeys,
 		}))
 
-		conf, err := svc.Config()
+		conf, err := svc.Config("")
 		require.NoError(t, err)
 		require.Equal(t, ENDPOINT, conf.Endpoint())
 		require.Equal(t, routingKeys, conf.Keys())
@@ -729,7 +867,7 @@ func TestConfig(t *testing.T) {
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
-		conf, err := svc.Config()
+		conf, err := svc.Config("")
 		require.Error(t, err)
 		require.Equal(t, err, ErrRouterNotRegistered)
 		require.Nil(t, conf)
@@ -746,9 +884,9 @@ func TestConfig(t *testing.T) {
 
 		require.NoError(t, svc.saveRouterConnectionID("connID-123"))
 
-		conf, err := svc.Config()
+		conf, err := svc.Config("")
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "get router config data")
+		require.Equal(t, err, ErrRouterNotRegistered)
 		require.Nil(t, conf)
 	})
 
@@ -762,35 +900,523 @@ func TestConfig(t *testing.T) {
 		require.NoError(t, err)
 
 		require.NoError(t, svc.saveRouterConnectionID("connID-123"))
-		require.NoError(t, svc.routeStore.Put(routeConfigDataKey, []byte("invalid data")))
+		require.NoError(t, svc.routeStore.Put(routerConfigKey("connID-123"), []byte("invalid data")))
 
-		conf, err := svc.Config()
+		conf, err := svc.Config("")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "unmarshal router config data")
 		require.Nil(t, conf)
 	})
 
 	t.Run("test config - router connectionID fetch error", func(t *testing.T) {
-		s := make(map[string][]byte)
+		store := &mockstore.MockStore{Store: make(map[string][]byte)}
 		svc, err := New(&mockprovider.Provider{
-			StorageProviderValue: &mockstore.MockStoreProvider{
-				Store: &mockstore.MockStore{Store: s, ErrGet: errors.New("get error")},
-			},
+			StorageProviderValue:          mockstore.NewCustomMockStoreProvider(store),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
 			KMSValue:                      &mockkms.CloseableKMS{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
+		store.ErrGet = errors.New("get error")
+
 		require.NoError(t, svc.saveRouterConnectionID("connID-123"))
-		require.NoError(t, svc.routeStore.Put(routeConfigDataKey, []byte("invalid data")))
+		require.NoError(t, svc.routeStore.Put(routerConfigKey("connID-123"), []byte("invalid data")))
 
-		conf, err := svc.Config()
+		conf, err := svc.Config("")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "fetch router connection id")
 		require.Nil(t, conf)
 	})
 }
 
+func TestServiceKeylistQueryMsg(t *testing.T) {
+	t.Run("test service handle inbound key list query msg - success", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                      &mockkms.CloseableKMS{},
+			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
+		require.NoError(t, err)
+
+		msgID := randomID()
+
+		id, err := svc.HandleInbound(generateKeylistQueryMsgPayload(t, msgID, nil), "", "")
+		require.NoError(t, err)
+		require.Equal(t, msgID, id)
+	})
+
+	t.Run("test service handle key list query msg - invalid message", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                      &mockkms.CloseableKMS{},
+			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
+		require.NoError(t, err)
+
+		msg := &service.DIDCommMsgMap{"@id": map[int]int{}}
+
+		err = svc.handleKeylistQuery(msg, MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "route keylist query message unmarshal")
+	})
+
+	t.Run("test service handle key list query msg - verify outbound message", func(t *testing.T) {
+		var keylist *Keylist
+
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                      &mockkms.CloseableKMS{},
+			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
+				ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+					require.Equal(t, MYDID, myDID)
+					require.Equal(t, THEIRDID, theirDID)
+
+					var ok bool
+					keylist, ok = msg.(*Keylist)
+					require.True(t, ok)
+
+					return nil
+				}}})
+		require.NoError(t, err)
+
+		require.Equal(t, success, svc.addRouteKey("ABC", THEIRDID))
+		require.Equal(t, success, svc.addRouteKey("DEF", THEIRDID))
+		require.Equal(t, success, svc.addRouteKey("GHI", THEIRDID))
+		require.Equal(t, success, svc.addRouteKey("XYZ", "otherDID"))
+
+		msgID := randomID()
+
+		require.NoError(t, svc.handleKeylistQuery(generateKeylistQueryMsgPayload(t, msgID, nil), MYDID, THEIRDID))
+		require.Equal(t, msgID, keylist.ID)
+		require.Equal(t, KeylistMsgType, keylist.Type)
+		require.Equal(t, []Keys{{RecipientKey: "ABC"}, {RecipientKey: "DEF"}, {RecipientKey: "GHI"}}, keylist.Keys)
+		require.Equal(t, &Pagination{Count: 3}, keylist.Pagination)
+
+		require.NoError(t, svc.handleKeylistQuery(generateKeylistQueryMsgPayload(t, msgID,
+			&Paginate{Limit: 1, Offset: 1}), MYDID, THEIRDID))
+		require.Equal(t, []Keys{{RecipientKey: "DEF"}}, keylist.Keys)
+		require.Equal(t, &Pagination{Count: 1, Offset: 1, Remaining: 1}, keylist.Pagination)
+
+		// removed keys are not returned
+		require.Equal(t, success, svc.removeRouteKey("DEF", THEIRDID))
+
+		require.NoError(t, svc.handleKeylistQuery(generateKeylistQueryMsgPayload(t, msgID, nil), MYDID, THEIRDID))
+		require.Equal(t, []Keys{{RecipientKey: "ABC"}, {RecipientKey: "GHI"}}, keylist.Keys)
+	})
+
+	t.Run("test service handle key list query msg - keylist fetch error", func(t *testing.T) {
+		store := &mockstore.MockStore{Store: make(map[string][]byte)}
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          mockstore.NewCustomMockStoreProvider(store),
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                      &mockkms.CloseableKMS{},
+			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
+		require.NoError(t, err)
+
+		store.ErrGet = errors.New("get error")
+
+		err = svc.handleKeylistQuery(generateKeylistQueryMsgPayload(t, randomID(), nil), MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get keylist data")
+	})
+}
+
+func TestServiceKeylistMsg(t *testing.T) {
+	t.Run("test service handle inbound key list msg - success", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                      &mockkms.CloseableKMS{},
+			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
+		require.NoError(t, err)
+
+		msgID := randomID()
+
+		id, err := svc.HandleInbound(generateKeylistMsgPayload(t, msgID, []Keys{{RecipientKey: "ABC"}}), "", "")
+		require.NoError(t, err)
+		require.Equal(t, msgID, id)
+	})
+
+	t.Run("test service handle key list msg - invalid message", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                      &mockkms.CloseableKMS{},
+			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
+		require.NoError(t, err)
+
+		msg := &service.DIDCommMsgMap{"@id": map[int]int{}}
+
+		err = svc.handleKeylist(msg)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "route keylist message unmarshal")
+	})
+}
+
+func TestRemoveRouteKey(t *testing.T) {
+	svc, err := New(&mockprovider.Provider{
+		StorageProviderValue:          mockstore.NewMockStoreProvider(),
+		TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		KMSValue:                      &mockkms.CloseableKMS{},
+		OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
+	require.NoError(t, err)
+
+	require.Equal(t, success, svc.addRouteKey("ABC", THEIRDID))
+
+	// key not registered
+	require.Equal(t, noChange, svc.removeRouteKey("XYZ", THEIRDID))
+
+	// key registered by another agent
+	require.Equal(t, noChange, svc.removeRouteKey("ABC", "otherDID"))
+
+	_, err = svc.routeStore.Get(dataKey("ABC"))
+	require.NoError(t, err)
+
+	require.Equal(t, success, svc.removeRouteKey("ABC", THEIRDID))
+
+	_, err = svc.routeStore.Get(dataKey("ABC"))
+	require.True(t, errors.Is(err, storage.ErrDataNotFound))
+}
+
+func TestRemoveKey(t *testing.T) {
+	t.Run("test remove key - success", func(t *testing.T) {
+		keyUpdateMsg := make(chan KeylistUpdate)
+
+		s := make(map[string][]byte)
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                      &mockkms.CloseableKMS{},
+			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
+				ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+					request, ok := msg.(*KeylistUpdate)
+					require.True(t, ok)
+
+					keyUpdateMsg <- *request
+					return nil
+				}}})
+		require.NoError(t, err)
+
+		saveRouter(t, svc, s, "conn1")
+
+		go func() {
+			updateMsg := <-keyUpdateMsg
+			require.Equal(t, remove, updateMsg.Updates[0].Action)
+
+			updates := []UpdateResponse{
+				{
+					RecipientKey: updateMsg.Updates[0].RecipientKey,
+					Action:       updateMsg.Updates[0].Action,
+					Result:       success,
+				},
+			}
+			require.NoError(t, svc.handleKeylistUpdateResponse(generateKeylistUpdateResponseMsgPayload(
+				t, updateMsg.ID, updates)))
+		}()
+
+		require.NoError(t, svc.RemoveKey("conn1", "recKey"))
+	})
+
+	t.Run("test remove key - no change", func(t *testing.T) {
+		keyUpdateMsg := make(chan KeylistUpdate)
+
+		s := make(map[string][]byte)
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                      &mockkms.CloseableKMS{},
+			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
+				ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+					request, ok := msg.(*KeylistUpdate)
+					require.True(t, ok)
+
+					keyUpdateMsg <- *request
+					return nil
+				}}})
+		require.NoError(t, err)
+
+		saveRouter(t, svc, s, "conn1")
+
+		go func() {
+			updateMsg := <-keyUpdateMsg
+
+			updates := []UpdateResponse{
+				{
+					RecipientKey: updateMsg.Updates[0].RecipientKey,
+					Action:       updateMsg.Updates[0].Action,
+					Result:       noChange,
+				},
+			}
+			require.NoError(t, svc.handleKeylistUpdateResponse(generateKeylistUpdateResponseMsgPayload(
+				t, updateMsg.ID, updates)))
+		}()
+
+		err = svc.RemoveKey("", "recKey")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to update the recipient key with the router : no_change")
+	})
+
+	t.Run("test remove key - router not registered", func(t *testing.T) {
+		s := make(map[string][]byte)
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                      &mockkms.CloseableKMS{},
+			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
+		require.NoError(t, err)
+
+		saveRouter(t, svc, s, "conn1")
+
+		err = svc.RemoveKey("conn2", "recKey")
+		require.Error(t, err)
+		require.Equal(t, ErrRouterNotRegistered, err)
+	})
+}
+
+func TestKeylist(t *testing.T) {
+	t.Run("test keylist - success", func(t *testing.T) {
+		s := make(map[string][]byte)
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                      &mockkms.CloseableKMS{},
+			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
+		require.NoError(t, err)
+
+		svc.outbound = &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				require.Equal(t, MYDID, myDID)
+				require.Equal(t, THEIRDID, theirDID)
+
+				query, ok := msg.(*KeylistQuery)
+				require.True(t, ok)
+
+				go func() {
+					require.NoError(t, svc.handleKeylist(generateKeylistMsgPayload(t, query.ID,
+						[]Keys{{RecipientKey: "ABC"}, {RecipientKey: "XYZ"}})))
+				}()
+
+				return nil
+			}}
+
+		saveRouter(t, svc, s, "conn1")
+
+		keys, err := svc.Keylist("")
+		require.NoError(t, err)
+		require.Equal(t, []string{"ABC", "XYZ"}, keys)
+	})
+
+	t.Run("test keylist - send error", func(t *testing.T) {
+		s := make(map[string][]byte)
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                      &mockkms.CloseableKMS{},
+			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
+				ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+					return errors.New("send error")
+				}}})
+		require.NoError(t, err)
+
+		saveRouter(t, svc, s, "conn1")
+
+		keys, err := svc.Keylist("conn1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "send route keylist query")
+		require.Nil(t, keys)
+	})
+
+	t.Run("test keylist - timeout error", func(t *testing.T) {
+		s := make(map[string][]byte)
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                      &mockkms.CloseableKMS{},
+			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
+		require.NoError(t, err)
+
+		saveRouter(t, svc, s, "conn1")
+
+		keys, err := svc.Keylist("conn1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "timeout waiting for keylist from the router")
+		require.Nil(t, keys)
+	})
+
+	t.Run("test keylist - router not registered", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                      &mockkms.CloseableKMS{},
+			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
+		require.NoError(t, err)
+
+		keys, err := svc.Keylist("")
+		require.Error(t, err)
+		require.Equal(t, ErrRouterNotRegistered, err)
+		require.Nil(t, keys)
+	})
+}
+
+func TestMultipleRouters(t *testing.T) {
+	t.Run("test multiple routers - default router", func(t *testing.T) {
+		s := make(map[string][]byte)
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                      &mockkms.CloseableKMS{},
+			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
+		require.NoError(t, err)
+
+		routers, err := svc.Routers()
+		require.NoError(t, err)
+		require.Empty(t, routers)
+
+		require.NoError(t, svc.saveRouterConfig("conn1", &config{RouterEndpoint: "http://router1.example.com"}))
+		require.NoError(t, svc.saveRouterConfig("conn2", &config{RouterEndpoint: "http://router2.example.com"}))
+		require.NoError(t, svc.saveRouterConnectionID("conn1"))
+
+		routers, err = svc.Routers()
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn1", "conn2"}, routers)
+
+		conf, err := svc.Config("")
+		require.NoError(t, err)
+		require.Equal(t, "http://router1.example.com", conf.Endpoint())
+
+		conf, err = svc.Config("conn2")
+		require.NoError(t, err)
+		require.Equal(t, "http://router2.example.com", conf.Endpoint())
+
+		// set default router
+		require.NoError(t, svc.SetDefaultRouter("conn2"))
+
+		conf, err = svc.Config("")
+		require.NoError(t, err)
+		require.Equal(t, "http://router2.example.com", conf.Endpoint())
+
+		err = svc.SetDefaultRouter("conn3")
+		require.Error(t, err)
+		require.Equal(t, ErrRouterNotRegistered, err)
+
+		// unregister the router which is not the default router
+		require.NoError(t, svc.Unregister("conn1"))
+
+		routers, err = svc.Routers()
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn2"}, routers)
+
+		conf, err = svc.Config("")
+		require.NoError(t, err)
+		require.Equal(t, "http://router2.example.com", conf.Endpoint())
+
+		// unregister the last router
+		require.NoError(t, svc.Unregister(""))
+
+		routers, err = svc.Routers()
+		require.NoError(t, err)
+		require.Empty(t, routers)
+
+		_, err = svc.Config("")
+		require.Equal(t, ErrRouterNotRegistered, err)
+
+		err = svc.Unregister("")
+		require.Error(t, err)
+		require.Equal(t, ErrRouterNotRegistered, err)
+	})
+
+	t.Run("test multiple routers - default router reassigned", func(t *testing.T) {
+		s := make(map[string][]byte)
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                      &mockkms.CloseableKMS{},
+			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
+		require.NoError(t, err)
+
+		require.NoError(t, svc.saveRouterConfig("conn1", &config{RouterEndpoint: "http://router1.example.com"}))
+		require.NoError(t, svc.saveRouterConfig("conn2", &config{RouterEndpoint: "http://router2.example.com"}))
+		require.NoError(t, svc.saveRouterConnectionID("conn1"))
+
+		require.NoError(t, svc.Unregister("conn1"))
+
+		conf, err := svc.Config("")
+		require.NoError(t, err)
+		require.Equal(t, "http://router2.example.com", conf.Endpoint())
+	})
+
+	t.Run("test multiple routers - register second router", func(t *testing.T) {
+		msgID := make(chan string)
+
+		s := make(map[string][]byte)
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                      &mockkms.CloseableKMS{},
+			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
+				ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+					request, ok := msg.(*Request)
+					require.True(t, ok)
+
+					msgID <- request.ID
+					return nil
+				}}})
+		require.NoError(t, err)
+
+		saveRouter(t, svc, s, "conn1")
+
+		connBytes, err := json.Marshal(&connection.Record{
+			ConnectionID: "conn2", MyDID: MYDID, TheirDID: THEIRDID, State: "complete"})
+		require.NoError(t, err)
+		s["conn_conn2"] = connBytes
+
+		go func() {
+			id := <-msgID
+			require.NoError(t, svc.handleGrant(generateGrantMsgPayload(t, id)))
+		}()
+
+		require.NoError(t, svc.Register("conn2"))
+
+		routers, err := svc.Routers()
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn1", "conn2"}, routers)
+
+		// the default router is not changed
+		routerConnID, err := svc.getRouterConnectionID()
+		require.NoError(t, err)
+		require.Equal(t, "conn1", routerConnID)
+	})
+
+	t.Run("test multiple routers - routers fetch error", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue: &mockstore.MockStoreProvider{
+				Store: &mockstore.MockStore{Store: make(map[string][]byte), ErrItr: errors.New("iterator error")},
+			},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                      &mockkms.CloseableKMS{},
+			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
+		require.NoError(t, err)
+
+		routers, err := svc.Routers()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "fetch routers")
+		require.Nil(t, routers)
+	})
+}
+
+// saveRouter saves the connection and the config of the router the agent is registered with.
+func saveRouter(t *testing.T, svc *Service, s map[string][]byte, connectionID string) {
+	connBytes, err := json.Marshal(&connection.Record{
+		ConnectionID: connectionID, MyDID: MYDID, TheirDID: THEIRDID, State: "complete"})
+	require.NoError(t, err)
+
+	s["conn_"+connectionID] = connBytes
+
+	require.NoError(t, svc.saveRouterConfig(connectionID, &config{RouterEndpoint: ENDPOINT}))
+	require.NoError(t, svc.saveRouterConnectionID(connectionID))
+}
+
 func generateRequestMsgPayload(t *testing.T, id string) service.DIDCommMsg {
 	requestBytes, err := json.Marshal(&Request{
 		Type: RequestMsgType,
@@ -845,6 +1471,34 @@ func generateKeylistUpdateResponseMsgPayload(t *testing.T, id string, updates []
 	return didMsg
 }
 
+func generateKeylistQueryMsgPayload(t *testing.T, id string, paginate *Paginate) service.DIDCommMsg {
+	queryBytes, err := json.Marshal(&KeylistQuery{
+		Type:     KeylistQueryMsgType,
+		ID:       id,
+		Paginate: paginate,
+	})
+	require.NoError(t, err)
+
+	didMsg, err := service.ParseDIDCommMsgMap(queryBytes)
+	require.NoError(t, err)
+
+	return didMsg
+}
+
+func generateKeylistMsgPayload(t *testing.T, id string, keys []Keys) service.DIDCommMsg {
+	keylistBytes, err := json.Marshal(&Keylist{
+		Type: KeylistMsgType,
+		ID:   id,
+		Keys: keys,
+	})
+	require.NoError(t, err)
+
+	didMsg, err := service.ParseDIDCommMsgMap(keylistBytes)
+	require.NoError(t, err)
+
+	return didMsg
+}
+
 func generateForwardMsgPayload(t *testing.T, id, to string, msg *model.Envelope) service.DIDCommMsg {
 	requestBytes, err := json.Marshal(&model.Forward{
 		Type: service.ForwardMsgType,
@@ -11,11 +11,14 @@ import (
 	"fmt"
 )
 
-// GetRouterConfig util to get the router configuration. The endpoint is overridden with routers endpoint,
-// if router is registered. Returns endpoint, routingKeys and error.
-func GetRouterConfig(routeSvc ProtocolService, endpoint string) (string, []string, error) {
-	routeConf, err := routeSvc.Config()
-	if err != nil && !errors.Is(err, ErrRouterNotRegistered) {
+// GetRouterConfig util to get the configuration of the router identified by connectionID (the default router
+// if empty). The endpoint is overridden with routers endpoint, if router is registered.
+// It fails if the router identified by connectionID is not registered, the endpoint is returned as is if
+// connectionID is empty and there is no default router.
+// Returns endpoint, routingKeys and error.
+func GetRouterConfig(routeSvc ProtocolService, connectionID, endpoint string) (string, []string, error) {
+	routeConf, err := routeSvc.Config(connectionID)
+	if err != nil && !isDefaultRouterNotRegistered(connectionID, err) {
 		return "", nil, fmt.Errorf("fetch router config : %w", err)
 	}
 
@@ -26,11 +29,18 @@ func GetRouterConfig(routeSvc ProtocolService, endpoint string) (string, []strin
 	return endpoint, nil, nil
 }
 
-// AddKeyToRouter util to add the recipient keys to the router.
-func AddKeyToRouter(routeSvc ProtocolService, recKey string) error {
-	if err := routeSvc.AddKey(recKey); err != nil && !errors.Is(err, ErrRouterNotRegistered) {
+// AddKeyToRouter util to add the recipient keys to the router identified by connectionID (the default router
+// if empty). It fails if the router identified by connectionID is not registered, nothing is done if
+// connectionID is empty and there is no default router.
+func AddKeyToRouter(routeSvc ProtocolService, connectionID, recKey string) error {
+	if err := routeSvc.AddKey(connectionID, recKey); err != nil && !isDefaultRouterNotRegistered(connectionID, err) {
 		return fmt.Errorf("add key to the router : %w", err)
 	}
 
 	return nil
 }
+
+// isDefaultRouterNotRegistered returns true if err is ErrRouterNotRegistered for the default router.
+func isDefaultRouterNotRegistered(connectionID string, err error) bool {
+	return connectionID == "" && errors.Is(err, ErrRouterNotRegistered)
+}
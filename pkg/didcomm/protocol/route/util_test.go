@@ -15,7 +15,7 @@ import (
 
 func TestGetRouterConfig(t *testing.T) {
 	t.Run("test get router config - ro router configured", func(t *testing.T) {
-		endpoint, routingKeys, err := GetRouterConfig(&mockRouteSvc{}, ENDPOINT)
+		endpoint, routingKeys, err := GetRouterConfig(&mockRouteSvc{}, "", ENDPOINT)
 		require.NoError(t, err)
 		require.Equal(t, ENDPOINT, endpoint)
 		require.Equal(t, 0, len(routingKeys))
@@ -28,6 +28,7 @@ func TestGetRouterConfig(t *testing.T) {
 				RouterEndpoint: ENDPOINT,
 				RoutingKeys:    routeKeys,
 			},
+			"",
 			"http://override-url.com",
 		)
 		require.NoError(t, err)
@@ -35,11 +36,23 @@ func TestGetRouterConfig(t *testing.T) {
 		require.Equal(t, routeKeys, routingKeys)
 	})
 
+	t.Run("test get router config - chosen router not registered", func(t *testing.T) {
+		_, _, err := GetRouterConfig(&mockRouteSvc{ConfigErr: ErrRouterNotRegistered}, "conn-1", ENDPOINT)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrRouterNotRegistered))
+
+		endpoint, routingKeys, err := GetRouterConfig(&mockRouteSvc{ConfigErr: ErrRouterNotRegistered}, "", ENDPOINT)
+		require.NoError(t, err)
+		require.Equal(t, ENDPOINT, endpoint)
+		require.Empty(t, routingKeys)
+	})
+
 	t.Run("test get router config - router error", func(t *testing.T) {
 		endpoint, routingKeys, err := GetRouterConfig(
 			&mockRouteSvc{
 				ConfigErr: errors.New("router error"),
 			},
+			"",
 			ENDPOINT,
 		)
 		require.Error(t, err)
@@ -51,21 +64,27 @@ func TestGetRouterConfig(t *testing.T) {
 
 func TestAddKeyToRouter(t *testing.T) {
 	t.Run("test add key to router - success", func(t *testing.T) {
-		err := AddKeyToRouter(&mockRouteSvc{}, ENDPOINT)
+		err := AddKeyToRouter(&mockRouteSvc{}, "", ENDPOINT)
 		require.NoError(t, err)
 	})
 
 	t.Run("test add key to router - router not registered", func(t *testing.T) {
 		err := AddKeyToRouter(&mockRouteSvc{
 			AddKeyErr: ErrRouterNotRegistered,
-		}, ENDPOINT)
+		}, "", ENDPOINT)
 		require.NoError(t, err)
+
+		err = AddKeyToRouter(&mockRouteSvc{
+			AddKeyErr: ErrRouterNotRegistered,
+		}, "conn-1", ENDPOINT)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrRouterNotRegistered))
 	})
 
 	t.Run("test add key to router - router error", func(t *testing.T) {
 		err := AddKeyToRouter(&mockRouteSvc{
 			AddKeyErr: errors.New("router error"),
-		}, ENDPOINT)
+		}, "", ENDPOINT)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "add key to the router")
 	})
@@ -79,12 +98,12 @@ type mockRouteSvc struct {
 }
 
 // AddKey adds agents recKey to the router
-func (m *mockRouteSvc) AddKey(recKey string) error {
+func (m *mockRouteSvc) AddKey(connectionID, recKey string) error {
 	return m.AddKeyErr
 }
 
 // Config gives back the router configuration
-func (m *mockRouteSvc) Config() (*Config, error) {
+func (m *mockRouteSvc) Config(connectionID string) (*Config, error) {
 	if m.ConfigErr != nil {
 		return nil, m.ConfigErr
 	}
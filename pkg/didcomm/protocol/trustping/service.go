@@ -0,0 +1,243 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+// constants for trust ping spec types
+const (
+	// TrustPing trust ping protocol
+	TrustPing = "trustping"
+
+	// TrustPingSpec defines the trust ping spec
+	TrustPingSpec = "https://didcomm.org/trust_ping/1.0/"
+
+	// PingMsgType defines the trust ping ping message type.
+	PingMsgType = TrustPingSpec + "ping"
+
+	// PingResponseMsgType defines the trust ping ping_response message type.
+	PingResponseMsgType = TrustPingSpec + "ping_response"
+)
+
+// ErrConnectionNotFound connection not found error
+var ErrConnectionNotFound = errors.New("connection not found")
+
+// ErrPingTimeout is returned when no ping response is received within the given timeout
+var ErrPingTimeout = errors.New("timeout waiting for ping response")
+
+// provider contains dependencies for the Trust Ping protocol and is typically created by using aries.Context()
+type provider interface {
+	OutboundDispatcher() dispatcher.Outbound
+	StorageProvider() storage.Provider
+	TransientStorageProvider() storage.Provider
+}
+
+// ProtocolService service interface for trust ping.
+type ProtocolService interface {
+	// Ping sends a ping to the other end of the connection and returns the round-trip time
+	Ping(connectionID string, timeout time.Duration) (time.Duration, error)
+}
+
+// Service for Trust Ping protocol.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0048-trust-ping
+type Service struct {
+	outbound         dispatcher.Outbound
+	connectionLookup *connection.Lookup
+	pingMap          map[string]chan struct{}
+	pingMapLock      sync.RWMutex
+}
+
+// New return trust ping service.
+func New(prov provider) (*Service, error) {
+	connectionLookup, err := connection.NewLookup(prov)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		outbound:         prov.OutboundDispatcher(),
+		connectionLookup: connectionLookup,
+		pingMap:          make(map[string]chan struct{}),
+	}, nil
+}
+
+// HandleInbound handles inbound trust ping messages.
+func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	switch msg.Type() {
+	case PingMsgType:
+		if err := s.handlePing(msg, myDID, theirDID); err != nil {
+			return "", fmt.Errorf("handle ping : %w", err)
+		}
+	case PingResponseMsgType:
+		if err := s.handlePingResponse(msg); err != nil {
+			return "", fmt.Errorf("handle ping response : %w", err)
+		}
+	default:
+		return "", fmt.Errorf("unrecognized msgType: %s", msg.Type())
+	}
+
+	return msg.ID(), nil
+}
+
+// HandleOutbound sends the trust ping message, the response (if requested) is not awaited.
+// Use Ping to measure the round-trip time.
+func (s *Service) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) error {
+	if msg.Type() != PingMsgType {
+		return fmt.Errorf("unsupported outbound msgType: %s", msg.Type())
+	}
+
+	return s.outbound.SendToDID(msg, myDID, theirDID)
+}
+
+// Accept checks whether the service can handle the message type.
+func (s *Service) Accept(msgType string) bool {
+	return msgType == PingMsgType || msgType == PingResponseMsgType
+}
+
+// MessageTypes returns the message types supported by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{PingMsgType, PingResponseMsgType}
+}
+
+// Name of the service
+func (s *Service) Name() string {
+	return TrustPing
+}
+
+func (s *Service) handlePing(msg service.DIDCommMsg, myDID, theirDID string) error {
+	// unmarshal the payload
+	ping := &Ping{}
+
+	err := msg.Decode(ping)
+	if err != nil {
+		return fmt.Errorf("ping message unmarshal : %w", err)
+	}
+
+	// the response is requested by default
+	if ping.ResponseRequested != nil && !*ping.ResponseRequested {
+		return nil
+	}
+
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return fmt.Errorf("ping message threadID : %w", err)
+	}
+
+	response := &PingResponse{
+		Type:   PingResponseMsgType,
+		ID:     uuid.New().String(),
+		Thread: &decorator.Thread{ID: thID},
+	}
+
+	return s.outbound.SendToDID(response, myDID, theirDID)
+}
+
+func (s *Service) handlePingResponse(msg service.DIDCommMsg) error {
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return fmt.Errorf("ping response message threadID : %w", err)
+	}
+
+	// check if there are any channels registered for the ping
+	pingCh := s.getPingCh(thID)
+
+	if pingCh != nil {
+		// the channel is buffered, the response is dropped if the ping was already answered
+		select {
+		case pingCh <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Ping sends a ping to the other end of the connection identified by connectionID. This method blocks until
+// a response is received or it times out, the round-trip time is returned.
+func (s *Service) Ping(connectionID string, timeout time.Duration) (time.Duration, error) {
+	// get the connection record for the ID to fetch DID information
+	conn, err := s.getConnection(connectionID)
+	if err != nil {
+		return 0, err
+	}
+
+	// generate message ID
+	msgID := uuid.New().String()
+
+	// register chan for callback processing
+	pingCh := make(chan struct{}, 1)
+	s.setPingCh(msgID, pingCh)
+
+	// remove the channel once its been processed
+	defer s.setPingCh(msgID, nil)
+
+	responseRequested := true
+
+	ping := &Ping{
+		ID:                msgID,
+		Type:              PingMsgType,
+		ResponseRequested: &responseRequested,
+	}
+
+	start := time.Now()
+
+	if err := s.outbound.SendToDID(ping, conn.MyDID, conn.TheirDID); err != nil {
+		return 0, fmt.Errorf("send ping : %w", err)
+	}
+
+	// callback processing (to make this function look like a sync function)
+	select {
+	case <-pingCh:
+		return time.Since(start), nil
+	case <-time.After(timeout):
+		return 0, ErrPingTimeout
+	}
+}
+
+func (s *Service) getPingCh(msgID string) chan struct{} {
+	s.pingMapLock.RLock()
+	defer s.pingMapLock.RUnlock()
+
+	return s.pingMap[msgID]
+}
+
+func (s *Service) setPingCh(msgID string, pingCh chan struct{}) {
+	s.pingMapLock.Lock()
+	defer s.pingMapLock.Unlock()
+
+	if pingCh == nil {
+		delete(s.pingMap, msgID)
+	} else {
+		s.pingMap[msgID] = pingCh
+	}
+}
+
+func (s *Service) getConnection(connectionID string) (*connection.Record, error) {
+	conn, err := s.connectionLookup.GetConnectionRecord(connectionID)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, ErrConnectionNotFound
+		}
+
+		return nil, fmt.Errorf("fetch connection record from store : %w", err)
+	}
+
+	return conn, nil
+}
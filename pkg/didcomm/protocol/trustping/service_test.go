@@ -0,0 +1,263 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/dispatcher"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/internal/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+const (
+	MYDID    = "myDID"
+	THEIRDID = "theirDID"
+)
+
+// this line checks that Service satisfies ProtocolService interface
+var _ ProtocolService = &Service{}
+
+func TestServiceNew(t *testing.T) {
+	t.Run("test new service - success", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:          mockstore.NewMockStoreProvider(),
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, TrustPing, svc.Name())
+	})
+
+	t.Run("test new service - failure", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue: &mockstore.MockStoreProvider{
+				ErrOpenStoreHandle: errors.New("error opening the store")},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error opening the store")
+		require.Nil(t, svc)
+	})
+}
+
+func TestServiceAccept(t *testing.T) {
+	s := &Service{}
+
+	require.True(t, s.Accept(PingMsgType))
+	require.True(t, s.Accept(PingResponseMsgType))
+	require.False(t, s.Accept("unsupported msg type"))
+	require.Equal(t, []string{PingMsgType, PingResponseMsgType}, s.MessageTypes())
+}
+
+func TestServiceHandleInbound(t *testing.T) {
+	t.Run("test handle ping - response is sent", func(t *testing.T) {
+		sent := make(chan *PingResponse, 1)
+
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				require.Equal(t, MYDID, myDID)
+				require.Equal(t, THEIRDID, theirDID)
+
+				response, ok := msg.(*PingResponse)
+				require.True(t, ok)
+
+				sent <- response
+
+				return nil
+			}})
+
+		id, err := svc.HandleInbound(service.NewDIDCommMsgMap(&Ping{Type: PingMsgType, ID: "ping-1"}), MYDID, THEIRDID)
+		require.NoError(t, err)
+		require.Equal(t, "ping-1", id)
+
+		response := <-sent
+		require.Equal(t, PingResponseMsgType, response.Type)
+		require.NotEmpty(t, response.ID)
+		require.Equal(t, "ping-1", response.Thread.ID)
+	})
+
+	t.Run("test handle ping - response is not requested", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				return errors.New("unexpected response")
+			}})
+
+		responseRequested := false
+
+		_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&Ping{
+			Type:              PingMsgType,
+			ID:                "ping-1",
+			ResponseRequested: &responseRequested,
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+	})
+
+	t.Run("test handle ping - send error", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{SendErr: errors.New("send error")})
+
+		_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&Ping{Type: PingMsgType, ID: "ping-1"}), MYDID, THEIRDID)
+		require.EqualError(t, err, "handle ping : send error")
+	})
+
+	t.Run("test handle ping - decode error", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{})
+
+		_, err := svc.HandleInbound(service.DIDCommMsgMap{
+			"@type": PingMsgType, "@id": "ping-1", "response_requested": "yes",
+		}, MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ping message unmarshal")
+	})
+
+	t.Run("test handle ping response - no pending ping", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{})
+
+		_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&PingResponse{
+			Type:   PingResponseMsgType,
+			ID:     "response-1",
+			Thread: &decorator.Thread{ID: "ping-1"},
+		}), MYDID, THEIRDID)
+		require.NoError(t, err)
+	})
+
+	t.Run("test handle - unrecognized msgType", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{})
+
+		_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&Ping{Type: "unknown", ID: "ping-1"}), MYDID, THEIRDID)
+		require.EqualError(t, err, "unrecognized msgType: unknown")
+	})
+}
+
+func TestServiceHandleOutbound(t *testing.T) {
+	t.Run("test handle outbound - ping is sent", func(t *testing.T) {
+		sent := false
+
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				require.Equal(t, MYDID, myDID)
+				require.Equal(t, THEIRDID, theirDID)
+
+				sent = true
+
+				return nil
+			}})
+
+		require.NoError(t, svc.HandleOutbound(service.NewDIDCommMsgMap(&Ping{Type: PingMsgType}), MYDID, THEIRDID))
+		require.True(t, sent)
+	})
+
+	t.Run("test handle outbound - unsupported msgType", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{})
+
+		err := svc.HandleOutbound(service.NewDIDCommMsgMap(&PingResponse{Type: PingResponseMsgType}), MYDID, THEIRDID)
+		require.EqualError(t, err, "unsupported outbound msgType: "+PingResponseMsgType)
+	})
+}
+
+func TestServicePing(t *testing.T) {
+	t.Run("test ping - success", func(t *testing.T) {
+		var svc *Service
+
+		svc = newService(t, map[string][]byte{"conn_conn1": connectionRecord(t)}, &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				require.Equal(t, MYDID, myDID)
+				require.Equal(t, THEIRDID, theirDID)
+
+				ping, ok := msg.(*Ping)
+				require.True(t, ok)
+				require.True(t, *ping.ResponseRequested)
+
+				// the other agent responds to the ping
+				go func() {
+					_, err := svc.HandleInbound(service.NewDIDCommMsgMap(&PingResponse{
+						Type:   PingResponseMsgType,
+						ID:     "response-1",
+						Thread: &decorator.Thread{ID: ping.ID},
+					}), MYDID, THEIRDID)
+					require.NoError(t, err)
+				}()
+
+				return nil
+			}})
+
+		rtt, err := svc.Ping("conn1", time.Second)
+		require.NoError(t, err)
+		require.True(t, rtt > 0)
+		require.Empty(t, svc.pingMap)
+	})
+
+	t.Run("test ping - timeout", func(t *testing.T) {
+		svc := newService(t, map[string][]byte{"conn_conn1": connectionRecord(t)}, &mockdispatcher.MockOutbound{})
+
+		rtt, err := svc.Ping("conn1", time.Millisecond)
+		require.True(t, errors.Is(err, ErrPingTimeout))
+		require.Zero(t, rtt)
+		require.Empty(t, svc.pingMap)
+	})
+
+	t.Run("test ping - send error", func(t *testing.T) {
+		svc := newService(t, map[string][]byte{"conn_conn1": connectionRecord(t)}, &mockdispatcher.MockOutbound{
+			SendErr: errors.New("send error"),
+		})
+
+		_, err := svc.Ping("conn1", time.Second)
+		require.EqualError(t, err, "send ping : send error")
+	})
+
+	t.Run("test ping - connection not found", func(t *testing.T) {
+		svc := newService(t, nil, &mockdispatcher.MockOutbound{})
+
+		_, err := svc.Ping("conn1", time.Second)
+		require.True(t, errors.Is(err, ErrConnectionNotFound))
+	})
+
+	t.Run("test ping - connection store error", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue: &mockstore.MockStoreProvider{Store: &mockstore.MockStore{
+				Store:  make(map[string][]byte),
+				ErrGet: errors.New("get error"),
+			}},
+			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.NoError(t, err)
+
+		_, err = svc.Ping("conn1", time.Second)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "fetch connection record from store")
+	})
+}
+
+func newService(t *testing.T, s map[string][]byte, outbound *mockdispatcher.MockOutbound) *Service {
+	if s == nil {
+		s = make(map[string][]byte)
+	}
+
+	svc, err := New(&mockprovider.Provider{
+		StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
+		TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
+		OutboundDispatcherValue:       outbound,
+	})
+	require.NoError(t, err)
+
+	return svc
+}
+
+func connectionRecord(t *testing.T) []byte {
+	connBytes, err := json.Marshal(&connection.Record{
+		ConnectionID: "conn1", MyDID: MYDID, TheirDID: THEIRDID, State: "completed"})
+	require.NoError(t, err)
+
+	return connBytes
+}
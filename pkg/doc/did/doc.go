@@ -82,6 +82,19 @@ const (
         ]
       }
     },
+    "keyAgreement": {
+      "type": "array",
+      "items": {
+        "oneOf": [
+          {
+            "$ref": "#/definitions/publicKey"
+          },
+          {
+            "type": "string"
+          }
+        ]
+      }
+    },
     "service": {
       "type": "array",
       "items": {
@@ -185,6 +198,8 @@ type Doc struct {
 	Created        *time.Time
 	Updated        *time.Time
 	Proof          []Proof
+	// omitted when empty to keep the identifiers computed from the document (e.g. peer DID) unchanged
+	KeyAgreement []VerificationMethod `json:",omitempty"`
 }
 
 // PublicKey DID doc public key
@@ -217,6 +232,7 @@ type rawDoc struct {
 	PublicKey      []map[string]interface{} `json:"publicKey,omitempty"`
 	Service        []map[string]interface{} `json:"service,omitempty"`
 	Authentication []interface{}            `json:"authentication,omitempty"`
+	KeyAgreement   []interface{}            `json:"keyAgreement,omitempty"`
 	Created        *time.Time               `json:"created,omitempty"`
 	Updated        *time.Time               `json:"updated,omitempty"`
 	Proof          []interface{}            `json:"proof,omitempty"`
@@ -251,11 +267,16 @@ func ParseDocument(data []byte) (*Doc, error) {
 		return nil, fmt.Errorf("populate public keys failed: %w", err)
 	}
 
-	authPKs, err := populateAuthentications(raw.Authentication, publicKeys)
+	authPKs, err := populateVerificationMethods(raw.Authentication, publicKeys, "authentication")
 	if err != nil {
 		return nil, fmt.Errorf("populate authentications failed: %w", err)
 	}
 
+	keyAgreementPKs, err := populateVerificationMethods(raw.KeyAgreement, publicKeys, "keyAgreement")
+	if err != nil {
+		return nil, fmt.Errorf("populate key agreements failed: %w", err)
+	}
+
 	proofs, err := populateProofs(raw.Proof)
 	if err != nil {
 		return nil, fmt.Errorf("populate proofs failed: %w", err)
@@ -266,6 +287,7 @@ func ParseDocument(data []byte) (*Doc, error) {
 		PublicKey:      publicKeys,
 		Service:        populateServices(raw.Service),
 		Authentication: authPKs,
+		KeyAgreement:   keyAgreementPKs,
 		Created:        raw.Created,
 		Updated:        raw.Updated,
 		Proof:          proofs,
@@ -338,11 +360,14 @@ func populateServices(rawServices []map[string]interface{}) []Service {
 	return services
 }
 
-func populateAuthentications(rawAuthentications []interface{}, pks []PublicKey) ([]VerificationMethod, error) {
+// populateVerificationMethods populates the verification methods of the relationship (e.g. authentication),
+// each one is either the reference to the public key of the document or the embedded public key.
+func populateVerificationMethods(rawVMs []interface{}, pks []PublicKey,
+	relationship string) ([]VerificationMethod, error) {
 	var vms []VerificationMethod
 
-	for _, rawAuthentication := range rawAuthentications {
-		valueString, ok := rawAuthentication.(string)
+	for _, rawVM := range rawVMs {
+		valueString, ok := rawVM.(string)
 		if ok {
 			keyExist := false
 
@@ -356,15 +381,15 @@ func populateAuthentications(rawAuthentications []interface{}, pks []PublicKey)
 			}
 
 			if !keyExist {
-				return nil, fmt.Errorf("authentication key %s not exist in did doc public key", valueString)
+				return nil, fmt.Errorf("%s key %s not exist in did doc public key", relationship, valueString)
 			}
 
 			continue
 		}
 
-		valuePK, ok := rawAuthentication.(map[string]interface{})
+		valuePK, ok := rawVM.(map[string]interface{})
 		if !ok {
-			return nil, errors.New("rawAuthentication is not map[string]interface{}")
+			return nil, fmt.Errorf("raw %s is not map[string]interface{}", relationship)
 		}
 
 		pk, err := populatePublicKeys([]map[string]interface{}{valuePK})
@@ -488,7 +513,8 @@ func (doc *Doc) JSONBytes() ([]byte, error) {
 		Context:        doc.Context,
 		ID:             doc.ID,
 		PublicKey:      populateRawPublicKeys(doc.PublicKey),
-		Authentication: populateRawAuthentications(doc.Authentication),
+		Authentication: populateRawVerificationMethods(doc.Authentication),
+		KeyAgreement:   populateRawVerificationMethods(doc.KeyAgreement),
 		Service:        populateRawServices(doc.Service),
 		Created:        doc.Created,
 		Proof:          populateRawProofs(doc.Proof),
@@ -585,14 +611,14 @@ func populateRawPublicKey(pk PublicKey) map[string]interface{} {
 	return rawPK
 }
 
-func populateRawAuthentications(vms []VerificationMethod) []interface{} {
-	var rawAuthentications []interface{}
+func populateRawVerificationMethods(vms []VerificationMethod) []interface{} {
+	var rawVMs []interface{}
 
 	for _, vm := range vms {
-		rawAuthentications = append(rawAuthentications, populateRawPublicKey(vm.PublicKey))
+		rawVMs = append(rawVMs, populateRawPublicKey(vm.PublicKey))
 	}
 
-	return rawAuthentications
+	return rawVMs
 }
 
 func populateRawProofs(proofs []Proof) []interface{} {
@@ -628,6 +654,13 @@ func WithAuthentication(auth []VerificationMethod) DocOption {
 	}
 }
 
+// WithKeyAgreement DID doc KeyAgreement.
+func WithKeyAgreement(keyAgreement []VerificationMethod) DocOption {
+	return func(opts *Doc) {
+		opts.KeyAgreement = keyAgreement
+	}
+}
+
 // WithService DID doc services.
 func WithService(svc []Service) DocOption {
 	return func(opts *Doc) {
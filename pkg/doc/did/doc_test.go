@@ -224,6 +224,51 @@ func TestPopulateAuthentications(t *testing.T) {
 	})
 }
 
+func TestPopulateKeyAgreements(t *testing.T) {
+	raw := &rawDoc{}
+	require.NoError(t, json.Unmarshal([]byte(validDoc), &raw))
+
+	t.Run("test key agreement - referenced and embedded keys", func(t *testing.T) {
+		raw.KeyAgreement = []interface{}{
+			"did:example:123456789abcdefghi#keys-1",
+			map[string]interface{}{
+				"id":              "did:example:123456789abcdefghi#keys-2",
+				"type":            "X25519KeyAgreementKey2019",
+				"controller":      "did:example:123456789abcdefghi",
+				"publicKeyBase58": "JhNWeSVLMYccCk7iopQW4guaSJTojqpMEELgSLhKwRr",
+			},
+		}
+		bytes, err := json.Marshal(raw)
+		require.NoError(t, err)
+
+		doc, err := ParseDocument(bytes)
+		require.NoError(t, err)
+		require.Len(t, doc.KeyAgreement, 2)
+		require.Equal(t, doc.PublicKey[0], doc.KeyAgreement[0].PublicKey)
+		require.Equal(t, "X25519KeyAgreementKey2019", doc.KeyAgreement[1].PublicKey.Type)
+		require.Equal(t, base58.Decode("JhNWeSVLMYccCk7iopQW4guaSJTojqpMEELgSLhKwRr"),
+			doc.KeyAgreement[1].PublicKey.Value)
+
+		bytes, err = doc.JSONBytes()
+		require.NoError(t, err)
+
+		parsed, err := ParseDocument(bytes)
+		require.NoError(t, err)
+		require.Equal(t, doc.KeyAgreement, parsed.KeyAgreement)
+	})
+
+	t.Run("test key agreement - key not exist", func(t *testing.T) {
+		raw.KeyAgreement = []interface{}{"did:example:123456789abcdefghs#key4"}
+		bytes, err := json.Marshal(raw)
+		require.NoError(t, err)
+
+		_, err = ParseDocument(bytes)
+		require.Error(t, err)
+		require.Contains(t, err.Error(),
+			"keyAgreement key did:example:123456789abcdefghs#key4 not exist in did doc public key")
+	})
+}
+
 func TestPublicKeys(t *testing.T) {
 	t.Run("test failed to decode PEM block", func(t *testing.T) {
 		raw := &rawDoc{}
@@ -5,24 +5,35 @@ SPDX-License-Identifier: Apache-2.0
 
 package did
 
+import "sort"
+
 // LookupService returns the service from the given DIDDoc matching the given service type.
+// The service with the highest priority (the lowest priority value) is returned.
 func LookupService(didDoc *Doc, serviceType string) (*Service, bool) {
-	const notFound = -1
-	index := notFound
-
-	for i, s := range didDoc.Service {
-		if s.Type == serviceType {
-			if index == notFound || didDoc.Service[index].Priority > s.Priority {
-				index = i
-			}
-		}
+	services := LookupServices(didDoc, serviceType)
+	if len(services) == 0 {
+		return nil, false
 	}
 
-	if index == notFound {
-		return nil, false
+	return services[0], true
+}
+
+// LookupServices returns the services from the given DIDDoc matching the given service type sorted by
+// priority (the lowest priority value first). The services with the same priority keep the DIDDoc order.
+func LookupServices(didDoc *Doc, serviceType string) []*Service {
+	var services []*Service
+
+	for i := range didDoc.Service {
+		if didDoc.Service[i].Type == serviceType {
+			services = append(services, &didDoc.Service[i])
+		}
 	}
 
-	return &didDoc.Service[index], true
+	sort.SliceStable(services, func(i, j int) bool {
+		return services[i].Priority < services[j].Priority
+	})
+
+	return services
 }
 
 // LookupRecipientKeys gets the recipient keys from the did doc which match the given parameters.
@@ -32,13 +43,18 @@ func LookupRecipientKeys(didDoc *Doc, serviceType, keyType string) ([]string, bo
 		return nil, false
 	}
 
-	if len(didCommService.RecipientKeys) == 0 {
+	return LookupServiceRecipientKeys(didDoc, didCommService, keyType)
+}
+
+// LookupServiceRecipientKeys gets the recipient keys of the given service which match the given key type.
+func LookupServiceRecipientKeys(didDoc *Doc, service *Service, keyType string) ([]string, bool) {
+	if len(service.RecipientKeys) == 0 {
 		return nil, false
 	}
 
 	var recipientKeys []string
 
-	for _, keyID := range didCommService.RecipientKeys {
+	for _, keyID := range service.RecipientKeys {
 		key, ok := LookupPublicKey(keyID, didDoc)
 		if !ok {
 			return nil, false
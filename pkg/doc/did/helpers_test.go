@@ -94,3 +94,48 @@ func TestGetDidCommService(t *testing.T) {
 		require.Nil(t, s)
 	})
 }
+
+func TestLookupServices(t *testing.T) {
+	didCommServiceType := "did-communication"
+
+	t.Run("successfully getting services sorted by priority", func(t *testing.T) {
+		didDoc := mockdiddoc.GetMockDIDDoc()
+		didDoc.Service = []Service{
+			{ID: "s1", Type: didCommServiceType, Priority: 2},
+			{ID: "s2", Type: "other-type", Priority: 0},
+			{ID: "s3", Type: didCommServiceType, Priority: 1},
+			{ID: "s4", Type: didCommServiceType, Priority: 2},
+		}
+
+		services := LookupServices(didDoc, didCommServiceType)
+		require.Len(t, services, 3)
+		require.Equal(t, "s3", services[0].ID)
+		require.Equal(t, "s1", services[1].ID)
+		require.Equal(t, "s4", services[2].ID)
+
+		s, ok := LookupService(didDoc, didCommServiceType)
+		require.True(t, ok)
+		require.Equal(t, "s3", s.ID)
+	})
+
+	t.Run("no services", func(t *testing.T) {
+		didDoc := mockdiddoc.GetMockDIDDoc()
+		didDoc.Service = nil
+
+		require.Empty(t, LookupServices(didDoc, didCommServiceType))
+	})
+}
+
+func TestLookupServiceRecipientKeys(t *testing.T) {
+	ed25519KeyType := "Ed25519VerificationKey2018"
+	didDoc := mockdiddoc.GetMockDIDDoc()
+
+	recipientKeys, ok := LookupServiceRecipientKeys(didDoc, &didDoc.Service[0], ed25519KeyType)
+	require.True(t, ok)
+	require.Len(t, recipientKeys, 1)
+
+	// the service has only the keys of the other types
+	recipientKeys, ok = LookupServiceRecipientKeys(didDoc, &didDoc.Service[1], ed25519KeyType)
+	require.False(t, ok)
+	require.Nil(t, recipientKeys)
+}
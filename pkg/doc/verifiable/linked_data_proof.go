@@ -6,6 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 package verifiable
 
 import (
+	"crypto/ed25519"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -54,12 +55,14 @@ func (k *keyResolverAdapter) Resolve(id string) ([]byte, error) {
 		return nil, err
 	}
 
-	pubKeyBytes, ok := fetcher.([]byte)
-	if !ok {
+	switch pubKey := fetcher.(type) {
+	case []byte:
+		return pubKey, nil
+	case ed25519.PublicKey:
+		return pubKey, nil
+	default:
 		return nil, errors.New("expecting []byte public key, got something else")
 	}
-
-	return pubKeyBytes, nil
 }
 
 // LinkedDataProofContext holds options needed to build a Linked Data Proof.
@@ -25,6 +25,15 @@ func Test_keyResolverAdapter_Resolve(t *testing.T) {
 		require.Equal(t, []byte(pubKey), resolvedPubKey)
 	})
 
+	t.Run("successful ed25519 public key resolving", func(t *testing.T) {
+		pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		kra := &keyResolverAdapter{pubKeyFetcher: SingleKey(pubKey)}
+		resolvedPubKey, err := kra.Resolve("any")
+		require.NoError(t, err)
+		require.Equal(t, []byte(pubKey), resolvedPubKey)
+	})
+
 	t.Run("error at public key resolving (e.g. not found)", func(t *testing.T) {
 		kra := &keyResolverAdapter{pubKeyFetcher: func(issuerID, keyID string) (interface{}, error) {
 			return nil, errors.New("no key found")
@@ -0,0 +1,79 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+)
+
+const ed25519KeyType = "Ed25519VerificationKey2018"
+
+// didResolver resolves DID documents, e.g. vdri.Registry.
+type didResolver interface {
+	Resolve(did string, opts ...vdriapi.ResolveOpts) (*did.Doc, error)
+}
+
+// DIDKeyResolver resolves DID in order to find public keys for VC/VP verification using vdri.Registry.
+// A source of DID could be issuer of VC or holder of VP. It can be also obtained from
+// JWS "kid" header or embedded proof "verificationMethod".
+type DIDKeyResolver struct {
+	vdriRegistry didResolver
+}
+
+// NewDIDKeyResolver creates DIDKeyResolver.
+func NewDIDKeyResolver(vdriRegistry didResolver) *DIDKeyResolver {
+	return &DIDKeyResolver{vdriRegistry: vdriRegistry}
+}
+
+// PublicKeyFetcher returns Public Key Fetcher via DID resolution mechanism.
+// Ed25519 keys are returned as ed25519.PublicKey, other keys are returned as raw bytes.
+func (r *DIDKeyResolver) PublicKeyFetcher() PublicKeyFetcher {
+	return r.resolvePublicKey
+}
+
+func (r *DIDKeyResolver) resolvePublicKey(issuerID, keyID string) (interface{}, error) {
+	didID := issuerID
+
+	// embedded proofs provide the key ID only, e.g. did:example:123#key-1
+	if didID == "" {
+		didID = strings.Split(keyID, "#")[0]
+	}
+
+	doc, err := r.vdriRegistry.Resolve(didID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve DID %s: %w", didID, err)
+	}
+
+	for _, key := range doc.PublicKey {
+		if keyFragment(key.ID) != keyFragment(keyID) {
+			continue
+		}
+
+		if key.Type == ed25519KeyType {
+			return ed25519.PublicKey(key.Value), nil
+		}
+
+		return key.Value, nil
+	}
+
+	return nil, fmt.Errorf("public key with KID %s is not found for DID %s", keyID, didID)
+}
+
+// keyFragment returns the fragment of the key ID, key IDs may be absolute (did:example:123#key-1)
+// or relative (#key-1)
+func keyFragment(keyID string) string {
+	if i := strings.LastIndex(keyID, "#"); i >= 0 {
+		return keyID[i+1:]
+	}
+
+	return keyID
+}
@@ -0,0 +1,93 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+)
+
+type mockResolver struct {
+	doc *did.Doc
+	err error
+}
+
+func (r *mockResolver) Resolve(_ string, _ ...vdriapi.ResolveOpts) (*did.Doc, error) {
+	return r.doc, r.err
+}
+
+func TestDIDKeyResolver_PublicKeyFetcher(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	const issuerDID = "did:example:76e12ec712ebc6f1c221ebfeb1f"
+
+	doc := &did.Doc{
+		ID: issuerDID,
+		PublicKey: []did.PublicKey{
+			{ID: issuerDID + "#keys-1", Type: ed25519KeyType, Controller: issuerDID, Value: pubKey},
+			{ID: "#keys-2", Type: "Secp256k1VerificationKey2018", Controller: issuerDID, Value: []byte("secp256k1 key")},
+		},
+	}
+
+	fetcher := NewDIDKeyResolver(&mockResolver{doc: doc}).PublicKeyFetcher()
+
+	t.Run("fetch ed25519 key", func(t *testing.T) {
+		key, err := fetcher(issuerDID, "#keys-1")
+		require.NoError(t, err)
+		require.Equal(t, pubKey, key)
+	})
+
+	t.Run("fetch key of embedded proof", func(t *testing.T) {
+		key, err := fetcher("", issuerDID+"#keys-2")
+		require.NoError(t, err)
+		require.Equal(t, []byte("secp256k1 key"), key)
+	})
+
+	t.Run("key not found", func(t *testing.T) {
+		key, err := fetcher(issuerDID, "#keys-3")
+		require.EqualError(t, err, "public key with KID #keys-3 is not found for DID "+issuerDID)
+		require.Nil(t, key)
+	})
+
+	t.Run("resolve error", func(t *testing.T) {
+		fetcher := NewDIDKeyResolver(&mockResolver{err: errors.New("resolve error")}).PublicKeyFetcher()
+
+		key, err := fetcher(issuerDID, "#keys-1")
+		require.EqualError(t, err, "resolve DID "+issuerDID+": resolve error")
+		require.Nil(t, key)
+	})
+
+	t.Run("verify credential JWS", func(t *testing.T) {
+		vc, _, err := NewCredential([]byte(`{
+  "@context": ["https://www.w3.org/2018/credentials/v1"],
+  "id": "http://example.edu/credentials/1872",
+  "type": "VerifiableCredential",
+  "credentialSubject": {"id": "did:example:ebfeb1f712ebc6f1c276e12ec21"},
+  "issuer": "did:example:76e12ec712ebc6f1c221ebfeb1f",
+  "issuanceDate": "2010-01-01T19:23:24Z"
+}`))
+		require.NoError(t, err)
+
+		jwtClaims, err := vc.JWTClaims(false)
+		require.NoError(t, err)
+
+		vcJWS, err := jwtClaims.MarshalJWS(EdDSA, privKey, issuerDID+"#keys-1")
+		require.NoError(t, err)
+
+		vcFromJWS, _, err := NewCredential([]byte(vcJWS), WithPublicKeyFetcher(fetcher))
+		require.NoError(t, err)
+		require.Equal(t, vc, vcFromJWS)
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	didcommtransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
@@ -26,11 +27,14 @@ type Provider interface {
 	OutboundDispatcher() dispatcher.Outbound
 	Messenger() service.Messenger
 	Service(id string) (interface{}, error)
+	ProtocolServices() []dispatcher.ProtocolService
+	MessageServiceProvider() MessageServiceProvider
 	StorageProvider() storage.Provider
 	LegacyKMS() legacykms.KeyManager
 	Crypto() crypto.Crypto
 	Packager() transport.Packager
 	InboundTransportEndpoint() string
+	InboundMessageHandler() didcommtransport.InboundMessageHandler
 	VDRIRegistry() vdriapi.Registry
 	Signer() legacykms.Signer
 	TransientStorageProvider() storage.Provider
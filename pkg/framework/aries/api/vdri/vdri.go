@@ -12,17 +12,22 @@ import (
 	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
 )
 
 // ErrNotFound is returned when a DID resolver does not find the DID.
 var ErrNotFound = errors.New("DID not found")
 
+// ErrDeactivated is returned when a DID resolver finds the DID deactivated.
+var ErrDeactivated = errors.New("DID is deactivated")
+
 // DIDCommServiceType default DID Communication service endpoint type
 const DIDCommServiceType = "did-communication"
 
 // Registry vdri registry
 type Registry interface {
 	Resolve(did string, opts ...ResolveOpts) (*did.Doc, error)
+	ResolveWithMetadata(did string, opts ...ResolveOpts) (*DocResolution, error)
 	Store(doc *did.Doc) error
 	Create(method string, opts ...DocOpts) (*did.Doc, error)
 	Close() error
@@ -37,6 +42,50 @@ type VDRI interface {
 	Close() error
 }
 
+// ResolutionReader is implemented by VDRI which reads the DID resolution result along with the document metadata.
+// The registry derives the metadata from the DID document for VDRI which does not implement it.
+type ResolutionReader interface {
+	ReadResolution(did string, opts ...ResolveOpts) (*DocResolution, error)
+}
+
+// ErrUpdateNotSupported is returned when the VDRI of the DID method does not support updates.
+var ErrUpdateNotSupported = errors.New("DID method does not support updates")
+
+// Updater is implemented by VDRI which supports signed updates of its DIDs, the updates are authorized with the
+// authentication key (keyID) of the current version of the DID document.
+// The registries implement it to invalidate the DID documents they cache.
+type Updater interface {
+	Update(doc *did.Doc, keyID string, signer legacykms.Signer) error
+	RotateKey(didID, oldKeyID string, newKey *did.PublicKey, keyID string, signer legacykms.Signer) error
+	Deactivate(didID, keyID string, signer legacykms.Signer) error
+}
+
+// DocResolution DID resolution result (https://w3c-ccg.github.io/did-resolution/#did-resolution-result)
+type DocResolution struct {
+	DIDDocument      *did.Doc
+	DocumentMetadata *DocumentMetadata
+	ResolverMetadata *ResolverMetadata
+}
+
+// DocumentMetadata metadata about the resolved DID document
+type DocumentMetadata struct {
+	Created     *time.Time
+	Updated     *time.Time
+	Deactivated bool
+	VersionID   string
+	// MethodMetadata method specific metadata (e.g. number of the peer DID document versions)
+	MethodMetadata map[string]interface{}
+}
+
+// ResolverMetadata metadata about the DID resolution process
+type ResolverMetadata struct {
+	ContentType string
+	Duration    time.Duration
+}
+
+// ContentTypeDIDLDJSON is the content type of the DID document representation
+const ContentTypeDIDLDJSON = "application/did+ld+json"
+
 // ResultType input option can be used to request a certain type of result.
 type ResultType int
 
@@ -10,21 +10,34 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packager"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/jwe/anoncrypt"
 	jwe "github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/jwe/authcrypt"
 	legacy "github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/legacy/authcrypt"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/discoverfeatures"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/introduce"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/messagepickup"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/route"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/trustping"
 	arieshttp "github.com/hyperledger/aries-framework-go/pkg/didcomm/transport/http"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
 )
 
+var logger = log.New("aries-framework/framework")
+
 // defFrameworkOpts provides default framework options
 func defFrameworkOpts(frameworkOpts *Aries) error {
 	// TODO https://github.com/hyperledger/aries-framework-go/issues/209 Move default providers to the sub-package
@@ -46,9 +59,11 @@ func defFrameworkOpts(frameworkOpts *Aries) error {
 		frameworkOpts.storeProvider = storeProv
 	}
 
-	// order is important as DIDExchange service depends on Route service and Introduce depends on DIDExchange
+	// order is important as DIDExchange service depends on Route service, Introduce depends on DIDExchange
+	// and OutOfBand depends on DIDExchange
 	frameworkOpts.protocolSvcCreators = append(frameworkOpts.protocolSvcCreators,
-		newRouteSvc(), newExchangeSvc(), newIntroduceSvc())
+		newRouteSvc(), newExchangeSvc(), newIntroduceSvc(), newIssueCredentialSvc(), newPresentProofSvc(),
+		newTrustPingSvc(), newDiscoverFeaturesSvc(), newMessagePickupSvc(), newOutOfBandSvc())
 
 	return setAdditionalDefaultOpts(frameworkOpts)
 }
@@ -65,6 +80,42 @@ func newIntroduceSvc() api.ProtocolSvcCreator {
 	}
 }
 
+func newOutOfBandSvc() api.ProtocolSvcCreator {
+	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
+		return outofband.New(prv)
+	}
+}
+
+func newIssueCredentialSvc() api.ProtocolSvcCreator {
+	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
+		return issuecredential.New(prv)
+	}
+}
+
+func newPresentProofSvc() api.ProtocolSvcCreator {
+	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
+		return presentproof.New(prv)
+	}
+}
+
+func newTrustPingSvc() api.ProtocolSvcCreator {
+	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
+		return trustping.New(prv)
+	}
+}
+
+func newDiscoverFeaturesSvc() api.ProtocolSvcCreator {
+	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
+		return discoverfeatures.New(prv)
+	}
+}
+
+func newMessagePickupSvc() api.ProtocolSvcCreator {
+	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
+		return messagepickup.New(prv)
+	}
+}
+
 func newRouteSvc() api.ProtocolSvcCreator {
 	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
 		return route.New(prv)
@@ -78,6 +129,20 @@ func setAdditionalDefaultOpts(frameworkOpts *Aries) error {
 		}
 	}
 
+	if frameworkOpts.secretLock == nil {
+		// keys are stored unprotected unless a secret lock is passed in frameworkOpts
+		logger.Warnf("no secret lock set with WithSecretLock: KMS keys are stored in plaintext, " +
+			"this is not safe for production")
+
+		frameworkOpts.secretLock = &noop.NoLock{}
+	}
+
+	if frameworkOpts.keyManagerCreator == nil {
+		frameworkOpts.keyManagerCreator = func(provider kms.Provider) (kms.KeyManager, error) {
+			return localkms.New(localkms.PrimaryKeyURI, provider)
+		}
+	}
+
 	if frameworkOpts.crypto == nil {
 		// create default tink crypto if not passed in frameworkOpts
 		cr, err := tinkcrypto.New()
@@ -100,6 +165,9 @@ func setAdditionalDefaultOpts(frameworkOpts *Aries) error {
 			func(provider packer.Provider) (packer.Packer, error) {
 				return jwe.New(provider, jwe.XC20P)
 			},
+			func(provider packer.Provider) (packer.Packer, error) {
+				return anoncrypt.New(provider)
+			},
 		}
 	}
 
@@ -8,6 +8,7 @@ package aries
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/messenger"
 
@@ -24,8 +25,11 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/context"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 	"github.com/hyperledger/aries-framework-go/pkg/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/vdri/key"
 	"github.com/hyperledger/aries-framework-go/pkg/vdri/peer"
 )
 
@@ -49,6 +53,9 @@ type Aries struct {
 	inboundTransport       transport.InboundTransport
 	kmsCreator             api.KMSCreator
 	kms                    api.CloseableKMS
+	keyManagerCreator      kms.Creator
+	keyManager             kms.KeyManager
+	secretLock             secretlock.Service
 	crypto                 crypto.Crypto
 	packagerCreator        packager.Creator
 	packager               commontransport.Packager
@@ -58,7 +65,10 @@ type Aries struct {
 	packers                []packer.Packer
 	vdriRegistry           vdriapi.Registry
 	vdri                   []vdriapi.VDRI
+	vdriCacheOpts          []vdri.CacheOption
+	vdriCache              bool
 	transportReturnRoute   string
+	outboundRetryPolicy    *dispatcher.RetryPolicy
 	id                     string
 }
 
@@ -178,6 +188,21 @@ func WithTransportReturnRoute(transportReturnRoute string) Option {
 	}
 }
 
+// WithOutboundRetryPolicy injects the redelivery policy of the outbound messages into the Aries framework.
+// The messages which failed to be sent are persisted and redelivered with exponential backoff,
+// dispatcher.DefaultRetryPolicy() provides the default values.
+func WithOutboundRetryPolicy(policy *dispatcher.RetryPolicy) Option {
+	return func(opts *Aries) error {
+		if policy != nil && policy.MaxAttempts < 1 {
+			return fmt.Errorf("invalid outbound retry policy : max attempts %d", policy.MaxAttempts)
+		}
+
+		opts.outboundRetryPolicy = policy
+
+		return nil
+	}
+}
+
 // WithStoreProvider injects a storage provider to the Aries framework.
 func WithStoreProvider(prov storage.Provider) Option {
 	return func(opts *Aries) error {
@@ -210,6 +235,24 @@ func WithLegacyKMS(k api.KMSCreator) Option {
 	}
 }
 
+// WithKMS injects a KMS service to the Aries framework.
+func WithKMS(k kms.Creator) Option {
+	return func(opts *Aries) error {
+		opts.keyManagerCreator = k
+		return nil
+	}
+}
+
+// WithSecretLock injects a secret lock service to the Aries framework, it is used by the KMS to protect keys.
+// Without it the framework defaults to a no-op lock and the KMS keys are stored in plaintext, which is only suitable
+// for tests and development.
+func WithSecretLock(s secretlock.Service) Option {
+	return func(opts *Aries) error {
+		opts.secretLock = s
+		return nil
+	}
+}
+
 // WithCrypto injects a crypto service to the Aries framework
 func WithCrypto(c crypto.Crypto) Option {
 	return func(opts *Aries) error {
@@ -226,6 +269,17 @@ func WithVDRI(v vdriapi.VDRI) Option {
 	}
 }
 
+// WithVDRICache enables the cache of the DID documents resolved through the VDRI registry of the Aries framework,
+// the size and TTL of the cache are configured using vdri.CacheOption (refer vdri.NewCachingRegistry).
+func WithVDRICache(cacheOpts ...vdri.CacheOption) Option {
+	return func(opts *Aries) error {
+		opts.vdriCache = true
+		opts.vdriCacheOpts = append(opts.vdriCacheOpts, cacheOpts...)
+
+		return nil
+	}
+}
+
 // WithMessageServiceProvider injects a message service provider to the Aries framework.
 // Message service provider returns list of message services which can be used to provide custom handle
 // functionality based on incoming messages type and purpose.
@@ -261,6 +315,8 @@ func (a *Aries) Context() (*context.Provider, error) {
 		context.WithOutboundTransports(a.outboundTransports...),
 		context.WithProtocolServices(a.services...),
 		context.WithLegacyKMS(a.kms),
+		context.WithKMS(a.keyManager),
+		context.WithSecretLock(a.secretLock),
 		context.WithCrypto(a.crypto),
 		context.WithInboundTransportEndpoint(endPoint),
 		context.WithStorageProvider(a.storeProvider),
@@ -282,6 +338,13 @@ func (a *Aries) Messenger() service.Messenger {
 
 // Close frees resources being maintained by the framework.
 func (a *Aries) Close() error {
+	// stop the redelivery of the outbound messages before closing the store
+	if closer, ok := a.outboundDispatcher.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			return fmt.Errorf("failed to close the outbound dispatcher: %w", err)
+		}
+	}
+
 	if a.kms != nil {
 		err := a.kms.Close()
 		if err != nil {
@@ -335,6 +398,19 @@ func createKMS(frameworkOpts *Aries) error {
 		return fmt.Errorf("create kms failed: %w", err)
 	}
 
+	ctx, err = context.New(
+		context.WithStorageProvider(frameworkOpts.storeProvider),
+		context.WithSecretLock(frameworkOpts.secretLock),
+	)
+	if err != nil {
+		return fmt.Errorf("create context failed: %w", err)
+	}
+
+	frameworkOpts.keyManager, err = frameworkOpts.keyManagerCreator(ctx)
+	if err != nil {
+		return fmt.Errorf("create new kms failed: %w", err)
+	}
+
 	return nil
 }
 
@@ -346,6 +422,7 @@ func createVDRI(frameworkOpts *Aries) error {
 
 	ctx, err := context.New(
 		context.WithLegacyKMS(frameworkOpts.kms),
+		context.WithKMS(frameworkOpts.keyManager),
 		context.WithCrypto(frameworkOpts.crypto),
 		context.WithStorageProvider(frameworkOpts.storeProvider),
 		context.WithInboundTransportEndpoint(endPoint),
@@ -366,12 +443,17 @@ func createVDRI(frameworkOpts *Aries) error {
 
 	opts = append(opts,
 		vdri.WithVDRI(p),
+		vdri.WithVDRI(key.New()),
 		vdri.WithDefaultServiceType(vdriapi.DIDCommServiceType),
 		vdri.WithDefaultServiceEndpoint(ctx.InboundTransportEndpoint()),
 	)
 
 	frameworkOpts.vdriRegistry = vdri.New(ctx, opts...)
 
+	if frameworkOpts.vdriCache {
+		frameworkOpts.vdriRegistry = vdri.NewCachingRegistry(frameworkOpts.vdriRegistry, frameworkOpts.vdriCacheOpts...)
+	}
+
 	return nil
 }
 
@@ -396,17 +478,23 @@ func createMessengerHandler(frameworkOpts *Aries) error {
 func createOutboundDispatcher(frameworkOpts *Aries) error {
 	ctx, err := context.New(
 		context.WithLegacyKMS(frameworkOpts.kms),
+		context.WithKMS(frameworkOpts.keyManager),
 		context.WithCrypto(frameworkOpts.crypto),
 		context.WithOutboundTransports(frameworkOpts.outboundTransports...),
 		context.WithPackager(frameworkOpts.packager),
 		context.WithTransportReturnRoute(frameworkOpts.transportReturnRoute),
 		context.WithVDRIRegistry(frameworkOpts.vdriRegistry),
+		context.WithStorageProvider(frameworkOpts.storeProvider),
+		context.WithOutboundRetryPolicy(frameworkOpts.outboundRetryPolicy),
 	)
 	if err != nil {
 		return fmt.Errorf("context creation failed: %w", err)
 	}
 
-	frameworkOpts.outboundDispatcher = dispatcher.NewOutbound(ctx)
+	frameworkOpts.outboundDispatcher, err = dispatcher.NewOutbound(ctx)
+	if err != nil {
+		return fmt.Errorf("outbound dispatcher creation failed: %w", err)
+	}
 
 	return nil
 }
@@ -414,6 +502,7 @@ func createOutboundDispatcher(frameworkOpts *Aries) error {
 func startTransports(frameworkOpts *Aries) error {
 	ctx, err := context.New(
 		context.WithLegacyKMS(frameworkOpts.kms),
+		context.WithKMS(frameworkOpts.keyManager),
 		context.WithCrypto(frameworkOpts.crypto),
 		context.WithPackager(frameworkOpts.packager),
 		context.WithProtocolServices(frameworkOpts.services...),
@@ -454,10 +543,12 @@ func loadServices(frameworkOpts *Aries) error {
 		context.WithStorageProvider(frameworkOpts.storeProvider),
 		context.WithTransientStorageProvider(frameworkOpts.transientStoreProvider),
 		context.WithLegacyKMS(frameworkOpts.kms),
+		context.WithKMS(frameworkOpts.keyManager),
 		context.WithCrypto(frameworkOpts.crypto),
 		context.WithPackager(frameworkOpts.packager),
 		context.WithInboundTransportEndpoint(endPoint),
 		context.WithVDRIRegistry(frameworkOpts.vdriRegistry),
+		context.WithMessageServiceProvider(frameworkOpts.msgSvcProvider),
 	)
 
 	if err != nil {
@@ -484,6 +575,7 @@ func loadServices(frameworkOpts *Aries) error {
 func createPackersAndPackager(frameworkOpts *Aries) error {
 	ctx, err := context.New(
 		context.WithLegacyKMS(frameworkOpts.kms),
+		context.WithKMS(frameworkOpts.keyManager),
 		context.WithCrypto(frameworkOpts.crypto),
 	)
 	if err != nil {
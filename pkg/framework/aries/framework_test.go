@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/tink/go/keyset"
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
@@ -29,16 +30,21 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
 	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/internal/mock/crypto"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/msghandler"
 	mockdidexchange "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/generic"
-	mockkms "github.com/hyperledger/aries-framework-go/pkg/internal/mock/kms/legacykms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/internal/mock/kms"
+	mocklegacykms "github.com/hyperledger/aries-framework-go/pkg/internal/mock/kms/legacykms"
+	mocksecretlock "github.com/hyperledger/aries-framework-go/pkg/internal/mock/secretlock"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/mock/storage"
 	mockvdri "github.com/hyperledger/aries-framework-go/pkg/internal/mock/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/storage/leveldb"
+	"github.com/hyperledger/aries-framework-go/pkg/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/vdri/peer"
 )
 
@@ -94,7 +100,7 @@ func TestFramework(t *testing.T) {
 		aries, err := New(
 			WithInboundTransport(&mockInboundTransport{}),
 			WithLegacyKMS(func(ctx api.Provider) (api.CloseableKMS, error) {
-				return &mockkms.CloseableKMS{SignMessageValue: []byte("mockValue")}, nil
+				return &mocklegacykms.CloseableKMS{SignMessageValue: []byte("mockValue")}, nil
 			}),
 			WithPacker(func(ctx packer.Provider) (packer.Packer, error) {
 				return &didcomm.MockAuthCrypt{
@@ -138,6 +144,31 @@ func TestFramework(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("test vdri - with cache", func(t *testing.T) {
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+		dbPath = path
+
+		reads := 0
+		v := &mockvdri.MockVDRI{AcceptValue: true,
+			ReadFunc: func(didID string, opts ...vdriapi.ResolveOpts) (*did.Doc, error) {
+				reads++
+				return &did.Doc{ID: didID}, nil
+			}}
+
+		aries, err := New(WithVDRI(v), WithVDRICache(vdri.WithCacheSize(1)),
+			WithInboundTransport(&mockInboundTransport{}))
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			_, err = aries.vdriRegistry.Resolve("did:example:123")
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, 1, reads)
+		require.NoError(t, aries.Close())
+	})
+
 	t.Run("test error create vdri", func(t *testing.T) {
 		_, err := New(
 			WithStoreProvider(&storage.MockStoreProvider{FailNamespace: peer.StoreNamespace}),
@@ -183,6 +214,12 @@ func TestFramework(t *testing.T) {
 		resolvedDoc, err := aries.vdriRegistry.Resolve(peerDID)
 		require.NoError(t, err)
 		require.Equal(t, originalDoc, resolvedDoc)
+
+		// did:key is resolved without storing the document
+		keyDID := "did:key:z6MkpTHR8VNsBxYAAWHut2Geadd9jSwuBV8xRoAnwWsdvktH"
+		resolvedDoc, err = aries.vdriRegistry.Resolve(keyDID)
+		require.NoError(t, err)
+		require.Equal(t, keyDID, resolvedDoc.ID)
 		err = aries.Close()
 		require.NoError(t, err)
 	})
@@ -299,7 +336,7 @@ func TestFramework(t *testing.T) {
 		// with custom kms
 		aries, err := New(WithInboundTransport(&mockInboundTransport{}),
 			WithLegacyKMS(func(ctx api.Provider) (api.CloseableKMS, error) {
-				return &mockkms.CloseableKMS{SignMessageValue: []byte("mockValue")}, nil
+				return &mocklegacykms.CloseableKMS{SignMessageValue: []byte("mockValue")}, nil
 			}))
 		require.NoError(t, err)
 		require.NotEmpty(t, aries)
@@ -314,6 +351,55 @@ func TestFramework(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("test new kms svc - with default kms", func(t *testing.T) {
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+		dbPath = path
+
+		aries, err := New()
+		require.NoError(t, err)
+
+		ctx, err := aries.Context()
+		require.NoError(t, err)
+		require.NotNil(t, ctx.SecretLock())
+
+		keyID, kh, err := ctx.KMS().Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		storedKH, err := ctx.KMS().Get(keyID)
+		require.NoError(t, err)
+		require.Equal(t, kh.(*keyset.Handle).String(), storedKH.(*keyset.Handle).String())
+
+		require.NoError(t, aries.Close())
+	})
+
+	t.Run("test new kms svc - with user provided kms and secret lock", func(t *testing.T) {
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+		dbPath = path
+
+		secretLock := &mocksecretlock.MockSecretLock{}
+
+		aries, err := New(WithSecretLock(secretLock),
+			WithKMS(func(p kms.Provider) (kms.KeyManager, error) {
+				require.Equal(t, secretLock, p.SecretLock())
+				require.NotNil(t, p.StorageProvider())
+
+				return &mockkms.KeyManager{CreateKeyID: "keyID"}, nil
+			}))
+		require.NoError(t, err)
+
+		ctx, err := aries.Context()
+		require.NoError(t, err)
+		require.Equal(t, secretLock, ctx.SecretLock())
+
+		keyID, _, err := ctx.KMS().Create(kms.ED25519Type)
+		require.NoError(t, err)
+		require.Equal(t, "keyID", keyID)
+
+		require.NoError(t, aries.Close())
+	})
+
 	t.Run("test crypto svc - with user provided crypto - Encrypt success", func(t *testing.T) {
 		// with custom crypto
 		aries, err := New(WithCrypto(&mockcrypto.Crypto{
@@ -390,6 +476,18 @@ func TestFramework(t *testing.T) {
 		require.Contains(t, err.Error(), "error from kms")
 	})
 
+	t.Run("test error from new kms svc", func(t *testing.T) {
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+		dbPath = path
+
+		_, err := New(WithKMS(func(p kms.Provider) (kms.KeyManager, error) {
+			return nil, fmt.Errorf("error from kms")
+		}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create new kms failed: error from kms")
+	})
+
 	t.Run("test transient store - with user provided transient store", func(t *testing.T) {
 		path, cleanup := generateTempDir(t)
 		defer cleanup()
@@ -458,6 +556,26 @@ func TestFramework(t *testing.T) {
 		require.Contains(t, err.Error(), "invalid transport return route option : "+transportReturnRoute)
 	})
 
+	t.Run("test new with outbound retry policy", func(t *testing.T) {
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+		dbPath = path
+
+		policy := dispatcher.DefaultRetryPolicy()
+		aries, err := New(WithOutboundRetryPolicy(policy))
+		require.NoError(t, err)
+		require.Equal(t, policy, aries.outboundRetryPolicy)
+
+		ctx, err := aries.Context()
+		require.NoError(t, err)
+		require.NoError(t, ctx.OutboundDispatcher().RegisterDeliveryEvent(make(chan dispatcher.DeliveryEvent)))
+		require.NoError(t, aries.Close())
+
+		_, err = New(WithOutboundRetryPolicy(&dispatcher.RetryPolicy{}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid outbound retry policy : max attempts 0")
+	})
+
 	t.Run("test message service provider option", func(t *testing.T) {
 		path, cleanup := generateTempDir(t)
 		defer cleanup()
@@ -17,7 +17,9 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
 
@@ -28,6 +30,8 @@ type Provider struct {
 	storeProvider            storage.Provider
 	transientStoreProvider   storage.Provider
 	kms                      legacykms.KMS
+	keyManager               kms.KeyManager
+	secretLock               secretlock.Service
 	crypto                   crypto.Crypto
 	packager                 commontransport.Packager
 	primaryPacker            packer.Packer
@@ -38,6 +42,7 @@ type Provider struct {
 	outboundTransports       []transport.OutboundTransport
 	vdriRegistry             vdriapi.Registry
 	transportReturnRoute     string
+	outboundRetryPolicy      *dispatcher.RetryPolicy
 	frameworkID              string
 }
 
@@ -76,11 +81,31 @@ func (p *Provider) Service(id string) (interface{}, error) {
 	return nil, api.ErrSvcNotFound
 }
 
+// ProtocolServices returns all the protocol services.
+func (p *Provider) ProtocolServices() []dispatcher.ProtocolService {
+	return p.services
+}
+
+// MessageServiceProvider returns a provider of the message services.
+func (p *Provider) MessageServiceProvider() api.MessageServiceProvider {
+	return p.msgSvcProvider
+}
+
 // LegacyKMS returns a kms service.
 func (p *Provider) LegacyKMS() legacykms.KeyManager {
 	return p.kms
 }
 
+// KMS returns a key manager service.
+func (p *Provider) KMS() kms.KeyManager {
+	return p.keyManager
+}
+
+// SecretLock returns a secret lock service.
+func (p *Provider) SecretLock() secretlock.Service {
+	return p.secretLock
+}
+
 // Crypto returns the Crypto service
 func (p *Provider) Crypto() crypto.Crypto {
 	return p.crypto
@@ -182,6 +207,11 @@ func (p *Provider) TransportReturnRoute() string {
 	return p.transportReturnRoute
 }
 
+// OutboundRetryPolicy returns the redelivery policy of the outbound messages.
+func (p *Provider) OutboundRetryPolicy() *dispatcher.RetryPolicy {
+	return p.outboundRetryPolicy
+}
+
 // AriesFrameworkID returns an inbound transport endpoint.
 func (p *Provider) AriesFrameworkID() string {
 	return p.frameworkID
@@ -222,6 +252,14 @@ func WithTransportReturnRoute(transportReturnRoute string) ProviderOption {
 	}
 }
 
+// WithOutboundRetryPolicy injects the redelivery policy of the outbound messages into the context.
+func WithOutboundRetryPolicy(policy *dispatcher.RetryPolicy) ProviderOption {
+	return func(opts *Provider) error {
+		opts.outboundRetryPolicy = policy
+		return nil
+	}
+}
+
 // WithProtocolServices injects a protocol services into the context.
 func WithProtocolServices(services ...dispatcher.ProtocolService) ProviderOption {
 	return func(opts *Provider) error {
@@ -238,6 +276,22 @@ func WithLegacyKMS(w legacykms.KMS) ProviderOption {
 	}
 }
 
+// WithKMS injects a key manager service into the context.
+func WithKMS(k kms.KeyManager) ProviderOption {
+	return func(opts *Provider) error {
+		opts.keyManager = k
+		return nil
+	}
+}
+
+// WithSecretLock injects a secret lock service into the context.
+func WithSecretLock(s secretlock.Service) ProviderOption {
+	return func(opts *Provider) error {
+		opts.secretLock = s
+		return nil
+	}
+}
+
 // WithCrypto injects a Crypto service into the context
 func WithCrypto(c crypto.Crypto) ProviderOption {
 	return func(opts *Provider) error {
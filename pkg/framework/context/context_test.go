@@ -18,6 +18,7 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
 	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
 	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/internal/mock/crypto"
 	mockdidcomm "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm"
@@ -26,7 +27,9 @@ import (
 	mockpackager "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/packager"
 	mockdidexchange "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/generic"
-	mockkms "github.com/hyperledger/aries-framework-go/pkg/internal/mock/kms/legacykms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/internal/mock/kms"
+	mocklegacykms "github.com/hyperledger/aries-framework-go/pkg/internal/mock/kms/legacykms"
+	mocksecretlock "github.com/hyperledger/aries-framework-go/pkg/internal/mock/secretlock"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/mock/storage"
 	mockvdri "github.com/hyperledger/aries-framework-go/pkg/internal/mock/vdri"
 )
@@ -215,7 +218,7 @@ func TestNewProvider(t *testing.T) {
 
 	t.Run("test new with kms and packager service", func(t *testing.T) {
 		prov, err := New(
-			WithLegacyKMS(&mockkms.CloseableKMS{SignMessageValue: []byte("mockValue")}),
+			WithLegacyKMS(&mocklegacykms.CloseableKMS{SignMessageValue: []byte("mockValue")}),
 			WithPackager(&mockpackager.Packager{PackValue: []byte("data")}),
 			WithPacker(
 				&mockdidcomm.MockAuthCrypt{
@@ -259,6 +262,15 @@ func TestNewProvider(t *testing.T) {
 		require.Equal(t, mCrypto, prov.Crypto())
 	})
 
+	t.Run("test new with kms and secret lock", func(t *testing.T) {
+		mKMS := &mockkms.KeyManager{}
+		mSecretLock := &mocksecretlock.MockSecretLock{}
+		prov, err := New(WithKMS(mKMS), WithSecretLock(mSecretLock))
+		require.NoError(t, err)
+		require.Equal(t, mKMS, prov.KMS())
+		require.Equal(t, mSecretLock, prov.SecretLock())
+	})
+
 	t.Run("test new with inbound transport endpoint", func(t *testing.T) {
 		prov, err := New(WithInboundTransportEndpoint("endpoint"))
 		require.NoError(t, err)
@@ -306,6 +318,13 @@ func TestNewProvider(t *testing.T) {
 		require.Equal(t, transportReturnRoute, prov.TransportReturnRoute())
 	})
 
+	t.Run("test new with outbound retry policy", func(t *testing.T) {
+		policy := dispatcher.DefaultRetryPolicy()
+		prov, err := New(WithOutboundRetryPolicy(policy))
+		require.NoError(t, err)
+		require.Equal(t, policy, prov.OutboundRetryPolicy())
+	})
+
 	t.Run("test new with framework id", func(t *testing.T) {
 		frameworkID := "aries-framework-1"
 		prov, err := New(WithAriesFrameworkID(frameworkID))
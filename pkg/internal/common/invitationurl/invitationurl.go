@@ -0,0 +1,60 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invitationurl
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrInvitationNotFound is returned when the URL has no invitation.
+var ErrInvitationNotFound = errors.New("invitation not found")
+
+// Encode renders the invitation as the URL with the base64url encoded invitation in the given query parameter,
+// e.g. https://example.com/path?c_i=eyJAdHlwZSI6...
+func Encode(baseURL, param string, invitation interface{}) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("parse base URL: %w", err)
+	}
+
+	bytes, err := json.Marshal(invitation)
+	if err != nil {
+		return "", fmt.Errorf("marshal invitation: %w", err)
+	}
+
+	query := u.Query()
+	query.Set(param, base64.URLEncoding.EncodeToString(bytes))
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// Decode decodes the invitation from the given query parameter of the URL (refer Encode),
+// ErrInvitationNotFound is returned if the URL has no such parameter.
+func Decode(u *url.URL, param string, invitation interface{}) error {
+	encoded := u.Query().Get(param)
+	if encoded == "" {
+		return ErrInvitationNotFound
+	}
+
+	// the padding is optional
+	bytes, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(encoded, "="))
+	if err != nil {
+		return fmt.Errorf("decode invitation: %w", err)
+	}
+
+	if err := json.Unmarshal(bytes, invitation); err != nil {
+		return fmt.Errorf("unmarshal invitation: %w", err)
+	}
+
+	return nil
+}
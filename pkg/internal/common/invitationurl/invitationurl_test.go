@@ -0,0 +1,73 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invitationurl
+
+import (
+	"encoding/base64"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testInvitation struct {
+	ID    string `json:"@id"`
+	Label string `json:"label"`
+}
+
+func TestEncodeDecode(t *testing.T) {
+	t.Run("test encode and decode - success", func(t *testing.T) {
+		invitationURL, err := Encode("https://example.com/path?a=b", "oob", &testInvitation{ID: "1", Label: "Alice"})
+		require.NoError(t, err)
+
+		u, err := url.Parse(invitationURL)
+		require.NoError(t, err)
+		require.Equal(t, "b", u.Query().Get("a"))
+
+		invitation := &testInvitation{}
+		require.NoError(t, Decode(u, "oob", invitation))
+		require.Equal(t, &testInvitation{ID: "1", Label: "Alice"}, invitation)
+
+		// the invitation is looked up in the given parameter only
+		require.Equal(t, ErrInvitationNotFound, Decode(u, "c_i", invitation))
+	})
+
+	t.Run("test decode - padding is optional", func(t *testing.T) {
+		u, err := url.Parse("https://example.com?c_i=" + base64.RawURLEncoding.EncodeToString([]byte(`{"@id":"1"}`)))
+		require.NoError(t, err)
+
+		invitation := &testInvitation{}
+		require.NoError(t, Decode(u, "c_i", invitation))
+		require.Equal(t, "1", invitation.ID)
+	})
+
+	t.Run("test encode errors", func(t *testing.T) {
+		_, err := Encode("%", "oob", &testInvitation{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse base URL")
+
+		_, err = Encode("https://example.com", "oob", make(chan int))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "marshal invitation")
+	})
+
+	t.Run("test decode errors", func(t *testing.T) {
+		u, err := url.Parse("https://example.com?oob=@@@")
+		require.NoError(t, err)
+
+		err = Decode(u, "oob", &testInvitation{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode invitation")
+
+		u, err = url.Parse("https://example.com?oob=" + base64.URLEncoding.EncodeToString([]byte("invalid")))
+		require.NoError(t, err)
+
+		err = Decode(u, "oob", &testInvitation{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal invitation")
+	})
+}
@@ -7,6 +7,7 @@ package mocks
 import (
 	gomock "github.com/golang/mock/gomock"
 	service "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	dispatcher "github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
 	reflect "reflect"
 )
 
@@ -47,6 +48,20 @@ func (mr *MockOutboundMockRecorder) Forward(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Forward", reflect.TypeOf((*MockOutbound)(nil).Forward), arg0, arg1)
 }
 
+// RegisterDeliveryEvent mocks base method
+func (m *MockOutbound) RegisterDeliveryEvent(arg0 chan<- dispatcher.DeliveryEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterDeliveryEvent", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterDeliveryEvent indicates an expected call of RegisterDeliveryEvent
+func (mr *MockOutboundMockRecorder) RegisterDeliveryEvent(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterDeliveryEvent", reflect.TypeOf((*MockOutbound)(nil).RegisterDeliveryEvent), arg0)
+}
+
 // Send mocks base method
 func (m *MockOutbound) Send(arg0 interface{}, arg1 string, arg2 *service.Destination) error {
 	m.ctrl.T.Helper()
@@ -74,3 +89,17 @@ func (mr *MockOutboundMockRecorder) SendToDID(arg0, arg1, arg2 interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendToDID", reflect.TypeOf((*MockOutbound)(nil).SendToDID), arg0, arg1, arg2)
 }
+
+// UnregisterDeliveryEvent mocks base method
+func (m *MockOutbound) UnregisterDeliveryEvent(arg0 chan<- dispatcher.DeliveryEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnregisterDeliveryEvent", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnregisterDeliveryEvent indicates an expected call of UnregisterDeliveryEvent
+func (mr *MockOutboundMockRecorder) UnregisterDeliveryEvent(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnregisterDeliveryEvent", reflect.TypeOf((*MockOutbound)(nil).UnregisterDeliveryEvent), arg0)
+}
@@ -6,6 +6,10 @@ SPDX-License-Identifier: Apache-2.0
 
 package crypto
 
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+)
+
 // Crypto mock
 type Crypto struct {
 	EncryptValue      []byte
@@ -16,6 +20,13 @@ type Crypto struct {
 	SignValue         []byte
 	SignErr           error
 	VerifyErr         error
+	WrapValue         *crypto.RecipientWrappedKey
+	WrapError         error
+	UnwrapValue       []byte
+	UnwrapError       error
+	ComputeMACValue   []byte
+	ComputeMACErr     error
+	VerifyMACErr      error
 }
 
 // Encrypt mocked value
@@ -37,3 +48,25 @@ func (c *Crypto) Sign(msg []byte, kh interface{}) ([]byte, error) {
 func (c *Crypto) Verify(signature, msg []byte, kh interface{}) error {
 	return c.VerifyErr
 }
+
+// WrapKey mocked value
+func (c *Crypto) WrapKey(cek, apu, apv []byte, recPubKey *crypto.PublicKey,
+	opts ...crypto.WrapKeyOpts) (*crypto.RecipientWrappedKey, error) {
+	return c.WrapValue, c.WrapError
+}
+
+// UnwrapKey mocked value
+func (c *Crypto) UnwrapKey(recWK *crypto.RecipientWrappedKey, kh interface{},
+	opts ...crypto.WrapKeyOpts) ([]byte, error) {
+	return c.UnwrapValue, c.UnwrapError
+}
+
+// ComputeMAC mocked value
+func (c *Crypto) ComputeMAC(data []byte, kh interface{}) ([]byte, error) {
+	return c.ComputeMACValue, c.ComputeMACErr
+}
+
+// VerifyMAC mocked value
+func (c *Crypto) VerifyMAC(mac, data []byte, kh interface{}) error {
+	return c.VerifyMACErr
+}
@@ -7,6 +7,7 @@ package dispatcher
 
 import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
 )
 
 // MockOutbound mock outbound dispatcher
@@ -15,6 +16,7 @@ type MockOutbound struct {
 	ValidateSendToDID func(msg interface{}, myDID, theirDID string) error
 	ValidateForward   func(msg interface{}, des *service.Destination) error
 	SendErr           error
+	DeliveryEventErr  error
 }
 
 // Send msg
@@ -43,3 +45,13 @@ func (m *MockOutbound) Forward(msg interface{}, des *service.Destination) error
 
 	return nil
 }
+
+// RegisterDeliveryEvent registers delivery event channel
+func (m *MockOutbound) RegisterDeliveryEvent(ch chan<- dispatcher.DeliveryEvent) error {
+	return m.DeliveryEventErr
+}
+
+// UnregisterDeliveryEvent unregisters delivery event channel
+func (m *MockOutbound) UnregisterDeliveryEvent(ch chan<- dispatcher.DeliveryEvent) error {
+	return m.DeliveryEventErr
+}
@@ -0,0 +1,62 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/discoverfeatures"
+)
+
+// MockDiscoverFeaturesSvc mock discover features service
+type MockDiscoverFeaturesSvc struct {
+	HandleFunc         func(service.DIDCommMsg) (string, error)
+	HandleOutboundFunc func(msg service.DIDCommMsg, myDID, theirDID string) error
+	QueryFunc          func(connectionID, query string,
+		timeout time.Duration) ([]discoverfeatures.ProtocolDescriptor, error)
+}
+
+// HandleInbound msg
+func (m *MockDiscoverFeaturesSvc) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if m.HandleFunc != nil {
+		return m.HandleFunc(msg)
+	}
+
+	return uuid.New().String(), nil
+}
+
+// HandleOutbound msg
+func (m *MockDiscoverFeaturesSvc) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) error {
+	if m.HandleOutboundFunc != nil {
+		return m.HandleOutboundFunc(msg, myDID, theirDID)
+	}
+
+	return nil
+}
+
+// Accept msg checks the msg type
+func (m *MockDiscoverFeaturesSvc) Accept(msgType string) bool {
+	return msgType == discoverfeatures.QueryMsgType || msgType == discoverfeatures.DiscloseMsgType
+}
+
+// Name return service name
+func (m *MockDiscoverFeaturesSvc) Name() string {
+	return discoverfeatures.DiscoverFeatures
+}
+
+// Query queries the features supported by the other end of the connection.
+func (m *MockDiscoverFeaturesSvc) Query(connectionID, query string,
+	timeout time.Duration) ([]discoverfeatures.ProtocolDescriptor, error) {
+	if m.QueryFunc != nil {
+		return m.QueryFunc(connectionID, query, timeout)
+	}
+
+	return nil, nil
+}
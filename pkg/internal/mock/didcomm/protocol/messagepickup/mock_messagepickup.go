@@ -0,0 +1,84 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package messagepickup
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/messagepickup"
+)
+
+// MockMessagePickupSvc mock message pickup service
+type MockMessagePickupSvc struct {
+	HandleFunc         func(service.DIDCommMsg) (string, error)
+	HandleOutboundFunc func(msg service.DIDCommMsg, myDID, theirDID string) error
+	AddMessageFunc     func(message []byte, theirDID string) error
+	StatusRequestFunc  func(connectionID string) (*messagepickup.Status, error)
+	BatchPickupFunc    func(connectionID string, size int) (int, error)
+	NoopErr            error
+}
+
+// HandleInbound msg
+func (m *MockMessagePickupSvc) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if m.HandleFunc != nil {
+		return m.HandleFunc(msg)
+	}
+
+	return uuid.New().String(), nil
+}
+
+// HandleOutbound msg
+func (m *MockMessagePickupSvc) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) error {
+	if m.HandleOutboundFunc != nil {
+		return m.HandleOutboundFunc(msg, myDID, theirDID)
+	}
+
+	return nil
+}
+
+// Accept msg checks the msg type
+func (m *MockMessagePickupSvc) Accept(msgType string) bool {
+	return (&messagepickup.Service{}).Accept(msgType)
+}
+
+// Name return service name
+func (m *MockMessagePickupSvc) Name() string {
+	return messagepickup.MessagePickup
+}
+
+// AddMessage queues the message for the recipient.
+func (m *MockMessagePickupSvc) AddMessage(message []byte, theirDID string) error {
+	if m.AddMessageFunc != nil {
+		return m.AddMessageFunc(message, theirDID)
+	}
+
+	return nil
+}
+
+// StatusRequest requests the status of the queued messages.
+func (m *MockMessagePickupSvc) StatusRequest(connectionID string) (*messagepickup.Status, error) {
+	if m.StatusRequestFunc != nil {
+		return m.StatusRequestFunc(connectionID)
+	}
+
+	return &messagepickup.Status{}, nil
+}
+
+// BatchPickup picks up a batch of the queued messages.
+func (m *MockMessagePickupSvc) BatchPickup(connectionID string, size int) (int, error) {
+	if m.BatchPickupFunc != nil {
+		return m.BatchPickupFunc(connectionID, size)
+	}
+
+	return 0, nil
+}
+
+// Noop requests the delivery of the queued messages.
+func (m *MockMessagePickupSvc) Noop(connectionID string) error {
+	return m.NoopErr
+}
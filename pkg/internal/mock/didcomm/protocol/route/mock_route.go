@@ -24,6 +24,14 @@ type MockRouteSvc struct {
 	RoutingKeys        []string
 	ConfigErr          error
 	AddKeyErr          error
+	AddKeyFunc         func(connectionID, recKey string) error
+	UnregisterErr      error
+	RoutersValue       []string
+	RoutersErr         error
+	RemoveKeyErr       error
+	Keys               []string
+	KeylistErr         error
+	SetDefaultErr      error
 }
 
 // HandleInbound msg
@@ -71,13 +79,50 @@ func (m *MockRouteSvc) Register(connectionID string) error {
 	return nil
 }
 
+// Unregister unregisters agent from the router.
+func (m *MockRouteSvc) Unregister(connectionID string) error {
+	return m.UnregisterErr
+}
+
+// Routers returns the router connection IDs.
+func (m *MockRouteSvc) Routers() ([]string, error) {
+	if m.RoutersErr != nil {
+		return nil, m.RoutersErr
+	}
+
+	return m.RoutersValue, nil
+}
+
+// SetDefaultRouter sets the default router.
+func (m *MockRouteSvc) SetDefaultRouter(connectionID string) error {
+	return m.SetDefaultErr
+}
+
 // AddKey adds agents recKey to the router
-func (m *MockRouteSvc) AddKey(recKey string) error {
+func (m *MockRouteSvc) AddKey(connectionID, recKey string) error {
+	if m.AddKeyFunc != nil {
+		return m.AddKeyFunc(connectionID, recKey)
+	}
+
 	return m.AddKeyErr
 }
 
+// RemoveKey removes agents recKey from the router
+func (m *MockRouteSvc) RemoveKey(connectionID, recKey string) error {
+	return m.RemoveKeyErr
+}
+
+// Keylist returns agents keys registered with the router
+func (m *MockRouteSvc) Keylist(connectionID string) ([]string, error) {
+	if m.KeylistErr != nil {
+		return nil, m.KeylistErr
+	}
+
+	return m.Keys, nil
+}
+
 // Config gives back the router configuration
-func (m *MockRouteSvc) Config() (*route.Config, error) {
+func (m *MockRouteSvc) Config(connectionID string) (*route.Config, error) {
 	if m.ConfigErr != nil {
 		return nil, m.ConfigErr
 	}
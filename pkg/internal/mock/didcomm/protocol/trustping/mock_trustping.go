@@ -0,0 +1,60 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/trustping"
+)
+
+// MockTrustPingSvc mock trust ping service
+type MockTrustPingSvc struct {
+	HandleFunc         func(service.DIDCommMsg) (string, error)
+	HandleOutboundFunc func(msg service.DIDCommMsg, myDID, theirDID string) error
+	PingFunc           func(connectionID string, timeout time.Duration) (time.Duration, error)
+}
+
+// HandleInbound msg
+func (m *MockTrustPingSvc) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if m.HandleFunc != nil {
+		return m.HandleFunc(msg)
+	}
+
+	return uuid.New().String(), nil
+}
+
+// HandleOutbound msg
+func (m *MockTrustPingSvc) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) error {
+	if m.HandleOutboundFunc != nil {
+		return m.HandleOutboundFunc(msg, myDID, theirDID)
+	}
+
+	return nil
+}
+
+// Accept msg checks the msg type
+func (m *MockTrustPingSvc) Accept(msgType string) bool {
+	return msgType == trustping.PingMsgType || msgType == trustping.PingResponseMsgType
+}
+
+// Name return service name
+func (m *MockTrustPingSvc) Name() string {
+	return trustping.TrustPing
+}
+
+// Ping sends a ping to the other end of the connection.
+func (m *MockTrustPingSvc) Ping(connectionID string, timeout time.Duration) (time.Duration, error) {
+	if m.PingFunc != nil {
+		return m.PingFunc(connectionID, timeout)
+	}
+
+	return time.Millisecond, nil
+}
@@ -0,0 +1,60 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+// KeyManager mock
+type KeyManager struct {
+	CreateKeyID            string
+	CreateKeyValue         interface{}
+	CreateKeyErr           error
+	GetKeyValue            interface{}
+	GetKeyErr              error
+	RotateKeyValue         interface{}
+	RotateKeyErr           error
+	ExportPubKeyBytesValue []byte
+	ExportPubKeyBytesErr   error
+}
+
+// Create a new mock key
+func (k *KeyManager) Create(kt kms.KeyType) (string, interface{}, error) {
+	if k.CreateKeyErr != nil {
+		return "", nil, k.CreateKeyErr
+	}
+
+	return k.CreateKeyID, k.CreateKeyValue, nil
+}
+
+// Get a mock key
+func (k *KeyManager) Get(keyID string) (interface{}, error) {
+	if k.GetKeyErr != nil {
+		return nil, k.GetKeyErr
+	}
+
+	return k.GetKeyValue, nil
+}
+
+// Rotate returns the mocked rotated key
+func (k *KeyManager) Rotate(kt kms.KeyType, keyID string) (interface{}, error) {
+	if k.RotateKeyErr != nil {
+		return nil, k.RotateKeyErr
+	}
+
+	return k.RotateKeyValue, nil
+}
+
+// ExportPubKeyBytes returns the mocked public key bytes
+func (k *KeyManager) ExportPubKeyBytes(keyID string) ([]byte, error) {
+	if k.ExportPubKeyBytesErr != nil {
+		return nil, k.ExportPubKeyBytesErr
+	}
+
+	return k.ExportPubKeyBytesValue, nil
+}
@@ -6,9 +6,14 @@ SPDX-License-Identifier: Apache-2.0
 package provider
 
 import (
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	commontransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
@@ -19,6 +24,8 @@ type Provider struct {
 	ServiceErr                    error
 	ServiceMap                    map[string]interface{}
 	KMSValue                      legacykms.KeyManager
+	KeyManagerValue               kms.KeyManager
+	CryptoValue                   crypto.Crypto
 	InboundEndpointValue          string
 	StorageProviderValue          storage.Provider
 	TransientStorageProviderValue storage.Provider
@@ -26,6 +33,10 @@ type Provider struct {
 	PackerValue                   packer.Packer
 	OutboundDispatcherValue       dispatcher.Outbound
 	VDRIRegistryValue             vdriapi.Registry
+	ProtocolServicesValue         []dispatcher.ProtocolService
+	MessageServiceProviderValue   api.MessageServiceProvider
+	PackagerValue                 commontransport.Packager
+	InboundMessageHandlerValue    transport.InboundMessageHandler
 }
 
 // Service return service
@@ -46,6 +57,16 @@ func (p *Provider) LegacyKMS() legacykms.KeyManager {
 	return p.KMSValue
 }
 
+// KMS returns a KeyManager instance
+func (p *Provider) KMS() kms.KeyManager {
+	return p.KeyManagerValue
+}
+
+// Crypto returns a Crypto instance
+func (p *Provider) Crypto() crypto.Crypto {
+	return p.CryptoValue
+}
+
 // InboundTransportEndpoint returns the inbound transport endpoint
 func (p *Provider) InboundTransportEndpoint() string {
 	return p.InboundEndpointValue
@@ -80,3 +101,23 @@ func (p *Provider) OutboundDispatcher() dispatcher.Outbound {
 func (p *Provider) VDRIRegistry() vdriapi.Registry {
 	return p.VDRIRegistryValue
 }
+
+// ProtocolServices returns the registered protocol services
+func (p *Provider) ProtocolServices() []dispatcher.ProtocolService {
+	return p.ProtocolServicesValue
+}
+
+// MessageServiceProvider returns the message service provider
+func (p *Provider) MessageServiceProvider() api.MessageServiceProvider {
+	return p.MessageServiceProviderValue
+}
+
+// Packager returns the packager
+func (p *Provider) Packager() commontransport.Packager {
+	return p.PackagerValue
+}
+
+// InboundMessageHandler returns the inbound message handler
+func (p *Provider) InboundMessageHandler() transport.InboundMessageHandler {
+	return p.InboundMessageHandlerValue
+}
@@ -60,11 +60,13 @@ func (s *MockStoreProvider) CloseStore(name string) error {
 
 // MockStore mock store.
 type MockStore struct {
-	Store  map[string][]byte
-	lock   sync.RWMutex
-	ErrPut error
-	ErrGet error
-	ErrItr error
+	Store     map[string][]byte
+	lock      sync.RWMutex
+	ErrPut    error
+	ErrGet    error
+	ErrItr    error
+	ErrDelete error
+	ErrBatch  error
 }
 
 // Put stores the key and the record
@@ -101,6 +103,40 @@ func (s *MockStore) Get(k string) ([]byte, error) {
 	return val, s.ErrGet
 }
 
+// Delete removes the record for given key
+func (s *MockStore) Delete(k string) error {
+	if s.ErrDelete != nil {
+		return s.ErrDelete
+	}
+
+	s.lock.Lock()
+	delete(s.Store, k)
+	s.lock.Unlock()
+
+	return nil
+}
+
+// Batch applies given operations
+func (s *MockStore) Batch(operations []storage.Operation) error {
+	if s.ErrBatch != nil {
+		return s.ErrBatch
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, op := range operations {
+		if op.Value == nil {
+			delete(s.Store, op.Key)
+			continue
+		}
+
+		s.Store[op.Key] = op.Value
+	}
+
+	return nil
+}
+
 // Iterator returns an iterator for the underlying mockstore
 func (s *MockStore) Iterator(start, limit string) storage.StoreIterator {
 	if s.ErrItr != nil {
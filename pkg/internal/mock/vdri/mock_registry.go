@@ -71,6 +71,21 @@ func (m *MockVDRIRegistry) Resolve(didID string, opts ...vdriapi.ResolveOpts) (*
 	return m.ResolveValue, nil
 }
 
+// ResolveWithMetadata did document along with the metadata
+func (m *MockVDRIRegistry) ResolveWithMetadata(didID string,
+	opts ...vdriapi.ResolveOpts) (*vdriapi.DocResolution, error) {
+	doc, err := m.Resolve(didID, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vdriapi.DocResolution{
+		DIDDocument:      doc,
+		DocumentMetadata: &vdriapi.DocumentMetadata{Created: doc.Created, Updated: doc.Updated},
+		ResolverMetadata: &vdriapi.ResolverMetadata{ContentType: vdriapi.ContentTypeDIDLDJSON},
+	}, nil
+}
+
 // Close frees resources being maintained by vdri.
 func (m *MockVDRIRegistry) Close() error {
 	return nil
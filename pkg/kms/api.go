@@ -0,0 +1,84 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package kms contains the KeyManager interface managing the keys used by the framework crypto service
+// (pkg/crypto.Crypto) as key handles.
+package kms
+
+import (
+	"errors"
+
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+// ErrKeyNotFound is returned when a key can't be found for a key ID
+var ErrKeyNotFound = errors.New("key not found")
+
+// KeyManager manages keys and their storage for the Aries framework
+type KeyManager interface {
+	// Create a new key/keyset/key handle for the type kt
+	// returns:
+	// 		string: keyID of the handle
+	// 		interface{}: handle instance (to private key)
+	// 		error: in case of errors
+	Create(kt KeyType) (string, interface{}, error)
+	// Get key handle for the given keyID
+	// returns:
+	// 		interface{}: handle instance (to private key)
+	// 		error: in case of errors (including ErrKeyNotFound)
+	Get(keyID string) (interface{}, error)
+	// Rotate a key referenced by keyID and return its updated handle. A new key of type kt becomes the primary
+	// key of the handle, previous keys are kept to decrypt or verify existing data.
+	// returns:
+	// 		interface{}: updated handle instance (to private key)
+	// 		error: in case of errors
+	Rotate(kt KeyType, keyID string) (interface{}, error)
+	// ExportPubKeyBytes will fetch the handle referenced by keyID and export its public keys as bytes, the format is
+	// specific to the implementation (the localkms package returns a binary serialized Tink public keyset, not raw
+	// key bytes)
+	// returns:
+	// 		[]byte: serialized public keys
+	// 		error: in case of errors (symmetric keys can't be exported)
+	ExportPubKeyBytes(keyID string) ([]byte, error)
+}
+
+// Provider for KeyManager builder/constructor
+type Provider interface {
+	StorageProvider() storage.Provider
+	SecretLock() secretlock.Service
+}
+
+// Creator method to create new key management service
+type Creator func(provider Provider) (KeyManager, error)
+
+// KeyType represents a key type supported by the KMS
+type KeyType string
+
+const (
+	// AES128GCMType key type value
+	AES128GCMType KeyType = "AES128GCM"
+	// AES256GCMType key type value
+	AES256GCMType KeyType = "AES256GCM"
+	// ChaCha20Poly1305Type key type value
+	ChaCha20Poly1305Type KeyType = "ChaCha20Poly1305"
+	// XChaCha20Poly1305Type key type value
+	XChaCha20Poly1305Type KeyType = "XChaCha20Poly1305"
+	// ECDSAP256Type key type value
+	ECDSAP256Type KeyType = "ECDSAP256"
+	// ECDSAP384Type key type value
+	ECDSAP384Type KeyType = "ECDSAP384"
+	// ED25519Type key type value
+	ED25519Type KeyType = "ED25519"
+	// HMACSHA256Tag256Type key type value
+	HMACSHA256Tag256Type KeyType = "HMACSHA256Tag256"
+	// ECDHP256KWType key type value of NIST P-256 key agreement keys used to wrap keys (crypto.Crypto.WrapKey)
+	ECDHP256KWType KeyType = "ECDHP256KW"
+	// ECDHP384KWType key type value of NIST P-384 key agreement keys used to wrap keys (crypto.Crypto.WrapKey)
+	ECDHP384KWType KeyType = "ECDHP384KW"
+	// ECDHX25519KWType key type value of X25519 key agreement keys used to wrap keys (crypto.Crypto.WrapKey)
+	ECDHX25519KWType KeyType = "ECDHX25519KW"
+)
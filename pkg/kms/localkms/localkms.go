@@ -0,0 +1,260 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package localkms provides the default kms.KeyManager implementation. Keys are Tink keysets stored in the
+// framework storage provider, encrypted with the master key of a secret lock service.
+package localkms
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/signature"
+	tinkpb "github.com/google/tink/proto/tink_go_proto"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/ecdhkw"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const (
+	// Namespace is the store name used to persist keys
+	Namespace = "kmsdb"
+
+	// PrimaryKeyURI is the default key URI of the master key protecting the keys
+	PrimaryKeyURI = "local-lock://primarykey"
+
+	keyIDSize = 32
+)
+
+// LocalKMS implements kms.KeyManager to provide key management capabilities using a local db.
+type LocalKMS struct {
+	store     storage.Store
+	masterKey *secretLockAEAD
+}
+
+// New creates a new instance of LocalKMS protecting keys with the master key identified by primaryKeyURI
+// of the provider's secret lock.
+func New(primaryKeyURI string, p kms.Provider) (*LocalKMS, error) {
+	if p.SecretLock() == nil {
+		return nil, errors.New("secret lock is mandatory")
+	}
+
+	store, err := p.StorageProvider().OpenStore(Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store for '%s', cause: %w", Namespace, err)
+	}
+
+	return &LocalKMS{
+		store:     store,
+		masterKey: &secretLockAEAD{secretLock: p.SecretLock(), keyURI: primaryKeyURI},
+	}, nil
+}
+
+// Create a new keyset handle for the type kt
+// returns:
+// 		string: keyID of the handle
+// 		interface{}: handle instance (*keyset.Handle)
+// 		error: in case of errors
+func (l *LocalKMS) Create(kt kms.KeyType) (string, interface{}, error) {
+	template, err := keyTemplate(kt)
+	if err != nil {
+		return "", nil, err
+	}
+
+	kh, err := keyset.NewHandle(template)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create key handle: %w", err)
+	}
+
+	keyID, err := newKeyID()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err = l.storeKeySet(keyID, kh); err != nil {
+		return "", nil, err
+	}
+
+	return keyID, kh, nil
+}
+
+// Get the keyset handle for the given keyID
+// returns:
+// 		interface{}: handle instance (*keyset.Handle)
+// 		error: in case of errors (including kms.ErrKeyNotFound)
+func (l *LocalKMS) Get(keyID string) (interface{}, error) {
+	return l.getKeySet(keyID)
+}
+
+// Rotate adds a new key of type kt to the keyset referenced by keyID and makes it the primary key of the keyset.
+// kt must be of the same primitive as the keyset (AEAD or signature).
+// returns:
+// 		interface{}: updated handle instance (*keyset.Handle)
+// 		error: in case of errors
+func (l *LocalKMS) Rotate(kt kms.KeyType, keyID string) (interface{}, error) {
+	template, err := keyTemplate(kt)
+	if err != nil {
+		return nil, err
+	}
+
+	kh, err := l.getKeySet(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	km := keyset.NewManagerFromHandle(kh)
+
+	if err = km.Rotate(template); err != nil {
+		return nil, fmt.Errorf("failed to rotate key: %w", err)
+	}
+
+	updatedKH, err := km.Handle()
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate key: %w", err)
+	}
+
+	if err = l.storeKeySet(keyID, updatedKH); err != nil {
+		return nil, err
+	}
+
+	return updatedKH, nil
+}
+
+// ExportPubKeyBytes will fetch the keyset referenced by keyID and export its public keys as a serialized keyset
+// returns:
+// 		[]byte: public keyset serialized with Tink's binary keyset writer (it can be read back with
+// 		keyset.NewBinaryReader), not the raw public key bytes
+// 		error: in case of errors (symmetric keys can't be exported)
+func (l *LocalKMS) ExportPubKeyBytes(keyID string) ([]byte, error) {
+	kh, err := l.getKeySet(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKH, err := kh.Public()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get public keyset handle: %w", err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	if err = pubKH.WriteWithNoSecrets(keyset.NewBinaryWriter(buf)); err != nil {
+		return nil, fmt.Errorf("failed to export public keyset: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (l *LocalKMS) storeKeySet(keyID string, kh *keyset.Handle) error {
+	buf := new(bytes.Buffer)
+
+	if err := kh.Write(keyset.NewBinaryWriter(buf), l.masterKey); err != nil {
+		return fmt.Errorf("failed to encrypt keyset: %w", err)
+	}
+
+	if err := l.store.Put(keyID, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to store keyset: %w", err)
+	}
+
+	return nil
+}
+
+func (l *LocalKMS) getKeySet(keyID string) (*keyset.Handle, error) {
+	data, err := l.store.Get(keyID)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, fmt.Errorf("failed to get keyset %s: %w", keyID, kms.ErrKeyNotFound)
+		}
+
+		return nil, fmt.Errorf("failed to get keyset %s: %w", keyID, err)
+	}
+
+	kh, err := keyset.Read(keyset.NewBinaryReader(bytes.NewReader(data)), l.masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keyset %s: %w", keyID, err)
+	}
+
+	return kh, nil
+}
+
+func keyTemplate(kt kms.KeyType) (*tinkpb.KeyTemplate, error) {
+	switch kt {
+	case kms.AES128GCMType:
+		return aead.AES128GCMKeyTemplate(), nil
+	case kms.AES256GCMType:
+		return aead.AES256GCMKeyTemplate(), nil
+	case kms.ChaCha20Poly1305Type:
+		return aead.ChaCha20Poly1305KeyTemplate(), nil
+	case kms.XChaCha20Poly1305Type:
+		return aead.XChaCha20Poly1305KeyTemplate(), nil
+	case kms.ECDSAP256Type:
+		return signature.ECDSAP256KeyTemplate(), nil
+	case kms.ECDSAP384Type:
+		return signature.ECDSAP384KeyTemplate(), nil
+	case kms.ED25519Type:
+		return signature.ED25519KeyTemplate(), nil
+	case kms.HMACSHA256Tag256Type:
+		return mac.HMACSHA256Tag256KeyTemplate(), nil
+	case kms.ECDHP256KWType:
+		return ecdhkw.P256KeyTemplate(), nil
+	case kms.ECDHP384KWType:
+		return ecdhkw.P384KeyTemplate(), nil
+	case kms.ECDHX25519KWType:
+		return ecdhkw.X25519KeyTemplate(), nil
+	default:
+		return nil, fmt.Errorf("key type '%s' not supported", kt)
+	}
+}
+
+func newKeyID() (string, error) {
+	id := make([]byte, keyIDSize)
+
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate key ID: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(id), nil
+}
+
+// secretLockAEAD is a tink.AEAD delegating to a secret lock service, used as master key to encrypt keysets.
+type secretLockAEAD struct {
+	secretLock secretlock.Service
+	keyURI     string
+}
+
+// Encrypt plaintext with additionalData as additional authenticated data
+func (s *secretLockAEAD) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	resp, err := s.secretLock.Encrypt(s.keyURI, &secretlock.EncryptRequest{
+		Plaintext:                   string(plaintext),
+		AdditionalAuthenticatedData: string(additionalData),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(resp.Ciphertext), nil
+}
+
+// Decrypt ciphertext with additionalData as additional authenticated data
+func (s *secretLockAEAD) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
+	resp, err := s.secretLock.Decrypt(s.keyURI, &secretlock.DecryptRequest{
+		Ciphertext:                  string(ciphertext),
+		AdditionalAuthenticatedData: string(additionalData),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(resp.Plaintext), nil
+}
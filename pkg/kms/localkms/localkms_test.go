@@ -0,0 +1,304 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/ecdhkw"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	mocksecretlock "github.com/hyperledger/aries-framework-go/pkg/internal/mock/secretlock"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/internal/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		k, err := New(PrimaryKeyURI, &mockProvider{storage: mem.NewProvider(), secretLock: &noop.NoLock{}})
+		require.NoError(t, err)
+		require.NotNil(t, k)
+	})
+
+	t.Run("test missing secret lock", func(t *testing.T) {
+		k, err := New(PrimaryKeyURI, &mockProvider{storage: mem.NewProvider()})
+		require.EqualError(t, err, "secret lock is mandatory")
+		require.Nil(t, k)
+	})
+
+	t.Run("test open store failure", func(t *testing.T) {
+		k, err := New(PrimaryKeyURI, &mockProvider{
+			storage:    &mockstorage.MockStoreProvider{ErrOpenStoreHandle: errors.New("open error")},
+			secretLock: &noop.NoLock{},
+		})
+		require.EqualError(t, err, "failed to open store for 'kmsdb', cause: open error")
+		require.Nil(t, k)
+	})
+}
+
+func TestLocalKMS_Create(t *testing.T) {
+	k := newLocalKMS(t, mem.NewProvider())
+
+	keyTypes := []kms.KeyType{
+		kms.AES128GCMType, kms.AES256GCMType, kms.ChaCha20Poly1305Type, kms.XChaCha20Poly1305Type,
+		kms.ECDSAP256Type, kms.ECDSAP384Type, kms.ED25519Type, kms.HMACSHA256Tag256Type,
+		kms.ECDHP256KWType, kms.ECDHP384KWType, kms.ECDHX25519KWType,
+	}
+
+	for _, kt := range keyTypes {
+		kt := kt
+
+		t.Run("test create "+string(kt), func(t *testing.T) {
+			keyID, kh, err := k.Create(kt)
+			require.NoError(t, err)
+			require.NotEmpty(t, keyID)
+			require.IsType(t, &keyset.Handle{}, kh)
+
+			storedKH, err := k.Get(keyID)
+			require.NoError(t, err)
+			require.Equal(t, kh.(*keyset.Handle).String(), storedKH.(*keyset.Handle).String())
+		})
+	}
+
+	t.Run("test create unsupported key type", func(t *testing.T) {
+		keyID, kh, err := k.Create("unsupported")
+		require.EqualError(t, err, "key type 'unsupported' not supported")
+		require.Empty(t, keyID)
+		require.Nil(t, kh)
+	})
+
+	t.Run("test create key with store failure", func(t *testing.T) {
+		k := newLocalKMS(t, &mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
+			Store: make(map[string][]byte), ErrPut: errors.New("put error"),
+		}})
+
+		_, _, err := k.Create(kms.AES256GCMType)
+		require.EqualError(t, err, "failed to store keyset: put error")
+	})
+
+	t.Run("test create key with secret lock failure", func(t *testing.T) {
+		k, err := New(PrimaryKeyURI, &mockProvider{
+			storage:    mem.NewProvider(),
+			secretLock: &mocksecretlock.MockSecretLock{ErrEncrypt: errors.New("encrypt error")},
+		})
+		require.NoError(t, err)
+
+		_, _, err = k.Create(kms.AES256GCMType)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to encrypt keyset")
+	})
+}
+
+func TestLocalKMS_Get(t *testing.T) {
+	storeProvider := mem.NewProvider()
+	k := newLocalKMS(t, storeProvider)
+
+	t.Run("test key not found", func(t *testing.T) {
+		kh, err := k.Get("unknown")
+		require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+		require.Nil(t, kh)
+	})
+
+	t.Run("test store failure", func(t *testing.T) {
+		k := newLocalKMS(t, &mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
+			Store: make(map[string][]byte), ErrGet: errors.New("get error"),
+		}})
+
+		_, err := k.Get("keyID")
+		require.EqualError(t, err, "failed to get keyset keyID: get error")
+	})
+
+	t.Run("test keyset is stored encrypted", func(t *testing.T) {
+		keyID, _, err := k.Create(kms.AES256GCMType)
+		require.NoError(t, err)
+
+		store, err := storeProvider.OpenStore(Namespace)
+		require.NoError(t, err)
+
+		data, err := store.Get(keyID)
+		require.NoError(t, err)
+
+		// the stored keyset can't be read without the master key of the secret lock
+		_, err = keyset.Read(keyset.NewBinaryReader(bytes.NewReader(data)), &secretLockAEAD{
+			secretLock: newTestSecretLock(t), keyURI: PrimaryKeyURI,
+		})
+		require.Error(t, err)
+
+		// or with another master key URI
+		k2, err := New("local-lock://otherkey", &mockProvider{storage: storeProvider, secretLock: k.masterKey.secretLock})
+		require.NoError(t, err)
+
+		_, err = k2.Get(keyID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to decrypt keyset")
+	})
+}
+
+func TestLocalKMS_Rotate(t *testing.T) {
+	k := newLocalKMS(t, mem.NewProvider())
+
+	t.Run("test rotate aead key", func(t *testing.T) {
+		keyID, kh, err := k.Create(kms.AES256GCMType)
+		require.NoError(t, err)
+
+		a, err := aead.New(kh.(*keyset.Handle))
+		require.NoError(t, err)
+
+		ct, err := a.Encrypt([]byte("plaintext"), nil)
+		require.NoError(t, err)
+
+		rotatedKH, err := k.Rotate(kms.AES256GCMType, keyID)
+		require.NoError(t, err)
+
+		storedKH, err := k.Get(keyID)
+		require.NoError(t, err)
+		require.Equal(t, rotatedKH.(*keyset.Handle).String(), storedKH.(*keyset.Handle).String())
+
+		a, err = aead.New(storedKH.(*keyset.Handle))
+		require.NoError(t, err)
+
+		// data encrypted with the previous primary key can still be decrypted
+		pt, err := a.Decrypt(ct, nil)
+		require.NoError(t, err)
+		require.Equal(t, []byte("plaintext"), pt)
+
+		ct2, err := a.Encrypt([]byte("plaintext"), nil)
+		require.NoError(t, err)
+		require.NotEqual(t, ct[:5], ct2[:5], "new ciphertext must be prefixed by the new primary key ID")
+	})
+
+	t.Run("test rotate signature key", func(t *testing.T) {
+		keyID, _, err := k.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		rotatedKH, err := k.Rotate(kms.ED25519Type, keyID)
+		require.NoError(t, err)
+
+		signer, err := signature.NewSigner(rotatedKH.(*keyset.Handle))
+		require.NoError(t, err)
+
+		sig, err := signer.Sign([]byte("message"))
+		require.NoError(t, err)
+
+		pubKH, err := rotatedKH.(*keyset.Handle).Public()
+		require.NoError(t, err)
+
+		verifier, err := signature.NewVerifier(pubKH)
+		require.NoError(t, err)
+		require.NoError(t, verifier.Verify(sig, []byte("message")))
+	})
+
+	t.Run("test rotate failures", func(t *testing.T) {
+		_, err := k.Rotate("unsupported", "keyID")
+		require.EqualError(t, err, "key type 'unsupported' not supported")
+
+		_, err = k.Rotate(kms.AES256GCMType, "unknown")
+		require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+	})
+}
+
+func TestLocalKMS_ExportPubKeyBytes(t *testing.T) {
+	k := newLocalKMS(t, mem.NewProvider())
+
+	for _, kt := range []kms.KeyType{kms.ECDSAP256Type, kms.ECDSAP384Type, kms.ED25519Type} {
+		kt := kt
+
+		t.Run("test export public key "+string(kt), func(t *testing.T) {
+			keyID, kh, err := k.Create(kt)
+			require.NoError(t, err)
+
+			pubKeyBytes, err := k.ExportPubKeyBytes(keyID)
+			require.NoError(t, err)
+			require.NotEmpty(t, pubKeyBytes)
+
+			pubKH, err := keyset.ReadWithNoSecrets(keyset.NewBinaryReader(bytes.NewReader(pubKeyBytes)))
+			require.NoError(t, err)
+
+			signer, err := signature.NewSigner(kh.(*keyset.Handle))
+			require.NoError(t, err)
+
+			sig, err := signer.Sign([]byte("message"))
+			require.NoError(t, err)
+
+			verifier, err := signature.NewVerifier(pubKH)
+			require.NoError(t, err)
+			require.NoError(t, verifier.Verify(sig, []byte("message")))
+		})
+	}
+
+	t.Run("test export key wrapping public key", func(t *testing.T) {
+		keyID, kh, err := k.Create(kms.ECDHX25519KWType)
+		require.NoError(t, err)
+
+		pubKeyBytes, err := k.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+
+		pubKH, err := keyset.ReadWithNoSecrets(keyset.NewBinaryReader(bytes.NewReader(pubKeyBytes)))
+		require.NoError(t, err)
+
+		pubKey, err := ecdhkw.PublicKey(pubKH)
+		require.NoError(t, err)
+
+		expected, err := ecdhkw.PublicKey(kh.(*keyset.Handle))
+		require.NoError(t, err)
+		require.Equal(t, expected, pubKey)
+	})
+
+	t.Run("test export symmetric key fails", func(t *testing.T) {
+		keyID, _, err := k.Create(kms.AES256GCMType)
+		require.NoError(t, err)
+
+		_, err = k.ExportPubKeyBytes(keyID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to get public keyset handle")
+	})
+
+	t.Run("test export unknown key fails", func(t *testing.T) {
+		_, err := k.ExportPubKeyBytes("unknown")
+		require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+	})
+}
+
+func newLocalKMS(t *testing.T, storeProvider storage.Provider) *LocalKMS {
+	k, err := New(PrimaryKeyURI, &mockProvider{storage: storeProvider, secretLock: newTestSecretLock(t)})
+	require.NoError(t, err)
+
+	return k
+}
+
+func newTestSecretLock(t *testing.T) secretlock.Service {
+	masterKey, err := local.GenerateMasterKey()
+	require.NoError(t, err)
+
+	s, err := local.NewService(masterKey)
+	require.NoError(t, err)
+
+	return s
+}
+
+type mockProvider struct {
+	storage    storage.Provider
+	secretLock secretlock.Service
+}
+
+func (m *mockProvider) StorageProvider() storage.Provider {
+	return m.storage
+}
+
+func (m *mockProvider) SecretLock() secretlock.Service {
+	return m.secretLock
+}
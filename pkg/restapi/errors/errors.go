@@ -38,6 +38,12 @@ const (
 
 	// Introduce error group for Introduce protocol rest api errors
 	Introduce Group = 3000
+
+	// TrustPing error group for Trust Ping protocol rest api errors
+	TrustPing Group = 4000
+
+	// Route error group for Route Coordination protocol rest api errors
+	Route Group = 5000
 )
 
 // Code is the error code of aries rest api errors
@@ -79,6 +79,14 @@ func (m *msgService) Accept(msgType string, purpose []string) bool {
 	return purposeMatched && typeMatched
 }
 
+func (m *msgService) MessageTypes() []string {
+	if m.msgType == "" {
+		return nil
+	}
+
+	return []string{m.msgType}
+}
+
 func (m *msgService) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
 	if m.name == "" || m.topicHandle == nil {
 		return "", fmt.Errorf(errTopicNotFound)
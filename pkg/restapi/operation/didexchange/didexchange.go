@@ -35,6 +35,8 @@ const (
 	createInvitationPath         = operationID + "/create-invitation"
 	createImplicitInvitationPath = operationID + "/create-implicit-invitation"
 	receiveInvitationPath        = operationID + "/receive-invitation"
+	invitationURLPath            = operationID + "/invitation-url"
+	receiveInvitationURLPath     = operationID + "/receive-invitation-url"
 	acceptInvitationPath         = operationID + "/{id}/accept-invitation"
 	connections                  = operationID
 	connectionsByID              = operationID + "/{id}"
@@ -68,6 +70,12 @@ const (
 
 	// RemoveConnectionErrorCode is for failures in remove connection endpoint
 	RemoveConnectionErrorCode
+
+	// InvitationURLErrorCode is for failures in invitation URL endpoint
+	InvitationURLErrorCode
+
+	// ReceiveInvitationURLErrorCode is for failures in receive invitation URL endpoint
+	ReceiveInvitationURLErrorCode
 )
 
 // provider contains dependencies for the Exchange protocol and is typically created by using aries.Context()
@@ -142,10 +150,12 @@ func (c *Operation) CreateInvitation(rw http.ResponseWriter, req *http.Request)
 		return
 	}
 
-	var alias, did string
+	var alias, did, routerConnID, baseURL string
 	if request.CreateInvitationParams != nil {
 		alias = request.CreateInvitationParams.Alias
 		did = request.CreateInvitationParams.Public
+		routerConnID = request.CreateInvitationParams.RouterConnectionID
+		baseURL = request.CreateInvitationParams.BaseURL
 	}
 
 	var invitation *didexchange.Invitation
@@ -153,7 +163,7 @@ func (c *Operation) CreateInvitation(rw http.ResponseWriter, req *http.Request)
 	if did != "" {
 		invitation, err = c.client.CreateInvitationWithDID(c.defaultLabel, did)
 	} else {
-		invitation, err = c.client.CreateInvitation(c.defaultLabel)
+		invitation, err = c.client.CreateInvitation(c.defaultLabel, didexchange.WithRouterConnectionID(routerConnID))
 	}
 
 	if err != nil {
@@ -161,9 +171,20 @@ func (c *Operation) CreateInvitation(rw http.ResponseWriter, req *http.Request)
 		return
 	}
 
+	var invitationURL string
+
+	if baseURL != "" {
+		invitationURL, err = didexchange.InvitationURL(baseURL, invitation)
+		if err != nil {
+			resterrors.SendHTTPBadRequest(rw, InvalidRequestErrorCode, err)
+			return
+		}
+	}
+
 	c.writeResponse(rw, &models.CreateInvitationResponse{
-		Invitation: invitation,
-		Alias:      alias})
+		Invitation:    invitation,
+		Alias:         alias,
+		InvitationURL: invitationURL})
 }
 
 // ReceiveInvitation swagger:route POST /connections/receive-invitation did-exchange receiveInvitation
@@ -197,6 +218,81 @@ func (c *Operation) ReceiveInvitation(rw http.ResponseWriter, req *http.Request)
 	c.writeResponse(rw, resp)
 }
 
+// InvitationURL swagger:route POST /connections/invitation-url did-exchange invitationURL
+//
+// Renders the connection invitation as the URL with the `c_i` parameter....
+//
+// Responses:
+//    default: genericError
+//        200: invitationURLResponse
+func (c *Operation) InvitationURL(rw http.ResponseWriter, req *http.Request) {
+	logger.Debugf("Rendering connection invitation URL ")
+
+	var request models.InvitationURLRequest
+
+	err := json.NewDecoder(req.Body).Decode(&request.Params)
+	if err != nil {
+		resterrors.SendHTTPBadRequest(rw, InvalidRequestErrorCode, err)
+		return
+	}
+
+	if request.Params == nil || request.Params.BaseURL == "" {
+		resterrors.SendHTTPBadRequest(rw, InvalidRequestErrorCode, fmt.Errorf("empty base URL"))
+		return
+	}
+
+	invitationURL, err := didexchange.InvitationURL(request.Params.BaseURL, request.Params.Invitation)
+	if err != nil {
+		resterrors.SendHTTPBadRequest(rw, InvitationURLErrorCode, err)
+		return
+	}
+
+	c.writeResponse(rw, &models.InvitationURLResponse{
+		InvitationURL: invitationURL,
+	})
+}
+
+// ReceiveInvitationURL swagger:route POST /connections/receive-invitation-url did-exchange receiveInvitationURL
+//
+// Receive a new connection invitation from the invitation URL with the `c_i` parameter....
+//
+// Responses:
+//    default: genericError
+//        200: receiveInvitationResponse
+func (c *Operation) ReceiveInvitationURL(rw http.ResponseWriter, req *http.Request) {
+	logger.Debugf("Receiving connection invitation URL ")
+
+	var request models.ReceiveInvitationURLRequest
+
+	err := json.NewDecoder(req.Body).Decode(&request.Params)
+	if err != nil {
+		resterrors.SendHTTPBadRequest(rw, InvalidRequestErrorCode, err)
+		return
+	}
+
+	if request.Params == nil || request.Params.InvitationURL == "" {
+		resterrors.SendHTTPBadRequest(rw, InvalidRequestErrorCode, fmt.Errorf("empty invitation URL"))
+		return
+	}
+
+	// the short URL is not resolved to not make the agent fetch the URLs supplied by the caller
+	invitation, err := didexchange.ParseInvitationURL(request.Params.InvitationURL, didexchange.WithoutShortURL())
+	if err != nil {
+		resterrors.SendHTTPBadRequest(rw, ReceiveInvitationURLErrorCode, err)
+		return
+	}
+
+	connectionID, err := c.client.HandleInvitation(invitation)
+	if err != nil {
+		resterrors.SendHTTPInternalServerError(rw, ReceiveInvitationErrorCode, err)
+		return
+	}
+
+	c.writeResponse(rw, models.ReceiveInvitationResponse{
+		ConnectionID: connectionID,
+	})
+}
+
 // AcceptInvitation swagger:route POST /connections/{id}/accept-invitation did-exchange acceptInvitation
 //
 // Accept a stored connection invitation....
@@ -431,6 +527,8 @@ func (c *Operation) registerHandler() {
 		support.NewHTTPHandler(createInvitationPath, http.MethodPost, c.CreateInvitation),
 		support.NewHTTPHandler(createImplicitInvitationPath, http.MethodPost, c.CreateImplicitInvitation),
 		support.NewHTTPHandler(receiveInvitationPath, http.MethodPost, c.ReceiveInvitation),
+		support.NewHTTPHandler(invitationURLPath, http.MethodPost, c.InvitationURL),
+		support.NewHTTPHandler(receiveInvitationURLPath, http.MethodPost, c.ReceiveInvitationURL),
 		support.NewHTTPHandler(acceptInvitationPath, http.MethodPost, c.AcceptInvitation),
 		support.NewHTTPHandler(acceptExchangeRequest, http.MethodPost, c.AcceptExchangeRequest),
 		support.NewHTTPHandler(removeConnection, http.MethodPost, c.RemoveConnection),
@@ -239,6 +239,141 @@ func TestOperation_ReceiveInvitationFailure(t *testing.T) {
 	verifyRESTError(t, InvalidRequestErrorCode, buf.Bytes())
 }
 
+func TestOperation_InvitationURL(t *testing.T) {
+	t.Run("test invitation URL success", func(t *testing.T) {
+		var jsonStr = []byte(`{
+		"base_url":"https://example.com/connect",
+		"invitation":{
+			"serviceEndpoint":"http://alice.agent.example.com:8081",
+			"recipientKeys":["FDmegH8upiNquathbHZiGBZKwcudNfNWPeGQFBt8eNNi"],
+			"@id":"a35c0ac6-4fc3-46af-a072-c1036d036057",
+			"label":"agent",
+			"@type":"https://didcomm.org/didexchange/1.0/invitation"}}`)
+
+		handler := getHandler(t, invitationURLPath)
+		buf, err := getSuccessResponseFromHandler(handler, bytes.NewBuffer(jsonStr), handler.Path())
+		require.NoError(t, err)
+
+		response := models.InvitationURLResponse{}
+		err = json.Unmarshal(buf.Bytes(), &response)
+		require.NoError(t, err)
+
+		invitation, err := didexchange.ParseInvitationURL(response.InvitationURL)
+		require.NoError(t, err)
+		require.Equal(t, "a35c0ac6-4fc3-46af-a072-c1036d036057", invitation.ID)
+		require.Equal(t, "agent", invitation.Label)
+	})
+
+	t.Run("test invitation URL failures", func(t *testing.T) {
+		handler := getHandler(t, invitationURLPath)
+
+		buf, code, err := sendRequestToHandler(handler, bytes.NewBufferString("{"), handler.Path())
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyRESTError(t, InvalidRequestErrorCode, buf.Bytes())
+
+		buf, code, err = sendRequestToHandler(handler, bytes.NewBufferString(`{"invitation":{}}`), handler.Path())
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyRESTError(t, InvalidRequestErrorCode, buf.Bytes())
+
+		buf, code, err = sendRequestToHandler(handler, bytes.NewBufferString(`{"base_url":"https://example.com"}`),
+			handler.Path())
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyRESTError(t, InvitationURLErrorCode, buf.Bytes())
+	})
+
+	t.Run("test create invitation with invitation URL", func(t *testing.T) {
+		handler := getHandler(t, createInvitationPath)
+		buf, err := getSuccessResponseFromHandler(handler, nil,
+			handler.Path()+"?alias=mylabel&base_url=https://example.com/connect")
+		require.NoError(t, err)
+
+		response := models.CreateInvitationResponse{}
+		err = json.Unmarshal(buf.Bytes(), &response)
+		require.NoError(t, err)
+
+		invitation, err := didexchange.ParseInvitationURL(response.InvitationURL)
+		require.NoError(t, err)
+		require.Equal(t, response.Invitation.ID, invitation.ID)
+
+		buf, code, err := sendRequestToHandler(handler, nil, handler.Path()+"?base_url=%25")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyRESTError(t, InvalidRequestErrorCode, buf.Bytes())
+	})
+}
+
+func TestOperation_ReceiveInvitationURL(t *testing.T) {
+	invitationURL, err := didexchange.InvitationURL("https://example.com/connect",
+		&didexchange.Invitation{Invitation: &didexsvc.Invitation{
+			ID:              "a35c0ac6-4fc3-46af-a072-c1036d036057",
+			Type:            didexchange.InvitationMsgType,
+			Label:           "agent",
+			RecipientKeys:   []string{"FDmegH8upiNquathbHZiGBZKwcudNfNWPeGQFBt8eNNi"},
+			ServiceEndpoint: "http://alice.agent.example.com:8081",
+		}})
+	require.NoError(t, err)
+
+	request, err := json.Marshal(&models.ReceiveInvitationURLParams{InvitationURL: invitationURL})
+	require.NoError(t, err)
+
+	t.Run("test receive invitation URL success", func(t *testing.T) {
+		handler := getHandler(t, receiveInvitationURLPath)
+		buf, err := getSuccessResponseFromHandler(handler, bytes.NewBuffer(request), handler.Path())
+		require.NoError(t, err)
+
+		response := models.ReceiveInvitationResponse{}
+		err = json.Unmarshal(buf.Bytes(), &response)
+		require.NoError(t, err)
+		require.NotEmpty(t, response.ConnectionID)
+	})
+
+	t.Run("test receive invitation URL failures", func(t *testing.T) {
+		handler := getHandler(t, receiveInvitationURLPath)
+
+		buf, code, err := sendRequestToHandler(handler, bytes.NewBufferString("{"), handler.Path())
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyRESTError(t, InvalidRequestErrorCode, buf.Bytes())
+
+		buf, code, err = sendRequestToHandler(handler, bytes.NewBufferString("{}"), handler.Path())
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyRESTError(t, InvalidRequestErrorCode, buf.Bytes())
+
+		buf, code, err = sendRequestToHandler(handler,
+			bytes.NewBufferString(`{"invitation_url":"https://example.com?c_i=@@@"}`), handler.Path())
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyRESTError(t, ReceiveInvitationURLErrorCode, buf.Bytes())
+	})
+
+	t.Run("test receive invitation URL - short URL is not resolved", func(t *testing.T) {
+		fetched := false
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			fetched = true
+			http.Redirect(rw, req, invitationURL, http.StatusFound)
+		}))
+		defer server.Close()
+
+		handler := getHandler(t, receiveInvitationURLPath)
+		buf, code, err := sendRequestToHandler(handler,
+			bytes.NewBufferString(`{"invitation_url":"`+server.URL+`/short"}`), handler.Path())
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyRESTError(t, ReceiveInvitationURLErrorCode, buf.Bytes())
+		require.False(t, fetched)
+
+		handler = getHandlerWithError(t, receiveInvitationURLPath, errors.New("handler failed"), nil, nil)
+		buf, code, err = sendRequestToHandler(handler, bytes.NewBuffer(request), handler.Path())
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyRESTError(t, ReceiveInvitationErrorCode, buf.Bytes())
+	})
+}
+
 func TestOperation_AcceptInvitation(t *testing.T) {
 	t.Run("test accept invitation success", func(t *testing.T) {
 		handler := getHandler(t, acceptInvitationPath)
@@ -35,6 +35,14 @@ type CreateInvitationParams struct {
 
 	// Optional public DID to be used in invitation
 	Public string `json:"public,omitempty"`
+
+	// Optional connection ID of the router the invitation keys are advertised through,
+	// the default router is used if not provided
+	RouterConnectionID string `json:"router_connection_id,omitempty"`
+
+	// Optional base URL the invitation URL is rendered with, the invitation URL is
+	// returned only if provided
+	BaseURL string `json:"base_url,omitempty"`
 }
 
 // CreateInvitationResponse model
@@ -110,6 +118,70 @@ type ReceiveInvitationResponse struct {
 	InviterLabel string `json:"their_label,omitempty"`
 }
 
+// InvitationURLRequest model
+//
+// This is used for operation to render the invitation URL
+//
+// swagger:parameters invitationURL
+type InvitationURLRequest struct {
+	// Params for rendering the invitation URL
+	//
+	// required: true
+	// in: body
+	Params *InvitationURLParams `json:""`
+}
+
+// InvitationURLParams model
+//
+// This is used for rendering the invitation URL
+//
+type InvitationURLParams struct {
+	// The invitation to be encoded in the `c_i` parameter of the URL
+	//
+	// required: true
+	Invitation *didexchange.Invitation `json:"invitation"`
+
+	// The base URL of the invitation URL, e.g. https://example.com/connect
+	//
+	// required: true
+	BaseURL string `json:"base_url"`
+}
+
+// InvitationURLResponse model
+//
+// This is used for returning the invitation URL
+//
+// swagger:response invitationURLResponse
+type InvitationURLResponse struct {
+
+	// in: body
+	InvitationURL string `json:"invitation_url"`
+}
+
+// ReceiveInvitationURLRequest model
+//
+// This is used for operation to receive connection invitation URL
+//
+// swagger:parameters receiveInvitationURL
+type ReceiveInvitationURLRequest struct {
+	// Params for receiving the invitation URL
+	//
+	// required: true
+	// in: body
+	Params *ReceiveInvitationURLParams `json:""`
+}
+
+// ReceiveInvitationURLParams model
+//
+// This is used for receiving connection invitation URL
+//
+type ReceiveInvitationURLParams struct {
+	// The invitation URL with the `c_i` parameter, the short URL is not supported
+	//
+	// required: true
+	InvitationURL string `json:"invitation_url"`
+}
+
 // AcceptInvitationRequest model
 //
 // This is used for operation to accept connection invitation
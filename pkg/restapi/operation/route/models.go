@@ -0,0 +1,95 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package route
+
+// RouterConnection model
+//
+// This is used for registering and unregistering the router
+//
+// swagger:parameters registerRouter unregisterRouter
+type RouterConnection struct {
+	// Params for the router connection
+	//
+	// in: body
+	Params RouterConnectionParams
+}
+
+// RouterConnectionParams contains the connection ID of the router
+type RouterConnectionParams struct {
+	// The ID of the connection with the router
+	ConnectionID string `json:"connection_id"`
+}
+
+// RouterIDParam model
+//
+// This is used for selecting the router by connection ID
+//
+// swagger:parameters setDefaultRouter routerConfig queryKeylist
+type RouterIDParam struct {
+	// The ID of the connection with the router
+	//
+	// in: path
+	// required: true
+	ID string `json:"id"`
+}
+
+// QueryRoutersResponse model
+//
+// This is used for returning the connection IDs of the registered routers
+//
+// swagger:response queryRoutersResponse
+type QueryRoutersResponse struct {
+	// in: body
+	Routers []string `json:"routers"`
+}
+
+// RouterConfigResponse model
+//
+// This is used for returning the router endpoint and routing keys
+//
+// swagger:response routerConfigResponse
+type RouterConfigResponse struct {
+	// in: body
+	Endpoint string `json:"endpoint"`
+
+	// in: body
+	RoutingKeys []string `json:"routing_keys"`
+}
+
+// QueryKeylistResponse model
+//
+// This is used for returning the recipient keys registered with the router
+//
+// swagger:response queryKeylistResponse
+type QueryKeylistResponse struct {
+	// in: body
+	Keys []string `json:"keys"`
+}
+
+// RemoveKeyRequest model
+//
+// This is used for removing the recipient key from the router
+//
+// swagger:parameters removeKey
+type RemoveKeyRequest struct {
+	// The ID of the connection with the router
+	//
+	// in: path
+	// required: true
+	ID string `json:"id"`
+
+	// Params for removing the key
+	//
+	// in: body
+	Params RemoveKeyParams
+}
+
+// RemoveKeyParams contains the recipient key to be removed
+type RemoveKeyParams struct {
+	// The recipient key to be removed
+	RecipientKey string `json:"recipient_key"`
+}
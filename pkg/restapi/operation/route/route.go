@@ -0,0 +1,306 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package route
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/hyperledger/aries-framework-go/pkg/client/route"
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	routesvc "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/route"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/common/support"
+	resterrors "github.com/hyperledger/aries-framework-go/pkg/restapi/errors"
+	"github.com/hyperledger/aries-framework-go/pkg/restapi/operation"
+)
+
+var logger = log.New("aries-framework/controller/route")
+
+const (
+	operationID      = "/route"
+	registerPath     = operationID + "/register"
+	unregisterPath   = operationID + "/unregister"
+	routersPath      = operationID + "/routers"
+	defaultPath      = routersPath + "/{id}/default"
+	configPath       = routersPath + "/{id}/config"
+	keylistPath      = routersPath + "/{id}/keys"
+	removeKeyPath    = routersPath + "/{id}/remove-key"
+	emptyRouterIDErr = "empty router connection ID"
+)
+
+const (
+	// InvalidRequestErrorCode is typically a code for validation errors
+	// for invalid route controller requests
+	InvalidRequestErrorCode = resterrors.Code(iota + resterrors.Route)
+
+	// RegisterRouterErrorCode is for failures in register router endpoint
+	RegisterRouterErrorCode
+
+	// UnregisterRouterErrorCode is for failures in unregister router endpoint
+	UnregisterRouterErrorCode
+
+	// QueryRoutersErrorCode is for failures in query routers endpoint
+	QueryRoutersErrorCode
+
+	// SetDefaultRouterErrorCode is for failures in set default router endpoint
+	SetDefaultRouterErrorCode
+
+	// RouterConfigErrorCode is for failures in router config endpoint
+	RouterConfigErrorCode
+
+	// QueryKeylistErrorCode is for failures in query keylist endpoint
+	QueryKeylistErrorCode
+
+	// RemoveKeyErrorCode is for failures in remove key endpoint
+	RemoveKeyErrorCode
+)
+
+// provider contains dependencies for the Route Coordination protocol and is typically created by using aries.Context()
+type provider interface {
+	Service(id string) (interface{}, error)
+}
+
+// New returns new Route Coordination rest client protocol instance
+func New(ctx provider) (*Operation, error) {
+	client, err := route.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := &Operation{client: client}
+	svc.registerHandler()
+
+	return svc, nil
+}
+
+// Operation is controller REST service controller for Route Coordination
+type Operation struct {
+	client   *route.Client
+	handlers []operation.Handler
+}
+
+// RegisterRouter swagger:route POST /route/register route registerRouter
+//
+// Registers the agent with the router on the other end of the connection.
+//
+// Responses:
+//    default: genericError
+func (c *Operation) RegisterRouter(rw http.ResponseWriter, req *http.Request) {
+	var request RouterConnection
+
+	err := json.NewDecoder(req.Body).Decode(&request.Params)
+	if err != nil {
+		resterrors.SendHTTPBadRequest(rw, InvalidRequestErrorCode, err)
+		return
+	}
+
+	if request.Params.ConnectionID == "" {
+		resterrors.SendHTTPBadRequest(rw, InvalidRequestErrorCode, errors.New(emptyRouterIDErr))
+		return
+	}
+
+	logger.Debugf("Registering router for connection id [%s]", request.Params.ConnectionID)
+
+	err = c.client.Register(request.Params.ConnectionID)
+	if err != nil {
+		resterrors.SendHTTPInternalServerError(rw, RegisterRouterErrorCode, err)
+		return
+	}
+}
+
+// UnregisterRouter swagger:route POST /route/unregister route unregisterRouter
+//
+// Unregisters the agent from the router on the other end of the connection, the default router
+// if the connection ID is empty.
+//
+// Responses:
+//    default: genericError
+func (c *Operation) UnregisterRouter(rw http.ResponseWriter, req *http.Request) {
+	var request RouterConnection
+
+	err := json.NewDecoder(req.Body).Decode(&request.Params)
+	if err != nil {
+		resterrors.SendHTTPBadRequest(rw, InvalidRequestErrorCode, err)
+		return
+	}
+
+	logger.Debugf("Unregistering router for connection id [%s]", request.Params.ConnectionID)
+
+	err = c.client.Unregister(request.Params.ConnectionID)
+	if err != nil {
+		sendError(rw, UnregisterRouterErrorCode, err)
+		return
+	}
+}
+
+// QueryRouters swagger:route GET /route/routers route queryRouters
+//
+// Returns the connection IDs of the routers the agent is registered with.
+//
+// Responses:
+//    default: genericError
+//        200: queryRoutersResponse
+func (c *Operation) QueryRouters(rw http.ResponseWriter, _ *http.Request) {
+	routers, err := c.client.Routers()
+	if err != nil {
+		resterrors.SendHTTPInternalServerError(rw, QueryRoutersErrorCode, err)
+		return
+	}
+
+	c.writeResponse(rw, &QueryRoutersResponse{Routers: routers})
+}
+
+// SetDefaultRouter swagger:route POST /route/routers/{id}/default route setDefaultRouter
+//
+// Sets the router as the default router, the keys of the new connections are advertised through the default router.
+//
+// Responses:
+//    default: genericError
+func (c *Operation) SetDefaultRouter(rw http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)["id"]
+	if id == "" {
+		resterrors.SendHTTPBadRequest(rw, InvalidRequestErrorCode, errors.New(emptyRouterIDErr))
+		return
+	}
+
+	logger.Debugf("Setting default router for connection id [%s]", id)
+
+	err := c.client.SetDefaultRouter(id)
+	if err != nil {
+		sendError(rw, SetDefaultRouterErrorCode, err)
+		return
+	}
+}
+
+// RouterConfig swagger:route GET /route/routers/{id}/config route routerConfig
+//
+// Returns the endpoint and routing keys of the router.
+//
+// Responses:
+//    default: genericError
+//        200: routerConfigResponse
+func (c *Operation) RouterConfig(rw http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)["id"]
+	if id == "" {
+		resterrors.SendHTTPBadRequest(rw, InvalidRequestErrorCode, errors.New(emptyRouterIDErr))
+		return
+	}
+
+	conf, err := c.client.Config(id)
+	if err != nil {
+		sendError(rw, RouterConfigErrorCode, err)
+		return
+	}
+
+	c.writeResponse(rw, &RouterConfigResponse{
+		Endpoint:    conf.Endpoint(),
+		RoutingKeys: conf.Keys(),
+	})
+}
+
+// QueryKeylist swagger:route GET /route/routers/{id}/keys route queryKeylist
+//
+// Queries the recipient keys of the agent registered with the router.
+//
+// Responses:
+//    default: genericError
+//        200: queryKeylistResponse
+func (c *Operation) QueryKeylist(rw http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)["id"]
+	if id == "" {
+		resterrors.SendHTTPBadRequest(rw, InvalidRequestErrorCode, errors.New(emptyRouterIDErr))
+		return
+	}
+
+	logger.Debugf("Querying keylist for router connection id [%s]", id)
+
+	keys, err := c.client.Keylist(id)
+	if err != nil {
+		sendError(rw, QueryKeylistErrorCode, err)
+		return
+	}
+
+	c.writeResponse(rw, &QueryKeylistResponse{Keys: keys})
+}
+
+// RemoveKey swagger:route POST /route/routers/{id}/remove-key route removeKey
+//
+// Removes the recipient key of the agent from the router.
+//
+// Responses:
+//    default: genericError
+func (c *Operation) RemoveKey(rw http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)["id"]
+	if id == "" {
+		resterrors.SendHTTPBadRequest(rw, InvalidRequestErrorCode, errors.New(emptyRouterIDErr))
+		return
+	}
+
+	var request RemoveKeyRequest
+
+	err := json.NewDecoder(req.Body).Decode(&request.Params)
+	if err != nil {
+		resterrors.SendHTTPBadRequest(rw, InvalidRequestErrorCode, err)
+		return
+	}
+
+	if request.Params.RecipientKey == "" {
+		resterrors.SendHTTPBadRequest(rw, InvalidRequestErrorCode, fmt.Errorf("empty recipient key"))
+		return
+	}
+
+	logger.Debugf("Removing key [%s] from router connection id [%s]", request.Params.RecipientKey, id)
+
+	err = c.client.RemoveKey(id, request.Params.RecipientKey)
+	if err != nil {
+		sendError(rw, RemoveKeyErrorCode, err)
+		return
+	}
+}
+
+// sendError sends http status code NOT FOUND if the router is not registered,
+// INTERNAL SERVER ERROR otherwise
+func sendError(rw http.ResponseWriter, code resterrors.Code, err error) {
+	if errors.Is(err, routesvc.ErrRouterNotRegistered) {
+		resterrors.SendHTTPStatusError(rw, code, err, http.StatusNotFound)
+		return
+	}
+
+	resterrors.SendHTTPInternalServerError(rw, code, err)
+}
+
+// writeResponse writes interface value to response
+func (c *Operation) writeResponse(rw io.Writer, v interface{}) {
+	err := json.NewEncoder(rw).Encode(v)
+	// as of now, just log errors for writing response
+	if err != nil {
+		logger.Errorf("Unable to send error response, %s", err)
+	}
+}
+
+// GetRESTHandlers get all controller API handler available for this protocol service
+func (c *Operation) GetRESTHandlers() []operation.Handler {
+	return c.handlers
+}
+
+// registerHandler register handlers to be exposed from this protocol service as REST API endpoints
+func (c *Operation) registerHandler() {
+	c.handlers = []operation.Handler{
+		support.NewHTTPHandler(registerPath, http.MethodPost, c.RegisterRouter),
+		support.NewHTTPHandler(unregisterPath, http.MethodPost, c.UnregisterRouter),
+		support.NewHTTPHandler(routersPath, http.MethodGet, c.QueryRouters),
+		support.NewHTTPHandler(defaultPath, http.MethodPost, c.SetDefaultRouter),
+		support.NewHTTPHandler(configPath, http.MethodGet, c.RouterConfig),
+		support.NewHTTPHandler(keylistPath, http.MethodGet, c.QueryKeylist),
+		support.NewHTTPHandler(removeKeyPath, http.MethodPost, c.RemoveKey),
+	}
+}
@@ -0,0 +1,267 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package route
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/route"
+	mockroute "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/route"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
+	resterrors "github.com/hyperledger/aries-framework-go/pkg/restapi/errors"
+	"github.com/hyperledger/aries-framework-go/pkg/restapi/operation"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test new - success", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{ServiceValue: &mockroute.MockRouteSvc{}})
+		require.NoError(t, err)
+		require.Len(t, svc.GetRESTHandlers(), 7)
+	})
+
+	t.Run("test new - service error", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{ServiceErr: errors.New("service error")})
+		require.EqualError(t, err, "service error")
+		require.Nil(t, svc)
+	})
+}
+
+func TestOperation_RegisterRouter(t *testing.T) {
+	t.Run("test register router - success", func(t *testing.T) {
+		handler := getHandler(t, registerPath, &mockroute.MockRouteSvc{
+			RegisterFunc: func(connectionID string) error {
+				require.Equal(t, "conn1", connectionID)
+				return nil
+			}})
+
+		_, code := sendRequestToHandler(t, handler, `{"connection_id":"conn1"}`, registerPath)
+		require.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("test register router - invalid request", func(t *testing.T) {
+		handler := getHandler(t, registerPath, &mockroute.MockRouteSvc{})
+
+		buf, code := sendRequestToHandler(t, handler, `{`, registerPath)
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyError(t, InvalidRequestErrorCode, "", buf.Bytes())
+
+		buf, code = sendRequestToHandler(t, handler, `{}`, registerPath)
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyError(t, InvalidRequestErrorCode, emptyRouterIDErr, buf.Bytes())
+	})
+
+	t.Run("test register router - error", func(t *testing.T) {
+		handler := getHandler(t, registerPath, &mockroute.MockRouteSvc{
+			RegisterFunc: func(string) error {
+				return errors.New("router is already registered")
+			}})
+
+		buf, code := sendRequestToHandler(t, handler, `{"connection_id":"conn1"}`, registerPath)
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyError(t, RegisterRouterErrorCode, "router is already registered", buf.Bytes())
+	})
+}
+
+func TestOperation_UnregisterRouter(t *testing.T) {
+	t.Run("test unregister router - success", func(t *testing.T) {
+		handler := getHandler(t, unregisterPath, &mockroute.MockRouteSvc{})
+
+		_, code := sendRequestToHandler(t, handler, `{"connection_id":"conn1"}`, unregisterPath)
+		require.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("test unregister router - invalid request", func(t *testing.T) {
+		handler := getHandler(t, unregisterPath, &mockroute.MockRouteSvc{})
+
+		buf, code := sendRequestToHandler(t, handler, `{`, unregisterPath)
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyError(t, InvalidRequestErrorCode, "", buf.Bytes())
+	})
+
+	t.Run("test unregister router - router not registered", func(t *testing.T) {
+		handler := getHandler(t, unregisterPath, &mockroute.MockRouteSvc{UnregisterErr: route.ErrRouterNotRegistered})
+
+		buf, code := sendRequestToHandler(t, handler, `{}`, unregisterPath)
+		require.Equal(t, http.StatusNotFound, code)
+		verifyError(t, UnregisterRouterErrorCode, "router not registered", buf.Bytes())
+	})
+}
+
+func TestOperation_QueryRouters(t *testing.T) {
+	t.Run("test query routers - success", func(t *testing.T) {
+		handler := getHandler(t, routersPath, &mockroute.MockRouteSvc{RoutersValue: []string{"conn1", "conn2"}})
+
+		buf, code := sendRequestToHandler(t, handler, "", routersPath)
+		require.Equal(t, http.StatusOK, code)
+
+		response := QueryRoutersResponse{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &response))
+		require.Equal(t, []string{"conn1", "conn2"}, response.Routers)
+	})
+
+	t.Run("test query routers - error", func(t *testing.T) {
+		handler := getHandler(t, routersPath, &mockroute.MockRouteSvc{RoutersErr: errors.New("iterator error")})
+
+		buf, code := sendRequestToHandler(t, handler, "", routersPath)
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyError(t, QueryRoutersErrorCode, "iterator error", buf.Bytes())
+	})
+}
+
+func TestOperation_SetDefaultRouter(t *testing.T) {
+	t.Run("test set default router - success", func(t *testing.T) {
+		handler := getHandler(t, defaultPath, &mockroute.MockRouteSvc{})
+
+		_, code := sendRequestToHandler(t, handler, "", "/route/routers/conn1/default")
+		require.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("test set default router - router not registered", func(t *testing.T) {
+		handler := getHandler(t, defaultPath, &mockroute.MockRouteSvc{SetDefaultErr: route.ErrRouterNotRegistered})
+
+		buf, code := sendRequestToHandler(t, handler, "", "/route/routers/conn1/default")
+		require.Equal(t, http.StatusNotFound, code)
+		verifyError(t, SetDefaultRouterErrorCode, "router not registered", buf.Bytes())
+	})
+}
+
+func TestOperation_RouterConfig(t *testing.T) {
+	t.Run("test router config - success", func(t *testing.T) {
+		handler := getHandler(t, configPath, &mockroute.MockRouteSvc{
+			RouterEndpoint: "http://router.example.com",
+			RoutingKeys:    []string{"abc"},
+		})
+
+		buf, code := sendRequestToHandler(t, handler, "", "/route/routers/conn1/config")
+		require.Equal(t, http.StatusOK, code)
+
+		response := RouterConfigResponse{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &response))
+		require.Equal(t, "http://router.example.com", response.Endpoint)
+		require.Equal(t, []string{"abc"}, response.RoutingKeys)
+	})
+
+	t.Run("test router config - error", func(t *testing.T) {
+		handler := getHandler(t, configPath, &mockroute.MockRouteSvc{ConfigErr: errors.New("config error")})
+
+		buf, code := sendRequestToHandler(t, handler, "", "/route/routers/conn1/config")
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyError(t, RouterConfigErrorCode, "config error", buf.Bytes())
+	})
+}
+
+func TestOperation_QueryKeylist(t *testing.T) {
+	t.Run("test query keylist - success", func(t *testing.T) {
+		handler := getHandler(t, keylistPath, &mockroute.MockRouteSvc{Keys: []string{"abc", "xyz"}})
+
+		buf, code := sendRequestToHandler(t, handler, "", "/route/routers/conn1/keys")
+		require.Equal(t, http.StatusOK, code)
+
+		response := QueryKeylistResponse{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &response))
+		require.Equal(t, []string{"abc", "xyz"}, response.Keys)
+	})
+
+	t.Run("test query keylist - error", func(t *testing.T) {
+		handler := getHandler(t, keylistPath, &mockroute.MockRouteSvc{KeylistErr: errors.New("keylist error")})
+
+		buf, code := sendRequestToHandler(t, handler, "", "/route/routers/conn1/keys")
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyError(t, QueryKeylistErrorCode, "keylist error", buf.Bytes())
+	})
+}
+
+func TestOperation_RemoveKey(t *testing.T) {
+	t.Run("test remove key - success", func(t *testing.T) {
+		handler := getHandler(t, removeKeyPath, &mockroute.MockRouteSvc{})
+
+		_, code := sendRequestToHandler(t, handler, `{"recipient_key":"abc"}`, "/route/routers/conn1/remove-key")
+		require.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("test remove key - invalid request", func(t *testing.T) {
+		handler := getHandler(t, removeKeyPath, &mockroute.MockRouteSvc{})
+
+		buf, code := sendRequestToHandler(t, handler, `{`, "/route/routers/conn1/remove-key")
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyError(t, InvalidRequestErrorCode, "", buf.Bytes())
+
+		buf, code = sendRequestToHandler(t, handler, `{}`, "/route/routers/conn1/remove-key")
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyError(t, InvalidRequestErrorCode, "empty recipient key", buf.Bytes())
+	})
+
+	t.Run("test remove key - error", func(t *testing.T) {
+		handler := getHandler(t, removeKeyPath, &mockroute.MockRouteSvc{RemoveKeyErr: errors.New("remove error")})
+
+		buf, code := sendRequestToHandler(t, handler, `{"recipient_key":"abc"}`, "/route/routers/conn1/remove-key")
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyError(t, RemoveKeyErrorCode, "remove error", buf.Bytes())
+	})
+}
+
+func TestOperation_EmptyRouterID(t *testing.T) {
+	svc, err := New(&mockprovider.Provider{ServiceValue: &mockroute.MockRouteSvc{}})
+	require.NoError(t, err)
+
+	for _, handle := range []http.HandlerFunc{svc.SetDefaultRouter, svc.RouterConfig, svc.QueryKeylist, svc.RemoveKey} {
+		rr := httptest.NewRecorder()
+		handle(rr, httptest.NewRequest(http.MethodPost, "/route/routers//keys", nil))
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		verifyError(t, InvalidRequestErrorCode, emptyRouterIDErr, rr.Body.Bytes())
+	}
+}
+
+func getHandler(t *testing.T, path string, svc *mockroute.MockRouteSvc) operation.Handler {
+	op, err := New(&mockprovider.Provider{ServiceValue: svc})
+	require.NoError(t, err)
+
+	for _, h := range op.GetRESTHandlers() {
+		if h.Path() == path {
+			return h
+		}
+	}
+
+	require.Failf(t, "handler not found", "path %s", path)
+
+	return nil
+}
+
+// sendRequestToHandler sends the request to the handler and returns the response body and status code
+func sendRequestToHandler(t *testing.T, handler operation.Handler, body, path string) (*bytes.Buffer, int) {
+	req, err := http.NewRequest(handler.Method(), path, bytes.NewBufferString(body))
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	return rr.Body, rr.Code
+}
+
+func verifyError(t *testing.T, expectedCode resterrors.Code, expectedMsg string, data []byte) {
+	t.Helper()
+
+	errResponse := struct {
+		Code    resterrors.Code `json:"code"`
+		Message string          `json:"message"`
+	}{}
+
+	require.NoError(t, json.Unmarshal(data, &errResponse))
+	require.Equal(t, expectedCode, errResponse.Code)
+	require.Contains(t, errResponse.Message, expectedMsg)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+// PingRequest model
+//
+// This is used for sending a trust ping to the other end of the connection
+//
+// swagger:parameters pingConnection
+type PingRequest struct {
+	// The ID of the connection to ping
+	//
+	// in: path
+	// required: true
+	ID string `json:"id"`
+
+	// Params for sending the ping
+	//
+	// in: query
+	*PingParams
+}
+
+// PingParams contains parameters for sending a trust ping
+type PingParams struct {
+	// Optional time to wait for the ping response (e.g. 500ms, 5s), 5s by default
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// PingResponse model
+//
+// This is used for returning the round-trip time of the trust ping
+//
+// swagger:response pingConnectionResponse
+type PingResponse struct {
+	// in: body
+	ConnectionID string `json:"connection_id"`
+
+	// Round-trip time of the ping (e.g. 1.5ms)
+	//
+	// in: body
+	RoundTripTime string `json:"round_trip_time"`
+}
@@ -0,0 +1,163 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/hyperledger/aries-framework-go/pkg/client/trustping"
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/common/support"
+	resterrors "github.com/hyperledger/aries-framework-go/pkg/restapi/errors"
+	"github.com/hyperledger/aries-framework-go/pkg/restapi/operation"
+)
+
+var logger = log.New("aries-framework/controller/trust-ping")
+
+const (
+	operationID    = "/connections"
+	pingConnection = operationID + "/{id}/ping"
+
+	defaultPingTimeout = 5 * time.Second
+)
+
+const (
+	// InvalidRequestErrorCode is typically a code for validation errors
+	// for invalid trust ping controller requests
+	InvalidRequestErrorCode = resterrors.Code(iota + resterrors.TrustPing)
+
+	// PingErrorCode is for failures in ping connection endpoint
+	PingErrorCode
+
+	// PingTimeoutErrorCode is for ping responses which are not received in time
+	PingTimeoutErrorCode
+)
+
+// provider contains dependencies for the Trust Ping protocol and is typically created by using aries.Context()
+type provider interface {
+	Service(id string) (interface{}, error)
+}
+
+// New returns new Trust Ping rest client protocol instance
+func New(ctx provider) (*Operation, error) {
+	client, err := trustping.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := &Operation{client: client}
+	svc.registerHandler()
+
+	return svc, nil
+}
+
+// Operation is controller REST service controller for Trust Ping
+type Operation struct {
+	client   *trustping.Client
+	handlers []operation.Handler
+}
+
+// PingConnection swagger:route POST /connections/{id}/ping trust-ping pingConnection
+//
+// Sends a trust ping to the other end of the connection and measures the round-trip time.
+//
+// Responses:
+//    default: genericError
+//        200: pingConnectionResponse
+func (c *Operation) PingConnection(rw http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)["id"]
+	if id == "" {
+		resterrors.SendHTTPBadRequest(rw, InvalidRequestErrorCode, fmt.Errorf("empty connection ID"))
+		return
+	}
+
+	var params PingParams
+
+	err := getQueryParams(&params, req.URL.Query())
+	if err != nil {
+		resterrors.SendHTTPBadRequest(rw, InvalidRequestErrorCode, err)
+		return
+	}
+
+	timeout := defaultPingTimeout
+
+	if params.Timeout != "" {
+		timeout, err = time.ParseDuration(params.Timeout)
+		if err != nil {
+			resterrors.SendHTTPBadRequest(rw, InvalidRequestErrorCode, fmt.Errorf("invalid timeout: %w", err))
+			return
+		}
+	}
+
+	logger.Debugf("Pinging connection for id [%s]", id)
+
+	rtt, err := c.client.Ping(id, timeout)
+	if errors.Is(err, trustping.ErrPingTimeout) {
+		resterrors.SendHTTPStatusError(rw, PingTimeoutErrorCode, err, http.StatusGatewayTimeout)
+		return
+	}
+
+	if err != nil {
+		resterrors.SendHTTPInternalServerError(rw, PingErrorCode, err)
+		return
+	}
+
+	c.writeResponse(rw, &PingResponse{
+		ConnectionID:  id,
+		RoundTripTime: rtt.String(),
+	})
+}
+
+// writeResponse writes interface value to response
+func (c *Operation) writeResponse(rw io.Writer, v interface{}) {
+	err := json.NewEncoder(rw).Encode(v)
+	// as of now, just log errors for writing response
+	if err != nil {
+		logger.Errorf("Unable to send error response, %s", err)
+	}
+}
+
+// GetRESTHandlers get all controller API handler available for this protocol service
+func (c *Operation) GetRESTHandlers() []operation.Handler {
+	return c.handlers
+}
+
+// registerHandler register handlers to be exposed from this protocol service as REST API endpoints
+func (c *Operation) registerHandler() {
+	c.handlers = []operation.Handler{
+		support.NewHTTPHandler(pingConnection, http.MethodPost, c.PingConnection),
+	}
+}
+
+// getQueryParams converts query strings to `map[string]string`
+// and unmarshals to the value pointed by v by following
+// `json.Unmarshal` rules.
+func getQueryParams(v interface{}, vals url.Values) error {
+	// normalize all query string key/values
+	args := make(map[string]string)
+
+	for k, v := range vals {
+		if len(v) > 0 {
+			args[k] = v[0]
+		}
+	}
+
+	bytes, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bytes, v)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/trustping"
+	mocktrustping "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/trustping"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
+	resterrors "github.com/hyperledger/aries-framework-go/pkg/restapi/errors"
+	"github.com/hyperledger/aries-framework-go/pkg/restapi/operation"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test new - success", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{ServiceValue: &mocktrustping.MockTrustPingSvc{}})
+		require.NoError(t, err)
+		require.Len(t, svc.GetRESTHandlers(), 1)
+	})
+
+	t.Run("test new - service error", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{ServiceErr: errors.New("service error")})
+		require.EqualError(t, err, "service error")
+		require.Nil(t, svc)
+	})
+}
+
+func TestOperation_PingConnection(t *testing.T) {
+	t.Run("test ping connection - success", func(t *testing.T) {
+		handler := getHandler(t, func(connectionID string, timeout time.Duration) (time.Duration, error) {
+			require.Equal(t, "1234", connectionID)
+			require.Equal(t, defaultPingTimeout, timeout)
+
+			return 3 * time.Millisecond, nil
+		})
+
+		buf, code := sendRequestToHandler(t, handler, "/connections/1234/ping")
+		require.Equal(t, http.StatusOK, code)
+
+		response := PingResponse{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &response))
+		require.Equal(t, "1234", response.ConnectionID)
+		require.Equal(t, "3ms", response.RoundTripTime)
+	})
+
+	t.Run("test ping connection - custom timeout", func(t *testing.T) {
+		handler := getHandler(t, func(_ string, timeout time.Duration) (time.Duration, error) {
+			require.Equal(t, 500*time.Millisecond, timeout)
+
+			return time.Millisecond, nil
+		})
+
+		_, code := sendRequestToHandler(t, handler, "/connections/1234/ping?timeout=500ms")
+		require.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("test ping connection - invalid timeout", func(t *testing.T) {
+		handler := getHandler(t, nil)
+
+		buf, code := sendRequestToHandler(t, handler, "/connections/1234/ping?timeout=soon")
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyError(t, InvalidRequestErrorCode, "invalid timeout", buf.Bytes())
+	})
+
+	t.Run("test ping connection - timeout", func(t *testing.T) {
+		handler := getHandler(t, func(string, time.Duration) (time.Duration, error) {
+			return 0, trustping.ErrPingTimeout
+		})
+
+		buf, code := sendRequestToHandler(t, handler, "/connections/1234/ping")
+		require.Equal(t, http.StatusGatewayTimeout, code)
+		verifyError(t, PingTimeoutErrorCode, "timeout waiting for ping response", buf.Bytes())
+	})
+
+	t.Run("test ping connection - error", func(t *testing.T) {
+		handler := getHandler(t, func(string, time.Duration) (time.Duration, error) {
+			return 0, trustping.ErrConnectionNotFound
+		})
+
+		buf, code := sendRequestToHandler(t, handler, "/connections/1234/ping")
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyError(t, PingErrorCode, "connection not found", buf.Bytes())
+	})
+
+	t.Run("test ping connection - empty connection ID", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{ServiceValue: &mocktrustping.MockTrustPingSvc{}})
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		svc.PingConnection(rr, httptest.NewRequest(http.MethodPost, "/connections//ping", nil))
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		verifyError(t, InvalidRequestErrorCode, "empty connection ID", rr.Body.Bytes())
+	})
+}
+
+func getHandler(t *testing.T, ping func(string, time.Duration) (time.Duration, error)) operation.Handler {
+	svc, err := New(&mockprovider.Provider{ServiceValue: &mocktrustping.MockTrustPingSvc{PingFunc: ping}})
+	require.NoError(t, err)
+
+	handlers := svc.GetRESTHandlers()
+	require.NotEmpty(t, handlers)
+
+	return handlers[0]
+}
+
+// sendRequestToHandler sends the request to the handler and returns the response body and status code
+func sendRequestToHandler(t *testing.T, handler operation.Handler, path string) (*bytes.Buffer, int) {
+	req, err := http.NewRequest(handler.Method(), path, nil)
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	return rr.Body, rr.Code
+}
+
+func verifyError(t *testing.T, expectedCode resterrors.Code, expectedMsg string, data []byte) {
+	t.Helper()
+
+	errResponse := struct {
+		Code    resterrors.Code `json:"code"`
+		Message string          `json:"message"`
+	}{}
+
+	require.NoError(t, json.Unmarshal(data, &errResponse))
+	require.Equal(t, expectedCode, errResponse.Code)
+	require.Contains(t, errResponse.Message, expectedMsg)
+}
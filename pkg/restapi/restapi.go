@@ -11,6 +11,8 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/restapi/operation"
 	"github.com/hyperledger/aries-framework-go/pkg/restapi/operation/common"
 	"github.com/hyperledger/aries-framework-go/pkg/restapi/operation/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/restapi/operation/route"
+	"github.com/hyperledger/aries-framework-go/pkg/restapi/operation/trustping"
 	"github.com/hyperledger/aries-framework-go/pkg/restapi/webhook"
 )
 
@@ -69,6 +71,18 @@ func New(ctx *context.Provider, opts ...Opt) (*Controller, error) {
 		return nil, err
 	}
 
+	// Add Trust Ping Rest Handlers
+	ping, err := trustping.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add Route Coordination Rest Handlers
+	router, err := route.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Add common Rest Handlers
 	general, err := common.New(ctx, restAPIOpts.msgHandler, webhook.NewHTTPNotifier(restAPIOpts.webhookURLs))
 	if err != nil {
@@ -76,6 +90,8 @@ func New(ctx *context.Provider, opts ...Opt) (*Controller, error) {
 	}
 
 	allHandlers = append(allHandlers, exchange.GetRESTHandlers()...)
+	allHandlers = append(allHandlers, ping.GetRESTHandlers()...)
+	allHandlers = append(allHandlers, router.GetRESTHandlers()...)
 	allHandlers = append(allHandlers, general.GetRESTHandlers()...)
 
 	return &Controller{handlers: allHandlers}, nil
@@ -0,0 +1,247 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package local provides a secretlock.Service protecting secrets with a master key held locally by the agent.
+//
+// Every keyURI gets its own AES-256-GCM key derived from the master key with HKDF, so that ciphertexts created
+// for a keyURI can't be decrypted with another one. Ciphertexts are prefixed with the ID of the master key used
+// to create them, which allows rotating the master key while secrets encrypted with previous keys are re-encrypted.
+package local
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+)
+
+const (
+	// MasterKeySize is the size in bytes of master keys
+	MasterKeySize = 32
+
+	keyIDSize = 4
+)
+
+// errUnknownMasterKey is returned when a ciphertext was not created with any of the master keys of the lock.
+var errUnknownMasterKey = errors.New("ciphertext was encrypted with an unknown master key")
+
+// Option configures the local secret lock.
+type Option func(l *Lock)
+
+// WithPreviousMasterKeys adds master keys which were rotated out. They are only used to decrypt (and re-encrypt)
+// secrets created before the rotation.
+func WithPreviousMasterKeys(masterKeys ...[]byte) Option {
+	return func(l *Lock) {
+		l.previousKeys = append(l.previousKeys, masterKeys...)
+	}
+}
+
+// Lock is a local secretlock.Service implementation.
+type Lock struct {
+	primary      *masterKey
+	keys         map[uint32]*masterKey
+	previousKeys [][]byte
+	lock         sync.RWMutex
+}
+
+// NewService creates a new local secret lock protecting secrets with given master key.
+func NewService(key []byte, opts ...Option) (*Lock, error) {
+	l := &Lock{keys: make(map[uint32]*masterKey)}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	for _, k := range l.previousKeys {
+		mk, err := newMasterKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("invalid previous master key: %w", err)
+		}
+
+		l.keys[mk.id] = mk
+	}
+
+	l.previousKeys = nil
+
+	mk, err := newMasterKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	l.primary = mk
+	l.keys[mk.id] = mk
+
+	return l, nil
+}
+
+// Encrypt req for master key in keyURI
+func (l *Lock) Encrypt(keyURI string, req *secretlock.EncryptRequest) (*secretlock.EncryptResponse, error) {
+	l.lock.RLock()
+	mk := l.primary
+	l.lock.RUnlock()
+
+	ct, err := mk.encrypt(keyURI, []byte(req.Plaintext), []byte(req.AdditionalAuthenticatedData))
+	if err != nil {
+		return nil, err
+	}
+
+	return &secretlock.EncryptResponse{Ciphertext: base64.URLEncoding.EncodeToString(ct)}, nil
+}
+
+// Decrypt req for master key in keyURI
+func (l *Lock) Decrypt(keyURI string, req *secretlock.DecryptRequest) (*secretlock.DecryptResponse, error) {
+	ct, err := base64.URLEncoding.DecodeString(req.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode ciphertext: %w", err)
+	}
+
+	if len(ct) < keyIDSize {
+		return nil, errors.New("invalid ciphertext")
+	}
+
+	l.lock.RLock()
+	mk, ok := l.keys[binary.BigEndian.Uint32(ct)]
+	l.lock.RUnlock()
+
+	if !ok {
+		return nil, errUnknownMasterKey
+	}
+
+	pt, err := mk.decrypt(keyURI, ct, []byte(req.AdditionalAuthenticatedData))
+	if err != nil {
+		return nil, err
+	}
+
+	return &secretlock.DecryptResponse{Plaintext: string(pt)}, nil
+}
+
+// RotateMasterKey makes given key the master key used for new encryptions. The current master key is kept
+// to decrypt existing secrets until they are re-encrypted.
+func (l *Lock) RotateMasterKey(key []byte) error {
+	mk, err := newMasterKey(key)
+	if err != nil {
+		return err
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.primary = mk
+	l.keys[mk.id] = mk
+
+	return nil
+}
+
+// Reencrypt decrypts req with the master key it was encrypted with and encrypts it again with the current master key.
+func (l *Lock) Reencrypt(keyURI string, req *secretlock.DecryptRequest) (*secretlock.EncryptResponse, error) {
+	resp, err := l.Decrypt(keyURI, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.Encrypt(keyURI, &secretlock.EncryptRequest{
+		Plaintext:                   resp.Plaintext,
+		AdditionalAuthenticatedData: req.AdditionalAuthenticatedData,
+	})
+}
+
+// masterKey derives and caches one AEAD per keyURI.
+type masterKey struct {
+	id    uint32
+	key   []byte
+	aeads map[string]cipher.AEAD
+	lock  sync.Mutex
+}
+
+func newMasterKey(key []byte) (*masterKey, error) {
+	if len(key) != MasterKeySize {
+		return nil, fmt.Errorf("master key must be %d bytes", MasterKeySize)
+	}
+
+	h := sha256.Sum256(key)
+
+	return &masterKey{
+		id:    binary.BigEndian.Uint32(h[:keyIDSize]),
+		key:   append([]byte(nil), key...),
+		aeads: make(map[string]cipher.AEAD),
+	}, nil
+}
+
+func (m *masterKey) aead(keyURI string) (cipher.AEAD, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if a, ok := m.aeads[keyURI]; ok {
+		return a, nil
+	}
+
+	key := make([]byte, MasterKeySize)
+
+	if _, err := io.ReadFull(hkdf.New(sha256.New, m.key, nil, []byte(keyURI)), key); err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	m.aeads[keyURI] = a
+
+	return a, nil
+}
+
+// encrypt returns key ID || nonce || ciphertext.
+func (m *masterKey) encrypt(keyURI string, plaintext, aad []byte) ([]byte, error) {
+	a, err := m.aead(keyURI)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, keyIDSize+a.NonceSize(), keyIDSize+a.NonceSize()+len(plaintext)+a.Overhead())
+	binary.BigEndian.PutUint32(out, m.id)
+
+	if _, err = rand.Read(out[keyIDSize:]); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return a.Seal(out, out[keyIDSize:], plaintext, aad), nil
+}
+
+func (m *masterKey) decrypt(keyURI string, ciphertext, aad []byte) ([]byte, error) {
+	a, err := m.aead(keyURI)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < keyIDSize+a.NonceSize() {
+		return nil, errors.New("invalid ciphertext")
+	}
+
+	nonce := ciphertext[keyIDSize : keyIDSize+a.NonceSize()]
+
+	pt, err := a.Open(nil, nonce, ciphertext[keyIDSize+a.NonceSize():], aad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+
+	return pt, nil
+}
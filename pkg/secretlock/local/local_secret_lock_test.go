@@ -0,0 +1,167 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package local
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+)
+
+const (
+	testKeyURI      = "local-lock://test/key/1"
+	otherTestKeyURI = "local-lock://test/key/2"
+)
+
+func TestNewService(t *testing.T) {
+	t.Run("test invalid master key", func(t *testing.T) {
+		l, err := NewService([]byte("too short"))
+		require.EqualError(t, err, "master key must be 32 bytes")
+		require.Nil(t, l)
+	})
+
+	t.Run("test invalid previous master key", func(t *testing.T) {
+		l, err := NewService(newTestMasterKey(t), WithPreviousMasterKeys([]byte("too short")))
+		require.EqualError(t, err, "invalid previous master key: master key must be 32 bytes")
+		require.Nil(t, l)
+	})
+}
+
+func TestLock_EncryptDecrypt(t *testing.T) {
+	l, err := NewService(newTestMasterKey(t))
+	require.NoError(t, err)
+
+	const plaintext = "secret"
+
+	encResp, err := l.Encrypt(testKeyURI, &secretlock.EncryptRequest{
+		Plaintext:                   plaintext,
+		AdditionalAuthenticatedData: "aad",
+	})
+	require.NoError(t, err)
+	require.NotContains(t, encResp.Ciphertext, plaintext)
+
+	t.Run("test decrypt success", func(t *testing.T) {
+		decResp, err := l.Decrypt(testKeyURI, &secretlock.DecryptRequest{
+			Ciphertext:                  encResp.Ciphertext,
+			AdditionalAuthenticatedData: "aad",
+		})
+		require.NoError(t, err)
+		require.Equal(t, plaintext, decResp.Plaintext)
+	})
+
+	t.Run("test encryption is not deterministic", func(t *testing.T) {
+		encResp2, err := l.Encrypt(testKeyURI, &secretlock.EncryptRequest{
+			Plaintext:                   plaintext,
+			AdditionalAuthenticatedData: "aad",
+		})
+		require.NoError(t, err)
+		require.NotEqual(t, encResp.Ciphertext, encResp2.Ciphertext)
+	})
+
+	t.Run("test decrypt with other key URI fails", func(t *testing.T) {
+		_, err := l.Decrypt(otherTestKeyURI, &secretlock.DecryptRequest{
+			Ciphertext:                  encResp.Ciphertext,
+			AdditionalAuthenticatedData: "aad",
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to decrypt")
+	})
+
+	t.Run("test decrypt with other aad fails", func(t *testing.T) {
+		_, err := l.Decrypt(testKeyURI, &secretlock.DecryptRequest{
+			Ciphertext:                  encResp.Ciphertext,
+			AdditionalAuthenticatedData: "other aad",
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to decrypt")
+	})
+
+	t.Run("test decrypt with other master key fails", func(t *testing.T) {
+		l2, err := NewService(newTestMasterKey(t))
+		require.NoError(t, err)
+
+		_, err = l2.Decrypt(testKeyURI, &secretlock.DecryptRequest{
+			Ciphertext:                  encResp.Ciphertext,
+			AdditionalAuthenticatedData: "aad",
+		})
+		require.Equal(t, errUnknownMasterKey, err)
+	})
+
+	t.Run("test decrypt invalid ciphertext", func(t *testing.T) {
+		_, err := l.Decrypt(testKeyURI, &secretlock.DecryptRequest{Ciphertext: "%"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to decode ciphertext")
+
+		_, err = l.Decrypt(testKeyURI, &secretlock.DecryptRequest{
+			Ciphertext: base64.URLEncoding.EncodeToString([]byte("ab")),
+		})
+		require.EqualError(t, err, "invalid ciphertext")
+
+		ct, err := base64.URLEncoding.DecodeString(encResp.Ciphertext)
+		require.NoError(t, err)
+
+		_, err = l.Decrypt(testKeyURI, &secretlock.DecryptRequest{
+			Ciphertext: base64.URLEncoding.EncodeToString(ct[:keyIDSize+2]),
+		})
+		require.EqualError(t, err, "invalid ciphertext")
+	})
+}
+
+func TestLock_RotateMasterKey(t *testing.T) {
+	oldKey := newTestMasterKey(t)
+
+	l, err := NewService(oldKey)
+	require.NoError(t, err)
+
+	encResp, err := l.Encrypt(testKeyURI, &secretlock.EncryptRequest{Plaintext: "secret"})
+	require.NoError(t, err)
+
+	require.EqualError(t, l.RotateMasterKey([]byte("too short")), "master key must be 32 bytes")
+
+	newKey := newTestMasterKey(t)
+	require.NoError(t, l.RotateMasterKey(newKey))
+
+	// secrets encrypted with the previous master key can still be decrypted
+	decResp, err := l.Decrypt(testKeyURI, &secretlock.DecryptRequest{Ciphertext: encResp.Ciphertext})
+	require.NoError(t, err)
+	require.Equal(t, "secret", decResp.Plaintext)
+
+	reencResp, err := l.Reencrypt(testKeyURI, &secretlock.DecryptRequest{Ciphertext: encResp.Ciphertext})
+	require.NoError(t, err)
+
+	_, err = l.Reencrypt(otherTestKeyURI, &secretlock.DecryptRequest{Ciphertext: encResp.Ciphertext})
+	require.Error(t, err)
+
+	// re-encrypted secret only requires the new master key
+	l2, err := NewService(newKey)
+	require.NoError(t, err)
+
+	decResp, err = l2.Decrypt(testKeyURI, &secretlock.DecryptRequest{Ciphertext: reencResp.Ciphertext})
+	require.NoError(t, err)
+	require.Equal(t, "secret", decResp.Plaintext)
+
+	_, err = l2.Decrypt(testKeyURI, &secretlock.DecryptRequest{Ciphertext: encResp.Ciphertext})
+	require.Equal(t, errUnknownMasterKey, err)
+
+	// previous master keys can be provided when the lock is created
+	l3, err := NewService(newKey, WithPreviousMasterKeys(oldKey))
+	require.NoError(t, err)
+
+	decResp, err = l3.Decrypt(testKeyURI, &secretlock.DecryptRequest{Ciphertext: encResp.Ciphertext})
+	require.NoError(t, err)
+	require.Equal(t, "secret", decResp.Plaintext)
+}
+
+func newTestMasterKey(t *testing.T) []byte {
+	key, err := GenerateMasterKey()
+	require.NoError(t, err)
+
+	return key
+}
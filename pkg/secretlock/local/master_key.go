@@ -0,0 +1,96 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package local
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// scrypt parameters recommended for interactive logins
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+
+	minSaltSize = 16
+)
+
+// GenerateMasterKey creates a new random master key.
+func GenerateMasterKey() ([]byte, error) {
+	key := make([]byte, MasterKeySize)
+
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate master key: %w", err)
+	}
+
+	return key, nil
+}
+
+// EncodeMasterKey encodes given master key in the format expected by MasterKeyFromPath and MasterKeyFromEnv.
+func EncodeMasterKey(key []byte) string {
+	return base64.URLEncoding.EncodeToString(key)
+}
+
+// MasterKeyFromPath reads the base64url encoded master key from the file at given path.
+func MasterKeyFromPath(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to read master key file: %w", err)
+	}
+
+	return decodeMasterKey(string(data))
+}
+
+// MasterKeyFromEnv reads the base64url encoded master key from given environment variable.
+func MasterKeyFromEnv(envKey string) ([]byte, error) {
+	value, ok := os.LookupEnv(envKey)
+	if !ok {
+		return nil, fmt.Errorf("environment variable %s not set", envKey)
+	}
+
+	return decodeMasterKey(value)
+}
+
+// MasterKeyFromPassphrase derives the master key from given passphrase with scrypt. The salt should be random,
+// at least 16 bytes long and stored along with the protected data; the same salt is required to derive the key again.
+func MasterKeyFromPassphrase(passphrase string, salt []byte) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errors.New("passphrase is mandatory")
+	}
+
+	if len(salt) < minSaltSize {
+		return nil, fmt.Errorf("salt must be at least %d bytes", minSaltSize)
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, MasterKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive master key: %w", err)
+	}
+
+	return key, nil
+}
+
+func decodeMasterKey(encoded string) ([]byte, error) {
+	key, err := base64.URLEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode master key: %w", err)
+	}
+
+	if len(key) != MasterKeySize {
+		return nil, fmt.Errorf("master key must be %d bytes", MasterKeySize)
+	}
+
+	return key, nil
+}
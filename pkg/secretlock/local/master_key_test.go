@@ -0,0 +1,98 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package local
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMasterKeyFromPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "masterkey")
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, os.RemoveAll(dir))
+	}()
+
+	key := newTestMasterKey(t)
+
+	t.Run("test success", func(t *testing.T) {
+		path := filepath.Join(dir, "valid")
+		require.NoError(t, ioutil.WriteFile(path, []byte(EncodeMasterKey(key)+"\n"), 0600))
+
+		k, err := MasterKeyFromPath(path)
+		require.NoError(t, err)
+		require.Equal(t, key, k)
+	})
+
+	t.Run("test missing file", func(t *testing.T) {
+		_, err := MasterKeyFromPath(filepath.Join(dir, "missing"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read master key file")
+	})
+
+	t.Run("test invalid file content", func(t *testing.T) {
+		path := filepath.Join(dir, "invalid")
+		require.NoError(t, ioutil.WriteFile(path, []byte("%"), 0600))
+
+		_, err := MasterKeyFromPath(path)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to decode master key")
+
+		require.NoError(t, ioutil.WriteFile(path, []byte(EncodeMasterKey([]byte("short"))), 0600))
+
+		_, err = MasterKeyFromPath(path)
+		require.EqualError(t, err, "master key must be 32 bytes")
+	})
+}
+
+func TestMasterKeyFromEnv(t *testing.T) {
+	const envKey = "ARIES_TEST_MASTER_KEY"
+
+	_, err := MasterKeyFromEnv(envKey)
+	require.EqualError(t, err, "environment variable "+envKey+" not set")
+
+	key := newTestMasterKey(t)
+
+	require.NoError(t, os.Setenv(envKey, EncodeMasterKey(key)))
+
+	defer func() {
+		require.NoError(t, os.Unsetenv(envKey))
+	}()
+
+	k, err := MasterKeyFromEnv(envKey)
+	require.NoError(t, err)
+	require.Equal(t, key, k)
+}
+
+func TestMasterKeyFromPassphrase(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+
+	key, err := MasterKeyFromPassphrase("passphrase", salt)
+	require.NoError(t, err)
+	require.Len(t, key, MasterKeySize)
+
+	// same passphrase and salt derive the same key
+	key2, err := MasterKeyFromPassphrase("passphrase", salt)
+	require.NoError(t, err)
+	require.Equal(t, key, key2)
+
+	key2, err = MasterKeyFromPassphrase("other passphrase", salt)
+	require.NoError(t, err)
+	require.NotEqual(t, key, key2)
+
+	_, err = MasterKeyFromPassphrase("", salt)
+	require.EqualError(t, err, "passphrase is mandatory")
+
+	_, err = MasterKeyFromPassphrase("passphrase", []byte("short"))
+	require.EqualError(t, err, "salt must be at least 16 bytes")
+}
@@ -0,0 +1,24 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package noop provides a secretlock.Service which doesn't protect secrets at all.
+// It is meant for development and testing only.
+package noop
+
+import "github.com/hyperledger/aries-framework-go/pkg/secretlock"
+
+// NoLock is a secretlock.Service returning the plaintext as ciphertext and vice versa.
+type NoLock struct{}
+
+// Encrypt returns the plaintext of req as ciphertext
+func (s *NoLock) Encrypt(keyURI string, req *secretlock.EncryptRequest) (*secretlock.EncryptResponse, error) {
+	return &secretlock.EncryptResponse{Ciphertext: req.Plaintext}, nil
+}
+
+// Decrypt returns the ciphertext of req as plaintext
+func (s *NoLock) Decrypt(keyURI string, req *secretlock.DecryptRequest) (*secretlock.DecryptResponse, error) {
+	return &secretlock.DecryptResponse{Plaintext: req.Ciphertext}, nil
+}
@@ -0,0 +1,27 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package noop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+)
+
+func TestNoLock(t *testing.T) {
+	s := &NoLock{}
+
+	encResp, err := s.Encrypt("", &secretlock.EncryptRequest{Plaintext: "secret"})
+	require.NoError(t, err)
+	require.Equal(t, "secret", encResp.Ciphertext)
+
+	decResp, err := s.Decrypt("", &secretlock.DecryptRequest{Ciphertext: encResp.Ciphertext})
+	require.NoError(t, err)
+	require.Equal(t, "secret", decResp.Plaintext)
+}
@@ -0,0 +1,283 @@
+// +build !js,!wasm
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbolt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const (
+	fileMode    os.FileMode = 0600
+	openTimeout             = 3 * time.Second
+)
+
+// Provider bbolt implementation of storage.Provider interface.
+// All stores opened by the provider are kept as separate buckets of a single database file.
+type Provider struct {
+	db   *bbolt.DB
+	dbs  map[string]*bboltStore
+	lock sync.RWMutex
+}
+
+// NewProvider instantiates Provider backed by database file at given path
+func NewProvider(dbPath string) (*Provider, error) {
+	if dbPath == "" {
+		return nil, errors.New("db path is mandatory")
+	}
+
+	db, err := bbolt.Open(dbPath, fileMode, &bbolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bbolt database: %w", err)
+	}
+
+	return &Provider{db: db, dbs: make(map[string]*bboltStore)}, nil
+}
+
+// OpenStore opens and returns a store for given name space.
+func (p *Provider) OpenStore(name string) (storage.Store, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	k := strings.ToLower(name)
+
+	store, ok := p.dbs[k]
+	if ok {
+		return store, nil
+	}
+
+	err := p.db.Update(func(tx *bbolt.Tx) error {
+		_, e := tx.CreateBucketIfNotExists([]byte(k))
+		return e
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bucket %s: %w", k, err)
+	}
+
+	store = &bboltStore{db: p.db, bucket: []byte(k)}
+	p.dbs[k] = store
+
+	return store, nil
+}
+
+// Close closes all stores created under this store provider along with the underlying database file
+func (p *Provider) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.dbs = make(map[string]*bboltStore)
+
+	return p.db.Close()
+}
+
+// CloseStore closes bbolt store of given name
+func (p *Provider) CloseStore(name string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	delete(p.dbs, strings.ToLower(name))
+
+	return nil
+}
+
+type bboltStore struct {
+	db     *bbolt.DB
+	bucket []byte
+}
+
+// Put stores the key and the record
+func (s *bboltStore) Put(k string, v []byte) error {
+	if k == "" || v == nil {
+		return errors.New("key and value are mandatory")
+	}
+
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b, err := s.getBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		return b.Put([]byte(k), v)
+	})
+}
+
+// Get fetches the record based on key
+func (s *bboltStore) Get(k string) ([]byte, error) {
+	if k == "" {
+		return nil, errors.New("key is mandatory")
+	}
+
+	var data []byte
+
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		b, err := s.getBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		v := b.Get([]byte(k))
+		if v == nil {
+			return storage.ErrDataNotFound
+		}
+
+		// the value is only valid for the life of the transaction
+		data = append([]byte(nil), v...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Delete removes the record for given key
+func (s *bboltStore) Delete(k string) error {
+	if k == "" {
+		return errors.New("key is mandatory")
+	}
+
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b, err := s.getBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		return b.Delete([]byte(k))
+	})
+}
+
+// Batch applies given operations atomically within a single transaction
+func (s *bboltStore) Batch(operations []storage.Operation) error {
+	for _, op := range operations {
+		if op.Key == "" {
+			return errors.New("key is mandatory")
+		}
+	}
+
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b, err := s.getBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, op := range operations {
+			if op.Value == nil {
+				err = b.Delete([]byte(op.Key))
+			} else {
+				err = b.Put([]byte(op.Key), op.Value)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Iterator returns iterator for the latest snapshot of the underlying db.
+// Range semantics are the same as in leveldb, start is included and limit is not included in the range.
+func (s *bboltStore) Iterator(start, limit string) storage.StoreIterator {
+	if start == "" || limit == "" {
+		return &bboltIterator{err: errors.New("start or limit key is mandatory")}
+	}
+
+	var items []kvPair
+
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		b, err := s.getBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		c := b.Cursor()
+		for k, v := c.Seek([]byte(start)); k != nil && bytes.Compare(k, []byte(limit)) < 0; k, v = c.Next() {
+			// keys and values are only valid for the life of the transaction
+			items = append(items, kvPair{key: append([]byte(nil), k...), value: append([]byte(nil), v...)})
+		}
+
+		return nil
+	})
+
+	return &bboltIterator{items: items, index: -1, err: err}
+}
+
+func (s *bboltStore) getBucket(tx *bbolt.Tx) (*bbolt.Bucket, error) {
+	b := tx.Bucket(s.bucket)
+	if b == nil {
+		return nil, fmt.Errorf("bucket %s not found", s.bucket)
+	}
+
+	return b, nil
+}
+
+type kvPair struct {
+	key   []byte
+	value []byte
+}
+
+// bboltIterator iterates over a copy of the key/value pairs read within a single transaction.
+type bboltIterator struct {
+	items []kvPair
+	index int
+	err   error
+}
+
+// Next moves pointer to next value of iterator.
+// It returns false if the iterator is exhausted.
+func (i *bboltIterator) Next() bool {
+	if i.index+1 >= len(i.items) {
+		return false
+	}
+
+	i.index++
+
+	return true
+}
+
+// Release releases associated resources.
+func (i *bboltIterator) Release() {
+	i.items = nil
+	i.index = -1
+}
+
+// Error returns error in iterator.
+func (i *bboltIterator) Error() error {
+	return i.err
+}
+
+// Key returns the key of the current key/value pair.
+func (i *bboltIterator) Key() []byte {
+	if i.index < 0 || i.index >= len(i.items) {
+		return nil
+	}
+
+	return i.items[i.index].key
+}
+
+// Value returns the value of the current key/value pair.
+func (i *bboltIterator) Value() []byte {
+	if i.index < 0 || i.index >= len(i.items) {
+		return nil
+	}
+
+	return i.items[i.index].value
+}
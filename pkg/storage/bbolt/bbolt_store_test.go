@@ -0,0 +1,277 @@
+// +build !js,!wasm
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbolt
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+func setupBBoltDB(t testing.TB) (string, func()) {
+	dbDir, err := ioutil.TempDir("", "bbolt")
+	if err != nil {
+		t.Fatalf("Failed to create bbolt directory: %s", err)
+	}
+
+	return filepath.Join(dbDir, "aries.db"), func() {
+		err := os.RemoveAll(dbDir)
+		if err != nil {
+			t.Fatalf("Failed to clear bbolt directory: %s", err)
+		}
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	t.Run("Test bbolt provider - empty path", func(t *testing.T) {
+		prov, err := NewProvider("")
+		require.Error(t, err)
+		require.Nil(t, prov)
+	})
+
+	t.Run("Test bbolt provider - invalid path", func(t *testing.T) {
+		dbDir, err := ioutil.TempDir("", "bbolt")
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, os.RemoveAll(dbDir))
+		}()
+
+		// pass directory instead of file
+		prov, err := NewProvider(dbDir)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to open bbolt database")
+		require.Nil(t, prov)
+	})
+}
+
+func TestBBoltStore(t *testing.T) {
+	path, cleanup := setupBBoltDB(t)
+	defer cleanup()
+
+	t.Run("Test bbolt store put and get", func(t *testing.T) {
+		prov, err := NewProvider(path)
+		require.NoError(t, err)
+
+		store, err := prov.OpenStore("test")
+		require.NoError(t, err)
+
+		const key = "did:example:123"
+		data := []byte("value")
+
+		err = store.Put(key, data)
+		require.NoError(t, err)
+
+		doc, err := store.Get(key)
+		require.NoError(t, err)
+		require.NotEmpty(t, doc)
+		require.Equal(t, data, doc)
+
+		did2 := "did:example:789"
+		_, err = store.Get(did2)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		// nil key
+		_, err = store.Get("")
+		require.Error(t, err)
+
+		// nil value
+		err = store.Put(key, nil)
+		require.Error(t, err)
+
+		// nil key
+		err = store.Put("", data)
+		require.Error(t, err)
+
+		err = prov.Close()
+		require.NoError(t, err)
+
+		// try to get after provider is closed
+		_, err = store.Get(key)
+		require.Error(t, err)
+	})
+
+	t.Run("Test bbolt store data survives reopening the file", func(t *testing.T) {
+		prov, err := NewProvider(path)
+		require.NoError(t, err)
+
+		store, err := prov.OpenStore("test")
+		require.NoError(t, err)
+
+		doc, err := store.Get("did:example:123")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value"), doc)
+
+		require.NoError(t, prov.Close())
+	})
+
+	t.Run("Test bbolt multi store put and get", func(t *testing.T) {
+		prov, err := NewProvider(path)
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, prov.Close())
+		}()
+
+		const commonKey = "did:example:1"
+		data := []byte("value1")
+		// create store 1 & store 2
+		store1, err := prov.OpenStore("store1")
+		require.NoError(t, err)
+
+		store2, err := prov.OpenStore("store2")
+		require.NoError(t, err)
+
+		// put in store 1
+		err = store1.Put(commonKey, data)
+		require.NoError(t, err)
+
+		// get in store 1 - found
+		doc, err := store1.Get(commonKey)
+		require.NoError(t, err)
+		require.Equal(t, data, doc)
+
+		// get in store 2 - not found
+		doc, err = store2.Get(commonKey)
+		require.Equal(t, err, storage.ErrDataNotFound)
+		require.Empty(t, doc)
+
+		// create new store 3 with same name as store1
+		store3, err := prov.OpenStore("STORE1")
+		require.NoError(t, err)
+
+		// get in store 3 - found
+		doc, err = store3.Get(commonKey)
+		require.NoError(t, err)
+		require.Equal(t, data, doc)
+
+		// store length
+		require.Len(t, prov.dbs, 2)
+
+		require.NoError(t, prov.CloseStore("Store1"))
+		require.Len(t, prov.dbs, 1)
+
+		// try to close non existing store
+		require.NoError(t, prov.CloseStore("store_x"))
+		require.Len(t, prov.dbs, 1)
+	})
+
+	t.Run("Test bbolt store delete and batch", func(t *testing.T) {
+		prov, err := NewProvider(path)
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, prov.Close())
+		}()
+
+		store, err := prov.OpenStore("test-batch")
+		require.NoError(t, err)
+
+		require.NoError(t, store.Put("k1", []byte("v1")))
+		require.NoError(t, store.Delete("k1"))
+
+		_, err = store.Get("k1")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		// delete missing key
+		require.NoError(t, store.Delete("k1"))
+
+		// empty key
+		require.Error(t, store.Delete(""))
+
+		require.NoError(t, store.Put("k1", []byte("v1")))
+
+		err = store.Batch([]storage.Operation{
+			{Key: "k1"},
+			{Key: "k2", Value: []byte("v2")},
+			{Key: "k3", Value: []byte("v3")},
+		})
+		require.NoError(t, err)
+
+		_, err = store.Get("k1")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		v, err := store.Get("k2")
+		require.NoError(t, err)
+		require.Equal(t, []byte("v2"), v)
+
+		// nothing is applied if one of the operations is invalid
+		err = store.Batch([]storage.Operation{
+			{Key: "k2"},
+			{Value: []byte("v4")},
+		})
+		require.Error(t, err)
+
+		v, err = store.Get("k2")
+		require.NoError(t, err)
+		require.Equal(t, []byte("v2"), v)
+	})
+
+	t.Run("Test bbolt store iterator", func(t *testing.T) {
+		prov, err := NewProvider(path)
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, prov.Close())
+		}()
+
+		store, err := prov.OpenStore("test-iterator")
+		require.NoError(t, err)
+
+		const valPrefix = "val-for-%s"
+		keys := []string{"abc_123", "abc_124", "abc_125", "abc_126", "jkl_123", "mno_123"}
+
+		for _, key := range keys {
+			err = store.Put(key, []byte(fmt.Sprintf(valPrefix, key)))
+			require.NoError(t, err)
+		}
+
+		itr := store.Iterator("abc_", "abc_~")
+		verifyItr(t, itr, 4, "abc_")
+
+		itr = store.Iterator("", "")
+		require.Error(t, itr.Error())
+		verifyItr(t, itr, 0, "")
+
+		itr = store.Iterator("abc_", "mno_~")
+		verifyItr(t, itr, 6, "")
+
+		// limit is not included in the range
+		itr = store.Iterator("abc_", "mno_123")
+		verifyItr(t, itr, 5, "")
+	})
+}
+
+func verifyItr(t *testing.T, itr storage.StoreIterator, count int, prefix string) {
+	var vals []string
+
+	require.Empty(t, itr.Key())
+
+	for itr.Next() {
+		if prefix != "" {
+			require.True(t, strings.HasPrefix(string(itr.Key()), prefix))
+		}
+
+		vals = append(vals, string(itr.Value()))
+	}
+	require.Len(t, vals, count)
+
+	itr.Release()
+	require.False(t, itr.Next())
+	require.Empty(t, itr.Key())
+	require.Empty(t, itr.Value())
+}
@@ -0,0 +1,381 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package encrypted
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const (
+	// keySeparator splits keys in segments which are HMACed separately to keep prefix iteration working
+	keySeparator = "_"
+	// limitSegment is the last segment of the limit key of a prefix iteration (see connection.Lookup)
+	limitSegment = "~"
+
+	hmacKeyStoreName = "encryptedstore"
+	hmacKeyName      = "hmackey"
+	hmacKeySize      = 32
+)
+
+// Option configures the encrypted storage provider.
+type Option func(p *Provider)
+
+// WithKeyHMAC enables replacing keys with their HMAC in the underlying provider, so that the keys don't leak
+// information either. Each segment of a key separated by '_' is HMACed separately in order to keep prefix
+// iteration working, ie: Iterator("prefix_", "prefix_~") returns all records with keys starting with "prefix_".
+// Other ranges are not supported for HMACed keys.
+// The HMAC key is generated on first use and stored in the underlying provider, protected by the secret lock.
+func WithKeyHMAC() Option {
+	return func(p *Provider) {
+		p.hmacEnabled = true
+	}
+}
+
+// Provider wraps a storage.Provider and transparently encrypts the values of all stores opened through it
+// using the master key identified by keyURI of the secret lock service.
+type Provider struct {
+	provider    storage.Provider
+	secretLock  secretlock.Service
+	keyURI      string
+	hmacEnabled bool
+	hmacKey     []byte
+}
+
+// NewProvider instantiates an encrypted Provider wrapping given storage provider.
+func NewProvider(provider storage.Provider, secretLock secretlock.Service, keyURI string,
+	opts ...Option) (*Provider, error) {
+	if provider == nil || secretLock == nil {
+		return nil, errors.New("storage provider and secret lock are mandatory")
+	}
+
+	p := &Provider{provider: provider, secretLock: secretLock, keyURI: keyURI}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if p.hmacEnabled {
+		hmacKey, err := p.loadHMACKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load hmac key: %w", err)
+		}
+
+		p.hmacKey = hmacKey
+	}
+
+	return p, nil
+}
+
+// OpenStore opens and returns an encrypted store for given name space.
+func (p *Provider) OpenStore(name string) (storage.Store, error) {
+	store, err := p.provider.OpenStore(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptedStore{store: store, provider: p}, nil
+}
+
+// Close closes all stores created under the wrapped store provider
+func (p *Provider) Close() error {
+	return p.provider.Close()
+}
+
+// CloseStore closes store of given name of the wrapped store provider
+func (p *Provider) CloseStore(name string) error {
+	return p.provider.CloseStore(name)
+}
+
+// loadHMACKey fetches the HMAC key from the underlying provider, a new key is created if it doesn't exist yet.
+func (p *Provider) loadHMACKey() ([]byte, error) {
+	store, err := p.provider.OpenStore(hmacKeyStoreName)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := store.Get(hmacKeyName)
+	if err == nil {
+		return p.decrypt(ciphertext, hmacKeyName)
+	}
+
+	if !errors.Is(err, storage.ErrDataNotFound) {
+		return nil, err
+	}
+
+	hmacKey := make([]byte, hmacKeySize)
+
+	if _, err = rand.Read(hmacKey); err != nil {
+		return nil, err
+	}
+
+	ciphertext, err = p.encrypt(hmacKey, hmacKeyName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = store.Put(hmacKeyName, ciphertext); err != nil {
+		return nil, err
+	}
+
+	return hmacKey, nil
+}
+
+func (p *Provider) encrypt(plaintext []byte, aad string) ([]byte, error) {
+	resp, err := p.secretLock.Encrypt(p.keyURI, &secretlock.EncryptRequest{
+		Plaintext:                   string(plaintext),
+		AdditionalAuthenticatedData: aad,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+
+	return []byte(resp.Ciphertext), nil
+}
+
+func (p *Provider) decrypt(ciphertext []byte, aad string) ([]byte, error) {
+	resp, err := p.secretLock.Decrypt(p.keyURI, &secretlock.DecryptRequest{
+		Ciphertext:                  string(ciphertext),
+		AdditionalAuthenticatedData: aad,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+
+	return []byte(resp.Plaintext), nil
+}
+
+// record is the plaintext of the values written to the underlying store. The original key is kept
+// next to the value so that iterators can return it when keys are HMACed.
+type record struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+type encryptedStore struct {
+	store    storage.Store
+	provider *Provider
+}
+
+// Put encrypts and stores the key and the record
+func (s *encryptedStore) Put(k string, v []byte) error {
+	if k == "" || v == nil {
+		return errors.New("key and value are mandatory")
+	}
+
+	storeKey := s.storeKey(k)
+
+	ciphertext, err := s.encryptRecord(storeKey, k, v)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Put(storeKey, ciphertext)
+}
+
+// Get fetches and decrypts the record based on key
+func (s *encryptedStore) Get(k string) ([]byte, error) {
+	if k == "" {
+		return nil, errors.New("key is mandatory")
+	}
+
+	storeKey := s.storeKey(k)
+
+	ciphertext, err := s.store.Get(storeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	rec, err := s.decryptRecord(storeKey, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	return rec.Value, nil
+}
+
+// Delete removes the record for given key
+func (s *encryptedStore) Delete(k string) error {
+	if k == "" {
+		return errors.New("key is mandatory")
+	}
+
+	return s.store.Delete(s.storeKey(k))
+}
+
+// Batch encrypts given operations and applies them on the underlying store
+func (s *encryptedStore) Batch(operations []storage.Operation) error {
+	encryptedOps := make([]storage.Operation, len(operations))
+
+	for i, op := range operations {
+		if op.Key == "" {
+			return errors.New("key is mandatory")
+		}
+
+		encryptedOps[i].Key = s.storeKey(op.Key)
+
+		if op.Value == nil {
+			continue
+		}
+
+		ciphertext, err := s.encryptRecord(encryptedOps[i].Key, op.Key, op.Value)
+		if err != nil {
+			return err
+		}
+
+		encryptedOps[i].Value = ciphertext
+	}
+
+	return s.store.Batch(encryptedOps)
+}
+
+// Iterator returns iterator decrypting the records of the underlying store.
+// When keys are HMACed, only prefix ranges are supported (see WithKeyHMAC).
+func (s *encryptedStore) Iterator(start, limit string) storage.StoreIterator {
+	return &encryptedIterator{
+		StoreIterator: s.store.Iterator(s.rangeKey(start), s.rangeKey(limit)),
+		store:         s,
+	}
+}
+
+func (s *encryptedStore) encryptRecord(storeKey, k string, v []byte) ([]byte, error) {
+	plaintext, err := json.Marshal(&record{Key: k, Value: v})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal record: %w", err)
+	}
+
+	// the key is used as additional authenticated data so that records can't be swapped in the underlying store
+	return s.provider.encrypt(plaintext, storeKey)
+}
+
+func (s *encryptedStore) decryptRecord(storeKey string, ciphertext []byte) (*record, error) {
+	plaintext, err := s.provider.decrypt(ciphertext, storeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &record{}
+
+	if err = json.Unmarshal(plaintext, rec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal record: %w", err)
+	}
+
+	return rec, nil
+}
+
+// storeKey returns the key used in the underlying store.
+func (s *encryptedStore) storeKey(k string) string {
+	if !s.provider.hmacEnabled {
+		return k
+	}
+
+	segments := strings.Split(k, keySeparator)
+	for i := range segments {
+		segments[i] = s.hmacSegment(segments[i])
+	}
+
+	return strings.Join(segments, keySeparator)
+}
+
+// rangeKey returns the start or limit key used in the underlying store. The last segment is kept as is when it is
+// empty or the limit segment, since the HMACed segments only contain hex characters which sort in between.
+func (s *encryptedStore) rangeKey(k string) string {
+	if !s.provider.hmacEnabled || k == "" {
+		return k
+	}
+
+	segments := strings.Split(k, keySeparator)
+	for i := range segments {
+		if i == len(segments)-1 && (segments[i] == "" || segments[i] == limitSegment) {
+			break
+		}
+
+		segments[i] = s.hmacSegment(segments[i])
+	}
+
+	return strings.Join(segments, keySeparator)
+}
+
+func (s *encryptedStore) hmacSegment(segment string) string {
+	mac := hmac.New(sha256.New, s.provider.hmacKey)
+
+	// hash.Hash never returns an error on Write
+	_, _ = mac.Write([]byte(segment)) //nolint:errcheck
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// encryptedIterator decrypts the records returned by the underlying iterator.
+type encryptedIterator struct {
+	storage.StoreIterator
+	store *encryptedStore
+	rec   *record
+	err   error
+}
+
+// Next moves pointer to next value of iterator and decrypts it.
+// It returns false if the iterator is exhausted or if the record can't be decrypted.
+func (i *encryptedIterator) Next() bool {
+	i.rec = nil
+
+	if i.err != nil || !i.StoreIterator.Next() {
+		return false
+	}
+
+	rec, err := i.store.decryptRecord(string(i.StoreIterator.Key()), i.StoreIterator.Value())
+	if err != nil {
+		i.err = err
+
+		return false
+	}
+
+	i.rec = rec
+
+	return true
+}
+
+// Release releases associated resources.
+func (i *encryptedIterator) Release() {
+	i.rec = nil
+	i.StoreIterator.Release()
+}
+
+// Error returns error in iterator.
+func (i *encryptedIterator) Error() error {
+	if i.err != nil {
+		return i.err
+	}
+
+	return i.StoreIterator.Error()
+}
+
+// Key returns the original key of the current key/value pair.
+func (i *encryptedIterator) Key() []byte {
+	if i.rec == nil {
+		return nil
+	}
+
+	return []byte(i.rec.Key)
+}
+
+// Value returns the decrypted value of the current key/value pair.
+func (i *encryptedIterator) Value() []byte {
+	if i.rec == nil {
+		return nil
+	}
+
+	return i.rec.Value
+}
@@ -0,0 +1,350 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package encrypted
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	mocksecretlock "github.com/hyperledger/aries-framework-go/pkg/internal/mock/secretlock"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/internal/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+const testKeyURI = "local-lock://test/master/key"
+
+func TestNewProvider(t *testing.T) {
+	t.Run("test missing arguments", func(t *testing.T) {
+		p, err := NewProvider(nil, newTestSecretLock(t), testKeyURI)
+		require.Error(t, err)
+		require.Nil(t, p)
+
+		p, err = NewProvider(mem.NewProvider(), nil, testKeyURI)
+		require.Error(t, err)
+		require.Nil(t, p)
+	})
+
+	t.Run("test hmac key is created once", func(t *testing.T) {
+		memProvider := mem.NewProvider()
+		lock := newTestSecretLock(t)
+
+		p, err := NewProvider(memProvider, lock, testKeyURI, WithKeyHMAC())
+		require.NoError(t, err)
+		require.Len(t, p.hmacKey, hmacKeySize)
+
+		p2, err := NewProvider(memProvider, lock, testKeyURI, WithKeyHMAC())
+		require.NoError(t, err)
+		require.Equal(t, p.hmacKey, p2.hmacKey)
+
+		// the hmac key is not stored in plaintext
+		store, err := memProvider.OpenStore(hmacKeyStoreName)
+		require.NoError(t, err)
+
+		ciphertext, err := store.Get(hmacKeyName)
+		require.NoError(t, err)
+		require.NotContains(t, string(ciphertext), string(p.hmacKey))
+	})
+
+	t.Run("test hmac key failures", func(t *testing.T) {
+		p, err := NewProvider(&mockstorage.MockStoreProvider{ErrOpenStoreHandle: errors.New("open error")},
+			newTestSecretLock(t), testKeyURI, WithKeyHMAC())
+		require.EqualError(t, err, "failed to load hmac key: open error")
+		require.Nil(t, p)
+
+		p, err = NewProvider(&mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
+			Store: map[string][]byte{}, ErrGet: errors.New("get error"),
+		}}, newTestSecretLock(t), testKeyURI, WithKeyHMAC())
+		require.EqualError(t, err, "failed to load hmac key: get error")
+		require.Nil(t, p)
+
+		p, err = NewProvider(&mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
+			Store: map[string][]byte{}, ErrPut: errors.New("put error"),
+		}}, newTestSecretLock(t), testKeyURI, WithKeyHMAC())
+		require.EqualError(t, err, "failed to load hmac key: put error")
+		require.Nil(t, p)
+
+		p, err = NewProvider(mem.NewProvider(), &mocksecretlock.MockSecretLock{ErrEncrypt: errors.New("encrypt error")},
+			testKeyURI, WithKeyHMAC())
+		require.EqualError(t, err, "failed to load hmac key: failed to encrypt: encrypt error")
+		require.Nil(t, p)
+
+		memProvider := mem.NewProvider()
+
+		_, err = NewProvider(memProvider, newTestSecretLock(t), testKeyURI, WithKeyHMAC())
+		require.NoError(t, err)
+
+		// hmac key was encrypted with a different master key
+		p, err = NewProvider(memProvider, newTestSecretLock(t), testKeyURI, WithKeyHMAC())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to load hmac key: failed to decrypt")
+		require.Nil(t, p)
+	})
+}
+
+func TestEncryptedStore(t *testing.T) {
+	for _, hmacEnabled := range []bool{false, true} {
+		var opts []Option
+		if hmacEnabled {
+			opts = append(opts, WithKeyHMAC())
+		}
+
+		t.Run(fmt.Sprintf("test put and get (hmac: %t)", hmacEnabled), func(t *testing.T) {
+			memProvider := mem.NewProvider()
+
+			p, err := NewProvider(memProvider, newTestSecretLock(t), testKeyURI, opts...)
+			require.NoError(t, err)
+
+			store, err := p.OpenStore("test")
+			require.NoError(t, err)
+
+			const key = "did:example:123"
+			data := []byte("secret value")
+
+			require.NoError(t, store.Put(key, data))
+
+			v, err := store.Get(key)
+			require.NoError(t, err)
+			require.Equal(t, data, v)
+
+			// value is not stored in plaintext in the underlying store
+			underlyingStore, err := memProvider.OpenStore("test")
+			require.NoError(t, err)
+
+			storeKey := store.(*encryptedStore).storeKey(key)
+			require.Equal(t, !hmacEnabled, storeKey == key)
+
+			ciphertext, err := underlyingStore.Get(storeKey)
+			require.NoError(t, err)
+			require.NotContains(t, string(ciphertext), string(data))
+
+			_, err = store.Get("did:example:789")
+			require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+			_, err = store.Get("")
+			require.Error(t, err)
+
+			require.Error(t, store.Put(key, nil))
+			require.Error(t, store.Put("", data))
+
+			require.NoError(t, p.CloseStore("test"))
+			require.NoError(t, p.Close())
+		})
+
+		t.Run(fmt.Sprintf("test swapped records are rejected (hmac: %t)", hmacEnabled), func(t *testing.T) {
+			memProvider := mem.NewProvider()
+
+			p, err := NewProvider(memProvider, newTestSecretLock(t), testKeyURI, opts...)
+			require.NoError(t, err)
+
+			store, err := p.OpenStore("test")
+			require.NoError(t, err)
+
+			require.NoError(t, store.Put("k1", []byte("v1")))
+			require.NoError(t, store.Put("k2", []byte("v2")))
+
+			underlyingStore, err := memProvider.OpenStore("test")
+			require.NoError(t, err)
+
+			k1 := store.(*encryptedStore).storeKey("k1")
+			k2 := store.(*encryptedStore).storeKey("k2")
+
+			ciphertext, err := underlyingStore.Get(k1)
+			require.NoError(t, err)
+			require.NoError(t, underlyingStore.Put(k2, ciphertext))
+
+			_, err = store.Get("k2")
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "failed to decrypt")
+		})
+
+		t.Run(fmt.Sprintf("test delete and batch (hmac: %t)", hmacEnabled), func(t *testing.T) {
+			p, err := NewProvider(mem.NewProvider(), newTestSecretLock(t), testKeyURI, opts...)
+			require.NoError(t, err)
+
+			store, err := p.OpenStore("test")
+			require.NoError(t, err)
+
+			require.NoError(t, store.Put("k1", []byte("v1")))
+			require.NoError(t, store.Delete("k1"))
+
+			_, err = store.Get("k1")
+			require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+			require.Error(t, store.Delete(""))
+
+			require.NoError(t, store.Put("k1", []byte("v1")))
+
+			err = store.Batch([]storage.Operation{
+				{Key: "k1"},
+				{Key: "k2", Value: []byte("v2")},
+			})
+			require.NoError(t, err)
+
+			_, err = store.Get("k1")
+			require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+			v, err := store.Get("k2")
+			require.NoError(t, err)
+			require.Equal(t, []byte("v2"), v)
+
+			err = store.Batch([]storage.Operation{{Key: "k2"}, {Value: []byte("v3")}})
+			require.Error(t, err)
+		})
+
+		t.Run(fmt.Sprintf("test prefix iterator (hmac: %t)", hmacEnabled), func(t *testing.T) {
+			p, err := NewProvider(mem.NewProvider(), newTestSecretLock(t), testKeyURI, opts...)
+			require.NoError(t, err)
+
+			store, err := p.OpenStore("test")
+			require.NoError(t, err)
+
+			keys := []string{"conn_123", "conn_456", "connstate_123_invited", "connstate_123_requested",
+				"connstate_456_invited", "inv_123", "conn"}
+
+			for _, key := range keys {
+				require.NoError(t, store.Put(key, []byte("val-for-"+key)))
+			}
+
+			verifyItr(t, store.Iterator("conn_", "conn_~"), "conn_", 2)
+			verifyItr(t, store.Iterator("connstate_", "connstate_~"), "connstate_", 3)
+			verifyItr(t, store.Iterator("connstate_123_", "connstate_123_~"), "connstate_123_", 2)
+			verifyItr(t, store.Iterator("inv_", "inv_~"), "inv_", 1)
+			verifyItr(t, store.Iterator("did_", "did_~"), "did_", 0)
+		})
+	}
+
+	t.Run("test encryption failures", func(t *testing.T) {
+		memProvider := mem.NewProvider()
+
+		p, err := NewProvider(memProvider, &mocksecretlock.MockSecretLock{
+			ErrEncrypt: errors.New("encrypt error"),
+			ErrDecrypt: errors.New("decrypt error"),
+		}, testKeyURI)
+		require.NoError(t, err)
+
+		store, err := p.OpenStore("test")
+		require.NoError(t, err)
+
+		err = store.Put("k1", []byte("v1"))
+		require.EqualError(t, err, "failed to encrypt: encrypt error")
+
+		err = store.Batch([]storage.Operation{{Key: "k1", Value: []byte("v1")}})
+		require.EqualError(t, err, "failed to encrypt: encrypt error")
+
+		underlyingStore, err := memProvider.OpenStore("test")
+		require.NoError(t, err)
+		require.NoError(t, underlyingStore.Put("k1", []byte("ciphertext")))
+
+		_, err = store.Get("k1")
+		require.EqualError(t, err, "failed to decrypt: decrypt error")
+
+		itr := store.Iterator("k", "k~")
+		require.False(t, itr.Next())
+		require.EqualError(t, itr.Error(), "failed to decrypt: decrypt error")
+		require.Nil(t, itr.Key())
+		require.Nil(t, itr.Value())
+		itr.Release()
+
+		// decrypted value is not a valid record
+		p, err = NewProvider(memProvider, &mocksecretlock.MockSecretLock{ValDecrypt: "invalid"}, testKeyURI)
+		require.NoError(t, err)
+
+		store, err = p.OpenStore("test")
+		require.NoError(t, err)
+
+		_, err = store.Get("k1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal record")
+	})
+
+	t.Run("test open store failure", func(t *testing.T) {
+		p, err := NewProvider(&mockstorage.MockStoreProvider{FailNamespace: "test"}, newTestSecretLock(t), testKeyURI)
+		require.NoError(t, err)
+
+		store, err := p.OpenStore("test")
+		require.Error(t, err)
+		require.Nil(t, store)
+	})
+}
+
+func verifyItr(t *testing.T, itr storage.StoreIterator, prefix string, count int) {
+	defer itr.Release()
+
+	var keys []string
+
+	for itr.Next() {
+		key := string(itr.Key())
+		require.True(t, strings.HasPrefix(key, prefix))
+		require.Equal(t, "val-for-"+key, string(itr.Value()))
+
+		keys = append(keys, key)
+	}
+
+	require.NoError(t, itr.Error())
+	require.Len(t, keys, count)
+}
+
+// testSecretLock encrypts with AES-GCM using a random master key, regardless of the key URI.
+type testSecretLock struct {
+	aead cipher.AEAD
+}
+
+func newTestSecretLock(t *testing.T) *testSecretLock {
+	key := make([]byte, 32)
+
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+
+	aead, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+
+	return &testSecretLock{aead: aead}
+}
+
+func (l *testSecretLock) Encrypt(keyURI string, req *secretlock.EncryptRequest) (*secretlock.EncryptResponse, error) {
+	nonce := make([]byte, l.aead.NonceSize())
+
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	ct := l.aead.Seal(nonce, nonce, []byte(req.Plaintext), []byte(req.AdditionalAuthenticatedData))
+
+	return &secretlock.EncryptResponse{Ciphertext: base64.URLEncoding.EncodeToString(ct)}, nil
+}
+
+func (l *testSecretLock) Decrypt(keyURI string, req *secretlock.DecryptRequest) (*secretlock.DecryptResponse, error) {
+	ct, err := base64.URLEncoding.DecodeString(req.Ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ct) < l.aead.NonceSize() {
+		return nil, errors.New("invalid ciphertext")
+	}
+
+	pt, err := l.aead.Open(nil, ct[:l.aead.NonceSize()], ct[l.aead.NonceSize():],
+		[]byte(req.AdditionalAuthenticatedData))
+	if err != nil {
+		return nil, err
+	}
+
+	return &secretlock.DecryptResponse{Plaintext: string(pt)}, nil
+}
@@ -113,6 +113,54 @@ func (s *store) Get(k string) ([]byte, error) {
 	return []byte(data.Get("value").String()), nil
 }
 
+// Delete removes the record for given key
+func (s *store) Delete(k string) error {
+	if k == "" {
+		return errors.New("key is mandatory")
+	}
+
+	req := s.db.Call("transaction", s.name, "readwrite").Call("objectStore", s.name).Call("delete", k)
+
+	_, err := getResult(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete data: %w", err)
+	}
+
+	return nil
+}
+
+// Batch applies given operations atomically within a single readwrite transaction
+func (s *store) Batch(operations []storage.Operation) error {
+	for _, op := range operations {
+		if op.Key == "" {
+			return errors.New("key is mandatory")
+		}
+	}
+
+	tx := s.db.Call("transaction", s.name, "readwrite")
+	objectStore := tx.Call("objectStore", s.name)
+
+	for _, op := range operations {
+		if op.Value == nil {
+			objectStore.Call("delete", op.Key)
+			continue
+		}
+
+		m := make(map[string]interface{})
+		m["key"] = op.Key
+		m["value"] = string(op.Value)
+
+		objectStore.Call("put", m)
+	}
+
+	err := waitForTransaction(tx)
+	if err != nil {
+		return fmt.Errorf("failed to apply batch: %w", err)
+	}
+
+	return nil
+}
+
 // Iterator returns iterator for the latest snapshot of the underlying db.
 func (s *store) Iterator(start, limit string) storage.StoreIterator {
 	// TODO Change Store Iterator https://github.com/hyperledger/aries-framework-go/issues/852
@@ -201,3 +249,37 @@ func getResult(req js.Value) (*js.Value, error) {
 		return nil, errors.New("timeout waiting for eve")
 	}
 }
+
+func waitForTransaction(tx js.Value) error {
+	oncomplete := make(chan struct{}, 1)
+	// buffered as a failed transaction fires both onerror and onabort
+	onerror := make(chan js.Value, 2)
+
+	const timeout = 3
+
+	tx.Set("oncomplete", js.FuncOf(func(this js.Value, inputs []js.Value) interface{} {
+		oncomplete <- struct{}{}
+		return nil
+	}))
+	tx.Set("onerror", js.FuncOf(func(this js.Value, inputs []js.Value) interface{} {
+		onerror <- this.Get("error")
+		return nil
+	}))
+	tx.Set("onabort", js.FuncOf(func(this js.Value, inputs []js.Value) interface{} {
+		onerror <- this.Get("error")
+		return nil
+	}))
+	select {
+	case <-oncomplete:
+		return nil
+	case value := <-onerror:
+		if !value.Truthy() {
+			return errors.New("transaction aborted")
+		}
+
+		return fmt.Errorf("%s %s", value.Get("name").String(),
+			value.Get("message").String())
+	case <-time.After(timeout * time.Second):
+		return errors.New("timeout waiting for transaction")
+	}
+}
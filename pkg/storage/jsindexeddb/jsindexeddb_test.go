@@ -60,6 +60,48 @@ func TestStore(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("Test store delete and batch", func(t *testing.T) {
+		prov, err := NewProvider()
+		require.NoError(t, err)
+		store, err := prov.OpenStore("test-batch")
+		require.NoError(t, err)
+
+		const key = "did:example:123"
+
+		require.NoError(t, store.Put(key, []byte("value")))
+		require.NoError(t, store.Delete(key))
+
+		_, err = store.Get(key)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), storage.ErrDataNotFound.Error())
+
+		// empty key
+		err = store.Delete("")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key is mandatory")
+
+		require.NoError(t, store.Put("k1", []byte("v1")))
+
+		err = store.Batch([]storage.Operation{
+			{Key: "k1"},
+			{Key: "k2", Value: []byte("v2")},
+		})
+		require.NoError(t, err)
+
+		_, err = store.Get("k1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), storage.ErrDataNotFound.Error())
+
+		doc, err := store.Get("k2")
+		require.NoError(t, err)
+		require.Equal(t, []byte("v2"), doc)
+
+		// empty key
+		err = store.Batch([]storage.Operation{{Value: []byte("v3")}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key is mandatory")
+	})
+
 	t.Run("Test error from open db", func(t *testing.T) {
 		dbVersion = 3
 		defer func() { dbVersion = 1 }()
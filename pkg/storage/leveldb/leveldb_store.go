@@ -141,6 +141,35 @@ func (s *leveldbStore) Get(k string) ([]byte, error) {
 	return data, nil
 }
 
+// Delete removes the record for given key
+func (s *leveldbStore) Delete(k string) error {
+	if k == "" {
+		return errors.New("key is mandatory")
+	}
+
+	return s.db.Delete([]byte(k), nil)
+}
+
+// Batch applies given operations atomically
+func (s *leveldbStore) Batch(operations []storage.Operation) error {
+	batch := new(leveldb.Batch)
+
+	for _, op := range operations {
+		if op.Key == "" {
+			return errors.New("key is mandatory")
+		}
+
+		if op.Value == nil {
+			batch.Delete([]byte(op.Key))
+			continue
+		}
+
+		batch.Put([]byte(op.Key), op.Value)
+	}
+
+	return s.db.Write(batch, nil)
+}
+
 // Iterator returns iterator for the latest snapshot of the underlying db.
 func (s *leveldbStore) Iterator(start, limit string) storage.StoreIterator {
 	if start == "" || limit == "" {
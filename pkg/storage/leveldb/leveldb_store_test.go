@@ -9,6 +9,7 @@ SPDX-License-Identifier: Apache-2.0
 package leveldb
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -231,6 +232,68 @@ func TestLevelDBStore(t *testing.T) {
 	})
 }
 
+func TestLevelDBStoreDeleteAndBatch(t *testing.T) {
+	path, cleanup := setupLevelDB(t)
+	defer cleanup()
+
+	prov := NewProvider(path)
+	defer func() {
+		require.NoError(t, prov.Close())
+	}()
+
+	store, err := prov.OpenStore("test-batch")
+	require.NoError(t, err)
+
+	t.Run("Test Leveldb store delete", func(t *testing.T) {
+		const key = "did:example:123"
+
+		require.NoError(t, store.Put(key, []byte("value")))
+		require.NoError(t, store.Delete(key))
+
+		_, err = store.Get(key)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		// delete missing key
+		require.NoError(t, store.Delete(key))
+
+		// empty key
+		require.Error(t, store.Delete(""))
+	})
+
+	t.Run("Test Leveldb store batch", func(t *testing.T) {
+		require.NoError(t, store.Put("k1", []byte("v1")))
+
+		err = store.Batch([]storage.Operation{
+			{Key: "k1"},
+			{Key: "k2", Value: []byte("v2")},
+			{Key: "k3", Value: []byte("v3")},
+		})
+		require.NoError(t, err)
+
+		_, err = store.Get("k1")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		v, err := store.Get("k2")
+		require.NoError(t, err)
+		require.Equal(t, []byte("v2"), v)
+
+		v, err = store.Get("k3")
+		require.NoError(t, err)
+		require.Equal(t, []byte("v3"), v)
+
+		// nothing is applied if one of the operations is invalid
+		err = store.Batch([]storage.Operation{
+			{Key: "k2"},
+			{Value: []byte("v4")},
+		})
+		require.Error(t, err)
+
+		v, err = store.Get("k2")
+		require.NoError(t, err)
+		require.Equal(t, []byte("v2"), v)
+	})
+}
+
 func verifyItr(t *testing.T, itr storage.StoreIterator, count int, prefix string) {
 	var vals []string
 
@@ -125,6 +125,42 @@ func (s *memStore) Get(k string) ([]byte, error) {
 	return data, nil
 }
 
+// Delete removes the record for given key
+func (s *memStore) Delete(k string) error {
+	if k == "" {
+		return errors.New("key is mandatory")
+	}
+
+	s.Lock()
+	delete(s.db, k)
+	s.Unlock()
+
+	return nil
+}
+
+// Batch applies given operations atomically
+func (s *memStore) Batch(operations []storage.Operation) error {
+	for _, op := range operations {
+		if op.Key == "" {
+			return errors.New("key is mandatory")
+		}
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	for _, op := range operations {
+		if op.Value == nil {
+			delete(s.db, op.Key)
+			continue
+		}
+
+		s.db[op.Key] = op.Value
+	}
+
+	return nil
+}
+
 // Iterator returns iterator for the latest snapshot of the underlying db.
 func (s *memStore) Iterator(start, limit string) storage.StoreIterator {
 	// TODO Change Store Iterator https://github.com/hyperledger/aries-framework-go/issues/852
@@ -0,0 +1,15 @@
+// Copyright SecureKey Technologies Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+module github.com/hyperledger/aries-framework-go/pkg/storage/sql
+
+replace github.com/hyperledger/aries-framework-go => ../../..
+
+require (
+	github.com/hyperledger/aries-framework-go v0.0.0
+	github.com/stretchr/testify v1.4.0
+	modernc.org/sqlite v1.7.5
+)
+
+go 1.13
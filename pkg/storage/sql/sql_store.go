@@ -0,0 +1,333 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package sql provides a storage.Provider on top of database/sql. It is a separate module so that
+// the SQLite driver used by its tests is not pulled into the framework dependencies. The tests use the pure Go
+// SQLite driver modernc.org/sqlite (driver name "sqlite"), they don't require cgo.
+package sql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+var logger = log.New("aries-framework/storage/sql")
+
+const (
+	defaultTableName = "aries_store"
+
+	// the key column is binary and the keys are bound as bytes, so that they compare bytewise (case-sensitive)
+	// in any database and range queries follow the same ordering as the other storage providers.
+	createTableStmt = "CREATE TABLE IF NOT EXISTS %s (" +
+		"namespace VARCHAR(255) NOT NULL, " +
+		"store_key VARBINARY(512) NOT NULL, " +
+		"store_value BLOB NOT NULL, " +
+		"PRIMARY KEY (namespace, store_key))"
+	selectStmt    = "SELECT store_value FROM %s WHERE namespace = ? AND store_key = ?"
+	insertStmt    = "INSERT INTO %s (namespace, store_key, store_value) VALUES (?, ?, ?)"
+	deleteStmt    = "DELETE FROM %s WHERE namespace = ? AND store_key = ?"
+	selectRngStmt = "SELECT store_key, store_value FROM %s " +
+		"WHERE namespace = ? AND store_key >= ? AND store_key < ? ORDER BY store_key"
+)
+
+// Option configures the SQL storage provider.
+type Option func(p *Provider)
+
+// WithTableName sets the name of the table holding the records of all stores (defaults to "aries_store").
+func WithTableName(name string) Option {
+	return func(p *Provider) {
+		p.tableName = name
+	}
+}
+
+// Provider SQL implementation of storage.Provider interface.
+// Records of all stores opened by the provider are kept in a single table, separated by a namespace column.
+// Statements use '?' placeholders, so the driver must support them (e.g. SQLite, MySQL).
+type Provider struct {
+	db        *sql.DB
+	tableName string
+	dbs       map[string]*sqlStore
+	lock      sync.RWMutex
+}
+
+// NewProvider instantiates Provider connected to the database identified by given driver name and data source name.
+// The driver has to be registered with database/sql by the caller (usually through a blank import).
+func NewProvider(driverName, dataSourceName string, opts ...Option) (*Provider, error) {
+	if driverName == "" || dataSourceName == "" {
+		return nil, errors.New("driver name and data source name are mandatory")
+	}
+
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sql database: %w", err)
+	}
+
+	p, err := NewProviderFromDB(db, opts...)
+	if err != nil {
+		// the connection was opened here, so it's not leaked to the caller
+		if e := db.Close(); e != nil {
+			logger.Warnf("failed to close sql database: %s", e)
+		}
+
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// NewProviderFromDB instantiates Provider on top of an already opened database handle.
+// The records table is created if it doesn't exist.
+func NewProviderFromDB(db *sql.DB, opts ...Option) (*Provider, error) {
+	p := &Provider{db: db, tableName: defaultTableName, dbs: make(map[string]*sqlStore)}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if _, err := db.Exec(fmt.Sprintf(createTableStmt, p.tableName)); err != nil {
+		return nil, fmt.Errorf("failed to create table %s: %w", p.tableName, err)
+	}
+
+	return p, nil
+}
+
+// OpenStore opens and returns a store for given name space.
+func (p *Provider) OpenStore(name string) (storage.Store, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	k := strings.ToLower(name)
+
+	store, ok := p.dbs[k]
+	if !ok {
+		store = &sqlStore{db: p.db, namespace: k, tableName: p.tableName}
+		p.dbs[k] = store
+	}
+
+	return store, nil
+}
+
+// Close closes all stores created under this store provider along with the underlying database handle
+func (p *Provider) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.dbs = make(map[string]*sqlStore)
+
+	return p.db.Close()
+}
+
+// CloseStore closes sql store of given name
+func (p *Provider) CloseStore(name string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	delete(p.dbs, strings.ToLower(name))
+
+	return nil
+}
+
+type sqlStore struct {
+	db        *sql.DB
+	namespace string
+	tableName string
+}
+
+// Put stores the key and the record
+func (s *sqlStore) Put(k string, v []byte) error {
+	if k == "" || v == nil {
+		return errors.New("key and value are mandatory")
+	}
+
+	return s.Batch([]storage.Operation{{Key: k, Value: v}})
+}
+
+// Get fetches the record based on key
+func (s *sqlStore) Get(k string) ([]byte, error) {
+	if k == "" {
+		return nil, errors.New("key is mandatory")
+	}
+
+	var data []byte
+
+	err := s.db.QueryRow(s.stmt(selectStmt), s.namespace, []byte(k)).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrDataNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query record: %w", err)
+	}
+
+	return data, nil
+}
+
+// Delete removes the record for given key
+func (s *sqlStore) Delete(k string) error {
+	if k == "" {
+		return errors.New("key is mandatory")
+	}
+
+	if _, err := s.db.Exec(s.stmt(deleteStmt), s.namespace, []byte(k)); err != nil {
+		return fmt.Errorf("failed to delete record: %w", err)
+	}
+
+	return nil
+}
+
+// Batch applies given operations atomically within a single transaction
+func (s *sqlStore) Batch(operations []storage.Operation) error {
+	for _, op := range operations {
+		if op.Key == "" {
+			return errors.New("key is mandatory")
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err = s.apply(tx, operations); err != nil {
+		if e := tx.Rollback(); e != nil {
+			return fmt.Errorf("failed to rollback transaction (%s): %w", e, err)
+		}
+
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// apply executes operations within given transaction. Upserts are done as delete followed by insert
+// since there is no portable upsert statement.
+func (s *sqlStore) apply(tx *sql.Tx, operations []storage.Operation) error {
+	for _, op := range operations {
+		if _, err := tx.Exec(s.stmt(deleteStmt), s.namespace, []byte(op.Key)); err != nil {
+			return fmt.Errorf("failed to delete record: %w", err)
+		}
+
+		if op.Value == nil {
+			continue
+		}
+
+		if _, err := tx.Exec(s.stmt(insertStmt), s.namespace, []byte(op.Key), op.Value); err != nil {
+			return fmt.Errorf("failed to insert record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Iterator returns iterator for the records matching given range at the time of the call.
+// Range semantics are the same as in leveldb, start is included and limit is not included in the range.
+func (s *sqlStore) Iterator(start, limit string) storage.StoreIterator {
+	if start == "" || limit == "" {
+		return &sqlIterator{err: errors.New("start or limit key is mandatory")}
+	}
+
+	items, err := s.queryRange(start, limit)
+
+	return &sqlIterator{items: items, index: -1, err: err}
+}
+
+func (s *sqlStore) queryRange(start, limit string) ([]kvPair, error) {
+	rows, err := s.db.Query(s.stmt(selectRngStmt), s.namespace, []byte(start), []byte(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query records: %w", err)
+	}
+
+	defer func() {
+		if e := rows.Close(); e != nil {
+			logger.Warnf("failed to close rows: %s", e)
+		}
+	}()
+
+	var items []kvPair
+
+	for rows.Next() {
+		var key, value []byte
+
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("failed to read record: %w", err)
+		}
+
+		items = append(items, kvPair{key: key, value: value})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read records: %w", err)
+	}
+
+	return items, nil
+}
+
+func (s *sqlStore) stmt(format string) string {
+	return fmt.Sprintf(format, s.tableName)
+}
+
+type kvPair struct {
+	key   []byte
+	value []byte
+}
+
+// sqlIterator iterates over the records read by a single range query.
+type sqlIterator struct {
+	items []kvPair
+	index int
+	err   error
+}
+
+// Next moves pointer to next value of iterator.
+// It returns false if the iterator is exhausted.
+func (i *sqlIterator) Next() bool {
+	if i.index+1 >= len(i.items) {
+		return false
+	}
+
+	i.index++
+
+	return true
+}
+
+// Release releases associated resources.
+func (i *sqlIterator) Release() {
+	i.items = nil
+	i.index = -1
+}
+
+// Error returns error in iterator.
+func (i *sqlIterator) Error() error {
+	return i.err
+}
+
+// Key returns the key of the current key/value pair.
+func (i *sqlIterator) Key() []byte {
+	if i.index < 0 || i.index >= len(i.items) {
+		return nil
+	}
+
+	return i.items[i.index].key
+}
+
+// Value returns the value of the current key/value pair.
+func (i *sqlIterator) Value() []byte {
+	if i.index < 0 || i.index >= len(i.items) {
+		return nil
+	}
+
+	return i.items[i.index].value
+}
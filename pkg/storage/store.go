@@ -31,6 +31,14 @@ type Store interface {
 	// Get fetches the record based on key
 	Get(k string) ([]byte, error)
 
+	// Delete removes the record for given key. Deleting a key which
+	// doesn't exist is not considered to be an error.
+	Delete(k string) error
+
+	// Batch applies given operations atomically, either all of them
+	// are persisted or none of them.
+	Batch(operations []Operation) error
+
 	// Iterator returns an iterator for the latest snapshot of the
 	// underlying store
 	//
@@ -45,6 +53,15 @@ type Store interface {
 	Iterator(start, limit string) StoreIterator
 }
 
+// Operation is a single write operation applied as part of a Store batch.
+type Operation struct {
+	// Key of the record, mandatory.
+	Key string
+
+	// Value to be stored for the key. The record is deleted if Value is nil.
+	Value []byte
+}
+
 // StoreIterator is the iterator for the latest snapshot of the underlying store.
 type StoreIterator interface {
 	// Next moves the iterator to the next key/value pair.
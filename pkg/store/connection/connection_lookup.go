@@ -24,6 +24,9 @@ const (
 	connIDKeyPrefix    = "conn"
 	connStateKeyPrefix = "connstate"
 	invKeyPrefix       = "inv"
+	invRouterKeyPrefix = "invrouter"
+	invConnKeyPrefix   = "invconn"
+	connFeaturesPrefix = "connfeatures"
 	eventDataKeyprefix = "connevent"
 	// limitPattern with `~` at the end for lte of given prefix (less than or equal)
 	limitPattern    = "%s~"
@@ -186,6 +189,30 @@ func (c *Lookup) GetInvitation(id string, target interface{}) error {
 	return getAndUnmarshal(getInvitationKeyPrefix()(id), target, c.store)
 }
 
+// GetInvitationRouter returns the connection ID of the router saved for the invitation identified by id
+// (storage.ErrDataNotFound if the invitation keys are advertised through the default router)
+func (c *Lookup) GetInvitationRouter(id string) (string, error) {
+	if id == "" {
+		return "", fmt.Errorf(errMsgInvalidKey)
+	}
+
+	routerConnectionID, err := c.store.Get(getInvitationRouterKeyPrefix()(id))
+	if err != nil {
+		return "", err
+	}
+
+	return string(routerConnectionID), nil
+}
+
+// GetConnectionFeatures finds and parses the features saved for the connection to target type
+func (c *Lookup) GetConnectionFeatures(connectionID string, target interface{}) error {
+	if connectionID == "" {
+		return fmt.Errorf(errMsgInvalidKey)
+	}
+
+	return getAndUnmarshal(getConnectionFeaturesKeyPrefix()(connectionID), target, c.store)
+}
+
 // GetEvent returns persisted event data for given connection ID
 // TODO connection event data shouldn't be transient [Issues #1029]
 func (c *Recorder) GetEvent(connectionID string) ([]byte, error) {
@@ -231,6 +258,27 @@ func getInvitationKeyPrefix() KeyPrefix {
 	}
 }
 
+// getInvitationRouterKeyPrefix key prefix for saving the routers of invitations
+func getInvitationRouterKeyPrefix() KeyPrefix {
+	return func(key ...string) string {
+		return fmt.Sprintf(keyPattern, invRouterKeyPrefix, strings.Join(key, keySeparator))
+	}
+}
+
+// getInvitationConnectionKeyPrefix key prefix for saving the connections created from invitations
+func getInvitationConnectionKeyPrefix() KeyPrefix {
+	return func(key ...string) string {
+		return fmt.Sprintf(keyPattern, invConnKeyPrefix, strings.Join(key, keySeparator))
+	}
+}
+
+// getConnectionFeaturesKeyPrefix key prefix for saving the features of connections
+func getConnectionFeaturesKeyPrefix() KeyPrefix {
+	return func(key ...string) string {
+		return fmt.Sprintf(keyPattern, connFeaturesPrefix, strings.Join(key, keySeparator))
+	}
+}
+
 // getNamespaceKeyPrefix key prefix for saving connections records with mappings
 func getNamespaceKeyPrefix(prefix string) KeyPrefix {
 	return func(key ...string) string {
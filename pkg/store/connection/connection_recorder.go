@@ -14,6 +14,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
@@ -27,6 +28,10 @@ const (
 	errMsgInvalidKey = "invalid key"
 )
 
+// invitationLock serializes the updates of the invitations connections of all the recorders, so that an invitation
+// is not removed while a connection is created from it
+var invitationLock sync.Mutex //nolint:gochecknoglobals
+
 // NewRecorder returns new connection recorder.
 // Recorder is read-write connection store which provides
 // write features on top query features from Lookup
@@ -54,8 +59,34 @@ func (c *Recorder) SaveInvitation(id string, invitation interface{}) error {
 	return marshalAndSave(getInvitationKeyPrefix()(id), invitation, c.store)
 }
 
+// SaveConnectionFeatures saves the features of the agent on the other end of the connection,
+// the features are removed with the connection
+func (c *Recorder) SaveConnectionFeatures(connectionID string, features interface{}) error {
+	if connectionID == "" {
+		return fmt.Errorf(errMsgInvalidKey)
+	}
+
+	return marshalAndSave(getConnectionFeaturesKeyPrefix()(connectionID), features, c.store)
+}
+
+// SaveInvitationRouter saves the connection ID of the router the keys of the invitation identified by id are
+// advertised through, the keys of the connections created from the invitation use the same router
+func (c *Recorder) SaveInvitationRouter(id, routerConnectionID string) error {
+	if id == "" || routerConnectionID == "" {
+		return fmt.Errorf(errMsgInvalidKey)
+	}
+
+	return c.store.Put(getInvitationRouterKeyPrefix()(id), []byte(routerConnectionID))
+}
+
 // SaveConnectionRecord saves given connection records in underlying store
 func (c *Recorder) SaveConnectionRecord(record *Record) error {
+	if record.InvitationID != "" {
+		if err := c.saveInvitationConnection(record.InvitationID, record.ConnectionID); err != nil {
+			return fmt.Errorf("save invitation connection in permanent store: %w", err)
+		}
+	}
+
 	if err := marshalAndSave(getConnectionKeyPrefix()(record.ConnectionID),
 		record, c.transientStore); err != nil {
 		return fmt.Errorf("save connection record in transient store: %w", err)
@@ -125,6 +156,143 @@ func (c *Recorder) SaveNamespaceThreadID(threadID, namespace, connectionID strin
 	return c.transientStore.Put(getNamespaceKeyPrefix(prefix)(key), []byte(connectionID))
 }
 
+// RemoveInvitation removes invitation and its router for given key from permanent store
+func (c *Recorder) RemoveInvitation(id string) error {
+	if id == "" {
+		return fmt.Errorf(errMsgInvalidKey)
+	}
+
+	return c.store.Batch([]storage.Operation{
+		{Key: getInvitationKeyPrefix()(id)},
+		{Key: getInvitationRouterKeyPrefix()(id)},
+	})
+}
+
+// RemoveEvent removes event data saved for given connection ID
+func (c *Recorder) RemoveEvent(connectionID string) error {
+	if connectionID == "" {
+		return fmt.Errorf(errMsgInvalidKey)
+	}
+
+	return c.transientStore.Delete(getEventDataKeyPrefix()(connectionID))
+}
+
+// RemoveConnection removes connection record for given connection ID from permanent and transient store
+// along with its state records, namespaced thread ID mapping and event data. The invitation the connection was
+// created from is removed too unless other connections were created from it.
+// Removing a connection which doesn't exist is not considered to be an error.
+func (c *Recorder) RemoveConnection(connectionID string) error {
+	if connectionID == "" {
+		return fmt.Errorf(errMsgInvalidKey)
+	}
+
+	record, err := c.GetConnectionRecord(connectionID)
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return fmt.Errorf("remove connection - get connection record: %w", err)
+	}
+
+	operations := []storage.Operation{
+		{Key: getConnectionKeyPrefix()(connectionID)},
+		{Key: getEventDataKeyPrefix()(connectionID)},
+	}
+
+	stateKeys, err := c.connectionStateKeys(connectionID)
+	if err != nil {
+		return fmt.Errorf("remove connection - get connection state keys: %w", err)
+	}
+
+	for _, k := range stateKeys {
+		operations = append(operations, storage.Operation{Key: k})
+	}
+
+	if record != nil && record.ThreadID != "" && record.Namespace != "" {
+		nsThIDKey, e := CreateNamespaceKey(record.Namespace, record.ThreadID)
+		if e != nil {
+			return fmt.Errorf("remove connection - create namespace key: %w", e)
+		}
+
+		operations = append(operations, storage.Operation{Key: nsThIDKey})
+	}
+
+	if err = c.transientStore.Batch(operations); err != nil {
+		return fmt.Errorf("remove connection from transient store: %w", err)
+	}
+
+	operations = []storage.Operation{
+		{Key: getConnectionKeyPrefix()(connectionID)},
+		{Key: getConnectionFeaturesKeyPrefix()(connectionID)},
+	}
+
+	if record != nil && record.InvitationID != "" {
+		invitationLock.Lock()
+		defer invitationLock.Unlock()
+
+		invitationOperations, e := c.removeInvitationConnection(record.InvitationID, connectionID)
+		if e != nil {
+			return fmt.Errorf("remove connection - remove invitation: %w", e)
+		}
+
+		operations = append(operations, invitationOperations...)
+	}
+
+	if err = c.store.Batch(operations); err != nil {
+		return fmt.Errorf("remove connection from permanent store: %w", err)
+	}
+
+	return nil
+}
+
+// saveInvitationConnection saves the connection created from the invitation identified by invitationID
+func (c *Recorder) saveInvitationConnection(invitationID, connectionID string) error {
+	invitationLock.Lock()
+	defer invitationLock.Unlock()
+
+	return c.store.Put(getInvitationConnectionKeyPrefix()(invitationID, connectionID), []byte(connectionID))
+}
+
+// removeInvitationConnection returns the operations removing the connection created from the invitation identified
+// by invitationID, the invitation is removed too unless other connections were created from it.
+func (c *Recorder) removeInvitationConnection(invitationID, connectionID string) ([]storage.Operation, error) {
+	connectionKey := getInvitationConnectionKeyPrefix()(invitationID, connectionID)
+	operations := []storage.Operation{{Key: connectionKey}}
+
+	searchKey := getInvitationConnectionKeyPrefix()(invitationID, "")
+
+	itr := c.store.Iterator(searchKey, fmt.Sprintf(limitPattern, searchKey))
+	defer itr.Release()
+
+	for itr.Next() {
+		if string(itr.Key()) != connectionKey {
+			return operations, nil
+		}
+	}
+
+	if err := itr.Error(); err != nil {
+		return nil, err
+	}
+
+	return append(operations,
+		storage.Operation{Key: getInvitationKeyPrefix()(invitationID)},
+		storage.Operation{Key: getInvitationRouterKeyPrefix()(invitationID)},
+	), nil
+}
+
+// connectionStateKeys returns transient store keys of all state records saved for given connection ID
+func (c *Recorder) connectionStateKeys(connectionID string) ([]string, error) {
+	searchKey := getConnectionStateKeyPrefix()(connectionID, "")
+
+	itr := c.transientStore.Iterator(searchKey, fmt.Sprintf(limitPattern, searchKey))
+	defer itr.Release()
+
+	var keys []string
+
+	for itr.Next() {
+		keys = append(keys, string(itr.Key()))
+	}
+
+	return keys, itr.Error()
+}
+
 func marshalAndSave(k string, v interface{}, store storage.Store) error {
 	bytes, err := json.Marshal(v)
 	if err != nil {
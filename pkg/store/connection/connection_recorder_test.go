@@ -10,6 +10,7 @@
 package connection
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -413,6 +414,249 @@ func TestConnectionRecorder_ConnectionRecordMappings(t *testing.T) {
 	})
 }
 
+func TestConnectionRecorder_RemoveConnection(t *testing.T) {
+	t.Run("remove connection record - success", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+		require.NotNil(t, recorder)
+
+		connRec := &Record{ThreadID: threadIDValue,
+			ConnectionID: sampleConnID, State: stateNameInvited, Namespace: myNSPrefix}
+		require.NoError(t, recorder.SaveConnectionRecordWithMappings(connRec))
+
+		connRec.State = stateNameCompleted
+		require.NoError(t, recorder.SaveConnectionRecord(connRec))
+		require.NoError(t, recorder.SaveEvent(sampleConnID, []byte("sample-event")))
+		require.NoError(t, recorder.SaveConnectionFeatures(sampleConnID, []string{"feature"}))
+
+		err = recorder.RemoveConnection(sampleConnID)
+		require.NoError(t, err)
+
+		_, err = recorder.GetConnectionRecord(sampleConnID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		_, err = recorder.GetConnectionRecordAtState(sampleConnID, stateNameInvited)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		_, err = recorder.GetConnectionRecordAtState(sampleConnID, stateNameCompleted)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		nsThreadID, err := CreateNamespaceKey(myNSPrefix, threadIDValue)
+		require.NoError(t, err)
+
+		_, err = recorder.GetConnectionRecordByNSThreadID(nsThreadID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		_, err = recorder.GetEvent(sampleConnID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		var features []string
+		err = recorder.GetConnectionFeatures(sampleConnID, &features)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		// removing missing connection is not an error
+		require.NoError(t, recorder.RemoveConnection(sampleConnID))
+	})
+
+	t.Run("remove connection record - invitation is removed along with its last connection", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		const invitationID = "sample-inv-id"
+
+		require.NoError(t, recorder.SaveInvitation(invitationID, &struct{ ID string }{ID: invitationID}))
+		require.NoError(t, recorder.SaveInvitationRouter(invitationID, "router-1"))
+
+		for _, connID := range []string{"conn-1", "conn-2"} {
+			require.NoError(t, recorder.SaveConnectionRecord(&Record{ThreadID: connID, ConnectionID: connID,
+				State: stateNameCompleted, Namespace: theirNSPrefix, InvitationID: invitationID}))
+		}
+
+		// the invitation is kept while other connections were created from it
+		require.NoError(t, recorder.RemoveConnection("conn-1"))
+		require.NoError(t, recorder.GetInvitation(invitationID, &struct{ ID string }{}))
+
+		require.NoError(t, recorder.RemoveConnection("conn-2"))
+
+		err = recorder.GetInvitation(invitationID, &struct{ ID string }{})
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		_, err = recorder.GetInvitationRouter(invitationID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("remove connection record - invitation is kept while a connection in progress uses it", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		const invitationID = "sample-inv-id"
+
+		require.NoError(t, recorder.SaveInvitation(invitationID, &struct{ ID string }{ID: invitationID}))
+
+		require.NoError(t, recorder.SaveConnectionRecord(&Record{ThreadID: "conn-1", ConnectionID: "conn-1",
+			State: stateNameCompleted, Namespace: theirNSPrefix, InvitationID: invitationID}))
+		require.NoError(t, recorder.SaveConnectionRecord(&Record{ThreadID: "conn-2", ConnectionID: "conn-2",
+			State: "requested", Namespace: theirNSPrefix, InvitationID: invitationID}))
+
+		require.NoError(t, recorder.RemoveConnection("conn-1"))
+		require.NoError(t, recorder.GetInvitation(invitationID, &struct{ ID string }{}))
+
+		require.NoError(t, recorder.RemoveConnection("conn-2"))
+
+		err = recorder.GetInvitation(invitationID, &struct{ ID string }{})
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("remove connection record - remove invitation error", func(t *testing.T) {
+		store := &mockstorage.MockStore{Store: make(map[string][]byte)}
+		recorder, err := NewRecorder(&protocol.MockProvider{StoreProvider: mockstorage.NewCustomMockStoreProvider(store)})
+		require.NoError(t, err)
+
+		require.NoError(t, recorder.SaveConnectionRecord(&Record{ThreadID: threadIDValue, ConnectionID: sampleConnID,
+			State: stateNameCompleted, Namespace: theirNSPrefix, InvitationID: "sample-inv-id"}))
+
+		store.ErrItr = errors.New("iterator error")
+
+		err = recorder.RemoveConnection(sampleConnID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "remove connection - remove invitation: iterator error")
+	})
+
+	t.Run("save connection record - save invitation connection error", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{
+			StoreProvider: mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{
+				Store:  make(map[string][]byte),
+				ErrPut: errors.New("put error"),
+			}),
+		})
+		require.NoError(t, err)
+
+		err = recorder.SaveConnectionRecord(&Record{ThreadID: threadIDValue, ConnectionID: sampleConnID,
+			State: stateNameCompleted, Namespace: theirNSPrefix, InvitationID: "sample-inv-id"})
+		require.EqualError(t, err, "save invitation connection in permanent store: put error")
+	})
+
+	t.Run("remove connection record - invalid key", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		err = recorder.RemoveConnection("")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errMsgInvalidKey)
+	})
+
+	t.Run("remove connection record - transient store error", func(t *testing.T) {
+		const errMsg = "batch error"
+		recorder, err := NewRecorder(&protocol.MockProvider{
+			TransientStoreProvider: mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{
+				Store:    make(map[string][]byte),
+				ErrBatch: fmt.Errorf(errMsg),
+			}),
+		})
+		require.NoError(t, err)
+
+		err = recorder.RemoveConnection(sampleConnID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errMsg)
+	})
+
+	t.Run("remove connection record - permanent store error", func(t *testing.T) {
+		const errMsg = "batch error"
+		recorder, err := NewRecorder(&protocol.MockProvider{
+			StoreProvider: mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{
+				Store:    make(map[string][]byte),
+				ErrBatch: fmt.Errorf(errMsg),
+			}),
+		})
+		require.NoError(t, err)
+
+		err = recorder.RemoveConnection(sampleConnID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errMsg)
+	})
+
+	t.Run("remove connection record - get record error", func(t *testing.T) {
+		const errMsg = "get error"
+		recorder, err := NewRecorder(&protocol.MockProvider{
+			StoreProvider: mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{
+				Store:  make(map[string][]byte),
+				ErrGet: fmt.Errorf(errMsg),
+			}),
+		})
+		require.NoError(t, err)
+
+		err = recorder.RemoveConnection(sampleConnID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errMsg)
+	})
+}
+
+func TestConnectionRecorder_InvitationRouter(t *testing.T) {
+	recorder, err := NewRecorder(&protocol.MockProvider{})
+	require.NoError(t, err)
+
+	const id = "sample-inv-id"
+
+	_, err = recorder.GetInvitationRouter(id)
+	require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+	require.NoError(t, recorder.SaveInvitationRouter(id, "router-1"))
+
+	routerConnID, err := recorder.GetInvitationRouter(id)
+	require.NoError(t, err)
+	require.Equal(t, "router-1", routerConnID)
+
+	require.EqualError(t, recorder.SaveInvitationRouter("", "router-1"), errMsgInvalidKey)
+	require.EqualError(t, recorder.SaveInvitationRouter(id, ""), errMsgInvalidKey)
+
+	_, err = recorder.GetInvitationRouter("")
+	require.EqualError(t, err, errMsgInvalidKey)
+}
+
+func TestConnectionRecorder_ConnectionFeatures(t *testing.T) {
+	recorder, err := NewRecorder(&protocol.MockProvider{})
+	require.NoError(t, err)
+
+	var features []string
+
+	err = recorder.GetConnectionFeatures(sampleConnID, &features)
+	require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+	require.NoError(t, recorder.SaveConnectionFeatures(sampleConnID, []string{"feature-1", "feature-2"}))
+
+	require.NoError(t, recorder.GetConnectionFeatures(sampleConnID, &features))
+	require.Equal(t, []string{"feature-1", "feature-2"}, features)
+
+	require.EqualError(t, recorder.SaveConnectionFeatures("", features), errMsgInvalidKey)
+	require.EqualError(t, recorder.GetConnectionFeatures("", &features), errMsgInvalidKey)
+}
+
+func TestConnectionRecorder_RemoveInvitationAndEvent(t *testing.T) {
+	recorder, err := NewRecorder(&protocol.MockProvider{})
+	require.NoError(t, err)
+
+	const id = "sample-inv-id"
+
+	require.NoError(t, recorder.SaveInvitation(id, &struct{ ID string }{ID: id}))
+	require.NoError(t, recorder.SaveInvitationRouter(id, "router-1"))
+	require.NoError(t, recorder.RemoveInvitation(id))
+
+	err = recorder.GetInvitation(id, &struct{ ID string }{})
+	require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+	_, err = recorder.GetInvitationRouter(id)
+	require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+	require.NoError(t, recorder.SaveEvent(sampleConnID, []byte("sample-event")))
+	require.NoError(t, recorder.RemoveEvent(sampleConnID))
+
+	_, err = recorder.GetEvent(sampleConnID)
+	require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+	require.Error(t, recorder.RemoveInvitation(""))
+	require.Error(t, recorder.RemoveEvent(""))
+}
+
 func TestConnectionRecorder_CreateNSKeys(t *testing.T) {
 	t.Run("creating their namespace key success", func(t *testing.T) {
 		key, err := CreateNamespaceKey(theirNSPrefix, threadIDValue)
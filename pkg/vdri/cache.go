@@ -0,0 +1,353 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vdri
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+
+	diddoc "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+)
+
+const (
+	defaultCacheSize        = 100
+	defaultCacheTTL         = 5 * time.Minute
+	defaultNegativeCacheTTL = 30 * time.Second
+)
+
+// CacheOption is a caching registry option
+type CacheOption func(opts *CachingRegistry)
+
+// CachingRegistry decorates vdri registry with the cache of the resolved DID documents.
+// The least recently used documents are evicted once the cache is full, every document expires after TTL.
+// DIDs which are not found (vdriapi.ErrNotFound) are cached for the negative TTL.
+type CachingRegistry struct {
+	vdriapi.Registry
+	size        int
+	ttl         time.Duration
+	negativeTTL time.Duration
+	now         func() time.Time
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type cacheEntry struct {
+	did     string
+	doc     *diddoc.Doc
+	err     error
+	expires time.Time
+}
+
+// NewCachingRegistry returns new instance of the caching vdri registry
+func NewCachingRegistry(registry vdriapi.Registry, opts ...CacheOption) *CachingRegistry {
+	r := &CachingRegistry{
+		Registry:    registry,
+		size:        defaultCacheSize,
+		ttl:         defaultCacheTTL,
+		negativeTTL: defaultNegativeCacheTTL,
+		now:         time.Now,
+		entries:     make(map[string]*list.Element),
+		lru:         list.New(),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// WithCacheSize sets the maximum number of the cached DIDs
+func WithCacheSize(size int) CacheOption {
+	return func(opts *CachingRegistry) {
+		opts.size = size
+	}
+}
+
+// WithCacheTTL sets the time the resolved DID document is cached for
+func WithCacheTTL(ttl time.Duration) CacheOption {
+	return func(opts *CachingRegistry) {
+		opts.ttl = ttl
+	}
+}
+
+// WithNegativeCacheTTL sets the time the DID which is not found is cached for, zero disables the negative caching
+func WithNegativeCacheTTL(ttl time.Duration) CacheOption {
+	return func(opts *CachingRegistry) {
+		opts.negativeTTL = ttl
+	}
+}
+
+// Resolve did document, the cache is skipped if the specific version or the resolution result is requested.
+// The no-cache option skips the lookup, the resolved document is still cached. The cached document is copied,
+// the caller may modify the returned document.
+func (r *CachingRegistry) Resolve(did string, opts ...vdriapi.ResolveOpts) (*diddoc.Doc, error) {
+	resolveOpts := &vdriapi.ResolveDIDOpts{}
+	// Apply options
+	for _, opt := range opts {
+		opt(resolveOpts)
+	}
+
+	if resolveOpts.ResultType != vdriapi.DidDocumentResult || resolveOpts.VersionID != nil ||
+		resolveOpts.VersionTime != "" {
+		return r.Registry.Resolve(did, opts...)
+	}
+
+	if !resolveOpts.NoCache {
+		if entry, ok := r.get(did); ok {
+			return copyDoc(entry.doc), entry.err
+		}
+	}
+
+	doc, err := r.Registry.Resolve(did, opts...)
+
+	switch {
+	case err == nil:
+		r.put(&cacheEntry{did: did, doc: copyDoc(doc)}, r.ttl)
+	case errors.Is(err, vdriapi.ErrNotFound):
+		r.put(&cacheEntry{did: did, err: err}, r.negativeTTL)
+	}
+
+	return doc, err
+}
+
+// Store did store, the cached document is invalidated once the document is stored
+func (r *CachingRegistry) Store(doc *diddoc.Doc) error {
+	if err := r.Registry.Store(doc); err != nil {
+		return err
+	}
+
+	r.remove(doc.ID)
+
+	return nil
+}
+
+// Update stores the signed update of the DID document, the cached document is invalidated
+func (r *CachingRegistry) Update(doc *diddoc.Doc, keyID string, signer legacykms.Signer) error {
+	updater, err := r.updater()
+	if err != nil {
+		return err
+	}
+
+	if err := updater.Update(doc, keyID, signer); err != nil {
+		return err
+	}
+
+	r.remove(doc.ID)
+
+	return nil
+}
+
+// RotateKey replaces the public key of the DID document, the cached document is invalidated
+func (r *CachingRegistry) RotateKey(didID, oldKeyID string, newKey *diddoc.PublicKey, keyID string,
+	signer legacykms.Signer) error {
+	updater, err := r.updater()
+	if err != nil {
+		return err
+	}
+
+	if err := updater.RotateKey(didID, oldKeyID, newKey, keyID, signer); err != nil {
+		return err
+	}
+
+	r.remove(didID)
+
+	return nil
+}
+
+// Deactivate deactivates the DID, the cached document is invalidated
+func (r *CachingRegistry) Deactivate(didID, keyID string, signer legacykms.Signer) error {
+	updater, err := r.updater()
+	if err != nil {
+		return err
+	}
+
+	if err := updater.Deactivate(didID, keyID, signer); err != nil {
+		return err
+	}
+
+	r.remove(didID)
+
+	return nil
+}
+
+func (r *CachingRegistry) updater() (vdriapi.Updater, error) {
+	updater, ok := r.Registry.(vdriapi.Updater)
+	if !ok {
+		return nil, vdriapi.ErrUpdateNotSupported
+	}
+
+	return updater, nil
+}
+
+// Create returns new DID Document, the cached lookup of the DID is invalidated
+func (r *CachingRegistry) Create(method string, opts ...vdriapi.DocOpts) (*diddoc.Doc, error) {
+	doc, err := r.Registry.Create(method, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	r.remove(doc.ID)
+
+	return doc, nil
+}
+
+func (r *CachingRegistry) get(did string) (*cacheEntry, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	element, ok := r.entries[did]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if !r.now().Before(entry.expires) {
+		r.lru.Remove(element)
+		delete(r.entries, did)
+
+		return nil, false
+	}
+
+	r.lru.MoveToFront(element)
+
+	return entry, true
+}
+
+func (r *CachingRegistry) put(entry *cacheEntry, ttl time.Duration) {
+	if ttl <= 0 || r.size <= 0 {
+		return
+	}
+
+	entry.expires = r.now().Add(ttl)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if element, ok := r.entries[entry.did]; ok {
+		element.Value = entry
+		r.lru.MoveToFront(element)
+
+		return
+	}
+
+	r.entries[entry.did] = r.lru.PushFront(entry)
+
+	for r.lru.Len() > r.size {
+		oldest := r.lru.Back()
+		r.lru.Remove(oldest)
+		delete(r.entries, oldest.Value.(*cacheEntry).did)
+	}
+}
+
+func (r *CachingRegistry) remove(did string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if element, ok := r.entries[did]; ok {
+		r.lru.Remove(element)
+		delete(r.entries, did)
+	}
+}
+
+// copyDoc returns the deep copy of the DID document.
+func copyDoc(doc *diddoc.Doc) *diddoc.Doc {
+	if doc == nil {
+		return nil
+	}
+
+	c := *doc
+	c.Context = append([]string(nil), doc.Context...)
+	c.Created = copyTime(doc.Created)
+	c.Updated = copyTime(doc.Updated)
+
+	c.PublicKey = nil
+	for i := range doc.PublicKey {
+		c.PublicKey = append(c.PublicKey, copyPublicKey(doc.PublicKey[i]))
+	}
+
+	c.Authentication = copyVerificationMethods(doc.Authentication)
+	c.KeyAgreement = copyVerificationMethods(doc.KeyAgreement)
+
+	c.Service = nil
+	for i := range doc.Service {
+		svc := doc.Service[i]
+		svc.RecipientKeys = append([]string(nil), svc.RecipientKeys...)
+		svc.RoutingKeys = append([]string(nil), svc.RoutingKeys...)
+
+		if svc.Properties != nil {
+			svc.Properties = copyValue(svc.Properties).(map[string]interface{})
+		}
+
+		c.Service = append(c.Service, svc)
+	}
+
+	c.Proof = nil
+	for i := range doc.Proof {
+		proof := doc.Proof[i]
+		proof.Created = copyTime(proof.Created)
+		proof.ProofValue = append([]byte(nil), proof.ProofValue...)
+		proof.Nonce = append([]byte(nil), proof.Nonce...)
+		c.Proof = append(c.Proof, proof)
+	}
+
+	return &c
+}
+
+func copyPublicKey(pk diddoc.PublicKey) diddoc.PublicKey {
+	pk.Value = append([]byte(nil), pk.Value...)
+
+	return pk
+}
+
+func copyVerificationMethods(vms []diddoc.VerificationMethod) []diddoc.VerificationMethod {
+	var c []diddoc.VerificationMethod
+
+	for i := range vms {
+		c = append(c, diddoc.VerificationMethod{PublicKey: copyPublicKey(vms[i].PublicKey)})
+	}
+
+	return c
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	c := *t
+
+	return &c
+}
+
+// copyValue copies the JSON values (e.g. service properties), other values are not copied.
+func copyValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(value))
+		for k, e := range value {
+			c[k] = copyValue(e)
+		}
+
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(value))
+		for i, e := range value {
+			c[i] = copyValue(e)
+		}
+
+		return c
+	default:
+		return v
+	}
+}
@@ -0,0 +1,299 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vdri
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
+	mockvdri "github.com/hyperledger/aries-framework-go/pkg/internal/mock/vdri"
+)
+
+func TestCachingRegistry_Resolve(t *testing.T) {
+	resolved := make(map[string]int)
+	registry := &mockvdri.MockVDRIRegistry{
+		ResolveFunc: func(didID string, opts ...vdriapi.ResolveOpts) (*did.Doc, error) {
+			resolved[didID]++
+
+			switch didID {
+			case "did:example:unknown":
+				return nil, vdriapi.ErrNotFound
+			case "did:example:error":
+				return nil, errors.New("resolve error")
+			default:
+				return &did.Doc{ID: didID}, nil
+			}
+		},
+	}
+
+	now := time.Now()
+	cache := NewCachingRegistry(registry, WithCacheSize(2), WithCacheTTL(time.Minute),
+		WithNegativeCacheTTL(time.Second))
+	cache.now = func() time.Time { return now }
+
+	t.Run("test resolve - cached", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			doc, err := cache.Resolve("did:example:1")
+			require.NoError(t, err)
+			require.Equal(t, "did:example:1", doc.ID)
+		}
+
+		require.Equal(t, 1, resolved["did:example:1"])
+	})
+
+	t.Run("test resolve - expired", func(t *testing.T) {
+		now = now.Add(time.Minute)
+
+		_, err := cache.Resolve("did:example:1")
+		require.NoError(t, err)
+		require.Equal(t, 2, resolved["did:example:1"])
+	})
+
+	t.Run("test resolve - no cache", func(t *testing.T) {
+		_, err := cache.Resolve("did:example:1", vdriapi.WithNoCache(true))
+		require.NoError(t, err)
+		require.Equal(t, 3, resolved["did:example:1"])
+
+		_, err = cache.Resolve("did:example:1")
+		require.NoError(t, err)
+		require.Equal(t, 3, resolved["did:example:1"])
+	})
+
+	t.Run("test resolve - specific version is not cached", func(t *testing.T) {
+		_, err := cache.Resolve("did:example:1", vdriapi.WithVersionID("1"))
+		require.NoError(t, err)
+		_, err = cache.Resolve("did:example:1", vdriapi.WithVersionTime(now))
+		require.NoError(t, err)
+		require.Equal(t, 5, resolved["did:example:1"])
+	})
+
+	t.Run("test resolve - least recently used is evicted", func(t *testing.T) {
+		_, err := cache.Resolve("did:example:2")
+		require.NoError(t, err)
+		_, err = cache.Resolve("did:example:1")
+		require.NoError(t, err)
+		_, err = cache.Resolve("did:example:3")
+		require.NoError(t, err)
+
+		// did:example:2 is evicted
+		_, err = cache.Resolve("did:example:1")
+		require.NoError(t, err)
+		_, err = cache.Resolve("did:example:2")
+		require.NoError(t, err)
+		require.Equal(t, 5, resolved["did:example:1"])
+		require.Equal(t, 2, resolved["did:example:2"])
+	})
+
+	t.Run("test resolve - not found is cached for negative TTL", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			_, err := cache.Resolve("did:example:unknown")
+			require.True(t, errors.Is(err, vdriapi.ErrNotFound))
+		}
+
+		require.Equal(t, 1, resolved["did:example:unknown"])
+
+		now = now.Add(time.Second)
+
+		_, err := cache.Resolve("did:example:unknown")
+		require.True(t, errors.Is(err, vdriapi.ErrNotFound))
+		require.Equal(t, 2, resolved["did:example:unknown"])
+	})
+
+	t.Run("test resolve - errors are not cached", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			_, err := cache.Resolve("did:example:error")
+			require.EqualError(t, err, "resolve error")
+		}
+
+		require.Equal(t, 2, resolved["did:example:error"])
+	})
+}
+
+func TestCachingRegistry_Copy(t *testing.T) {
+	created := time.Now()
+	registry := &mockvdri.MockVDRIRegistry{
+		ResolveFunc: func(didID string, opts ...vdriapi.ResolveOpts) (*did.Doc, error) {
+			created := created
+
+			return &did.Doc{
+				Context:        []string{"https://w3id.org/did/v1"},
+				ID:             didID,
+				PublicKey:      []did.PublicKey{{ID: didID + "#key-1", Value: []byte("key")}},
+				Authentication: []did.VerificationMethod{{PublicKey: did.PublicKey{Value: []byte("key")}}},
+				Service: []did.Service{{
+					ID:            "#agent",
+					RecipientKeys: []string{"key"},
+					Properties:    map[string]interface{}{"nested": map[string]interface{}{"list": []interface{}{"a"}}},
+				}},
+				Created: &created,
+				Proof:   []did.Proof{{ProofValue: []byte("proof")}},
+			}, nil
+		},
+	}
+
+	cache := NewCachingRegistry(registry)
+
+	doc, err := cache.Resolve("did:example:1")
+	require.NoError(t, err)
+
+	expected, err := registry.Resolve("did:example:1")
+	require.NoError(t, err)
+	require.Equal(t, expected, doc)
+
+	// the returned documents don't share any data with the cached one
+	for i := 0; i < 2; i++ {
+		doc.Context[0] = "modified"
+		doc.PublicKey[0].Value[0] = 'x'
+		doc.Authentication[0].PublicKey.Value[0] = 'x'
+		doc.Service[0].RecipientKeys[0] = "modified"
+		doc.Service[0].Properties["nested"].(map[string]interface{})["list"].([]interface{})[0] = "modified"
+		*doc.Created = time.Time{}
+		doc.Proof[0].ProofValue[0] = 'x'
+
+		doc, err = cache.Resolve("did:example:1")
+		require.NoError(t, err)
+		require.Equal(t, expected, doc)
+	}
+
+	require.Nil(t, copyDoc(nil))
+}
+
+func TestCachingRegistry_Invalidate(t *testing.T) {
+	resolved := 0
+	registry := &mockvdri.MockVDRIRegistry{
+		CreateValue: &did.Doc{ID: "did:example:1"},
+		ResolveFunc: func(didID string, opts ...vdriapi.ResolveOpts) (*did.Doc, error) {
+			resolved++
+			return nil, vdriapi.ErrNotFound
+		},
+	}
+
+	cache := NewCachingRegistry(registry)
+
+	t.Run("test create invalidates the cached DID", func(t *testing.T) {
+		_, err := cache.Resolve("did:example:1")
+		require.True(t, errors.Is(err, vdriapi.ErrNotFound))
+
+		doc, err := cache.Create("example")
+		require.NoError(t, err)
+		require.Equal(t, "did:example:1", doc.ID)
+
+		_, err = cache.Resolve("did:example:1")
+		require.True(t, errors.Is(err, vdriapi.ErrNotFound))
+		require.Equal(t, 2, resolved)
+	})
+
+	t.Run("test store invalidates the cached DID", func(t *testing.T) {
+		require.NoError(t, cache.Store(&did.Doc{ID: "did:example:1"}))
+
+		_, err := cache.Resolve("did:example:1")
+		require.True(t, errors.Is(err, vdriapi.ErrNotFound))
+		require.Equal(t, 3, resolved)
+	})
+
+	t.Run("test failed store keeps the cached DID", func(t *testing.T) {
+		cache := NewCachingRegistry(&failingStoreRegistry{MockVDRIRegistry: registry})
+
+		_, err := cache.Resolve("did:example:1")
+		require.True(t, errors.Is(err, vdriapi.ErrNotFound))
+
+		require.EqualError(t, cache.Store(&did.Doc{ID: "did:example:1"}), "store error")
+
+		_, err = cache.Resolve("did:example:1")
+		require.True(t, errors.Is(err, vdriapi.ErrNotFound))
+		require.Equal(t, 4, resolved)
+	})
+
+	t.Run("test create error", func(t *testing.T) {
+		registry.CreateErr = fmt.Errorf("create error")
+
+		_, err := cache.Create("example")
+		require.EqualError(t, err, "create error")
+	})
+
+	t.Run("test caching disabled", func(t *testing.T) {
+		cache := NewCachingRegistry(registry, WithCacheSize(0))
+
+		for i := 0; i < 2; i++ {
+			_, err := cache.Resolve("did:example:1")
+			require.True(t, errors.Is(err, vdriapi.ErrNotFound))
+		}
+
+		require.Equal(t, 6, resolved)
+		require.NoError(t, cache.Close())
+	})
+}
+
+func TestCachingRegistry_Update(t *testing.T) {
+	resolved := 0
+	updater := &mockUpdater{MockVDRI: mockvdri.MockVDRI{AcceptValue: true,
+		ReadFunc: func(didID string, opts ...vdriapi.ResolveOpts) (*did.Doc, error) {
+			resolved++
+			return &did.Doc{ID: didID}, nil
+		}}}
+
+	cache := NewCachingRegistry(New(&mockprovider.Provider{}, WithVDRI(updater)))
+
+	resolve := func(expected int) {
+		doc, err := cache.Resolve("did:example:1")
+		require.NoError(t, err)
+		require.Equal(t, "did:example:1", doc.ID)
+		require.Equal(t, expected, resolved)
+	}
+
+	t.Run("test update invalidates the cached DID", func(t *testing.T) {
+		resolve(1)
+		resolve(1)
+
+		require.NoError(t, cache.Update(&did.Doc{ID: "did:example:1"}, "key-1", nil))
+		resolve(2)
+
+		require.NoError(t, cache.RotateKey("did:example:1", "key-1", &did.PublicKey{}, "key-1", nil))
+		resolve(3)
+
+		require.NoError(t, cache.Deactivate("did:example:1", "key-1", nil))
+		resolve(4)
+	})
+
+	t.Run("test failed update keeps the cached DID", func(t *testing.T) {
+		updater.err = fmt.Errorf("update error")
+
+		require.EqualError(t, cache.Update(&did.Doc{ID: "did:example:1"}, "key-1", nil), "update error")
+		require.EqualError(t, cache.RotateKey("did:example:1", "key-1", &did.PublicKey{}, "key-1", nil),
+			"update error")
+		require.EqualError(t, cache.Deactivate("did:example:1", "key-1", nil), "update error")
+		resolve(4)
+	})
+
+	t.Run("test registry does not support updates", func(t *testing.T) {
+		cache := NewCachingRegistry(&mockvdri.MockVDRIRegistry{})
+
+		err := cache.Update(&did.Doc{ID: "did:example:1"}, "key-1", nil)
+		require.True(t, errors.Is(err, vdriapi.ErrUpdateNotSupported))
+
+		err = cache.RotateKey("did:example:1", "key-1", &did.PublicKey{}, "key-1", nil)
+		require.True(t, errors.Is(err, vdriapi.ErrUpdateNotSupported))
+
+		err = cache.Deactivate("did:example:1", "key-1", nil)
+		require.True(t, errors.Is(err, vdriapi.ErrUpdateNotSupported))
+	})
+}
+
+type failingStoreRegistry struct {
+	*mockvdri.MockVDRIRegistry
+}
+
+func (r *failingStoreRegistry) Store(*did.Doc) error {
+	return errors.New("store error")
+}
@@ -6,39 +6,79 @@ SPDX-License-Identifier: Apache-2.0
 package httpbinding
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"path"
+	"strings"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 )
 
-// resolveDID makes DID resolution via HTTP
-func (v *VDRI) resolveDID(uri string) ([]byte, error) {
-	resp, err := v.client.Get(uri)
+const (
+	// contentTypeResolutionResult is the content type of the DID resolution result
+	// (https://w3c-ccg.github.io/did-resolution/#output-resolutionresult)
+	contentTypeResolutionResult = `application/ld+json;profile="https://w3id.org/did-resolution"`
+
+	versionIDParam   = "versionId"
+	versionTimeParam = "versionTime"
+)
+
+// resolutionResult is the DID resolution result returned by the remote DID resolver
+type resolutionResult struct {
+	DIDDocument         json.RawMessage        `json:"didDocument,omitempty"`
+	DIDDocumentMetadata *documentMetadata      `json:"didDocumentMetadata,omitempty"`
+	MethodMetadata      map[string]interface{} `json:"methodMetadata,omitempty"`
+	ResolverMetadata    *resolverMetadata      `json:"resolverMetadata,omitempty"`
+}
+
+type documentMetadata struct {
+	Created     *time.Time `json:"created,omitempty"`
+	Updated     *time.Time `json:"updated,omitempty"`
+	Deactivated bool       `json:"deactivated,omitempty"`
+	VersionID   string     `json:"versionId,omitempty"`
+}
+
+type resolverMetadata struct {
+	ContentType string `json:"contentType,omitempty"`
+}
+
+// resolveDID makes DID resolution via HTTP and returns the response body along with its content type
+func (v *VDRI) resolveDID(uri, accept string) ([]byte, string, error) {
+	req, err := http.NewRequest(http.MethodGet, uri, nil)
 	if err != nil {
-		return nil, fmt.Errorf("HTTP Get request failed: %w", err)
+		return nil, "", fmt.Errorf("HTTP create get request failed: %w", err)
+	}
+
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("HTTP Get request failed: %w", err)
 	}
 
 	defer closeResponseBody(resp.Body)
 
-	if containsDIDDocument(resp) {
+	if containsDIDDocument(resp) || containsResolutionResult(resp) {
 		var gotBody []byte
 
 		gotBody, err = ioutil.ReadAll(resp.Body)
 		if err != nil {
-			return nil, fmt.Errorf("reading response body failed: %w", err)
+			return nil, "", fmt.Errorf("reading response body failed: %w", err)
 		}
 
-		return gotBody, nil
+		return gotBody, resp.Header.Get("Content-type"), nil
 	} else if notExistentDID(resp) {
-		return nil, fmt.Errorf("DID does not exist for request: %s", uri)
+		return nil, "", fmt.Errorf("DID does not exist for request: %s", uri)
 	}
 
-	return nil, fmt.Errorf("unsupported response from DID resolver [%v] header [%s]",
+	return nil, "", fmt.Errorf("unsupported response from DID resolver [%v] header [%s]",
 		resp.StatusCode, resp.Header.Get("Content-type"))
 }
 
@@ -49,26 +89,141 @@ func notExistentDID(resp *http.Response) bool {
 
 // containsDIDDocument checks weather reply from remote DID resolver contains DID document
 func containsDIDDocument(resp *http.Response) bool {
-	return resp.StatusCode == http.StatusOK && resp.Header.Get("Content-type") == "application/did+ld+json"
+	return resp.StatusCode == http.StatusOK && resp.Header.Get("Content-type") == vdriapi.ContentTypeDIDLDJSON
+}
+
+// containsResolutionResult checks weather reply from remote DID resolver contains DID resolution result
+func containsResolutionResult(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusOK && isResolutionResult(resp.Header.Get("Content-type"))
+}
+
+func isResolutionResult(contentType string) bool {
+	return strings.ReplaceAll(contentType, " ", "") == contentTypeResolutionResult
 }
 
 // Read implements didresolver.DidMethod.Read interface (https://w3c-ccg.github.io/did-resolution/#resolving-input)
-func (v *VDRI) Read(didID string, _ ...vdriapi.ResolveOpts) (*did.Doc, error) {
-	reqURL, err := url.ParseRequestURI(v.endpointURL)
+func (v *VDRI) Read(didID string, opts ...vdriapi.ResolveOpts) (*did.Doc, error) {
+	reqURL, err := v.requestURL(didID, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("url parse request uri failed: %w", err)
+		return nil, err
 	}
 
-	reqURL.Path = path.Join(reqURL.Path, didID)
-
-	data, err := v.resolveDID(reqURL.String())
+	data, contentType, err := v.resolveDID(reqURL, "")
 	if err != nil {
 		return nil, err
 	}
 
+	if isResolutionResult(contentType) {
+		resolution, e := parseResolutionResult(data)
+		if e != nil {
+			return nil, e
+		}
+
+		return resolution.DIDDocument, nil
+	}
+
 	if len(data) == 0 {
 		return nil, vdriapi.ErrNotFound
 	}
 
 	return did.ParseDocument(data)
 }
+
+// ReadResolution reads the DID resolution result from the remote DID resolver. The resolution result is requested,
+// the DID document returned instead is wrapped with the metadata derived from the document.
+func (v *VDRI) ReadResolution(didID string, opts ...vdriapi.ResolveOpts) (*vdriapi.DocResolution, error) {
+	reqURL, err := v.requestURL(didID, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	data, contentType, err := v.resolveDID(reqURL, contentTypeResolutionResult)
+	if err != nil {
+		return nil, err
+	}
+
+	if isResolutionResult(contentType) {
+		return parseResolutionResult(data)
+	}
+
+	if len(data) == 0 {
+		return nil, vdriapi.ErrNotFound
+	}
+
+	doc, err := did.ParseDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vdriapi.DocResolution{
+		DIDDocument:      doc,
+		DocumentMetadata: &vdriapi.DocumentMetadata{Created: doc.Created, Updated: doc.Updated},
+		ResolverMetadata: &vdriapi.ResolverMetadata{ContentType: contentType},
+	}, nil
+}
+
+// requestURL builds the resolution request URL with the version query parameters
+func (v *VDRI) requestURL(didID string, opts ...vdriapi.ResolveOpts) (string, error) {
+	resolveOpts := &vdriapi.ResolveDIDOpts{}
+
+	for _, opt := range opts {
+		opt(resolveOpts)
+	}
+
+	reqURL, err := url.ParseRequestURI(v.endpointURL)
+	if err != nil {
+		return "", fmt.Errorf("url parse request uri failed: %w", err)
+	}
+
+	reqURL.Path = path.Join(reqURL.Path, didID)
+
+	query := reqURL.Query()
+
+	if resolveOpts.VersionID != nil {
+		query.Set(versionIDParam, fmt.Sprint(resolveOpts.VersionID))
+	}
+
+	if resolveOpts.VersionTime != "" {
+		query.Set(versionTimeParam, resolveOpts.VersionTime)
+	}
+
+	reqURL.RawQuery = query.Encode()
+
+	return reqURL.String(), nil
+}
+
+func parseResolutionResult(data []byte) (*vdriapi.DocResolution, error) {
+	result := &resolutionResult{}
+
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, fmt.Errorf("parse resolution result: %w", err)
+	}
+
+	if len(result.DIDDocument) == 0 || string(result.DIDDocument) == "null" {
+		return nil, vdriapi.ErrNotFound
+	}
+
+	doc, err := did.ParseDocument(result.DIDDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	resolution := &vdriapi.DocResolution{
+		DIDDocument:      doc,
+		DocumentMetadata: &vdriapi.DocumentMetadata{MethodMetadata: result.MethodMetadata},
+		ResolverMetadata: &vdriapi.ResolverMetadata{ContentType: vdriapi.ContentTypeDIDLDJSON},
+	}
+
+	if m := result.DIDDocumentMetadata; m != nil {
+		resolution.DocumentMetadata.Created = m.Created
+		resolution.DocumentMetadata.Updated = m.Updated
+		resolution.DocumentMetadata.Deactivated = m.Deactivated
+		resolution.DocumentMetadata.VersionID = m.VersionID
+	}
+
+	if m := result.ResolverMetadata; m != nil && m.ContentType != "" {
+		resolution.ResolverMetadata.ContentType = m.ContentType
+	}
+
+	return resolution, nil
+}
@@ -226,3 +226,136 @@ func TestDIDResolver_Accept(t *testing.T) {
 	require.NoError(t, err)
 	require.False(t, resolver.accept("example"))
 }
+
+func TestRead_VersionOptions(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "/did:example:334455", req.URL.Path)
+		require.Equal(t, "2", req.URL.Query().Get("versionId"))
+		require.Equal(t, "2020-01-02T15:04:05Z", req.URL.Query().Get("versionTime"))
+		res.Header().Add("Content-type", "application/did+ld+json")
+		res.WriteHeader(http.StatusOK)
+		_, err := res.Write([]byte(doc))
+		require.NoError(t, err)
+	}))
+
+	defer func() { testServer.Close() }()
+
+	resolver, err := New(testServer.URL)
+	require.NoError(t, err)
+	gotDocument, err := resolver.Read("did:example:334455", vdriapi.WithVersionID(2),
+		vdriapi.WithVersionTime(time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)))
+	require.NoError(t, err)
+	require.Equal(t, "did:peer:21tDAKCERh95uGgKbJNHYp", gotDocument.ID)
+}
+
+func TestReadResolution(t *testing.T) {
+	resolution := `{
+  "didDocument": ` + doc + `,
+  "didDocumentMetadata": {
+    "created": "2020-01-02T15:04:05Z",
+    "updated": "2020-02-02T15:04:05Z",
+    "deactivated": true,
+    "versionId": "2"
+  },
+  "methodMetadata": {"published": true},
+  "resolverMetadata": {"contentType": "application/did+json"}
+}`
+
+	t.Run("test resolution result", func(t *testing.T) {
+		var accept string
+
+		testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			accept = req.Header.Get("Accept")
+			res.Header().Add("Content-type", contentTypeResolutionResult)
+			res.WriteHeader(http.StatusOK)
+			_, err := res.Write([]byte(resolution))
+			require.NoError(t, err)
+		}))
+
+		defer func() { testServer.Close() }()
+
+		resolver, err := New(testServer.URL)
+		require.NoError(t, err)
+		result, err := resolver.ReadResolution("did:example:334455")
+		require.NoError(t, err)
+		require.Equal(t, contentTypeResolutionResult, accept)
+		require.Equal(t, "did:peer:21tDAKCERh95uGgKbJNHYp", result.DIDDocument.ID)
+		require.Equal(t, time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC), *result.DocumentMetadata.Created)
+		require.Equal(t, time.Date(2020, 2, 2, 15, 4, 5, 0, time.UTC), *result.DocumentMetadata.Updated)
+		require.True(t, result.DocumentMetadata.Deactivated)
+		require.Equal(t, "2", result.DocumentMetadata.VersionID)
+		require.Equal(t, map[string]interface{}{"published": true}, result.DocumentMetadata.MethodMetadata)
+		require.Equal(t, "application/did+json", result.ResolverMetadata.ContentType)
+
+		// the document is taken from the resolution result
+		gotDocument, err := resolver.Read("did:example:334455")
+		require.NoError(t, err)
+		require.Equal(t, "did:peer:21tDAKCERh95uGgKbJNHYp", gotDocument.ID)
+	})
+
+	t.Run("test DID document", func(t *testing.T) {
+		testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			res.Header().Add("Content-type", "application/did+ld+json")
+			res.WriteHeader(http.StatusOK)
+			_, err := res.Write([]byte(doc))
+			require.NoError(t, err)
+		}))
+
+		defer func() { testServer.Close() }()
+
+		resolver, err := New(testServer.URL)
+		require.NoError(t, err)
+		result, err := resolver.ReadResolution("did:example:334455")
+		require.NoError(t, err)
+		require.Equal(t, "did:peer:21tDAKCERh95uGgKbJNHYp", result.DIDDocument.ID)
+		require.False(t, result.DocumentMetadata.Deactivated)
+		require.Equal(t, vdriapi.ContentTypeDIDLDJSON, result.ResolverMetadata.ContentType)
+	})
+
+	t.Run("test not found", func(t *testing.T) {
+		testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			res.Header().Add("Content-type", contentTypeResolutionResult)
+			res.WriteHeader(http.StatusOK)
+			_, err := res.Write([]byte(`{"didDocument": null}`))
+			require.NoError(t, err)
+		}))
+
+		defer func() { testServer.Close() }()
+
+		resolver, err := New(testServer.URL)
+		require.NoError(t, err)
+		_, err = resolver.ReadResolution("did:example:334455")
+		require.True(t, errors.Is(err, vdriapi.ErrNotFound))
+	})
+
+	t.Run("test invalid resolution result", func(t *testing.T) {
+		testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			res.Header().Add("Content-type", contentTypeResolutionResult)
+			res.WriteHeader(http.StatusOK)
+			_, err := res.Write([]byte(`{`))
+			require.NoError(t, err)
+		}))
+
+		defer func() { testServer.Close() }()
+
+		resolver, err := New(testServer.URL)
+		require.NoError(t, err)
+		_, err = resolver.ReadResolution("did:example:334455")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse resolution result")
+	})
+
+	t.Run("test resolution error", func(t *testing.T) {
+		testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			res.WriteHeader(http.StatusNotFound)
+		}))
+
+		defer func() { testServer.Close() }()
+
+		resolver, err := New(testServer.URL)
+		require.NoError(t, err)
+		_, err = resolver.ReadResolution("did:example:334455")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "DID does not exist")
+	})
+}
@@ -0,0 +1,42 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package key
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+)
+
+// Build builds new did:key document from the public key.
+// The document options (e.g. the service) are not supported by did:key, hence ignored.
+func (v *VDRI) Build(pubKey *vdriapi.PubKey, _ ...vdriapi.DocOpts) (*did.Doc, error) {
+	codec, size, err := keyCodec(pubKey.Type)
+	if err != nil {
+		return nil, fmt.Errorf("create did:key : %w", err)
+	}
+
+	keyValue := base58.Decode(pubKey.Value)
+	if len(keyValue) != size {
+		return nil, fmt.Errorf("create did:key : invalid key size %d, expected %d", len(keyValue), size)
+	}
+
+	fp, err := fingerprint(codec, keyValue)
+	if err != nil {
+		return nil, fmt.Errorf("create did:key : %w", err)
+	}
+
+	didDoc, err := createDoc(pubKey.Type, keyValue, fp)
+	if err != nil {
+		return nil, fmt.Errorf("create did:key : %w", err)
+	}
+
+	return didDoc, nil
+}
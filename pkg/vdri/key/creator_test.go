@@ -0,0 +1,84 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package key
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/require"
+
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+)
+
+const (
+	// test vector of https://w3c-ccg.github.io/did-method-key/
+	ed25519Key    = "B12NYF8RrR3h41TDCTJojY59usg3mbtbjnFs7Eud1Y6u"
+	ed25519DIDKey = "did:key:z6MkpTHR8VNsBxYAAWHut2Geadd9jSwuBV8xRoAnwWsdvktH"
+	x25519Key     = "JhNWeSVLMYccCk7iopQW4guaSJTojqpMEELgSLhKwRr"
+)
+
+func TestBuild(t *testing.T) {
+	v := New()
+
+	t.Run("test build - Ed25519", func(t *testing.T) {
+		didDoc, err := v.Build(&vdriapi.PubKey{Value: ed25519Key, Type: ed25519PubKeyType})
+		require.NoError(t, err)
+		require.Equal(t, ed25519DIDKey, didDoc.ID)
+
+		require.Len(t, didDoc.PublicKey, 1)
+		require.Equal(t, ed25519DIDKey+"#z6MkpTHR8VNsBxYAAWHut2Geadd9jSwuBV8xRoAnwWsdvktH", didDoc.PublicKey[0].ID)
+		require.Equal(t, ed25519DIDKey, didDoc.PublicKey[0].Controller)
+		require.Equal(t, base58.Decode(ed25519Key), didDoc.PublicKey[0].Value)
+
+		require.Len(t, didDoc.Authentication, 1)
+		require.Equal(t, didDoc.PublicKey[0], didDoc.Authentication[0].PublicKey)
+
+		require.Len(t, didDoc.KeyAgreement, 1)
+		require.Equal(t, x25519PubKeyType, didDoc.KeyAgreement[0].PublicKey.Type)
+		require.Equal(t, base58.Decode(x25519Key), didDoc.KeyAgreement[0].PublicKey.Value)
+		require.True(t, strings.HasPrefix(didDoc.KeyAgreement[0].PublicKey.ID, ed25519DIDKey+"#z6LS"))
+	})
+
+	t.Run("test build - X25519", func(t *testing.T) {
+		didDoc, err := v.Build(&vdriapi.PubKey{Value: x25519Key, Type: x25519PubKeyType})
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(didDoc.ID, "did:key:z6LS"))
+		require.Empty(t, didDoc.Authentication)
+		require.Len(t, didDoc.KeyAgreement, 1)
+		require.Equal(t, didDoc.PublicKey[0], didDoc.KeyAgreement[0].PublicKey)
+	})
+
+	t.Run("test build - EC keys", func(t *testing.T) {
+		ecKey := base58.Encode(append([]byte{0x02}, make([]byte, 32)...))
+
+		for _, keyType := range []string{p256PubKeyType, secp256k1PubKeyType} {
+			didDoc, err := v.Build(&vdriapi.PubKey{Value: ecKey, Type: keyType})
+			require.NoError(t, err)
+			require.Equal(t, keyType, didDoc.PublicKey[0].Type)
+			require.Equal(t, didDoc.PublicKey[0], didDoc.Authentication[0].PublicKey)
+			require.Equal(t, didDoc.PublicKey[0], didDoc.KeyAgreement[0].PublicKey)
+
+			resolved, err := v.Read(didDoc.ID)
+			require.NoError(t, err)
+			require.Equal(t, didDoc, resolved)
+		}
+	})
+
+	t.Run("test build - unsupported key type", func(t *testing.T) {
+		_, err := v.Build(&vdriapi.PubKey{Value: ed25519Key, Type: "RsaVerificationKey2018"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key type not supported")
+	})
+
+	t.Run("test build - invalid key size", func(t *testing.T) {
+		_, err := v.Build(&vdriapi.PubKey{Value: base58.Encode([]byte("key")), Type: ed25519PubKeyType})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid key size 3, expected 32")
+	})
+}
@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package key
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/multiformats/go-multibase"
+)
+
+const (
+	ed25519PubKeyType   = "Ed25519VerificationKey2018"
+	x25519PubKeyType    = "X25519KeyAgreementKey2019"
+	p256PubKeyType      = "EcdsaSecp256r1VerificationKey2019"
+	secp256k1PubKeyType = "EcdsaSecp256k1VerificationKey2019"
+
+	// multicodec codes of the public keys, refer https://github.com/multiformats/multicodec/blob/master/table.csv
+	ed25519Codec   = 0xed
+	x25519Codec    = 0xec
+	p256Codec      = 0x1200
+	secp256k1Codec = 0xe7
+
+	curve25519KeySize = 32
+	// EC keys are encoded in the compressed form
+	compressedECKeySize = 33
+)
+
+// keyCodec returns the multicodec code and the expected size of the key of the given type.
+func keyCodec(keyType string) (uint64, int, error) {
+	switch keyType {
+	case ed25519PubKeyType:
+		return ed25519Codec, curve25519KeySize, nil
+	case x25519PubKeyType:
+		return x25519Codec, curve25519KeySize, nil
+	case p256PubKeyType:
+		return p256Codec, compressedECKeySize, nil
+	case secp256k1PubKeyType:
+		return secp256k1Codec, compressedECKeySize, nil
+	default:
+		return 0, 0, fmt.Errorf("key type not supported: %s", keyType)
+	}
+}
+
+// keyType returns the type and the expected size of the key with the given multicodec code.
+func keyType(codec uint64) (string, int, error) {
+	switch codec {
+	case ed25519Codec:
+		return ed25519PubKeyType, curve25519KeySize, nil
+	case x25519Codec:
+		return x25519PubKeyType, curve25519KeySize, nil
+	case p256Codec:
+		return p256PubKeyType, compressedECKeySize, nil
+	case secp256k1Codec:
+		return secp256k1PubKeyType, compressedECKeySize, nil
+	default:
+		return "", 0, fmt.Errorf("multicodec not supported: %#x", codec)
+	}
+}
+
+// fingerprint returns the multibase (base58-btc) encoded key prefixed with the multicodec code of the key type.
+func fingerprint(codec uint64, pubKey []byte) (string, error) {
+	prefix := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(prefix, codec)
+
+	return multibase.Encode(multibase.Base58BTC, append(prefix[:n], pubKey...))
+}
+
+// parseFingerprint returns the multicodec code of the key type and the key of the fingerprint.
+func parseFingerprint(fp string) (uint64, []byte, error) {
+	encoding, data, err := multibase.Decode(fp)
+	if err != nil {
+		return 0, nil, fmt.Errorf("decode fingerprint: %w", err)
+	}
+
+	if encoding != multibase.Base58BTC {
+		return 0, nil, errors.New("fingerprint is not base58-btc encoded")
+	}
+
+	codec, n := binary.Uvarint(data)
+	if n <= 0 {
+		return 0, nil, errors.New("invalid multicodec prefix")
+	}
+
+	return codec, data[n:], nil
+}
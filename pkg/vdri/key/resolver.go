@@ -0,0 +1,103 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package key
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
+)
+
+// Read expands did:key into the DID document (https://w3c-ccg.github.io/did-method-key/#read).
+func (v *VDRI) Read(didKey string, _ ...vdriapi.ResolveOpts) (*did.Doc, error) {
+	if !strings.HasPrefix(didKey, didKeyPrefix) {
+		return nil, fmt.Errorf("invalid did:key : %s", didKey)
+	}
+
+	fp := strings.TrimPrefix(didKey, didKeyPrefix)
+
+	codec, keyValue, err := parseFingerprint(fp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid did:key %s : %w", didKey, err)
+	}
+
+	pubKeyType, size, err := keyType(codec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid did:key %s : %w", didKey, err)
+	}
+
+	if len(keyValue) != size {
+		return nil, fmt.Errorf("invalid did:key %s : invalid key size %d, expected %d", didKey, len(keyValue), size)
+	}
+
+	return createDoc(pubKeyType, keyValue, fp)
+}
+
+// createDoc creates the DID document of the key with the given fingerprint:
+//  - Ed25519 key is used for the authentication, the X25519 key derived from it for the key agreement
+//  - X25519 key is used for the key agreement only
+//  - EC keys are used for both authentication and key agreement
+func createDoc(pubKeyType string, keyValue []byte, fp string) (*did.Doc, error) {
+	didKey := didKeyPrefix + fp
+
+	publicKey := did.PublicKey{
+		ID:         didKey + "#" + fp,
+		Type:       pubKeyType,
+		Controller: didKey,
+		Value:      keyValue,
+	}
+
+	var authentication, keyAgreement []did.VerificationMethod
+
+	switch pubKeyType {
+	case ed25519PubKeyType:
+		keyAgreementKey, err := deriveKeyAgreementKey(didKey, keyValue)
+		if err != nil {
+			return nil, err
+		}
+
+		authentication = []did.VerificationMethod{{PublicKey: publicKey}}
+		keyAgreement = []did.VerificationMethod{{PublicKey: *keyAgreementKey}}
+	case x25519PubKeyType:
+		keyAgreement = []did.VerificationMethod{{PublicKey: publicKey}}
+	default:
+		authentication = []did.VerificationMethod{{PublicKey: publicKey}}
+		keyAgreement = []did.VerificationMethod{{PublicKey: publicKey}}
+	}
+
+	didDoc := did.BuildDoc(
+		did.WithPublicKey([]did.PublicKey{publicKey}),
+		did.WithAuthentication(authentication),
+		did.WithKeyAgreement(keyAgreement),
+	)
+	didDoc.ID = didKey
+
+	return didDoc, nil
+}
+
+// deriveKeyAgreementKey derives X25519 key agreement key from Ed25519 key.
+func deriveKeyAgreementKey(didKey string, ed25519Key []byte) (*did.PublicKey, error) {
+	x25519Key, err := cryptoutil.PublicEd25519toCurve25519(ed25519Key)
+	if err != nil {
+		return nil, fmt.Errorf("derive key agreement key: %w", err)
+	}
+
+	fp, err := fingerprint(x25519Codec, x25519Key)
+	if err != nil {
+		return nil, fmt.Errorf("derive key agreement key: %w", err)
+	}
+
+	return &did.PublicKey{
+		ID:         didKey + "#" + fp,
+		Type:       x25519PubKeyType,
+		Controller: didKey,
+		Value:      x25519Key,
+	}, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package key
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/multiformats/go-multibase"
+	"github.com/stretchr/testify/require"
+
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+)
+
+func TestRead(t *testing.T) {
+	v := New()
+
+	t.Run("test read - deterministic expansion", func(t *testing.T) {
+		didDoc, err := v.Read(ed25519DIDKey)
+		require.NoError(t, err)
+		require.Equal(t, ed25519DIDKey, didDoc.ID)
+		require.Equal(t, base58.Decode(ed25519Key), didDoc.PublicKey[0].Value)
+
+		built, err := v.Build(&vdriapi.PubKey{Value: ed25519Key, Type: ed25519PubKeyType})
+		require.NoError(t, err)
+		require.Equal(t, built, didDoc)
+
+		again, err := v.Read(ed25519DIDKey)
+		require.NoError(t, err)
+		require.Equal(t, didDoc, again)
+
+		bytes, err := didDoc.JSONBytes()
+		require.NoError(t, err)
+		require.Contains(t, string(bytes), `"keyAgreement"`)
+	})
+
+	t.Run("test read - invalid DIDs", func(t *testing.T) {
+		_, err := v.Read("did:peer:z6MkpTHR8VNsBxYAAWHut2Geadd9jSwuBV8xRoAnwWsdvktH")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid did:key")
+
+		_, err = v.Read("did:key:invalid")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode fingerprint")
+
+		fp, err := multibase.Encode(multibase.Base64, []byte{0xed, 0x01})
+		require.NoError(t, err)
+		_, err = v.Read("did:key:" + fp)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "fingerprint is not base58-btc encoded")
+
+		fp, err = multibase.Encode(multibase.Base58BTC, []byte{0x80})
+		require.NoError(t, err)
+		_, err = v.Read("did:key:" + fp)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid multicodec prefix")
+
+		fp, err = multibase.Encode(multibase.Base58BTC, []byte{0x01, 0x02})
+		require.NoError(t, err)
+		_, err = v.Read("did:key:" + fp)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "multicodec not supported")
+
+		fp, err = multibase.Encode(multibase.Base58BTC, []byte{0xed, 0x01, 0x02})
+		require.NoError(t, err)
+		_, err = v.Read("did:key:" + fp)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid key size 1, expected 32")
+	})
+}
+
+func TestVDRI(t *testing.T) {
+	v := New()
+	require.True(t, v.Accept(DIDMethod))
+	require.False(t, v.Accept("peer"))
+	require.NoError(t, v.Store(nil, nil))
+	require.NoError(t, v.Close())
+}
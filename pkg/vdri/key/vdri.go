@@ -0,0 +1,44 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package key
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+)
+
+const (
+	// DIDMethod did method
+	DIDMethod = "key"
+
+	didKeyPrefix = "did:" + DIDMethod + ":"
+)
+
+// VDRI implements did:key method support (https://w3c-ccg.github.io/did-method-key/).
+// The DID document is derived from the key the DID is built from, hence nothing is stored.
+type VDRI struct {
+}
+
+// New returns new instance of key vdri
+func New() *VDRI {
+	return &VDRI{}
+}
+
+// Accept did method
+func (v *VDRI) Accept(method string) bool {
+	return method == DIDMethod
+}
+
+// Store did doc - the did:key document is derived from the DID, there is nothing to store
+func (v *VDRI) Store(_ *did.Doc, _ *[]vdriapi.ModifiedBy) error {
+	return nil
+}
+
+// Close frees resources being maintained by vdri
+func (v *VDRI) Close() error {
+	return nil
+}
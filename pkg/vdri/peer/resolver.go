@@ -7,23 +7,98 @@ SPDX-License-Identifier: Apache-2.0
 package peer
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
 
+// versionsMetadata is the method metadata holding the number of the stored document versions
+const versionsMetadata = "versions"
+
 // Read implements didresolver.DidMethod.Read interface (https://w3c-ccg.github.io/did-resolution/#resolving-input)
-func (v *VDRI) Read(didID string, _ ...vdriapi.ResolveOpts) (*did.Doc, error) {
-	// get the document from the store
-	doc, err := v.Get(didID)
+func (v *VDRI) Read(didID string, opts ...vdriapi.ResolveOpts) (*did.Doc, error) {
+	result, err := v.ReadResolution(didID, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("fetching data from store failed: %w", err)
+		return nil, err
+	}
+
+	return result.DIDDocument, nil
+}
+
+// ReadResolution reads the DID document along with its metadata. The latest version of the document is read
+// unless the specific version is requested with vdriapi.WithVersionID (the version number starting from 1)
+// or vdriapi.WithVersionTime.
+func (v *VDRI) ReadResolution(didID string, opts ...vdriapi.ResolveOpts) (*vdriapi.DocResolution, error) {
+	resolveOpts := &vdriapi.ResolveDIDOpts{}
+	// Apply options
+	for _, opt := range opts {
+		opt(resolveOpts)
 	}
 
-	if doc == nil {
+	if didID == "" {
+		return nil, errors.New("fetching data from store failed: ID is mandatory")
+	}
+
+	deltas, err := v.getDeltas(didID)
+	if errors.Is(err, storage.ErrDataNotFound) {
 		return nil, vdriapi.ErrNotFound
 	}
 
-	return doc, nil
+	if err != nil {
+		return nil, fmt.Errorf("fetching data from store failed: %w", err)
+	}
+
+	index, err := versionIndex(deltas, resolveOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := replay(didID, deltas, index)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vdriapi.DocResolution{
+		DIDDocument: doc,
+		DocumentMetadata: &vdriapi.DocumentMetadata{
+			Created:        &deltas[0].ModifiedAt,
+			Updated:        &deltas[index].ModifiedAt,
+			VersionID:      strconv.Itoa(index + 1),
+			MethodMetadata: map[string]interface{}{versionsMetadata: len(deltas)},
+		},
+	}, nil
+}
+
+// versionIndex returns the index of the delta of the requested version.
+func versionIndex(deltas []docDelta, opts *vdriapi.ResolveDIDOpts) (int, error) {
+	switch {
+	case opts.VersionID != nil:
+		version, err := strconv.Atoi(fmt.Sprint(opts.VersionID))
+		if err != nil || version < 1 || version > len(deltas) {
+			return 0, fmt.Errorf("version %v: %w", opts.VersionID, vdriapi.ErrNotFound)
+		}
+
+		return version - 1, nil
+	case opts.VersionTime != "":
+		versionTime, err := time.Parse(time.RFC3339, opts.VersionTime)
+		if err != nil {
+			return 0, fmt.Errorf("invalid version time: %w", err)
+		}
+
+		// the latest version stored by the given time
+		for i := len(deltas) - 1; i >= 0; i-- {
+			if !deltas[i].ModifiedAt.After(versionTime) {
+				return i, nil
+			}
+		}
+
+		return 0, fmt.Errorf("version at %s: %w", opts.VersionTime, vdriapi.ErrNotFound)
+	default:
+		return len(deltas) - 1, nil
+	}
 }
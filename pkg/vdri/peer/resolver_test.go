@@ -7,11 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package peer
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/mock/storage"
 )
 
@@ -32,6 +35,12 @@ func TestPeerDIDResolver(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, peerDID, doc.ID)
 	})
+	t.Run("test not found", func(t *testing.T) {
+		vdri, err := New(storage.NewMockStoreProvider())
+		require.NoError(t, err)
+		_, err = vdri.Read(peerDID)
+		require.True(t, errors.Is(err, vdriapi.ErrNotFound))
+	})
 	t.Run("test empty doc id", func(t *testing.T) {
 		vdri, err := New(storage.NewMockStoreProvider())
 		require.NoError(t, err)
@@ -40,3 +49,72 @@ func TestPeerDIDResolver(t *testing.T) {
 		require.Contains(t, err.Error(), "ID is mandatory")
 	})
 }
+
+func TestPeerDIDResolver_Versions(t *testing.T) {
+	svc := []did.Service{{ID: "did:example:123456789abcdefghi#did-communication", Type: "did-communication",
+		ServiceEndpoint: "https://agent.example.com/"}}
+
+	store := storage.NewMockStoreProvider()
+	vdri, err := New(store)
+	require.NoError(t, err)
+
+	pk, signer := newTestKey(t, peerDID, "key-1")
+	require.NoError(t, vdri.Store(newTestDoc(peerDID, pk), nil))
+	require.NoError(t, vdri.Update(newTestDoc(peerDID, pk, svc...), pk.ID, signer))
+
+	deltas, err := vdri.getDeltas(peerDID)
+	require.NoError(t, err)
+	require.Len(t, deltas, 2)
+
+	t.Run("test latest version", func(t *testing.T) {
+		result, err := vdri.ReadResolution(peerDID)
+		require.NoError(t, err)
+		require.Len(t, result.DIDDocument.Service, 1)
+		require.Equal(t, "2", result.DocumentMetadata.VersionID)
+		require.Equal(t, deltas[0].ModifiedAt, *result.DocumentMetadata.Created)
+		require.Equal(t, deltas[1].ModifiedAt, *result.DocumentMetadata.Updated)
+		require.Equal(t, 2, result.DocumentMetadata.MethodMetadata[versionsMetadata])
+	})
+
+	t.Run("test version ID", func(t *testing.T) {
+		doc, err := vdri.Read(peerDID, vdriapi.WithVersionID("1"))
+		require.NoError(t, err)
+		require.Empty(t, doc.Service)
+
+		doc, err = vdri.Read(peerDID, vdriapi.WithVersionID(2))
+		require.NoError(t, err)
+		require.Len(t, doc.Service, 1)
+
+		for _, versionID := range []interface{}{"0", "3", "latest"} {
+			_, err = vdri.Read(peerDID, vdriapi.WithVersionID(versionID))
+			require.True(t, errors.Is(err, vdriapi.ErrNotFound))
+		}
+	})
+
+	t.Run("test version time", func(t *testing.T) {
+		result, err := vdri.ReadResolution(peerDID, vdriapi.WithVersionTime(deltas[1].ModifiedAt.Add(time.Second)))
+		require.NoError(t, err)
+		require.Equal(t, "2", result.DocumentMetadata.VersionID)
+
+		_, err = vdri.Read(peerDID, vdriapi.WithVersionTime(deltas[0].ModifiedAt.Add(-time.Second)))
+		require.True(t, errors.Is(err, vdriapi.ErrNotFound))
+
+		_, err = vdri.Read(peerDID, func(opts *vdriapi.ResolveDIDOpts) {
+			opts.VersionTime = "yesterday"
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid version time")
+	})
+
+	t.Run("test invalid delta", func(t *testing.T) {
+		require.NoError(t, vdri.store.Put("did:peer:invalid", []byte(`[{"change":"@@@"}]`)))
+		_, err := vdri.Read("did:peer:invalid")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decoding of document delta failed")
+
+		require.NoError(t, vdri.store.Put("did:peer:empty", []byte(`[]`)))
+		_, err = vdri.Read("did:peer:empty")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "no document deltas found")
+	})
+}
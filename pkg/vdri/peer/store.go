@@ -15,23 +15,33 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
 
 type docDelta struct {
-	Change     string                `json:"change,omitempty"`
-	ModifiedBy *[]vdriapi.ModifiedBy `json:"by,omitempty"`
-	ModifiedAt time.Time             `json:"when,omitempty"`
+	Change      string                `json:"change,omitempty"`
+	ModifiedBy  *[]vdriapi.ModifiedBy `json:"by,omitempty"`
+	ModifiedAt  time.Time             `json:"when,omitempty"`
+	Deactivated bool                  `json:"deactivated,omitempty"`
 }
 
-// Store saves Peer DID Document along with user key/signature.
+// Store saves Peer DID Document along with user key/signature. The document is stored as the new version of the DID,
+// storing the document equal to the latest version is no-op. The new version has to be signed with the authentication
+// key of the latest version (refer Update).
 func (v *VDRI) Store(doc *did.Doc, by *[]vdriapi.ModifiedBy) error {
 	if doc == nil || doc.ID == "" {
 		return errors.New("DID and document are mandatory")
 	}
 
-	var deltas []docDelta
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	deltas, err := v.getDeltas(doc.ID)
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return fmt.Errorf("delta data fetch from store failed: %w", err)
+	}
 
-	// For now, assume the doc is a genesis document
+	// For now, every delta is the full document
 	jsonDoc, err := doc.JSONBytes()
 	if err != nil {
 		return fmt.Errorf("JSON marshalling of document failed: %w", err)
@@ -43,17 +53,36 @@ func (v *VDRI) Store(doc *did.Doc, by *[]vdriapi.ModifiedBy) error {
 		ModifiedAt: time.Now(),
 	}
 
-	deltas = append(deltas, *docDelta)
+	if len(deltas) > 0 && deltas[len(deltas)-1].Change == docDelta.Change {
+		return nil
+	}
 
-	val, err := json.Marshal(deltas)
+	return v.appendDelta(doc.ID, deltas, docDelta)
+}
+
+// appendDelta appends the delta to the log of the DID, it has to be signed with the authentication key
+// of the latest version unless it is the genesis one. The caller holds the lock of the delta logs.
+func (v *VDRI) appendDelta(id string, deltas []docDelta, delta *docDelta) error {
+	if len(deltas) > 0 {
+		prev, err := replay(id, deltas, len(deltas)-1)
+		if err != nil {
+			return err
+		}
+
+		if err = verify(id, prev, delta); err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+	}
+
+	val, err := json.Marshal(append(deltas, *delta))
 	if err != nil {
 		return fmt.Errorf("JSON marshalling of document deltas failed: %w", err)
 	}
 
-	return v.store.Put(doc.ID, val)
+	return v.store.Put(id, val)
 }
 
-// Get returns Peer DID Document
+// Get returns the latest version of Peer DID Document
 func (v *VDRI) Get(id string) (*did.Doc, error) {
 	if id == "" {
 		return nil, errors.New("ID is mandatory")
@@ -64,20 +93,7 @@ func (v *VDRI) Get(id string) (*did.Doc, error) {
 		return nil, fmt.Errorf("delta data fetch from store failed: %w", err)
 	}
 
-	// For now, assume storage contains only one delta(genesis document)
-	delta := deltas[0]
-
-	doc, err := base64.URLEncoding.DecodeString(delta.Change)
-	if err != nil {
-		return nil, fmt.Errorf("decoding of document delta failed: %w", err)
-	}
-
-	document, err := did.ParseDocument(doc)
-	if err != nil {
-		return nil, fmt.Errorf("document ParseDocument() failed: %w", err)
-	}
-
-	return document, nil
+	return replay(id, deltas, len(deltas)-1)
 }
 
 // Close frees resources being maintained by vdri.
@@ -98,5 +114,23 @@ func (v *VDRI) getDeltas(id string) ([]docDelta, error) {
 		return nil, fmt.Errorf("JSON unmarshalling of document deltas failed: %w", err)
 	}
 
+	if len(deltas) == 0 {
+		return nil, errors.New("no document deltas found")
+	}
+
 	return deltas, nil
 }
+
+func parseDelta(delta docDelta) (*did.Doc, error) {
+	doc, err := base64.URLEncoding.DecodeString(delta.Change)
+	if err != nil {
+		return nil, fmt.Errorf("decoding of document delta failed: %w", err)
+	}
+
+	document, err := did.ParseDocument(doc)
+	if err != nil {
+		return nil, fmt.Errorf("document ParseDocument() failed: %w", err)
+	}
+
+	return document, nil
+}
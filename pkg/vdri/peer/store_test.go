@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package peer
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -45,6 +46,33 @@ func TestPeerDIDStore(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, did1, doc.ID)
 
+	// put - the same document is not stored as the new version
+	err = store.Store(&did.Doc{Context: context, ID: did1}, nil)
+	require.NoError(t, err)
+	deltas, err := store.getDeltas(did1)
+	require.NoError(t, err)
+	require.Len(t, deltas, 1)
+
+	// put - the new version is not signed
+	err = store.Store(&did.Doc{Context: context, ID: did1, PublicKey: []did.PublicKey{{ID: "key-1", Type: "key-type",
+		Controller: did1, Value: []byte("key")}}}, nil)
+	require.True(t, errors.Is(err, ErrUnauthorizedUpdate))
+
+	// put - the new version signed with the authentication key
+	did3 := "did:peer:7890"
+	pk, signer := newTestKey(t, did3, "key-1")
+	require.NoError(t, store.Store(newTestDoc(did3, pk), nil))
+	svc := did.Service{ID: "#agent", Type: "did-communication", ServiceEndpoint: "https://agent.example.com/"}
+	require.NoError(t, store.Update(newTestDoc(did3, pk, svc), pk.ID, signer))
+	deltas, err = store.getDeltas(did3)
+	require.NoError(t, err)
+	require.Len(t, deltas, 2)
+
+	// get - the latest version
+	doc, err = store.Get(did3)
+	require.NoError(t, err)
+	require.Len(t, doc.Service, 1)
+
 	// get - empty id
 	_, err = store.Get("")
 	require.Error(t, err)
@@ -68,6 +96,11 @@ func TestPeerDIDStore(t *testing.T) {
 	require.NotNil(t, err)
 	require.Nil(t, v)
 	require.Contains(t, err.Error(), "delta data fetch from store failed")
+
+	// put - not json document
+	err = store.Store(&did.Doc{Context: context, ID: "not-json"}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "delta data fetch from store failed")
 }
 
 func TestVDRI_Close(t *testing.T) {
@@ -0,0 +1,200 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/ed25519signature2018"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+)
+
+// ErrUnauthorizedUpdate is returned when the update of the peer DID is not signed with the authentication key
+// of the previous version of the DID document.
+var ErrUnauthorizedUpdate = errors.New("unauthorized peer DID update")
+
+// Update stores the new version of the peer DID document signed with the authentication key (keyID)
+// of the current version.
+func (v *VDRI) Update(doc *did.Doc, keyID string, signer legacykms.Signer) error {
+	if doc == nil || doc.ID == "" {
+		return errors.New("DID and document are mandatory")
+	}
+
+	jsonDoc, err := doc.JSONBytes()
+	if err != nil {
+		return fmt.Errorf("JSON marshalling of document failed: %w", err)
+	}
+
+	by, err := v.sign(doc.ID, jsonDoc, keyID, signer)
+	if err != nil {
+		return err
+	}
+
+	return v.Store(doc, by)
+}
+
+// RotateKey replaces the public key (oldKeyID) of the peer DID document along with its authentication
+// and key agreement references with the new key. The update is signed with the authentication key (keyID)
+// of the current version, i.e. the old key itself can authorize its rotation.
+func (v *VDRI) RotateKey(didID, oldKeyID string, newKey *did.PublicKey, keyID string, signer legacykms.Signer) error {
+	if newKey == nil {
+		return errors.New("new key is mandatory")
+	}
+
+	doc, err := v.Get(didID)
+	if err != nil {
+		return err
+	}
+
+	rotated := false
+
+	for i := range doc.PublicKey {
+		if doc.PublicKey[i].ID == oldKeyID {
+			doc.PublicKey[i] = *newKey
+			rotated = true
+		}
+	}
+
+	for _, vms := range [][]did.VerificationMethod{doc.Authentication, doc.KeyAgreement} {
+		for i := range vms {
+			if vms[i].PublicKey.ID == oldKeyID {
+				vms[i].PublicKey = *newKey
+				rotated = true
+			}
+		}
+	}
+
+	if !rotated {
+		return fmt.Errorf("key %s not found in peer DID %s", oldKeyID, didID)
+	}
+
+	return v.Update(doc, keyID, signer)
+}
+
+// Deactivate deactivates the peer DID, the deactivation is signed with the authentication key (keyID)
+// of the current version. The deactivated DID can be neither resolved nor updated.
+func (v *VDRI) Deactivate(didID, keyID string, signer legacykms.Signer) error {
+	by, err := v.sign(didID, deactivationPayload(didID), keyID, signer)
+	if err != nil {
+		return err
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	deltas, err := v.getDeltas(didID)
+	if err != nil {
+		return fmt.Errorf("delta data fetch from store failed: %w", err)
+	}
+
+	return v.appendDelta(didID, deltas, &docDelta{Deactivated: true, ModifiedBy: by, ModifiedAt: time.Now()})
+}
+
+// sign signs the payload with the authentication key (keyID) of the latest version of the peer DID document.
+func (v *VDRI) sign(didID string, payload []byte, keyID string, signer legacykms.Signer) (*[]vdriapi.ModifiedBy, error) {
+	doc, err := v.Get(didID)
+	if err != nil {
+		return nil, err
+	}
+
+	pk, err := authenticationKey(doc, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the public key value of peer DID document is the base58 encoded verification key
+	sig, err := signer.SignMessage(payload, string(pk.Value))
+	if err != nil {
+		return nil, fmt.Errorf("sign peer DID update: %w", err)
+	}
+
+	return &[]vdriapi.ModifiedBy{{Key: keyID, Sig: base64.URLEncoding.EncodeToString(sig)}}, nil
+}
+
+// replay applies the deltas up to the given index, every delta but the genesis one has to be signed
+// with the authentication key of the previous version.
+func replay(didID string, deltas []docDelta, index int) (*did.Doc, error) {
+	doc, err := parseDelta(deltas[0])
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 1; i <= index; i++ {
+		if err = verify(didID, doc, &deltas[i]); err != nil {
+			return nil, fmt.Errorf("version %d: %w", i+1, err)
+		}
+
+		if deltas[i].Deactivated {
+			return nil, fmt.Errorf("%s: %w", didID, vdriapi.ErrDeactivated)
+		}
+
+		doc, err = parseDelta(deltas[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return doc, nil
+}
+
+// verify checks the delta is signed with one of the authentication keys of the previous version.
+func verify(didID string, prev *did.Doc, delta *docDelta) error {
+	if delta.ModifiedBy == nil {
+		return ErrUnauthorizedUpdate
+	}
+
+	// the document is signed, the deactivation has no document
+	payload := deactivationPayload(didID)
+
+	if !delta.Deactivated {
+		var err error
+
+		payload, err = base64.URLEncoding.DecodeString(delta.Change)
+		if err != nil {
+			return fmt.Errorf("decoding of document delta failed: %w", err)
+		}
+	}
+
+	for _, m := range *delta.ModifiedBy {
+		pk, err := authenticationKey(prev, m.Key)
+		if err != nil {
+			continue
+		}
+
+		sig, err := base64.URLEncoding.DecodeString(m.Sig)
+		if err != nil {
+			continue
+		}
+
+		if ed25519signature2018.New().Verify(base58.Decode(string(pk.Value)), payload, sig) == nil {
+			return nil
+		}
+	}
+
+	return ErrUnauthorizedUpdate
+}
+
+func authenticationKey(doc *did.Doc, keyID string) (*did.PublicKey, error) {
+	for i := range doc.Authentication {
+		if doc.Authentication[i].PublicKey.ID == keyID {
+			return &doc.Authentication[i].PublicKey, nil
+		}
+	}
+
+	return nil, fmt.Errorf("authentication key %s not found in peer DID %s", keyID, doc.ID)
+}
+
+func deactivationPayload(didID string) []byte {
+	return []byte("deactivate:" + didID)
+}
@@ -0,0 +1,276 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/mock/storage"
+)
+
+func TestVDRI_Update(t *testing.T) {
+	pk, signer := newTestKey(t, peerDID, "key-1")
+	svc := did.Service{ID: "#agent", Type: "did-communication", ServiceEndpoint: "https://agent.example.com/"}
+
+	t.Run("test update - success", func(t *testing.T) {
+		vdri := newTestVDRI(t, newTestDoc(peerDID, pk))
+
+		require.NoError(t, vdri.Update(newTestDoc(peerDID, pk, svc), pk.ID, signer))
+
+		doc, err := vdri.Read(peerDID)
+		require.NoError(t, err)
+		require.Len(t, doc.Service, 1)
+
+		deltas, err := vdri.getDeltas(peerDID)
+		require.NoError(t, err)
+		require.Len(t, deltas, 2)
+		require.Equal(t, pk.ID, (*deltas[1].ModifiedBy)[0].Key)
+	})
+
+	t.Run("test update - concurrent updates", func(t *testing.T) {
+		vdri := newTestVDRI(t, newTestDoc(peerDID, pk))
+
+		const updates = 50
+
+		var wg sync.WaitGroup
+
+		errs := make(chan error, updates)
+
+		for i := 0; i < updates; i++ {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				svc := did.Service{ID: fmt.Sprintf("#agent-%d", i), Type: "did-communication",
+					ServiceEndpoint: "https://agent.example.com/"}
+				errs <- vdri.Update(newTestDoc(peerDID, pk, svc), pk.ID, signer)
+			}(i)
+		}
+
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		deltas, err := vdri.getDeltas(peerDID)
+		require.NoError(t, err)
+		require.Len(t, deltas, updates+1)
+	})
+
+	t.Run("test update - not an authentication key", func(t *testing.T) {
+		vdri := newTestVDRI(t, newTestDoc(peerDID, pk))
+
+		err := vdri.Update(newTestDoc(peerDID, pk, svc), peerDID+"#key-2", signer)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "authentication key did:peer:1234#key-2 not found")
+	})
+
+	t.Run("test update - signed with other key", func(t *testing.T) {
+		vdri := newTestVDRI(t, newTestDoc(peerDID, pk))
+		_, otherSigner := newTestKey(t, peerDID, "key-2")
+
+		err := vdri.Update(newTestDoc(peerDID, pk, svc), pk.ID, otherSigner)
+		require.True(t, errors.Is(err, ErrUnauthorizedUpdate))
+	})
+
+	t.Run("test update - sign error", func(t *testing.T) {
+		vdri := newTestVDRI(t, newTestDoc(peerDID, pk))
+
+		err := vdri.Update(newTestDoc(peerDID, pk, svc), pk.ID, &testSigner{err: errors.New("sign error")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "sign peer DID update: sign error")
+	})
+
+	t.Run("test update - DID not found", func(t *testing.T) {
+		vdri := newTestVDRI(t)
+
+		err := vdri.Update(newTestDoc(peerDID, pk, svc), pk.ID, signer)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "delta data fetch from store failed")
+	})
+
+	t.Run("test update - missing document", func(t *testing.T) {
+		vdri := newTestVDRI(t)
+
+		require.EqualError(t, vdri.Update(nil, pk.ID, signer), "DID and document are mandatory")
+	})
+
+	t.Run("test read - tampered delta", func(t *testing.T) {
+		vdri := newTestVDRI(t, newTestDoc(peerDID, pk))
+		require.NoError(t, vdri.Update(newTestDoc(peerDID, pk, svc), pk.ID, signer))
+
+		deltas, err := vdri.getDeltas(peerDID)
+		require.NoError(t, err)
+
+		deltas[1].Change = deltas[0].Change
+		bytes, err := json.Marshal(deltas)
+		require.NoError(t, err)
+		require.NoError(t, vdri.store.Put(peerDID, bytes))
+
+		_, err = vdri.Read(peerDID)
+		require.True(t, errors.Is(err, ErrUnauthorizedUpdate))
+
+		// the genesis version is still valid
+		_, err = vdri.Read(peerDID, vdriapi.WithVersionID(1))
+		require.NoError(t, err)
+	})
+}
+
+func TestVDRI_RotateKey(t *testing.T) {
+	svc := did.Service{ID: "#agent", Type: "did-communication", ServiceEndpoint: "https://agent.example.com/"}
+	pk, signer := newTestKey(t, peerDID, "key-1")
+	newPK, newSigner := newTestKey(t, peerDID, "key-2")
+
+	t.Run("test rotate key - success", func(t *testing.T) {
+		vdri := newTestVDRI(t, newTestDoc(peerDID, pk))
+
+		require.NoError(t, vdri.RotateKey(peerDID, pk.ID, newPK, pk.ID, signer))
+
+		doc, err := vdri.Read(peerDID)
+		require.NoError(t, err)
+		require.Equal(t, []did.PublicKey{*newPK}, doc.PublicKey)
+		require.Equal(t, *newPK, doc.Authentication[0].PublicKey)
+		require.Equal(t, *newPK, doc.KeyAgreement[0].PublicKey)
+
+		// the old key can not authorize the update anymore
+		err = vdri.Update(newTestDoc(peerDID, newPK, svc), newPK.ID, signer)
+		require.True(t, errors.Is(err, ErrUnauthorizedUpdate))
+
+		require.NoError(t, vdri.Update(newTestDoc(peerDID, newPK, svc), newPK.ID, newSigner))
+	})
+
+	t.Run("test rotate key - key not found", func(t *testing.T) {
+		vdri := newTestVDRI(t, newTestDoc(peerDID, pk))
+
+		err := vdri.RotateKey(peerDID, peerDID+"#key-3", newPK, pk.ID, signer)
+		require.EqualError(t, err, "key did:peer:1234#key-3 not found in peer DID did:peer:1234")
+	})
+
+	t.Run("test rotate key - missing new key", func(t *testing.T) {
+		vdri := newTestVDRI(t, newTestDoc(peerDID, pk))
+
+		require.EqualError(t, vdri.RotateKey(peerDID, pk.ID, nil, pk.ID, signer), "new key is mandatory")
+	})
+
+	t.Run("test rotate key - DID not found", func(t *testing.T) {
+		vdri := newTestVDRI(t)
+
+		err := vdri.RotateKey(peerDID, pk.ID, newPK, pk.ID, signer)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "delta data fetch from store failed")
+	})
+}
+
+func TestVDRI_Deactivate(t *testing.T) {
+	svc := did.Service{ID: "#agent", Type: "did-communication", ServiceEndpoint: "https://agent.example.com/"}
+	pk, signer := newTestKey(t, peerDID, "key-1")
+
+	t.Run("test deactivate - success", func(t *testing.T) {
+		vdri := newTestVDRI(t, newTestDoc(peerDID, pk))
+
+		require.NoError(t, vdri.Deactivate(peerDID, pk.ID, signer))
+
+		_, err := vdri.Read(peerDID)
+		require.True(t, errors.Is(err, vdriapi.ErrDeactivated))
+
+		_, err = vdri.Get(peerDID)
+		require.True(t, errors.Is(err, vdriapi.ErrDeactivated))
+
+		// the versions before the deactivation are still resolvable
+		doc, err := vdri.Read(peerDID, vdriapi.WithVersionID(1))
+		require.NoError(t, err)
+		require.Equal(t, peerDID, doc.ID)
+
+		// the deactivated DID can not be updated
+		err = vdri.Store(newTestDoc(peerDID, pk, svc), nil)
+		require.True(t, errors.Is(err, vdriapi.ErrDeactivated))
+
+		err = vdri.Deactivate(peerDID, pk.ID, signer)
+		require.True(t, errors.Is(err, vdriapi.ErrDeactivated))
+	})
+
+	t.Run("test deactivate - signed with other key", func(t *testing.T) {
+		vdri := newTestVDRI(t, newTestDoc(peerDID, pk))
+		_, otherSigner := newTestKey(t, peerDID, "key-2")
+
+		err := vdri.Deactivate(peerDID, pk.ID, otherSigner)
+		require.True(t, errors.Is(err, ErrUnauthorizedUpdate))
+
+		_, err = vdri.Read(peerDID)
+		require.NoError(t, err)
+	})
+
+	t.Run("test deactivate - DID not found", func(t *testing.T) {
+		vdri := newTestVDRI(t)
+
+		err := vdri.Deactivate(peerDID, pk.ID, signer)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "delta data fetch from store failed")
+	})
+}
+
+type testSigner struct {
+	privKey ed25519.PrivateKey
+	err     error
+}
+
+func (s *testSigner) SignMessage(message []byte, _ string) ([]byte, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return ed25519.Sign(s.privKey, message), nil
+}
+
+// newTestKey creates the peer DID public key, the key value is the base58 encoded verification key.
+func newTestKey(t *testing.T, didID, id string) (*did.PublicKey, *testSigner) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return &did.PublicKey{
+		ID:         didID + "#" + id,
+		Type:       "Ed25519VerificationKey2018",
+		Controller: didID,
+		Value:      []byte(base58.Encode(pubKey)),
+	}, &testSigner{privKey: privKey}
+}
+
+func newTestDoc(didID string, pk *did.PublicKey, svc ...did.Service) *did.Doc {
+	return &did.Doc{
+		Context:        []string{"https://w3id.org/did/v1"},
+		ID:             didID,
+		PublicKey:      []did.PublicKey{*pk},
+		Authentication: []did.VerificationMethod{{PublicKey: *pk}},
+		KeyAgreement:   []did.VerificationMethod{{PublicKey: *pk}},
+		Service:        svc,
+	}
+}
+
+func newTestVDRI(t *testing.T, docs ...*did.Doc) *VDRI {
+	vdri, err := New(storage.NewMockStoreProvider())
+	require.NoError(t, err)
+
+	for _, doc := range docs {
+		require.NoError(t, vdri.Store(doc, nil))
+	}
+
+	return vdri
+}
@@ -8,6 +8,7 @@ package peer
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
@@ -20,6 +21,8 @@ const (
 // VDRI implements building new peer dids
 type VDRI struct {
 	store storage.Store
+	// lock serializes the read-modify-write of the DID delta logs
+	lock sync.Mutex
 }
 
 // New return new instance of peer vdri
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	diddoc "github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
@@ -50,25 +51,57 @@ func New(ctx provider, opts ...Option) *Registry {
 
 // Resolve did document
 func (r *Registry) Resolve(did string, opts ...vdriapi.ResolveOpts) (*diddoc.Doc, error) {
-	resolveOpts := &vdriapi.ResolveDIDOpts{}
-	// Apply options
-	for _, opt := range opts {
-		opt(resolveOpts)
+	method, err := r.resolveDIDVDRI(did)
+	if err != nil {
+		return nil, err
 	}
 
-	didMethod, err := getDidMethod(did)
+	// Obtain the DID Document
+	didDoc, err := method.Read(did, opts...)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, vdriapi.ErrNotFound) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("did method read failed failed: %w", err)
 	}
 
-	// resolve did method
-	method, err := r.resolveVDRI(didMethod)
+	return didDoc, nil
+}
+
+// ResolveWithMetadata resolves did document along with the document metadata and the resolver metadata
+// (https://w3c-ccg.github.io/did-resolution/#did-resolution-result)
+func (r *Registry) ResolveWithMetadata(did string, opts ...vdriapi.ResolveOpts) (*vdriapi.DocResolution, error) {
+	start := time.Now()
+
+	method, err := r.resolveDIDVDRI(did)
 	if err != nil {
 		return nil, err
 	}
 
-	// Obtain the DID Document
-	didDoc, err := method.Read(did, opts...)
+	var result *vdriapi.DocResolution
+
+	if reader, ok := method.(vdriapi.ResolutionReader); ok {
+		result, err = reader.ReadResolution(did, opts...)
+	} else {
+		var didDoc *diddoc.Doc
+
+		didDoc, err = method.Read(did, opts...)
+		if err == nil && didDoc == nil {
+			err = vdriapi.ErrNotFound
+		}
+
+		if err == nil {
+			result = &vdriapi.DocResolution{
+				DIDDocument: didDoc,
+				DocumentMetadata: &vdriapi.DocumentMetadata{
+					Created: didDoc.Created,
+					Updated: didDoc.Updated,
+				},
+			}
+		}
+	}
+
 	if err != nil {
 		if errors.Is(err, vdriapi.ErrNotFound) {
 			return nil, err
@@ -77,12 +110,31 @@ func (r *Registry) Resolve(did string, opts ...vdriapi.ResolveOpts) (*diddoc.Doc
 		return nil, fmt.Errorf("did method read failed failed: %w", err)
 	}
 
-	if resolveOpts.ResultType == vdriapi.ResolutionResult {
-		// TODO https://github.com/hyperledger/aries-framework-go/issues/745 Support resolution-result
-		return nil, errors.New("result type 'resolution-result' not supported")
+	if result.DocumentMetadata == nil {
+		result.DocumentMetadata = &vdriapi.DocumentMetadata{}
 	}
 
-	return didDoc, nil
+	if result.ResolverMetadata == nil {
+		result.ResolverMetadata = &vdriapi.ResolverMetadata{}
+	}
+
+	if result.ResolverMetadata.ContentType == "" {
+		result.ResolverMetadata.ContentType = vdriapi.ContentTypeDIDLDJSON
+	}
+
+	result.ResolverMetadata.Duration = time.Since(start)
+
+	return result, nil
+}
+
+func (r *Registry) resolveDIDVDRI(did string) (vdriapi.VDRI, error) {
+	didMethod, err := getDidMethod(did)
+	if err != nil {
+		return nil, err
+	}
+
+	// resolve did method
+	return r.resolveVDRI(didMethod)
 }
 
 // Create returns new DID Document
@@ -144,6 +196,51 @@ func (r *Registry) Store(doc *diddoc.Doc) error {
 	return method.Store(doc, nil)
 }
 
+// Update stores the signed update of the DID document with the VDRI of its method.
+func (r *Registry) Update(doc *diddoc.Doc, keyID string, signer legacykms.Signer) error {
+	updater, err := r.resolveDIDUpdater(doc.ID)
+	if err != nil {
+		return err
+	}
+
+	return updater.Update(doc, keyID, signer)
+}
+
+// RotateKey replaces the public key of the DID document with the VDRI of its method.
+func (r *Registry) RotateKey(didID, oldKeyID string, newKey *diddoc.PublicKey, keyID string,
+	signer legacykms.Signer) error {
+	updater, err := r.resolveDIDUpdater(didID)
+	if err != nil {
+		return err
+	}
+
+	return updater.RotateKey(didID, oldKeyID, newKey, keyID, signer)
+}
+
+// Deactivate deactivates the DID with the VDRI of its method.
+func (r *Registry) Deactivate(didID, keyID string, signer legacykms.Signer) error {
+	updater, err := r.resolveDIDUpdater(didID)
+	if err != nil {
+		return err
+	}
+
+	return updater.Deactivate(didID, keyID, signer)
+}
+
+func (r *Registry) resolveDIDUpdater(did string) (vdriapi.Updater, error) {
+	v, err := r.resolveDIDVDRI(did)
+	if err != nil {
+		return nil, err
+	}
+
+	updater, ok := v.(vdriapi.Updater)
+	if !ok {
+		return nil, fmt.Errorf("update did %s: %w", did, vdriapi.ErrUpdateNotSupported)
+	}
+
+	return updater, nil
+}
+
 // Close frees resources being maintained by vdri.
 func (r *Registry) Close() error {
 	for _, v := range r.vdri {
@@ -7,13 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package vdri
 
 import (
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
 	mockkms "github.com/hyperledger/aries-framework-go/pkg/internal/mock/kms/legacykms"
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
 	mockvdri "github.com/hyperledger/aries-framework-go/pkg/internal/mock/vdri"
@@ -89,11 +92,14 @@ func TestRegistry_Resolve(t *testing.T) {
 	})
 
 	t.Run("test ResultType", func(t *testing.T) {
-		registry := New(&mockprovider.Provider{}, WithVDRI(&mockvdri.MockVDRI{AcceptValue: true}))
+		registry := New(&mockprovider.Provider{}, WithVDRI(&mockvdri.MockVDRI{
+			AcceptValue: true, ReadFunc: func(didID string, opts ...vdriapi.ResolveOpts) (*did.Doc, error) {
+				return &did.Doc{ID: didID}, nil
+			}}))
+		// the resolution result is returned by ResolveWithMetadata, Resolve returns the document
 		doc, err := registry.Resolve("1:id:123", vdriapi.WithResultType(vdriapi.ResolutionResult))
-		require.Error(t, err)
-		require.Contains(t, err.Error(), "result type 'resolution-result' not supported")
-		require.Nil(t, doc)
+		require.NoError(t, err)
+		require.Equal(t, "1:id:123", doc.ID)
 	})
 
 	t.Run("test opts passed", func(t *testing.T) {
@@ -204,3 +210,138 @@ func TestRegistry_Create(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestRegistry_ResolveWithMetadata(t *testing.T) {
+	t.Run("test metadata derived from document", func(t *testing.T) {
+		created := time.Now()
+		registry := New(&mockprovider.Provider{}, WithVDRI(&mockvdri.MockVDRI{
+			AcceptValue: true, ReadFunc: func(didID string, opts ...vdriapi.ResolveOpts) (*did.Doc, error) {
+				return &did.Doc{ID: didID, Created: &created, Updated: &created}, nil
+			}}))
+
+		result, err := registry.ResolveWithMetadata("did:example:123")
+		require.NoError(t, err)
+		require.Equal(t, "did:example:123", result.DIDDocument.ID)
+		require.Equal(t, &created, result.DocumentMetadata.Created)
+		require.Equal(t, &created, result.DocumentMetadata.Updated)
+		require.Equal(t, vdriapi.ContentTypeDIDLDJSON, result.ResolverMetadata.ContentType)
+	})
+
+	t.Run("test metadata read by vdri", func(t *testing.T) {
+		registry := New(&mockprovider.Provider{}, WithVDRI(&mockResolutionReader{
+			MockVDRI: mockvdri.MockVDRI{AcceptValue: true},
+			result: &vdriapi.DocResolution{
+				DIDDocument:      &did.Doc{ID: "did:example:123"},
+				DocumentMetadata: &vdriapi.DocumentMetadata{VersionID: "2", Deactivated: true},
+			},
+		}))
+
+		result, err := registry.ResolveWithMetadata("did:example:123", vdriapi.WithVersionID("2"))
+		require.NoError(t, err)
+		require.Equal(t, "2", result.DocumentMetadata.VersionID)
+		require.True(t, result.DocumentMetadata.Deactivated)
+		require.Equal(t, vdriapi.ContentTypeDIDLDJSON, result.ResolverMetadata.ContentType)
+	})
+
+	t.Run("test errors", func(t *testing.T) {
+		registry := New(&mockprovider.Provider{}, WithVDRI(&mockvdri.MockVDRI{
+			AcceptValue: true, ReadFunc: func(didID string, opts ...vdriapi.ResolveOpts) (*did.Doc, error) {
+				if didID == "did:example:unknown" {
+					return nil, nil
+				}
+
+				return nil, fmt.Errorf("read error")
+			}}))
+
+		_, err := registry.ResolveWithMetadata("did:example:unknown")
+		require.Equal(t, vdriapi.ErrNotFound, err)
+
+		_, err = registry.ResolveWithMetadata("did:example:123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read error")
+
+		_, err = registry.ResolveWithMetadata("did:example")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "wrong format did input")
+
+		_, err = New(&mockprovider.Provider{}).ResolveWithMetadata("did:example:123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "did method example not supported for vdri")
+	})
+}
+
+func TestRegistry_Update(t *testing.T) {
+	t.Run("test success", func(t *testing.T) {
+		updater := &mockUpdater{MockVDRI: mockvdri.MockVDRI{AcceptValue: true}}
+		registry := New(&mockprovider.Provider{}, WithVDRI(updater))
+
+		require.NoError(t, registry.Update(&did.Doc{ID: "did:example:123"}, "key-1", nil))
+		require.NoError(t, registry.RotateKey("did:example:123", "key-1", &did.PublicKey{}, "key-1", nil))
+		require.NoError(t, registry.Deactivate("did:example:123", "key-1", nil))
+		require.Equal(t, []string{"update", "rotate", "deactivate"}, updater.calls)
+	})
+
+	t.Run("test update error", func(t *testing.T) {
+		registry := New(&mockprovider.Provider{}, WithVDRI(&mockUpdater{
+			MockVDRI: mockvdri.MockVDRI{AcceptValue: true},
+			err:      fmt.Errorf("update error"),
+		}))
+
+		require.EqualError(t, registry.Update(&did.Doc{ID: "did:example:123"}, "key-1", nil), "update error")
+		require.EqualError(t, registry.RotateKey("did:example:123", "key-1", &did.PublicKey{}, "key-1", nil),
+			"update error")
+		require.EqualError(t, registry.Deactivate("did:example:123", "key-1", nil), "update error")
+	})
+
+	t.Run("test vdri does not support updates", func(t *testing.T) {
+		registry := New(&mockprovider.Provider{}, WithVDRI(&mockvdri.MockVDRI{AcceptValue: true}))
+
+		err := registry.Update(&did.Doc{ID: "did:example:123"}, "key-1", nil)
+		require.True(t, errors.Is(err, vdriapi.ErrUpdateNotSupported))
+
+		err = registry.RotateKey("did:example:123", "key-1", &did.PublicKey{}, "key-1", nil)
+		require.True(t, errors.Is(err, vdriapi.ErrUpdateNotSupported))
+
+		err = registry.Deactivate("did:example:123", "key-1", nil)
+		require.True(t, errors.Is(err, vdriapi.ErrUpdateNotSupported))
+	})
+
+	t.Run("test did method not supported", func(t *testing.T) {
+		err := New(&mockprovider.Provider{}).Deactivate("did:example:123", "key-1", nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "did method example not supported for vdri")
+	})
+}
+
+type mockUpdater struct {
+	mockvdri.MockVDRI
+	calls []string
+	err   error
+}
+
+func (m *mockUpdater) Update(*did.Doc, string, legacykms.Signer) error {
+	m.calls = append(m.calls, "update")
+
+	return m.err
+}
+
+func (m *mockUpdater) RotateKey(string, string, *did.PublicKey, string, legacykms.Signer) error {
+	m.calls = append(m.calls, "rotate")
+
+	return m.err
+}
+
+func (m *mockUpdater) Deactivate(string, string, legacykms.Signer) error {
+	m.calls = append(m.calls, "deactivate")
+
+	return m.err
+}
+
+type mockResolutionReader struct {
+	mockvdri.MockVDRI
+	result *vdriapi.DocResolution
+}
+
+func (m *mockResolutionReader) ReadResolution(string, ...vdriapi.ResolveOpts) (*vdriapi.DocResolution, error) {
+	return m.result, nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package web
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+)
+
+const (
+	didWebPrefix = "did:" + DIDMethod + ":"
+
+	wellKnownPath = "/.well-known"
+	documentPath  = "/did.json"
+
+	// maxDocumentSize is the maximum size of the DID document read from the web server.
+	maxDocumentSize = 1 << 20
+)
+
+// Read fetches the DID document from the web server of the DID (https://w3c-ccg.github.io/did-method-web/#read-resolve)
+func (v *VDRI) Read(didID string, _ ...vdriapi.ResolveOpts) (*did.Doc, error) {
+	docURL, err := documentURL(didID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := v.client.Get(docURL)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP Get request failed: %w", err)
+	}
+
+	defer closeResponseBody(resp.Body)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, vdriapi.ErrNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response from %s [%d]", docURL, resp.StatusCode)
+	}
+
+	// the body is read up to one byte past the limit to detect the oversized document
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxDocumentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading response body failed: %w", err)
+	}
+
+	if len(data) > maxDocumentSize {
+		return nil, fmt.Errorf("did:web document from %s exceeds %d bytes", docURL, maxDocumentSize)
+	}
+
+	doc, err := did.ParseDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse did:web document: %w", err)
+	}
+
+	if doc.ID != didID {
+		return nil, fmt.Errorf("did:web document ID %s does not match the DID %s", doc.ID, didID)
+	}
+
+	return doc, nil
+}
+
+// documentURL maps did:web to the URL of its DID document:
+//  did:web:example.com                -> https://example.com/.well-known/did.json
+//  did:web:example.com%3A3000         -> https://example.com:3000/.well-known/did.json
+//  did:web:example.com:user:alice     -> https://example.com/user/alice/did.json
+func documentURL(didID string) (string, error) {
+	if !strings.HasPrefix(didID, didWebPrefix) {
+		return "", fmt.Errorf("invalid did:web : %s", didID)
+	}
+
+	parts := strings.Split(strings.TrimPrefix(didID, didWebPrefix), ":")
+
+	for i, part := range parts {
+		unescaped, err := url.PathUnescape(part)
+		if err != nil {
+			return "", fmt.Errorf("invalid did:web %s : %w", didID, err)
+		}
+
+		if unescaped == "" || strings.Contains(unescaped, "/") {
+			return "", fmt.Errorf("invalid did:web : %s", didID)
+		}
+
+		parts[i] = unescaped
+	}
+
+	docPath := wellKnownPath
+	if len(parts) > 1 {
+		docPath = "/" + strings.Join(parts[1:], "/")
+	}
+
+	docURL := &url.URL{Scheme: "https", Host: parts[0], Path: docPath + documentPath}
+
+	// the host must not carry anything but the domain name and the port
+	if u, err := url.Parse(docURL.String()); err != nil || u.Host != parts[0] {
+		return "", fmt.Errorf("invalid did:web : %s", didID)
+	}
+
+	return docURL.String(), nil
+}
+
+func closeResponseBody(respBody io.Closer) {
+	e := respBody.Close()
+	if e != nil {
+		logger.Warnf("Failed to close response body: %v", e)
+	}
+}
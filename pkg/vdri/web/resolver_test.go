@@ -0,0 +1,164 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+)
+
+const docTemplate = `{
+  "@context": ["https://w3id.org/did/v1"],
+  "id": "%s",
+  "publicKey": [
+    {
+      "id": "%s#key-1",
+      "type": "Ed25519VerificationKey2018",
+      "controller": "%s",
+      "publicKeyBase58": "B12NYF8RrR3h41TDCTJojY59usg3mbtbjnFs7Eud1Y6u"
+    }
+  ],
+  "authentication": ["%s#key-1"]
+}`
+
+func TestDocumentURL(t *testing.T) {
+	tests := []struct {
+		did string
+		url string
+	}{
+		{"did:web:example.com", "https://example.com/.well-known/did.json"},
+		{"did:web:example.com%3A3000", "https://example.com:3000/.well-known/did.json"},
+		{"did:web:example.com:user:alice", "https://example.com/user/alice/did.json"},
+		{"did:web:example.com%3A3000:user:alice", "https://example.com:3000/user/alice/did.json"},
+	}
+
+	for _, test := range tests {
+		docURL, err := documentURL(test.did)
+		require.NoError(t, err)
+		require.Equal(t, test.url, docURL)
+	}
+
+	for _, invalid := range []string{
+		"did:peer:example.com",
+		"did:web:",
+		"did:web:example.com::alice",
+		"did:web:example.com%2Fpath",
+		"did:web:example.com%ZZ",
+		"did:web:user%40example.com",
+	} {
+		_, err := documentURL(invalid)
+		require.Error(t, err, invalid)
+		require.Contains(t, err.Error(), "invalid did:web")
+	}
+}
+
+func TestRead(t *testing.T) {
+	var slow time.Duration
+
+	mux := http.NewServeMux()
+	server := httptest.NewTLSServer(mux)
+
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	// the port is percent-encoded
+	didWeb := "did:web:" + strings.Replace(serverURL.Host, ":", "%3A", 1)
+
+	serveDoc := func(path, didID string) {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(slow)
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, docTemplate, didID, didID, didID, didID)
+		})
+	}
+
+	serveDoc("/.well-known/did.json", didWeb)
+	serveDoc("/user/alice/did.json", didWeb+":user:alice")
+	serveDoc("/user/bob/did.json", didWeb+":user:alice")
+	mux.HandleFunc("/user/carol/did.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "not a document")
+	})
+	mux.HandleFunc("/user/dave/did.json", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/user/erin/did.json", func(w http.ResponseWriter, r *http.Request) {
+		// the valid document padded past the size limit
+		fmt.Fprintf(w, docTemplate, didWeb+":user:erin", didWeb+":user:erin", didWeb+":user:erin", didWeb+":user:erin")
+		fmt.Fprint(w, strings.Repeat(" ", maxDocumentSize))
+	})
+
+	v := New(WithTLSConfig(server.Client().Transport.(*http.Transport).TLSClientConfig))
+
+	t.Run("test read - domain", func(t *testing.T) {
+		doc, err := v.Read(didWeb)
+		require.NoError(t, err)
+		require.Equal(t, didWeb, doc.ID)
+		require.Len(t, doc.PublicKey, 1)
+		require.Len(t, doc.Authentication, 1)
+	})
+
+	t.Run("test read - path", func(t *testing.T) {
+		doc, err := v.Read(didWeb + ":user:alice")
+		require.NoError(t, err)
+		require.Equal(t, didWeb+":user:alice", doc.ID)
+	})
+
+	t.Run("test read - not found", func(t *testing.T) {
+		_, err := v.Read(didWeb + ":user:unknown")
+		require.True(t, errors.Is(err, vdriapi.ErrNotFound))
+	})
+
+	t.Run("test read - invalid documents", func(t *testing.T) {
+		_, err := v.Read(didWeb + ":user:bob")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not match the DID")
+
+		_, err = v.Read(didWeb + ":user:carol")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse did:web document")
+
+		_, err = v.Read(didWeb + ":user:dave")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "[500]")
+
+		_, err = v.Read(didWeb + ":user:erin")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "exceeds 1048576 bytes")
+
+		_, err = v.Read("did:web:")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid did:web")
+	})
+
+	t.Run("test read - untrusted certificate", func(t *testing.T) {
+		_, err := New().Read(didWeb)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "HTTP Get request failed")
+	})
+
+	t.Run("test read - timeout", func(t *testing.T) {
+		slow = 50 * time.Millisecond
+		defer func() { slow = 0 }()
+
+		_, err := New(WithTLSConfig(server.Client().Transport.(*http.Transport).TLSClientConfig),
+			WithTimeout(time.Millisecond)).Read(didWeb)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "HTTP Get request failed")
+	})
+}
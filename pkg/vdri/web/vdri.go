@@ -0,0 +1,84 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package web
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+)
+
+var logger = log.New("aries-framework/vdri/web")
+
+const (
+	// DIDMethod did method
+	DIDMethod = "web"
+
+	defaultTimeout = 10 * time.Second
+)
+
+// VDRI implements did:web method support (https://w3c-ccg.github.io/did-method-web/).
+// The DID document is published by the controller on its web server, the VDRI only reads it.
+type VDRI struct {
+	client *http.Client
+}
+
+// New returns new instance of web vdri
+func New(opts ...Option) *VDRI {
+	v := &VDRI{client: &http.Client{Timeout: defaultTimeout}}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// Accept did method
+func (v *VDRI) Accept(method string) bool {
+	return method == DIDMethod
+}
+
+// Build did doc - did:web document is published by the controller on its web server
+func (v *VDRI) Build(_ *vdriapi.PubKey, _ ...vdriapi.DocOpts) (*did.Doc, error) {
+	return nil, errors.New("build not supported in did:web vdri")
+}
+
+// Store did doc - did:web document is published by the controller on its web server
+func (v *VDRI) Store(_ *did.Doc, _ *[]vdriapi.ModifiedBy) error {
+	logger.Warnf("store not supported in did:web vdri")
+	return nil
+}
+
+// Close frees resources being maintained by vdri
+func (v *VDRI) Close() error {
+	return nil
+}
+
+// Option configures the web vdri
+type Option func(opts *VDRI)
+
+// WithTimeout option is for definition of HTTP(s) timeout value of the DID document request
+func WithTimeout(timeout time.Duration) Option {
+	return func(opts *VDRI) {
+		opts.client.Timeout = timeout
+	}
+}
+
+// WithTLSConfig option is for definition of secured HTTP transport using a tls.Config instance
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(opts *VDRI) {
+		opts.client.Transport = &http.Transport{
+			TLSClientConfig: tlsConfig,
+		}
+	}
+}
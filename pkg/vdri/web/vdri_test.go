@@ -0,0 +1,27 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package web
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+)
+
+func TestVDRI(t *testing.T) {
+	v := New()
+	require.True(t, v.Accept(DIDMethod))
+	require.False(t, v.Accept("key"))
+
+	_, err := v.Build(&vdriapi.PubKey{})
+	require.EqualError(t, err, "build not supported in did:web vdri")
+
+	require.NoError(t, v.Store(nil, nil))
+	require.NoError(t, v.Close())
+}
@@ -33,3 +33,9 @@ PKGS=`go list github.com/hyperledger/aries-framework-go/cmd/aries-agent-rest/...
 go test $PKGS -count=1 -race -coverprofile=profile.out -covermode=atomic -timeout=10m
 amend_coverage_file
 cd "$pwd" || exit
+
+# Running sql storage unit test
+cd pkg/storage/sql
+go test ./... -count=1 -race -coverprofile=profile.out -covermode=atomic -timeout=10m
+amend_coverage_file
+cd "$pwd" || exit